@@ -14,6 +14,7 @@ import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
 	"github.com/mynaparrot/plugnmeet-server/pkg/factory"
 	"github.com/mynaparrot/plugnmeet-server/pkg/routers"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 	"github.com/mynaparrot/plugnmeet-server/version"
 	"github.com/sirupsen/logrus"
 )
@@ -54,6 +55,12 @@ func startServer(configFile string) {
 	}
 	appCnf.Logger = logger
 
+	// 4.1. Set up OpenTelemetry tracing, it will be a no-op if disabled.
+	shutdownTracing, err := tracing.Init(ctx, appCnf.TracingSettings, logger)
+	if err != nil {
+		logger.WithError(err).Fatalln("Failed to setup tracing")
+	}
+
 	// 5. Prepare server dependencies like database, Redis, and NATS connections.
 	err = helpers.PrepareServer(ctx, appCnf)
 	if err != nil {
@@ -87,6 +94,13 @@ func startServer(configFile string) {
 		// shut down the application
 		appFactory.Shutdown()
 
+		// flush the pending spans
+		tracingCtx, tracingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := shutdownTracing(tracingCtx); err != nil {
+			logger.WithError(err).Warn("Failed to shutdown tracing")
+		}
+		tracingCancel()
+
 		// Attempt to gracefully shut down the Fiber server, waiting for active connections to finish.
 		if err := rt.ShutdownWithTimeout(15 * time.Second); err != nil {
 			logger.WithError(err).Warn("Graceful shutdown failed, forcing exit.")
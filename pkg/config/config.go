@@ -39,6 +39,13 @@ type AppConfig struct {
 	SharedNotePad                SharedNotePad                `yaml:"shared_notepad"`
 	AzureCognitiveServicesSpeech AzureCognitiveServicesSpeech `yaml:"azure_cognitive_services_speech"`
 	AnalyticsSettings            *AnalyticsSettings           `yaml:"analytics_settings"`
+	ChatArchiveSettings          *ChatArchiveSettings         `yaml:"chat_archive_settings"`
+	DownloadSettings             *DownloadSettings            `yaml:"download_settings"`
+	FileConversionSettings       *FileConversionSettings      `yaml:"file_conversion_settings"`
+	StorageSettings              StorageSettings              `yaml:"storage_settings"`
+	TracingSettings              *TracingSettings             `yaml:"tracing_settings"`
+	LtiSettings                  *LtiSettings                 `yaml:"lti_settings"`
+	BBBSettings                  *BBBSettings                 `yaml:"bbb_settings"`
 	NatsInfo                     NatsInfo                     `yaml:"nats_info"`
 }
 
@@ -57,9 +64,13 @@ type ClientInfo struct {
 }
 
 type WebhookConf struct {
-	Enable              bool   `yaml:"enable"`
-	Url                 string `yaml:"url,omitempty"`
-	EnableForPerMeeting bool   `yaml:"enable_for_per_meeting"`
+	Enable              bool          `yaml:"enable"`
+	Url                 string        `yaml:"url,omitempty"`
+	EnableForPerMeeting bool          `yaml:"enable_for_per_meeting"`
+	MaxAttempts         int           `yaml:"max_attempts"`
+	InitialBackoff      time.Duration `yaml:"initial_backoff"`
+	MaxBackoff          time.Duration `yaml:"max_backoff"`
+	DeadLetterMaxAge    time.Duration `yaml:"dead_letter_max_age"`
 }
 
 type PrometheusConf struct {
@@ -87,6 +98,27 @@ type RecorderInfo struct {
 	EnableDelRecordingBackup   bool          `yaml:"enable_del_recording_backup"`
 	DelRecordingBackupPath     string        `yaml:"del_recording_backup_path"`
 	DelRecordingBackupDuration time.Duration `yaml:"del_recording_backup_duration"`
+	// RequestTimeout is how long to wait for the acknowledgement of a recorder
+	// before trying with another one
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// ReservationTtl is how long a reserved slot will be kept
+	// if the recorder doesn't report that the task has started
+	ReservationTtl time.Duration `yaml:"reservation_ttl"`
+	// RecordingRetention is the default duration to keep the recordings.
+	// It can be overridden per tenant or per room, 0 means keep forever
+	RecordingRetention time.Duration `yaml:"recording_retention"`
+	// MediaInfo is to extract the duration, codecs & thumbnails of the recordings
+	MediaInfo *RecordingMediaInfo `yaml:"media_info"`
+}
+
+type RecordingMediaInfo struct {
+	Enabled     bool   `yaml:"enabled"`
+	FFprobePath string `yaml:"ffprobe_path"`
+	FFmpegPath  string `yaml:"ffmpeg_path"`
+	// Thumbnails is the number of images to generate, 0 will disable thumbnails
+	Thumbnails     int           `yaml:"thumbnails"`
+	ThumbnailWidth int           `yaml:"thumbnail_width"`
+	Timeout        time.Duration `yaml:"timeout"`
 }
 
 type SharedNotePad struct {
@@ -120,6 +152,132 @@ type AnalyticsSettings struct {
 	TokenValidity  *time.Duration `yaml:"token_validity"`
 }
 
+type ChatArchiveSettings struct {
+	Enabled bool `yaml:"enabled"`
+	// ArchiveAllRooms will archive chat of every room,
+	// otherwise room need to opt in using `archive_chat` in metadata extra_data
+	ArchiveAllRooms bool `yaml:"archive_all_rooms"`
+	// Retention of archived messages, default 90 days
+	Retention     time.Duration `yaml:"retention"`
+	TokenValidity time.Duration `yaml:"token_validity"`
+}
+
+type DownloadSettings struct {
+	// SingleUseTokens will make every download token single use by default,
+	// it can be changed during the token request too
+	SingleUseTokens bool `yaml:"single_use_tokens"`
+	EnableAudit     bool `yaml:"enable_audit"`
+	// AuditRetention of the download audit logs, default 90 days
+	AuditRetention time.Duration `yaml:"audit_retention"`
+}
+
+type FileConversionSettings struct {
+	// Backend can be local or http, default local
+	Backend string `yaml:"backend"`
+	// Concurrency is the number of files this server will convert at the same time, default 2
+	Concurrency int `yaml:"concurrency"`
+	// Timeout of a conversion job, default 10 minutes
+	Timeout time.Duration `yaml:"timeout"`
+	// HttpConverter is required for http backend
+	HttpConverter *HttpConverterSettings `yaml:"http_converter"`
+	// PageResolutions of the rendered page variants of the whiteboard files
+	PageResolutions *WhiteboardPageResolutions `yaml:"page_resolutions"`
+	// DisableTextExtraction will skip extracting the text of the pages
+	DisableTextExtraction bool `yaml:"disable_text_extraction"`
+}
+
+// WhiteboardPageResolutions are in DPI, every page will be rendered once for each of them
+type WhiteboardPageResolutions struct {
+	// Thumbnail default 30
+	Thumbnail int `yaml:"thumbnail"`
+	// Screen default 110
+	Screen int `yaml:"screen"`
+	// HighDpi default 300
+	HighDpi int `yaml:"high_dpi"`
+}
+
+type LtiSettings struct {
+	// ToolPrivateKey is the path of the RSA private key in PEM format,
+	// which is required to use LTI Advantage services of the LTI 1.3 platforms.
+	// The public key will be published at /lti/v1p3/jwks
+	ToolPrivateKey string                `yaml:"tool_private_key"`
+	GradePassback  *LtiGradePassbackInfo `yaml:"grade_passback"`
+}
+
+// LtiGradePassbackInfo is for sending the attendance of the users as the grade to the LMS
+// after the room has ended. It requires analytics_settings to be enabled.
+type LtiGradePassbackInfo struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxAttempts to send a grade, default 5
+	MaxAttempts int `yaml:"max_attempts"`
+	// InitialBackoff default 1 minute, will be doubled after every failure up to MaxBackoff
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	// MaxBackoff default 1 hour
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// BBBSettings is for the BigBlueButton compatible API
+type BBBSettings struct {
+	// ChecksumAlgorithms is the allow-list of sha1, sha256, sha384 & sha512,
+	// default all of them. The algorithm will be detected from the length of the checksum.
+	ChecksumAlgorithms []string `yaml:"checksum_algorithms"`
+	// JoinReplayProtection will reject the captured join urls
+	JoinReplayProtection *BBBJoinReplayProtection `yaml:"join_replay_protection"`
+}
+
+// BBBJoinReplayProtection requires the timestamp parameter (unix time) in the join requests,
+// which must be within the window & the same request can be used only once.
+// The nonce parameter can be added to create multiple join urls at the same time.
+type BBBJoinReplayProtection struct {
+	Enabled bool `yaml:"enabled"`
+	// Window default 5 minutes
+	Window time.Duration `yaml:"window"`
+}
+
+// HttpConverterSettings is for Gotenberg compatible converter,
+// which will be used to convert office documents to PDF
+type HttpConverterSettings struct {
+	Url      string `yaml:"url"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type StorageSettings struct {
+	// Driver can be local or s3, default local
+	Driver string             `yaml:"driver"`
+	S3     *S3StorageSettings `yaml:"s3"`
+}
+
+type S3StorageSettings struct {
+	Endpoint           string        `yaml:"endpoint"`
+	Region             string        `yaml:"region"`
+	AccessKey          string        `yaml:"access_key"`
+	SecretKey          string        `yaml:"secret_key"`
+	Bucket             string        `yaml:"bucket"`
+	UseSSL             bool          `yaml:"use_ssl"`
+	ForcePathStyle     bool          `yaml:"force_path_style"`
+	RecordingsPrefix   string        `yaml:"recordings_prefix"`
+	UploadsPrefix      string        `yaml:"uploads_prefix"`
+	AnalyticsPrefix    string        `yaml:"analytics_prefix"`
+	PresignedDownload  bool          `yaml:"presigned_download"`
+	PresignedUrlExpiry time.Duration `yaml:"presigned_url_expiry"`
+	// RecordingBackupsPrefix must not be inside the recordings_prefix,
+	// otherwise the deleted recordings will be listed with the recordings
+	RecordingBackupsPrefix string `yaml:"recording_backups_prefix"`
+}
+
+type TracingSettings struct {
+	Enabled bool `yaml:"enabled"`
+	// otlp or stdout
+	Exporter string `yaml:"exporter"`
+	// host:port of the OTLP/HTTP collector
+	OtlpEndpoint string `yaml:"otlp_endpoint"`
+	OtlpInsecure bool   `yaml:"otlp_insecure"`
+	ServiceName  string `yaml:"service_name"`
+	// between 0 and 1, default 1 (all the traces)
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
 type ChatParticipant struct {
 	RoomSid string
 	RoomId  string
@@ -208,6 +366,48 @@ func New(appCnf *AppConfig) (*AppConfig, error) {
 		appCnf.Client.TokenValidity = &validity
 	}
 
+	// webhook delivery retry policy
+	if appCnf.Client.WebhookConf.MaxAttempts <= 0 {
+		appCnf.Client.WebhookConf.MaxAttempts = 8
+	}
+	if appCnf.Client.WebhookConf.InitialBackoff <= 0 {
+		appCnf.Client.WebhookConf.InitialBackoff = time.Second * 5
+	}
+	if appCnf.Client.WebhookConf.MaxBackoff <= 0 {
+		appCnf.Client.WebhookConf.MaxBackoff = time.Minute * 10
+	}
+	if appCnf.Client.WebhookConf.DeadLetterMaxAge <= 0 {
+		appCnf.Client.WebhookConf.DeadLetterMaxAge = time.Hour * 24 * 7
+	}
+
+	// default storage driver is local
+	if appCnf.StorageSettings.Driver == "" {
+		appCnf.StorageSettings.Driver = StorageDriverLocal
+	}
+	if appCnf.StorageSettings.Driver == StorageDriverS3 && appCnf.StorageSettings.S3 == nil {
+		return nil, fmt.Errorf("storage_settings.s3 is required for %s storage driver", StorageDriverS3)
+	}
+	if s3 := appCnf.StorageSettings.S3; s3 != nil {
+		if s3.RecordingsPrefix == "" {
+			s3.RecordingsPrefix = "recordings"
+		}
+		if s3.UploadsPrefix == "" {
+			s3.UploadsPrefix = "uploads"
+		}
+		if s3.AnalyticsPrefix == "" {
+			s3.AnalyticsPrefix = "analytics"
+		}
+		if s3.RecordingBackupsPrefix == "" {
+			s3.RecordingBackupsPrefix = "recordings_del_backup"
+		}
+		if isS3SubPrefix(s3.RecordingBackupsPrefix, s3.RecordingsPrefix) {
+			return nil, fmt.Errorf("storage_settings.s3.recording_backups_prefix must be outside of the recordings_prefix")
+		}
+		if s3.PresignedUrlExpiry == 0 {
+			s3.PresignedUrlExpiry = time.Minute * 5
+		}
+	}
+
 	// set default values
 	if appCnf.AnalyticsSettings != nil {
 		if appCnf.AnalyticsSettings.FilesStorePath == nil {
@@ -222,7 +422,7 @@ func New(appCnf *AppConfig) (*AppConfig, error) {
 			p = filepath.Join(appCnf.RootWorkingDir, p)
 		}
 
-		if _, err := os.Stat(p); os.IsNotExist(err) {
+		if _, err := os.Stat(p); os.IsNotExist(err) && appCnf.StorageSettings.Driver == StorageDriverLocal {
 			err = os.MkdirAll(p, os.ModePerm)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create analytics directory %s: %w", p, err)
@@ -230,6 +430,97 @@ func New(appCnf *AppConfig) (*AppConfig, error) {
 		}
 	}
 
+	if appCnf.ChatArchiveSettings != nil {
+		if appCnf.ChatArchiveSettings.Retention <= 0 {
+			appCnf.ChatArchiveSettings.Retention = time.Hour * 24 * 90
+		}
+		if appCnf.ChatArchiveSettings.TokenValidity <= 0 {
+			appCnf.ChatArchiveSettings.TokenValidity = time.Minute * 30
+		}
+	}
+
+	if appCnf.DownloadSettings == nil {
+		appCnf.DownloadSettings = new(DownloadSettings)
+	}
+	if appCnf.DownloadSettings.AuditRetention <= 0 {
+		appCnf.DownloadSettings.AuditRetention = time.Hour * 24 * 90
+	}
+
+	if appCnf.FileConversionSettings == nil {
+		appCnf.FileConversionSettings = new(FileConversionSettings)
+	}
+	if appCnf.FileConversionSettings.Backend == "" {
+		appCnf.FileConversionSettings.Backend = ConverterBackendLocal
+	}
+	if appCnf.FileConversionSettings.Backend == ConverterBackendHttp && (appCnf.FileConversionSettings.HttpConverter == nil || appCnf.FileConversionSettings.HttpConverter.Url == "") {
+		return nil, fmt.Errorf("file_conversion_settings.http_converter.url is required for %s backend", ConverterBackendHttp)
+	}
+	if appCnf.FileConversionSettings.Concurrency <= 0 {
+		appCnf.FileConversionSettings.Concurrency = 2
+	}
+	if appCnf.FileConversionSettings.Timeout <= 0 {
+		appCnf.FileConversionSettings.Timeout = time.Minute * 10
+	}
+	if appCnf.FileConversionSettings.PageResolutions == nil {
+		appCnf.FileConversionSettings.PageResolutions = new(WhiteboardPageResolutions)
+	}
+	if appCnf.FileConversionSettings.PageResolutions.Thumbnail <= 0 {
+		appCnf.FileConversionSettings.PageResolutions.Thumbnail = 30
+	}
+	if appCnf.FileConversionSettings.PageResolutions.Screen <= 0 {
+		appCnf.FileConversionSettings.PageResolutions.Screen = 110
+	}
+	if appCnf.FileConversionSettings.PageResolutions.HighDpi <= 0 {
+		appCnf.FileConversionSettings.PageResolutions.HighDpi = 300
+	}
+
+	if appCnf.LtiSettings == nil {
+		appCnf.LtiSettings = new(LtiSettings)
+	}
+	if appCnf.LtiSettings.GradePassback == nil {
+		appCnf.LtiSettings.GradePassback = new(LtiGradePassbackInfo)
+	}
+	if appCnf.LtiSettings.GradePassback.MaxAttempts <= 0 {
+		appCnf.LtiSettings.GradePassback.MaxAttempts = 5
+	}
+	if appCnf.LtiSettings.GradePassback.InitialBackoff <= 0 {
+		appCnf.LtiSettings.GradePassback.InitialBackoff = time.Minute
+	}
+	if appCnf.LtiSettings.GradePassback.MaxBackoff <= 0 {
+		appCnf.LtiSettings.GradePassback.MaxBackoff = time.Hour
+	}
+
+	if appCnf.BBBSettings == nil {
+		appCnf.BBBSettings = new(BBBSettings)
+	}
+	if len(appCnf.BBBSettings.ChecksumAlgorithms) == 0 {
+		appCnf.BBBSettings.ChecksumAlgorithms = []string{"sha1", "sha256", "sha384", "sha512"}
+	}
+	for i, a := range appCnf.BBBSettings.ChecksumAlgorithms {
+		appCnf.BBBSettings.ChecksumAlgorithms[i] = strings.ToLower(strings.TrimSpace(a))
+	}
+	if appCnf.BBBSettings.JoinReplayProtection == nil {
+		appCnf.BBBSettings.JoinReplayProtection = new(BBBJoinReplayProtection)
+	}
+	if appCnf.BBBSettings.JoinReplayProtection.Window <= 0 {
+		appCnf.BBBSettings.JoinReplayProtection.Window = time.Minute * 5
+	}
+
+	if appCnf.TracingSettings != nil {
+		if appCnf.TracingSettings.Exporter == "" {
+			appCnf.TracingSettings.Exporter = TracingExporterOtlp
+		}
+		if appCnf.TracingSettings.OtlpEndpoint == "" {
+			appCnf.TracingSettings.OtlpEndpoint = "localhost:4318"
+		}
+		if appCnf.TracingSettings.ServiceName == "" {
+			appCnf.TracingSettings.ServiceName = "plugnmeet-server"
+		}
+		if appCnf.TracingSettings.SampleRatio <= 0 || appCnf.TracingSettings.SampleRatio > 1 {
+			appCnf.TracingSettings.SampleRatio = 1
+		}
+	}
+
 	// set default
 	if appCnf.RecorderInfo.EnableDelRecordingBackup {
 		if appCnf.RecorderInfo.DelRecordingBackupDuration == 0 {
@@ -240,11 +531,38 @@ func New(appCnf *AppConfig) (*AppConfig, error) {
 			appCnf.RecorderInfo.DelRecordingBackupPath = path.Join(appCnf.RecorderInfo.RecordingFilesPath, "del_backup")
 		}
 
-		err := os.MkdirAll(appCnf.RecorderInfo.DelRecordingBackupPath, 0755)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create recording backup directory %s: %w", appCnf.RecorderInfo.DelRecordingBackupPath, err)
+		if appCnf.StorageSettings.Driver == StorageDriverLocal {
+			err := os.MkdirAll(appCnf.RecorderInfo.DelRecordingBackupPath, 0755)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create recording backup directory %s: %w", appCnf.RecorderInfo.DelRecordingBackupPath, err)
+			}
+		}
+	}
+
+	if appCnf.RecorderInfo.RequestTimeout <= 0 {
+		appCnf.RecorderInfo.RequestTimeout = time.Second * 3
+	}
+	if appCnf.RecorderInfo.ReservationTtl <= 0 {
+		appCnf.RecorderInfo.ReservationTtl = time.Second * 30
+	}
+	if appCnf.RecorderInfo.MediaInfo == nil {
+		appCnf.RecorderInfo.MediaInfo = &RecordingMediaInfo{
+			Enabled:    true,
+			Thumbnails: 3,
 		}
 	}
+	if appCnf.RecorderInfo.MediaInfo.FFprobePath == "" {
+		appCnf.RecorderInfo.MediaInfo.FFprobePath = "ffprobe"
+	}
+	if appCnf.RecorderInfo.MediaInfo.FFmpegPath == "" {
+		appCnf.RecorderInfo.MediaInfo.FFmpegPath = "ffmpeg"
+	}
+	if appCnf.RecorderInfo.MediaInfo.ThumbnailWidth <= 0 {
+		appCnf.RecorderInfo.MediaInfo.ThumbnailWidth = 320
+	}
+	if appCnf.RecorderInfo.MediaInfo.Timeout <= 0 {
+		appCnf.RecorderInfo.MediaInfo.Timeout = time.Minute * 5
+	}
 
 	if appCnf.DatabaseInfo.Prefix != "" {
 		dbTablePrefix = appCnf.DatabaseInfo.Prefix
@@ -297,3 +615,9 @@ func FormatDBTable(table string) string {
 	}
 	return table
 }
+
+// isS3SubPrefix returns true if prefix is the same as or inside of the parent
+func isS3SubPrefix(prefix, parent string) bool {
+	prefix, parent = strings.Trim(prefix, "/"), strings.Trim(parent, "/")
+	return prefix == parent || strings.HasPrefix(prefix, parent+"/")
+}
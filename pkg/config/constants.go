@@ -8,6 +8,12 @@ const (
 	IngressUserIdPrefix                  = "ingres_"
 	RecorderUserAuthName                 = "PLUGNMEET_RECORDER_AUTH"
 	MaxPreloadedWhiteboardFileSize int64 = 5 * 1000000 // limit to 5MB
+	StorageDriverLocal                   = "local"
+	StorageDriverS3                      = "s3"
+	TracingExporterOtlp                  = "otlp"
+	TracingExporterStdout                = "stdout"
+	ConverterBackendLocal                = "local"
+	ConverterBackendHttp                 = "http"
 
 	// all the time.Sleep() values
 	WaitBeforeTriggerOnAfterRoomEnded        = 10 * time.Second
@@ -15,6 +21,7 @@ const (
 	WaitBeforeBreakoutRoomOnAfterRoomStart   = 2 * time.Second
 	WaitBeforeAnalyticsStartProcessing       = 40 * time.Second
 	MaxDurationWaitBeforeCleanRoomWebhook    = 1 * time.Minute
+	MaxDelayToCreateScheduledRoom            = 15 * time.Minute
 
-	DefaultWebhookQueueSize = 200
+	WebhookDeliveryTimeout = 10 * time.Second
 )
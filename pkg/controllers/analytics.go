@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"encoding/json"
+
 	"buf.build/go/protovalidate"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-protocol/utils"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
@@ -16,13 +19,19 @@ var unmarshalOpts = protojson.UnmarshalOptions{
 
 // AnalyticsController holds the dependencies for analytics-related handlers.
 type AnalyticsController struct {
-	AnalyticsModel *models.AnalyticsModel
+	AnalyticsModel     *models.AnalyticsModel
+	TenantModel        *models.TenantModel
+	DownloadAuditModel *models.DownloadAuditModel
+	StorageService     *storageservice.StorageService
 }
 
 // NewAnalyticsController creates a new AnalyticsController.
-func NewAnalyticsController(am *models.AnalyticsModel) *AnalyticsController {
+func NewAnalyticsController(am *models.AnalyticsModel, tm *models.TenantModel, dam *models.DownloadAuditModel, ss *storageservice.StorageService) *AnalyticsController {
 	return &AnalyticsController{
-		AnalyticsModel: am,
+		AnalyticsModel:     am,
+		TenantModel:        tm,
+		DownloadAuditModel: dam,
+		StorageService:     ss,
 	}
 }
 
@@ -53,7 +62,7 @@ func (ac *AnalyticsController) HandleFetchAnalytics(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
-	result, err := ac.AnalyticsModel.FetchAnalytics(req)
+	result, err := ac.AnalyticsModel.FetchAnalytics(getTenantId(c), req)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
@@ -76,6 +85,10 @@ func (ac *AnalyticsController) HandleDeleteAnalytics(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
+	if !ac.TenantModel.CanAccessAnalytics(getTenantId(c), req.FileId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "analytics not found")
+	}
+
 	err := ac.AnalyticsModel.DeleteAnalytics(req)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
@@ -91,7 +104,17 @@ func (ac *AnalyticsController) HandleGetAnalyticsDownloadToken(c *fiber.Ctx) err
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
-	token, err := ac.AnalyticsModel.GetAnalyticsDownloadToken(req)
+	if !ac.TenantModel.CanAccessAnalytics(getTenantId(c), req.FileId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "analytics not found")
+	}
+
+	// token restrictions aren't part of the protocol, so those will be read separately
+	opts := new(models.DownloadTokenOptions)
+	if err := json.Unmarshal(c.Body(), opts); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	token, err := ac.AnalyticsModel.GetAnalyticsDownloadToken(req, opts)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
@@ -111,11 +134,11 @@ func (ac *AnalyticsController) HandleDownloadAnalytics(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).SendString("token require or invalid url")
 	}
 
-	file, status, err := ac.AnalyticsModel.VerifyAnalyticsToken(token)
+	info, status, err := ac.AnalyticsModel.VerifyAnalyticsToken(c.UserContext(), token, c.IP())
 	if err != nil {
 		return c.Status(status).SendString(err.Error())
 	}
 
-	c.Attachment(file)
-	return c.SendFile(file, false)
+	audit := newDownloadAudit(c, ac.DownloadAuditModel, models.DownloadFileTypeAnalytics, info.Key, info)
+	return sendStorageFile(c, ac.StorageService, ac.StorageService.Analytics(), info.Key, !info.Restricted, audit)
 }
@@ -22,15 +22,17 @@ type AuthController struct {
 	AppConfig   *config.AppConfig
 	AuthModel   *models.AuthModel
 	RoomModel   *models.RoomModel
+	TenantModel *models.TenantModel
 	NatsService *natsservice.NatsService
 }
 
 // NewAuthController creates a new AuthController.
-func NewAuthController(config *config.AppConfig, natsService *natsservice.NatsService, authModel *models.AuthModel, roomModel *models.RoomModel) *AuthController {
+func NewAuthController(config *config.AppConfig, natsService *natsservice.NatsService, authModel *models.AuthModel, roomModel *models.RoomModel, tenantModel *models.TenantModel) *AuthController {
 	return &AuthController{
 		AppConfig:   config,
 		AuthModel:   authModel,
 		RoomModel:   roomModel,
+		TenantModel: tenantModel,
 		NatsService: natsService,
 	}
 }
@@ -41,16 +43,17 @@ func (ac *AuthController) HandleAuthHeaderCheck(c *fiber.Ctx) error {
 	signature := c.Get("HASH-SIGNATURE", "")
 	body := c.Body()
 
-	if apiKey != ac.AppConfig.Client.ApiKey {
+	tenantId, secret, err := ac.TenantModel.VerifyApiKey(apiKey)
+	if err != nil {
 		c.Status(fiber.StatusUnauthorized)
-		return utils.SendCommonProtoJsonResponse(c, false, "invalid API key")
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 	if signature == "" {
 		c.Status(fiber.StatusUnauthorized)
 		return utils.SendCommonProtoJsonResponse(c, false, "hash signature value required")
 	}
 
-	mac := hmac.New(sha256.New, []byte(ac.AppConfig.Client.Secret))
+	mac := hmac.New(sha256.New, []byte(secret))
 	mac.Write(body)
 	expectedSignature := hex.EncodeToString(mac.Sum(nil))
 	if subtle.ConstantTimeCompare([]byte(expectedSignature), []byte(signature)) != 1 {
@@ -58,6 +61,18 @@ func (ac *AuthController) HandleAuthHeaderCheck(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, "can't verify provided information")
 	}
 
+	// empty for the default API key from config
+	c.Locals("tenantId", tenantId)
+	return c.Next()
+}
+
+// HandleDefaultApiKeyOnly is a middleware to allow only the default API key from config.
+// It should be used after HandleAuthHeaderCheck for routes which can't be used by tenants.
+func (ac *AuthController) HandleDefaultApiKeyOnly(c *fiber.Ctx) error {
+	if getTenantId(c) != "" {
+		c.Status(fiber.StatusForbidden)
+		return utils.SendCommonProtoJsonResponse(c, false, "operation not allowed for this API key")
+	}
 	return c.Next()
 }
 
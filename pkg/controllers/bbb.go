@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/mynaparrot/plugnmeet-protocol/bbbapiwrapper"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -25,17 +28,19 @@ type BBBController struct {
 	UserModel          *models.UserModel
 	BBBApiWrapperModel *models.BBBApiWrapperModel
 	RecordingModel     *models.RecordingModel
+	TenantModel        *models.TenantModel
 	NatsService        *natsservice.NatsService
 }
 
 // NewBBBController creates a new BBBController.
-func NewBBBController(config *config.AppConfig, roomModel *models.RoomModel, userModel *models.UserModel, bbbApiWrapperModel *models.BBBApiWrapperModel, recordingModel *models.RecordingModel, natsService *natsservice.NatsService) *BBBController {
+func NewBBBController(config *config.AppConfig, roomModel *models.RoomModel, userModel *models.UserModel, bbbApiWrapperModel *models.BBBApiWrapperModel, recordingModel *models.RecordingModel, tenantModel *models.TenantModel, natsService *natsservice.NatsService) *BBBController {
 	return &BBBController{
 		AppConfig:          config,
 		RoomModel:          roomModel,
 		UserModel:          userModel,
 		BBBApiWrapperModel: bbbApiWrapperModel,
 		RecordingModel:     recordingModel,
+		TenantModel:        tenantModel,
 		NatsService:        natsService,
 	}
 }
@@ -43,8 +48,9 @@ func NewBBBController(config *config.AppConfig, roomModel *models.RoomModel, use
 // HandleVerifyApiRequest is a middleware to verify BBB API requests.
 func (bc *BBBController) HandleVerifyApiRequest(c *fiber.Ctx) error {
 	apiKey := c.Params("apiKey")
-	if apiKey == "" || apiKey != bc.AppConfig.Client.ApiKey {
-		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "apiKeyError", "invalid api key"))
+	tenantId, secret, integration, err := bc.BBBApiWrapperModel.VerifyApiKey(apiKey)
+	if err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "apiKeyError", err.Error()))
 	}
 
 	hasParams := false
@@ -78,14 +84,13 @@ func (bc *BBBController) HandleVerifyApiRequest(c *fiber.Ctx) error {
 		})
 	}
 
+	// the method is the path after /api/, e.g. create or hooks/create
+	_, method, _ = strings.Cut(c.Path(), "/bigbluebutton/api/")
+	method = strings.TrimSuffix(method, "/")
 	if rType == "post" {
-		s2 := strings.Split(c.Path(), "/")
-		method = s2[len(s2)-1]
 		data = string(c.Body())
 	} else {
 		s1 := strings.Split(c.OriginalURL(), "?")
-		s2 := strings.Split(c.Path(), "/")
-		method = s2[len(s2)-1]
 		data = s1[1]
 	}
 
@@ -104,19 +109,50 @@ func (bc *BBBController) HandleVerifyApiRequest(c *fiber.Ctx) error {
 		queries = strings.TrimSuffix(s3[0], "&")
 	}
 
-	ourSum := bbbapiwrapper.CalculateCheckSum(bc.AppConfig.Client.Secret, method, queries)
+	algo := helpers.BBBChecksumAlgorithm(checksum)
+	if algo == "" || !slices.Contains(bc.AppConfig.BBBSettings.ChecksumAlgorithms, algo) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "checksumError", "Checksums do not match"))
+	}
+	ourSum := helpers.BBBChecksum(algo, secret, method, queries)
 	if subtle.ConstantTimeCompare([]byte(checksum), []byte(ourSum)) != 1 {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "checksumError", "Checksums do not match"))
 	}
 
+	if method == "join" {
+		values, _ := url.ParseQuery(queries)
+		if err = bc.BBBApiWrapperModel.VerifyJoinRequest(c.UserContext(), values.Get("timestamp"), checksum); err != nil {
+			return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "checksumError", err.Error()))
+		}
+	}
+
+	// empty for the default API key from config
+	c.Locals("tenantId", tenantId)
+	if integration != nil {
+		c.Locals("bbbIntegration", integration)
+	}
 	return c.Next()
 }
 
 // HandleBBBCreate handles BBB create meeting requests.
 func (bc *BBBController) HandleBBBCreate(c *fiber.Ctx) error {
+	integration, _ := c.Locals("bbbIntegration").(*dbmodels.BBBIntegration)
+	isForm := c.Method() == "POST" && c.Get("Content-Type") == "application/x-www-form-urlencoded"
+	// the default parameters of the integration will be used if not sent with the request
+	if params := models.BBBIntegrationDefaultParams(integration); len(params) > 0 {
+		args := c.Request().URI().QueryArgs()
+		if isForm {
+			args = c.Request().PostArgs()
+		}
+		for k, v := range params {
+			if !args.Has(k) {
+				args.Add(k, v)
+			}
+		}
+	}
+
 	q := new(bbbapiwrapper.CreateMeetingReq)
 	var err error
-	if c.Method() == "POST" && c.Get("Content-Type") == "application/x-www-form-urlencoded" {
+	if isForm {
 		err = c.BodyParser(q)
 	} else {
 		err = c.QueryParser(q)
@@ -151,12 +187,15 @@ func (bc *BBBController) HandleBBBCreate(c *fiber.Ctx) error {
 	if err != nil {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "error", err.Error()))
 	}
+	if integration != nil && integration.WebhookUrl != "" && pnmReq.Metadata != nil && pnmReq.Metadata.WebhookUrl == nil {
+		pnmReq.Metadata.WebhookUrl = &integration.WebhookUrl
+	}
 
 	if err = validateRequest(pnmReq); err != nil {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "validationError", err.Error()))
 	}
 
-	room, err := bc.RoomModel.CreateRoom(pnmReq)
+	room, err := bc.RoomModel.CreateRoom(getTenantId(c), pnmReq)
 	if err != nil {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "error", err.Error()))
 	}
@@ -191,6 +230,9 @@ func (bc *BBBController) HandleBBBJoin(c *fiber.Ctx) error {
 	}
 
 	roomId := bbbapiwrapper.CheckMeetingIdToMatchFormat(q.MeetingID)
+	if !bc.TenantModel.CanAccessRoom(getTenantId(c), roomId) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "error", "meeting is not active"))
+	}
 	metadata, err := bc.NatsService.GetRoomMetadataStruct(roomId)
 	if err != nil {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "error", err.Error()))
@@ -292,6 +334,13 @@ func (bc *BBBController) HandleBBBIsMeetingRunning(c *fiber.Ctx) error {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
 	}
 
+	if !bc.TenantModel.CanAccessRoom(getTenantId(c), q.MeetingID) {
+		return c.XML(bbbapiwrapper.IsMeetingRunningRes{
+			ReturnCode: "SUCCESS",
+			Running:    false,
+		})
+	}
+
 	res, _, _, _ := bc.RoomModel.IsRoomActive(c.UserContext(), &plugnmeet.IsRoomActiveReq{
 		RoomId: q.MeetingID,
 	})
@@ -315,8 +364,13 @@ func (bc *BBBController) HandleBBBGetMeetingInfo(c *fiber.Ctx) error {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
 	}
 
+	roomId := bbbapiwrapper.CheckMeetingIdToMatchFormat(q.MeetingID)
+	if !bc.TenantModel.CanAccessRoom(getTenantId(c), roomId) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", "room is not active"))
+	}
+
 	status, msg, res := bc.RoomModel.GetActiveRoomInfo(c.UserContext(), &plugnmeet.GetActiveRoomInfoReq{
-		RoomId: bbbapiwrapper.CheckMeetingIdToMatchFormat(q.MeetingID),
+		RoomId: roomId,
 	})
 
 	if !status {
@@ -338,7 +392,7 @@ func (bc *BBBController) HandleBBBGetMeetingInfo(c *fiber.Ctx) error {
 
 // HandleBBBGetMeetings handles BBB getMeetings requests.
 func (bc *BBBController) HandleBBBGetMeetings(c *fiber.Ctx) error {
-	_, _, rooms := bc.RoomModel.GetActiveRoomsInfo()
+	_, _, rooms := bc.RoomModel.GetActiveRoomsInfo(getTenantId(c))
 
 	if rooms == nil {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("SUCCESS", "noMeetings", "no meetings were found on this server"))
@@ -370,8 +424,13 @@ func (bc *BBBController) HandleBBBEndMeetings(c *fiber.Ctx) error {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
 	}
 
+	roomId := bbbapiwrapper.CheckMeetingIdToMatchFormat(q.MeetingID)
+	if !bc.TenantModel.CanAccessRoom(getTenantId(c), roomId) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", "room is not active"))
+	}
+
 	status, msg := bc.RoomModel.EndRoom(c.UserContext(), &plugnmeet.RoomEndReq{
-		RoomId: bbbapiwrapper.CheckMeetingIdToMatchFormat(q.MeetingID),
+		RoomId: roomId,
 	})
 
 	if !status {
@@ -394,7 +453,7 @@ func (bc *BBBController) HandleBBBGetRecordings(c *fiber.Ctx) error {
 	}
 
 	host := fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname())
-	recordings, pagination, err := bc.BBBApiWrapperModel.GetRecordings(host, q)
+	recordings, pagination, err := bc.BBBApiWrapperModel.GetRecordings(getTenantId(c), host, q, getBBBRecordingsFilter(c))
 	if err != nil {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "error", err.Error()))
 	}
@@ -402,7 +461,7 @@ func (bc *BBBController) HandleBBBGetRecordings(c *fiber.Ctx) error {
 	if len(recordings) == 0 {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("SUCCESS", "noRecordings", "There are no recordings for the meeting(s)."))
 	}
-	res := bbbapiwrapper.GetRecordingsRes{
+	res := models.BBBGetRecordingsRes{
 		ReturnCode: "SUCCESS",
 		Pagination: pagination,
 	}
@@ -423,6 +482,10 @@ func (bc *BBBController) HandleBBBDeleteRecordings(c *fiber.Ctx) error {
 		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
 	}
 
+	if !bc.TenantModel.CanAccessRecording(getTenantId(c), q.RecordID) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", "We could not find recordings"))
+	}
+
 	err = bc.RecordingModel.DeleteRecording(&plugnmeet.DeleteRecordingReq{
 		RecordId: q.RecordID,
 	})
@@ -437,18 +500,111 @@ func (bc *BBBController) HandleBBBDeleteRecordings(c *fiber.Ctx) error {
 	})
 }
 
-// HandleBBBPublishRecordings TO-DO: in the future
+// HandleBBBPublishRecordings handles BBB publishRecordings requests.
 func (bc *BBBController) HandleBBBPublishRecordings(c *fiber.Ctx) error {
+	q := new(bbbapiwrapper.PublishRecordingsReq)
+	var err error
+	if c.Method() == "POST" && c.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		err = c.BodyParser(q)
+	} else {
+		err = c.QueryParser(q)
+	}
+	if err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
+	}
+	if q.RecordID == "" {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "missingParamRecordID", "You must specify one or more a recording IDs."))
+	}
+
+	recordIds := strings.Split(q.RecordID, ",")
+	for _, id := range recordIds {
+		if !bc.TenantModel.CanAccessRecording(getTenantId(c), id) {
+			return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", "We could not find recordings"))
+		}
+	}
+
+	if err = bc.RecordingModel.PublishRecordings(recordIds, q.Publish); err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", err.Error()))
+	}
+
 	return c.XML(bbbapiwrapper.PublishRecordingsRes{
 		ReturnCode: "SUCCESS",
-		Published:  true,
+		Published:  q.Publish,
 	})
 }
 
-// HandleBBBUpdateRecordings TO-DO: in the future
+// HandleBBBUpdateRecordings handles BBB updateRecordings requests.
+// The meta_ parameters will be stored as the metadata of the recordings,
+// meta_name will be used as title & meta_description as description too.
 func (bc *BBBController) HandleBBBUpdateRecordings(c *fiber.Ctx) error {
+	q := new(bbbapiwrapper.UpdateRecordingsReq)
+	var err error
+	params := c.Queries()
+	if c.Method() == "POST" && c.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		err = c.BodyParser(q)
+		params = make(map[string]string)
+		c.Request().PostArgs().VisitAll(func(k, v []byte) {
+			params[string(k)] = string(v)
+		})
+	} else {
+		err = c.QueryParser(q)
+	}
+	if err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
+	}
+	if q.RecordID == "" {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "missingParamRecordID", "You must specify one or more a recording IDs."))
+	}
+
+	meta := make(map[string]string)
+	for k, v := range params {
+		if strings.HasPrefix(k, "meta_") {
+			meta[strings.TrimPrefix(k, "meta_")] = v
+		}
+	}
+
+	for _, id := range strings.Split(q.RecordID, ",") {
+		if !bc.TenantModel.CanAccessRecording(getTenantId(c), id) {
+			return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", "We could not find recordings"))
+		}
+
+		req := &models.UpdateRecordingReq{
+			RecordId: id,
+			Metadata: meta,
+		}
+		if name, ok := meta["name"]; ok {
+			req.Title = &name
+		}
+		if description, ok := meta["description"]; ok {
+			req.Description = &description
+		}
+		if err = bc.RecordingModel.UpdateRecording(req); err != nil {
+			return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", err.Error()))
+		}
+	}
+
 	return c.XML(bbbapiwrapper.UpdateRecordingsRes{
 		ReturnCode: "SUCCESS",
 		Updated:    true,
 	})
 }
+
+// getBBBRecordingsFilter converts the state parameter of getRecordings.
+// By default, BBB returns both published & unpublished recordings.
+func getBBBRecordingsFilter(c *fiber.Ctx) *models.FetchRecordingsFilter {
+	state := c.Query("state")
+	if c.Method() == "POST" && c.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		state = c.FormValue("state")
+	}
+	if state == "" || strings.Contains(state, "any") {
+		return nil
+	}
+
+	states := strings.Split(state, ",")
+	hasPublished := slices.Contains(states, "published")
+	hasUnpublished := slices.Contains(states, "unpublished")
+	if hasPublished == hasUnpublished {
+		return nil
+	}
+	return &models.FetchRecordingsFilter{Published: &hasPublished}
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/bbbapiwrapper"
+)
+
+// HandleBBBInsertDocument handles BBB insertDocument requests.
+// The documents will be converted in the background & added to the whiteboard of the room.
+func (bc *BBBController) HandleBBBInsertDocument(c *fiber.Ctx) error {
+	q := new(bbbapiwrapper.MeetingReq)
+	if err := c.QueryParser(q); err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
+	}
+	if q.MeetingID == "" {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "missingParamMeetingID", "You must specify a meeting ID for the meeting."))
+	}
+	if c.Method() != "POST" || len(c.Body()) == 0 {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "missingParamDocuments", "You must send the documents as XML in the request body."))
+	}
+
+	roomId := bbbapiwrapper.CheckMeetingIdToMatchFormat(q.MeetingID)
+	if !bc.TenantModel.CanAccessRoom(getTenantId(c), roomId) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", "room is not active"))
+	}
+
+	if err := bc.BBBApiWrapperModel.InsertDocument(roomId, c.Body()); err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "error", err.Error()))
+	}
+
+	return c.XML(bbbapiwrapper.CommonResponseMsg("SUCCESS", "documentsQueued", "The documents are being uploaded"))
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/bbbapiwrapper"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// HandleBBBCreateHook handles BBB hooks/create requests.
+// The hooks are saved as webhook subscriptions with the bbb format,
+// so the events will be sent in the same format as the BBB webhooks.
+func (bc *BBBController) HandleBBBCreateHook(c *fiber.Ctx) error {
+	q := new(models.BBBCreateHookReq)
+	if err := parseBBBRequest(c, q); err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
+	}
+	if q.CallbackURL == "" {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "missingParamCallbackURL", "You must specify a callbackURL in the parameters."))
+	}
+
+	tenantId := getTenantId(c)
+	if q.MeetingID != "" && !bc.TenantModel.OwnsRoom(tenantId, bbbapiwrapper.CheckMeetingIdToMatchFormat(q.MeetingID)) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "notFound", "We could not find the meeting"))
+	}
+
+	id, duplicate, err := bc.BBBApiWrapperModel.CreateHook(tenantId, q)
+	if err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "createHookError", err.Error()))
+	}
+
+	res := models.BBBCreateHookRes{
+		ReturnCode: "SUCCESS",
+		HookID:     id,
+	}
+	if duplicate {
+		res.MessageKey = "duplicateWarning"
+		res.Message = "There is already a hook for this callback URL."
+	}
+	return c.XML(res)
+}
+
+// HandleBBBListHooks handles BBB hooks/list requests
+func (bc *BBBController) HandleBBBListHooks(c *fiber.Ctx) error {
+	q := new(models.BBBListHooksReq)
+	if err := parseBBBRequest(c, q); err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
+	}
+
+	hooks, err := bc.BBBApiWrapperModel.ListHooks(getTenantId(c), q)
+	if err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "listHooksError", err.Error()))
+	}
+
+	res := models.BBBListHooksRes{
+		ReturnCode: "SUCCESS",
+	}
+	res.Hooks.Hook = hooks
+	return c.XML(res)
+}
+
+// HandleBBBDestroyHook handles BBB hooks/destroy requests
+func (bc *BBBController) HandleBBBDestroyHook(c *fiber.Ctx) error {
+	q := new(models.BBBDestroyHookReq)
+	if err := parseBBBRequest(c, q); err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "parsingError", "We can not parse request"))
+	}
+	if q.HookID == 0 {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "missingParamHookID", "You must specify a hookID in the parameters."))
+	}
+
+	err := bc.BBBApiWrapperModel.DestroyHook(getTenantId(c), q)
+	if errors.Is(err, models.ErrBBBHookNotFound) {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "destroyMissingHook", "The hook informed was not found."))
+	}
+	if err != nil {
+		return c.XML(bbbapiwrapper.CommonResponseMsg("FAILED", "destroyHookError", err.Error()))
+	}
+
+	return c.XML(models.BBBDestroyHookRes{
+		ReturnCode: "SUCCESS",
+		Removed:    true,
+	})
+}
+
+// parseBBBRequest parses the parameters from the body for the form posts,
+// otherwise from the query
+func parseBBBRequest(c *fiber.Ctx, out interface{}) error {
+	if c.Method() == "POST" && c.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		return c.BodyParser(out)
+	}
+	return c.QueryParser(out)
+}
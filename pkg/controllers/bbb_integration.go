@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// HandleCreateBBBIntegration handles creating a new BBB integration.
+// The secret will be only returned in this response.
+func (bc *BBBController) HandleCreateBBBIntegration(c *fiber.Ctx) error {
+	req := new(models.CreateBBBIntegrationReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	info, err := bc.BBBApiWrapperModel.CreateBBBIntegration(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":      true,
+		"msg":         "success",
+		"integration": info,
+	})
+}
+
+// HandleUpdateBBBIntegration handles updating an existing BBB integration.
+func (bc *BBBController) HandleUpdateBBBIntegration(c *fiber.Ctx) error {
+	req := new(models.UpdateBBBIntegrationReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ApiKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "api_key required")
+	}
+
+	info, err := bc.BBBApiWrapperModel.UpdateBBBIntegration(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":      true,
+		"msg":         "success",
+		"integration": info,
+	})
+}
+
+// HandleFetchBBBIntegrations handles listing BBB integrations.
+func (bc *BBBController) HandleFetchBBBIntegrations(c *fiber.Ctx) error {
+	req := new(models.FetchBBBIntegrationsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := bc.BBBApiWrapperModel.FetchBBBIntegrations(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalIntegrations == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no integrations found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleGetBBBIntegration handles fetching a single BBB integration.
+func (bc *BBBController) HandleGetBBBIntegration(c *fiber.Ctx) error {
+	req := new(models.BBBIntegrationReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ApiKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "api_key required")
+	}
+
+	info, err := bc.BBBApiWrapperModel.GetBBBIntegrationInfo(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":      true,
+		"msg":         "success",
+		"integration": info,
+	})
+}
+
+// HandleRegenerateBBBIntegrationSecret handles replacing the secret of a BBB integration.
+func (bc *BBBController) HandleRegenerateBBBIntegrationSecret(c *fiber.Ctx) error {
+	req := new(models.BBBIntegrationReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ApiKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "api_key required")
+	}
+
+	info, err := bc.BBBApiWrapperModel.RegenerateBBBIntegrationSecret(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":      true,
+		"msg":         "success",
+		"integration": info,
+	})
+}
+
+// HandleDeleteBBBIntegration handles deleting a BBB integration.
+func (bc *BBBController) HandleDeleteBBBIntegration(c *fiber.Ctx) error {
+	req := new(models.BBBIntegrationReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ApiKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "api_key required")
+	}
+
+	if err := bc.BBBApiWrapperModel.DeleteBBBIntegration(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
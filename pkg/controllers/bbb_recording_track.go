@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// HandleBBBGetRecordingTextTracks handles BBB getRecordingTextTracks requests, the response is JSON
+func (bc *BBBController) HandleBBBGetRecordingTextTracks(c *fiber.Ctx) error {
+	q := new(models.BBBGetRecordingTextTracksReq)
+	if err := parseBBBRequest(c, q); err != nil {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "parsingError", "We can not parse request"))
+	}
+	if q.RecordID == "" {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "missingParamRecordID", "You must specify a recordID."))
+	}
+	if !bc.TenantModel.CanAccessRecording(getTenantId(c), q.RecordID) {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "noRecordings", "No recording found for "+q.RecordID))
+	}
+
+	host := fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname())
+	tracks, err := bc.BBBApiWrapperModel.GetRecordingTextTracks(host, q.RecordID)
+	if errors.Is(err, models.ErrRecordingNotFound) {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "noRecordings", "No recording found for "+q.RecordID))
+	}
+	if err != nil {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "error", err.Error()))
+	}
+
+	res := models.BBBTextTrackRes("SUCCESS", "", "")
+	res.Response.Tracks = tracks
+	return c.JSON(res)
+}
+
+// HandleBBBPutRecordingTextTrack handles BBB putRecordingTextTrack requests.
+// The parameters are in the query & the track is uploaded as multipart file, the response is JSON.
+func (bc *BBBController) HandleBBBPutRecordingTextTrack(c *fiber.Ctx) error {
+	q := new(models.BBBPutRecordingTextTrackReq)
+	if err := c.QueryParser(q); err != nil {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "parsingError", "We can not parse request"))
+	}
+	if q.RecordID == "" {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "paramError", "Missing param recordID."))
+	}
+	if q.Kind == "" || q.Lang == "" {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "paramError", "Missing param kind or lang."))
+	}
+	if !bc.TenantModel.CanAccessRecording(getTenantId(c), q.RecordID) {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "noRecordings", "No recording found for "+q.RecordID))
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil || fh.Size == 0 {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "empty_uploaded_text_track", "Empty uploaded text track."))
+	}
+	if fh.Size > models.RecordingTextTrackMaxSize {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "upload_text_track_failed", "text track is too large"))
+	}
+	file, err := fh.Open()
+	if err != nil {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "error", err.Error()))
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "error", err.Error()))
+	}
+
+	err = bc.BBBApiWrapperModel.PutRecordingTextTrack(q, data)
+	if errors.Is(err, models.ErrRecordingNotFound) {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "noRecordings", "No recording found for "+q.RecordID))
+	}
+	if err != nil {
+		return c.JSON(models.BBBTextTrackRes("FAILED", "upload_text_track_failed", err.Error()))
+	}
+
+	res := models.BBBTextTrackRes("SUCCESS", "upload_text_track_success", "Text track uploaded successfully")
+	res.Response.RecordId = q.RecordID
+	return c.JSON(res)
+}
@@ -217,6 +217,112 @@ func (brc *BreakoutRoomController) HandleEndBreakoutRooms(c *fiber.Ctx) error {
 	return sendBreakoutRoomResponse(c, res)
 }
 
+// HandleAutoAssignBreakoutRooms handles creating breakout rooms where the server assigns the users.
+// Body is the CreateBreakoutRoomsReq, where users of the rooms will be ignored.
+// Assignment options are sent as query parameters: strategy, include_admins & max_users_per_room
+func (brc *BreakoutRoomController) HandleAutoAssignBreakoutRooms(c *fiber.Ctx) error {
+	isAdmin := c.Locals("isAdmin")
+	roomId := c.Locals("roomId")
+	requestedUserId := c.Locals("requestedUserId")
+	res := new(plugnmeet.BreakoutRoomRes)
+	res.Status = false
+
+	if isAdmin != true {
+		res.Msg = "only admin can perform this task"
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	req := new(plugnmeet.CreateBreakoutRoomsReq)
+	err := proto.Unmarshal(c.Body(), req)
+	if err != nil {
+		res.Msg = err.Error()
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	req.RoomId = roomId.(string)
+	req.RequestedUserId = requestedUserId.(string)
+	opts := &models.AutoAssignBreakoutRoomsOptions{
+		Strategy:        c.Query("strategy"),
+		IncludeAdmins:   c.QueryBool("include_admins"),
+		MaxUsersPerRoom: c.QueryInt("max_users_per_room"),
+	}
+
+	err = brc.BreakoutRoomModel.AutoAssignBreakoutRooms(req, opts)
+	if err != nil {
+		res.Msg = err.Error()
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	res.Status = true
+	res.Msg = "success"
+	return sendBreakoutRoomResponse(c, res)
+}
+
+// HandleChooseBreakoutRoom handles a participant choosing a breakout room by themselves.
+func (brc *BreakoutRoomController) HandleChooseBreakoutRoom(c *fiber.Ctx) error {
+	roomId := c.Locals("roomId")
+	requestedUserId := c.Locals("requestedUserId")
+	res := new(plugnmeet.BreakoutRoomRes)
+	res.Status = false
+
+	req := new(plugnmeet.JoinBreakoutRoomReq)
+	err := proto.Unmarshal(c.Body(), req)
+	if err != nil {
+		res.Msg = err.Error()
+		return sendBreakoutRoomResponse(c, res)
+	}
+	if req.BreakoutRoomId == "" {
+		res.Msg = "breakout_room_id required"
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	// participant can only choose for themselves
+	req.RoomId = roomId.(string)
+	req.UserId = requestedUserId.(string)
+	token, err := brc.BreakoutRoomModel.ChooseBreakoutRoom(c.UserContext(), req)
+	if err != nil {
+		res.Msg = err.Error()
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	res.Status = true
+	res.Msg = "success"
+	res.Token = &token
+	return sendBreakoutRoomResponse(c, res)
+}
+
+// HandleMoveBreakoutRoomUser handles moving a user to another breakout room.
+// user_id of the JoinBreakoutRoomReq is the user to move & breakout_room_id is the destination room.
+func (brc *BreakoutRoomController) HandleMoveBreakoutRoomUser(c *fiber.Ctx) error {
+	isAdmin := c.Locals("isAdmin")
+	roomId := c.Locals("roomId")
+	res := new(plugnmeet.BreakoutRoomRes)
+	res.Status = false
+
+	if isAdmin != true {
+		res.Msg = "only admin can perform this task"
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	req := new(plugnmeet.JoinBreakoutRoomReq)
+	err := proto.Unmarshal(c.Body(), req)
+	if err != nil {
+		res.Msg = err.Error()
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	req.RoomId = roomId.(string)
+	err = brc.BreakoutRoomModel.MoveBreakoutRoomUser(req)
+	if err != nil {
+		res.Msg = err.Error()
+		return sendBreakoutRoomResponse(c, res)
+	}
+
+	res.Status = true
+	res.Msg = "success"
+	return sendBreakoutRoomResponse(c, res)
+}
+
 func sendBreakoutRoomResponse(c *fiber.Ctx, res *plugnmeet.BreakoutRoomRes) error {
 	marshal, err := proto.Marshal(res)
 	if err != nil {
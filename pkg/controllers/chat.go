@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// ChatController holds dependencies for archived chat related handlers.
+type ChatController struct {
+	ChatArchiveModel *models.ChatArchiveModel
+}
+
+// NewChatController creates a new ChatController.
+func NewChatController(m *models.ChatArchiveModel) *ChatController {
+	return &ChatController{
+		ChatArchiveModel: m,
+	}
+}
+
+// HandleFetchChatMessages handles fetching archived chat messages of a session.
+func (cc *ChatController) HandleFetchChatMessages(c *fiber.Ctx) error {
+	req := new(models.FetchChatMessagesReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := cc.ChatArchiveModel.FetchChatMessages(getTenantId(c), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalMessages == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no chat messages found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleGetChatDownloadToken generates a download token for the chat transcript of a session.
+func (cc *ChatController) HandleGetChatDownloadToken(c *fiber.Ctx) error {
+	req := new(models.ChatDownloadTokenReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	token, err := cc.ChatArchiveModel.GetChatDownloadToken(getTenantId(c), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"token":  token,
+	})
+}
+
+// HandleDownloadChat handles the download of a chat transcript.
+func (cc *ChatController) HandleDownloadChat(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if len(token) == 0 {
+		return c.Status(fiber.StatusUnauthorized).SendString("token require or invalid url")
+	}
+
+	fileName, data, status, err := cc.ChatArchiveModel.ExportChatTranscript(token)
+	if err != nil {
+		return c.Status(status).SendString(err.Error())
+	}
+
+	c.Attachment(fileName)
+	c.Set("Content-Length", strconv.Itoa(len(data)))
+	return c.Send(data)
+}
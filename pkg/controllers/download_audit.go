@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// DownloadAuditController holds dependencies for download audit handlers.
+type DownloadAuditController struct {
+	DownloadAuditModel *models.DownloadAuditModel
+}
+
+// NewDownloadAuditController creates a new DownloadAuditController.
+func NewDownloadAuditController(m *models.DownloadAuditModel) *DownloadAuditController {
+	return &DownloadAuditController{
+		DownloadAuditModel: m,
+	}
+}
+
+// HandleFetchDownloadAudits handles fetching the logs of the downloads.
+func (dc *DownloadAuditController) HandleFetchDownloadAudits(c *fiber.Ctx) error {
+	req := new(models.FetchDownloadAuditsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := dc.DownloadAuditModel.FetchDownloadAudits(getTenantId(c), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
@@ -3,15 +3,13 @@ package controllers
 import (
 	"fmt"
 	"net/url"
-	"strconv"
-	"strings"
 
-	"github.com/gabriel-vasile/mimetype"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-protocol/utils"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
@@ -19,17 +17,21 @@ import (
 
 // FileController holds dependencies for file-related handlers.
 type FileController struct {
-	AppConfig *config.AppConfig
-	FileModel *models.FileModel
-	logger    *logrus.Entry
+	AppConfig          *config.AppConfig
+	FileModel          *models.FileModel
+	DownloadAuditModel *models.DownloadAuditModel
+	StorageService     *storageservice.StorageService
+	logger             *logrus.Entry
 }
 
 // NewFileController creates a new FileController.
-func NewFileController(config *config.AppConfig, fm *models.FileModel, logger *logrus.Logger) *FileController {
+func NewFileController(config *config.AppConfig, fm *models.FileModel, dam *models.DownloadAuditModel, ss *storageservice.StorageService, logger *logrus.Logger) *FileController {
 	return &FileController{
-		AppConfig: config,
-		FileModel: fm,
-		logger:    logger.WithField("controller", "file"),
+		AppConfig:          config,
+		FileModel:          fm,
+		DownloadAuditModel: dam,
+		StorageService:     ss,
+		logger:             logger.WithField("controller", "file"),
 	}
 }
 
@@ -131,18 +133,9 @@ func (fc *FileController) HandleDownloadUploadedFile(c *fiber.Ctx) error {
 	otherParts := c.Params("*")
 	otherParts, _ = url.QueryUnescape(otherParts)
 
-	file := fmt.Sprintf("%s/%s/%s", fc.AppConfig.UploadFileSettings.Path, sid, otherParts)
-	mtype, err := mimetype.DetectFile(file)
-	if err != nil {
-		ms := strings.SplitN(err.Error(), "/", -1)
-		return c.Status(fiber.StatusNotFound).SendString(ms[len(ms)-1])
-	}
-
-	ff := strings.SplitN(file, "/", -1)
-	c.Set("Content-Disposition", "attachment; filename="+strconv.Quote(ff[len(ff)-1]))
-	c.Set("Content-Type", mtype.String())
-
-	return c.SendFile(file)
+	key := fmt.Sprintf("%s/%s", sid, otherParts)
+	audit := newDownloadAudit(c, fc.DownloadAuditModel, models.DownloadFileTypeUploadedFile, key, nil)
+	return sendStorageFile(c, fc.StorageService, fc.StorageService.Uploads(), key, true, audit)
 }
 
 // HandleConvertWhiteboardFile handles converting a file for the whiteboard.
@@ -171,7 +164,28 @@ func (fc *FileController) HandleConvertWhiteboardFile(c *fiber.Ctx) error {
 		})
 	}
 
-	res, err := fc.FileModel.ConvertAndBroadcastWhiteboardFile(req.RoomId, req.RoomSid, req.FilePath)
+	if requestedUserId, ok := c.Locals("requestedUserId").(string); ok && requestedUserId != "" {
+		req.UserId = requestedUserId
+	}
+
+	if req.Async {
+		// the progress & result will be broadcast to the room
+		jobId, err := fc.FileModel.QueueWhiteboardFileConversion(req.RoomId, req.RoomSid, req.UserId, req.FilePath, false)
+		if err != nil {
+			return c.JSON(fiber.Map{
+				"status": false,
+				"msg":    err.Error(),
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"status": true,
+			"msg":    "success",
+			"jobId":  jobId,
+		})
+	}
+
+	res, err := fc.FileModel.ConvertWhiteboardFile(c.UserContext(), req.RoomId, req.RoomSid, req.UserId, req.FilePath)
 	if err != nil {
 		return c.JSON(fiber.Map{
 			"status": false,
@@ -235,3 +249,62 @@ func commonFileErrorResponse(c *fiber.Ctx, msg string, status int) error {
 		"msg":    msg,
 	})
 }
+
+// HandleGetWhiteboardFileInfo returns the page variants & optionally the text of a converted whiteboard file.
+func (fc *FileController) HandleGetWhiteboardFileInfo(c *fiber.Ctx) error {
+	roomId, _ := c.Locals("roomId").(string)
+	req := new(models.GetWhiteboardFileInfoReq)
+	if err := c.BodyParser(req); err != nil {
+		return c.JSON(fiber.Map{
+			"status": false,
+			"msg":    err.Error(),
+		})
+	}
+	if req.FileId == "" {
+		return c.JSON(fiber.Map{
+			"status": false,
+			"msg":    "file_id required",
+		})
+	}
+
+	info, text, err := fc.FileModel.GetWhiteboardFileInfo(roomId, req)
+	if err != nil {
+		return c.JSON(fiber.Map{
+			"status": false,
+			"msg":    err.Error(),
+		})
+	}
+
+	return c.JSON(&models.GetWhiteboardFileInfoRes{
+		Status: true,
+		Msg:    "success",
+		Info:   info,
+		Text:   text,
+	})
+}
+
+// HandleSearchWhiteboardFiles searches the text of the converted whiteboard files of the room.
+func (fc *FileController) HandleSearchWhiteboardFiles(c *fiber.Ctx) error {
+	roomId, _ := c.Locals("roomId").(string)
+	req := new(models.SearchWhiteboardFilesReq)
+	if err := c.BodyParser(req); err != nil {
+		return c.JSON(fiber.Map{
+			"status": false,
+			"msg":    err.Error(),
+		})
+	}
+
+	results, err := fc.FileModel.SearchWhiteboardFiles(roomId, req.Query)
+	if err != nil {
+		return c.JSON(fiber.Map{
+			"status": false,
+			"msg":    err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  true,
+		"msg":     "success",
+		"results": results,
+	})
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// HandleCreateLtiConsumer handles creating a new LTI 1.1 consumer.
+// The secret will be only returned in this response.
+func (lc *LtiV1Controller) HandleCreateLtiConsumer(c *fiber.Ctx) error {
+	req := new(models.CreateLtiConsumerReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	info, err := lc.LtiV1Model.CreateLtiConsumer(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"consumer": info,
+	})
+}
+
+// HandleUpdateLtiConsumer handles updating an existing LTI 1.1 consumer.
+func (lc *LtiV1Controller) HandleUpdateLtiConsumer(c *fiber.Ctx) error {
+	req := new(models.UpdateLtiConsumerReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ConsumerKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "consumer_key required")
+	}
+
+	info, err := lc.LtiV1Model.UpdateLtiConsumer(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"consumer": info,
+	})
+}
+
+// HandleFetchLtiConsumers handles listing LTI 1.1 consumers.
+func (lc *LtiV1Controller) HandleFetchLtiConsumers(c *fiber.Ctx) error {
+	req := new(models.FetchLtiConsumersReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := lc.LtiV1Model.FetchLtiConsumers(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalConsumers == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no consumers found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleGetLtiConsumer handles fetching a single LTI 1.1 consumer.
+func (lc *LtiV1Controller) HandleGetLtiConsumer(c *fiber.Ctx) error {
+	req := new(models.LtiConsumerReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ConsumerKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "consumer_key required")
+	}
+
+	info, err := lc.LtiV1Model.GetLtiConsumerInfo(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"consumer": info,
+	})
+}
+
+// HandleRegenerateLtiConsumerSecret handles replacing the secret of an LTI 1.1 consumer.
+func (lc *LtiV1Controller) HandleRegenerateLtiConsumerSecret(c *fiber.Ctx) error {
+	req := new(models.LtiConsumerReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ConsumerKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "consumer_key required")
+	}
+
+	info, err := lc.LtiV1Model.RegenerateLtiConsumerSecret(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"consumer": info,
+	})
+}
+
+// HandleDeleteLtiConsumer handles deleting an LTI 1.1 consumer.
+func (lc *LtiV1Controller) HandleDeleteLtiConsumer(c *fiber.Ctx) error {
+	req := new(models.LtiConsumerReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ConsumerKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "consumer_key required")
+	}
+
+	if err := lc.LtiV1Model.DeleteLtiConsumer(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
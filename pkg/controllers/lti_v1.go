@@ -36,11 +36,7 @@ func (lc *LtiV1Controller) HandleLTIV1Landing(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).SendString("empty body")
 	}
 
-	proto := "https"
-	if strings.Contains(c.Hostname(), "localhost") {
-		proto = "http"
-	}
-	signingURL := fmt.Sprintf("%s://%s%s", proto, c.Hostname(), c.Path())
+	signingURL := ltiRequestUrl(c, c.Path())
 
 	err := lc.LtiV1Model.LTIV1Landing(c, string(b), signingURL)
 	if err != nil {
@@ -50,6 +46,16 @@ func (lc *LtiV1Controller) HandleLTIV1Landing(c *fiber.Ctx) error {
 	return nil
 }
 
+// ltiRequestUrl returns the absolute url of the path on this server,
+// which must be the same as the one registered in the LMS
+func ltiRequestUrl(c *fiber.Ctx, path string) string {
+	proto := "https"
+	if strings.Contains(c.Hostname(), "localhost") {
+		proto = "http"
+	}
+	return fmt.Sprintf("%s://%s%s", proto, c.Hostname(), path)
+}
+
 // HandleLTIV1GETREQUEST handles GET requests to LTI endpoints, which are not allowed.
 func (lc *LtiV1Controller) HandleLTIV1GETREQUEST(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -175,7 +181,13 @@ func (lc *LtiV1Controller) HandleLTIV1FetchRecordings(c *fiber.Ctx) error {
 	}
 
 	req.RoomIds = []string{roomId.(string)}
-	result, err := lc.RecordingModel.FetchRecordings(req)
+	var filter *models.FetchRecordingsFilter
+	if !c.Locals("isAdmin").(bool) {
+		// only admin can see the unpublished recordings
+		published := true
+		filter = &models.FetchRecordingsFilter{Published: &published}
+	}
+	result, metadata, err := lc.RecordingModel.FetchRecordings("", req, filter)
 
 	if err != nil {
 		return c.JSON(fiber.Map{
@@ -185,9 +197,10 @@ func (lc *LtiV1Controller) HandleLTIV1FetchRecordings(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"status": true,
-		"msg":    "success",
-		"result": result,
+		"status":              true,
+		"msg":                 "success",
+		"result":              result,
+		"recordings_metadata": metadata,
 	})
 }
 
@@ -202,7 +215,12 @@ func (lc *LtiV1Controller) HandleLTIV1GetRecordingDownloadToken(c *fiber.Ctx) er
 		})
 	}
 
-	token, err := lc.RecordingModel.GetDownloadToken(req)
+	// the token will be used by the same browser, so it can be bound with the IP
+	opts := &models.DownloadTokenOptions{
+		BindIp:      c.IP(),
+		RequestedBy: c.Locals("userId").(string),
+	}
+	token, err := lc.RecordingModel.GetDownloadToken(req, opts)
 	if err != nil {
 		return c.JSON(fiber.Map{
 			"status": false,
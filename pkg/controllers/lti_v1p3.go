@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// HandleLTIV1p3Login handles the third party initiated login of LTI 1.3.
+// The user will be redirected to the platform for authentication.
+func (lc *LtiV1Controller) HandleLTIV1p3Login(c *fiber.Ctx) error {
+	req := new(models.LTIV1p3LoginReq)
+	var err error
+	if c.Method() == fiber.MethodGet {
+		err = c.QueryParser(req)
+	} else {
+		err = c.BodyParser(req)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	redirectUrl, err := lc.LtiV1Model.LTIV1p3Login(c.UserContext(), req, ltiRequestUrl(c, "/lti/v1p3/launch"))
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).SendString(err.Error())
+	}
+
+	return c.Redirect(redirectUrl, fiber.StatusFound)
+}
+
+// HandleLTIV1p3Launch handles the resource link launch of LTI 1.3 posted by the platform.
+func (lc *LtiV1Controller) HandleLTIV1p3Launch(c *fiber.Ctx) error {
+	req := new(models.LTIV1p3LaunchReq)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	if err := lc.LtiV1Model.LTIV1p3Launch(c, req); err != nil {
+		return c.Status(fiber.StatusUnauthorized).SendString(err.Error())
+	}
+
+	return nil
+}
+
+// HandleLTIV1p3Jwks publishes the public key of this tool,
+// which the platforms use to verify the requests for LTI Advantage services.
+func (lc *LtiV1Controller) HandleLTIV1p3Jwks(c *fiber.Ctx) error {
+	return c.JSON(lc.LtiV1Model.ToolJWKS())
+}
+
+// HandleCreateLtiPlatform handles registering a new LTI 1.3 platform.
+func (lc *LtiV1Controller) HandleCreateLtiPlatform(c *fiber.Ctx) error {
+	req := new(models.CreateLtiPlatformReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	info, err := lc.LtiV1Model.CreateLtiPlatform(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"platform": info,
+	})
+}
+
+// HandleUpdateLtiPlatform handles updating an existing LTI 1.3 platform.
+func (lc *LtiV1Controller) HandleUpdateLtiPlatform(c *fiber.Ctx) error {
+	req := new(models.UpdateLtiPlatformReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.PlatformId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "platform_id required")
+	}
+
+	info, err := lc.LtiV1Model.UpdateLtiPlatform(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"platform": info,
+	})
+}
+
+// HandleFetchLtiPlatforms handles listing LTI 1.3 platforms.
+func (lc *LtiV1Controller) HandleFetchLtiPlatforms(c *fiber.Ctx) error {
+	req := new(models.FetchLtiPlatformsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := lc.LtiV1Model.FetchLtiPlatforms(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalPlatforms == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no platforms found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleGetLtiPlatform handles fetching a single LTI 1.3 platform.
+func (lc *LtiV1Controller) HandleGetLtiPlatform(c *fiber.Ctx) error {
+	req := new(models.LtiPlatformReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.PlatformId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "platform_id required")
+	}
+
+	info, err := lc.LtiV1Model.GetLtiPlatformInfo(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"platform": info,
+	})
+}
+
+// HandleDeleteLtiPlatform handles deleting an LTI 1.3 platform.
+func (lc *LtiV1Controller) HandleDeleteLtiPlatform(c *fiber.Ctx) error {
+	req := new(models.LtiPlatformReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.PlatformId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "platform_id required")
+	}
+
+	if err := lc.LtiV1Model.DeleteLtiPlatform(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/metrics"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/version"
@@ -39,10 +41,16 @@ const (
 	natsConnectionEventQueueGroup = prefix + "conn-event-queue"
 	websocketClientType           = "websocket"
 	transcoderConsumerDurable     = "transcoderWorker"
+
+	natsJobTypeConnEvent = "conn_event"
+	natsJobTypeSysWorker = "system_worker"
 )
 
 type natsJob struct {
-	handler func()
+	// jobType is used as label of the metrics
+	jobType  string
+	queuedAt time.Time
+	handler  func()
 }
 
 type NatsController struct {
@@ -87,6 +95,9 @@ func (c *NatsController) BootUp(ctx context.Context, wg *sync.WaitGroup) {
 	for i := 0; i < DefaultNumWorkers; i++ {
 		go c.worker()
 	}
+	metrics.RegisterStateCollector(c.app, c.natsService, func() int {
+		return len(c.jobChan)
+	}, DefaultJobQueueSize, c.app.Logger)
 
 	// system receiver as worker
 	stream, err := c.app.JetStream.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
@@ -159,7 +170,10 @@ func (c *NatsController) BootUp(ctx context.Context, wg *sync.WaitGroup) {
 
 func (c *NatsController) worker() {
 	for job := range c.jobChan {
+		start := time.Now()
+		metrics.NatsJobWaitDuration.WithLabelValues(job.jobType).Observe(start.Sub(job.queuedAt).Seconds())
 		job.handler()
+		metrics.NatsJobDuration.WithLabelValues(job.jobType).Observe(time.Since(start).Seconds())
 	}
 }
 
@@ -178,7 +192,7 @@ func (c *NatsController) subscribeToUsersConnEvents() (*nats.Subscription, error
 		data := make([]byte, len(msg.Data))
 		copy(data, msg.Data)
 
-		c.jobChan <- natsJob{handler: func() {
+		c.jobChan <- natsJob{jobType: natsJobTypeConnEvent, queuedAt: time.Now(), handler: func() {
 			c.handleUserConnectionEvent(data, isConnect)
 		}}
 	})
@@ -240,7 +254,7 @@ func (c *NatsController) subscribeToSystemWorker(ctx context.Context, stream jet
 		data := make([]byte, len(msg.Data()))
 		copy(data, msg.Data())
 
-		c.jobChan <- natsJob{handler: func() {
+		c.jobChan <- natsJob{jobType: natsJobTypeSysWorker, queuedAt: time.Now(), handler: func() {
 			req := new(plugnmeet.NatsMsgClientToServer)
 			if err := proto.Unmarshal(data, req); err == nil {
 				p := strings.Split(sub, ".")
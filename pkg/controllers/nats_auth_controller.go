@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -8,10 +9,13 @@ import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/micro"
 	"github.com/nats-io/nkeys"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type NatsAuthController struct {
@@ -38,6 +42,10 @@ func (s *NatsAuthController) Handle(r micro.Request) {
 	var data []byte
 	var err error
 
+	ctx := tracing.ExtractNatsHeader(context.Background(), nats.Header(r.Headers()))
+	ctx, span := tracing.Tracer().Start(ctx, "NatsAuthController.Handle", trace.WithSpanKind(trace.SpanKindServer))
+	defer func() { tracing.End(span, err) }()
+
 	xKey := r.Headers().Get("Nats-Server-Xkey")
 	if len(xKey) > 0 {
 		if s.curveKeyPair == nil {
@@ -66,7 +74,7 @@ func (s *NatsAuthController) Handle(r micro.Request) {
 	userNkey := rc.UserNkey
 	serverId := rc.Server.ID
 
-	claims, err := s.handleClaims(rc)
+	claims, err := s.handleClaims(ctx, rc)
 	if err != nil {
 		s.logger.WithError(err).Errorln("error handling claims")
 		s.respond(r, userNkey, serverId, "", err)
@@ -77,7 +85,7 @@ func (s *NatsAuthController) Handle(r micro.Request) {
 	s.respond(r, userNkey, serverId, token, err)
 }
 
-func (s *NatsAuthController) handleClaims(req *jwt.AuthorizationRequestClaims) (*jwt.UserClaims, error) {
+func (s *NatsAuthController) handleClaims(ctx context.Context, req *jwt.AuthorizationRequestClaims) (*jwt.UserClaims, error) {
 	claims := jwt.NewUserClaims(req.UserNkey)
 	claims.Audience = s.app.NatsInfo.Account
 
@@ -92,12 +100,15 @@ func (s *NatsAuthController) handleClaims(req *jwt.AuthorizationRequestClaims) (
 		return nil, err
 	}
 
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(tracing.RoomIdKey.String(data.GetRoomId()), tracing.UserIdKey.String(data.GetUserId()))
+
 	if data.GetName() == config.RecorderUserAuthName {
 		s.setPermissionForRecorder(data, claims)
 		return claims, nil
 	}
 
-	err = s.setPermissionForClient(data, claims)
+	err = s.setPermissionForClient(ctx, data, claims)
 	if err != nil {
 		return nil, err
 	}
@@ -135,11 +146,12 @@ func (s *NatsAuthController) setPermissionForRecorder(data *plugnmeet.PlugNmeetT
 	}
 }
 
-func (s *NatsAuthController) setPermissionForClient(data *plugnmeet.PlugNmeetTokenClaims, claims *jwt.UserClaims) error {
+func (s *NatsAuthController) setPermissionForClient(ctx context.Context, data *plugnmeet.PlugNmeetTokenClaims, claims *jwt.UserClaims) error {
 	roomId := data.GetRoomId()
 	userId := data.GetUserId()
+	natsService := s.natsService.WithContext(ctx)
 
-	userInfo, err := s.natsService.GetUserInfo(roomId, userId)
+	userInfo, err := natsService.GetUserInfo(roomId, userId)
 	if err != nil {
 		return err
 	}
@@ -154,31 +166,31 @@ func (s *NatsAuthController) setPermissionForClient(data *plugnmeet.PlugNmeetTok
 		fmt.Sprintf("%s.%s.%s", s.app.NatsInfo.Subjects.SystemJsWorker, roomId, userId),
 	}
 
-	chatPermission, err := s.natsService.CreateChatConsumer(roomId, userId)
+	chatPermission, err := natsService.CreateChatConsumer(roomId, userId)
 	if err != nil {
 		return err
 	}
 	allowPub.Add(chatPermission...)
 
-	sysPublicPermission, err := s.natsService.CreateSystemPublicConsumer(roomId, userId)
+	sysPublicPermission, err := natsService.CreateSystemPublicConsumer(roomId, userId)
 	if err != nil {
 		return err
 	}
 	allowPub.Add(sysPublicPermission...)
 
-	sysPrivatePermission, err := s.natsService.CreateSystemPrivateConsumer(roomId, userId)
+	sysPrivatePermission, err := natsService.CreateSystemPrivateConsumer(roomId, userId)
 	if err != nil {
 		return err
 	}
 	allowPub.Add(sysPrivatePermission...)
 
-	whiteboardPermission, err := s.natsService.CreateWhiteboardConsumer(roomId, userId)
+	whiteboardPermission, err := natsService.CreateWhiteboardConsumer(roomId, userId)
 	if err != nil {
 		return err
 	}
 	allowPub.Add(whiteboardPermission...)
 
-	dataChannelPermission, err := s.natsService.CreateDataChannelConsumer(roomId, userId)
+	dataChannelPermission, err := natsService.CreateDataChannelConsumer(roomId, userId)
 	if err != nil {
 		return err
 	}
@@ -269,6 +269,119 @@ func (pc *PollsController) HandleGetResponsesResult(c *fiber.Ctx) error {
 	return sendPollResponse(c, res)
 }
 
+// HandleCreatePollWithSettings handles creating a new poll with settings, e.g. quiz, multiple choice etc.
+func (pc *PollsController) HandleCreatePollWithSettings(c *fiber.Ctx) error {
+	roomId := c.Locals("roomId")
+	isAdmin := c.Locals("isAdmin")
+	requestedUserId := c.Locals("requestedUserId")
+
+	if !isAdmin.(bool) {
+		return utils.SendCommonProtoJsonResponse(c, false, "only admin can perform this task")
+	}
+
+	req := new(models.CreatePollWithSettingsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	pollId, err := pc.PollModel.CreatePollWithSettings(roomId.(string), requestedUserId.(string), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  true,
+		"msg":     "success",
+		"poll_id": pollId,
+	})
+}
+
+// HandleUserSubmitResponses handles a user's poll submission with one or more options.
+func (pc *PollsController) HandleUserSubmitResponses(c *fiber.Ctx) error {
+	roomId := c.Locals("roomId")
+	requestedUserId := c.Locals("requestedUserId")
+
+	req := new(models.SubmitPollResponsesReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	err := pc.PollModel.UserSubmitResponses(roomId.(string), requestedUserId.(string), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
+
+// HandleGetPollSettings gets settings of a poll.
+func (pc *PollsController) HandleGetPollSettings(c *fiber.Ctx) error {
+	roomId := c.Locals("roomId")
+	isAdmin := c.Locals("isAdmin")
+	pollId := c.Params("pollId")
+
+	settings, err := pc.PollModel.GetPollSettings(roomId.(string), pollId, isAdmin.(bool))
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"poll_id":  pollId,
+		"settings": settings,
+	})
+}
+
+// HandleGetQuizResult gets result of a closed quiz with the leaderboard.
+func (pc *PollsController) HandleGetQuizResult(c *fiber.Ctx) error {
+	roomId := c.Locals("roomId")
+	pollId := c.Params("pollId")
+
+	result, err := pc.PollModel.GetQuizResult(roomId.(string), pollId)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleFetchPastPolls handles fetching or exporting stored polls of the ended sessions.
+func (pc *PollsController) HandleFetchPastPolls(c *fiber.Ctx) error {
+	req := new(models.FetchPastPollsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	if req.Format != "" {
+		fileName, data, err := pc.PollModel.ExportPastPolls(getTenantId(c), req)
+		if err != nil {
+			return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+		}
+		c.Attachment(fileName)
+		c.Set("Content-Length", strconv.Itoa(len(data)))
+		return c.Send(data)
+	}
+
+	result, err := pc.PollModel.FetchPastPolls(getTenantId(c), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalPolls == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no polls found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
 // HandleGetPollsStats gets statistics for all polls in a room.
 func (pc *PollsController) HandleGetPollsStats(c *fiber.Ctx) error {
 	roomId := c.Locals("roomId")
@@ -17,17 +17,19 @@ type RecorderController struct {
 	RecorderModel  *models.RecorderModel
 	RecordingModel *models.RecordingModel
 	RoomModel      *models.RoomModel
+	TenantModel    *models.TenantModel
 	ds             *dbservice.DatabaseService
 	logger         *logrus.Entry
 }
 
 // NewRecorderController creates a new RecorderController.
-func NewRecorderController(config *config.AppConfig, ds *dbservice.DatabaseService, recorderModel *models.RecorderModel, recordingModel *models.RecordingModel, roomModel *models.RoomModel, logger *logrus.Logger) *RecorderController {
+func NewRecorderController(config *config.AppConfig, ds *dbservice.DatabaseService, recorderModel *models.RecorderModel, recordingModel *models.RecordingModel, roomModel *models.RoomModel, tenantModel *models.TenantModel, logger *logrus.Logger) *RecorderController {
 	return &RecorderController{
 		AppConfig:      config,
 		RecorderModel:  recorderModel,
 		RecordingModel: recordingModel,
 		RoomModel:      roomModel,
+		TenantModel:    tenantModel,
 		ds:             ds,
 		logger:         logger.WithField("controller", "recorder"),
 	}
@@ -78,6 +80,12 @@ func (rc *RecorderController) HandleRecording(c *fiber.Ctx) error {
 		return utils.SendCommonProtobufResponse(c, false, "notifications.recording-not-running")
 	}
 
+	if req.Task == plugnmeet.RecordingTasks_START_RECORDING {
+		if err = rc.TenantModel.CheckRecordingQuota(room.TenantID); err != nil {
+			return utils.SendCommonProtobufResponse(c, false, err.Error())
+		}
+	}
+
 	if room.IsActiveRtmp == 1 && req.Task == plugnmeet.RecordingTasks_START_RTMP {
 		return utils.SendCommonProtobufResponse(c, false, "notifications.rtmp-already-running")
 	} else if room.IsActiveRtmp == 0 && req.Task == plugnmeet.RecordingTasks_STOP_RTMP {
@@ -87,10 +95,11 @@ func (rc *RecorderController) HandleRecording(c *fiber.Ctx) error {
 	req.RoomId = room.RoomId
 	req.RoomTableId = int64(room.ID)
 
-	err = rc.RecorderModel.SendMsgToRecorder(req)
+	err = rc.RecorderModel.SendMsgToRecorder(c.UserContext(), req)
 	if err != nil {
 		return utils.SendCommonProtobufResponse(c, false, err.Error())
 	}
+	rc.RecorderModel.OnRecordingTaskByHost(room.Sid, req.Task)
 
 	return utils.SendCommonProtobufResponse(c, true, "success")
 }
@@ -150,7 +159,7 @@ func (rc *RecorderController) HandleRTMP(c *fiber.Ctx) error {
 	req.RoomId = room.RoomId
 	req.RoomTableId = int64(room.ID)
 
-	err = rc.RecorderModel.SendMsgToRecorder(req)
+	err = rc.RecorderModel.SendMsgToRecorder(c.UserContext(), req)
 	if err != nil {
 		return utils.SendCommonProtobufResponse(c, false, err.Error())
 	}
@@ -1,21 +1,31 @@
 package controllers
 
 import (
+	"encoding/json"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-protocol/utils"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // RecordingController holds dependencies for recording-related handlers.
 type RecordingController struct {
-	RecordingModel *models.RecordingModel
+	RecordingModel     *models.RecordingModel
+	TenantModel        *models.TenantModel
+	DownloadAuditModel *models.DownloadAuditModel
+	StorageService     *storageservice.StorageService
 }
 
 // NewRecordingController creates a new RecordingController.
-func NewRecordingController(m *models.RecordingModel) *RecordingController {
+func NewRecordingController(m *models.RecordingModel, tm *models.TenantModel, dam *models.DownloadAuditModel, ss *storageservice.StorageService) *RecordingController {
 	return &RecordingController{
-		RecordingModel: m,
+		RecordingModel:     m,
+		TenantModel:        tm,
+		DownloadAuditModel: dam,
+		StorageService:     ss,
 	}
 }
 
@@ -26,7 +36,13 @@ func (rc *RecordingController) HandleFetchRecordings(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
-	result, err := rc.RecordingModel.FetchRecordings(req)
+	// published & tags filters aren't part of the protocol, so those will be read separately
+	filter := new(models.FetchRecordingsFilter)
+	if err := json.Unmarshal(c.Body(), filter); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, metadata, err := rc.RecordingModel.FetchRecordings(getTenantId(c), req, filter)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
@@ -34,12 +50,63 @@ func (rc *RecordingController) HandleFetchRecordings(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, "no recordings found")
 	}
 
-	r := &plugnmeet.FetchRecordingsRes{
-		Status: true,
-		Msg:    "success",
-		Result: result,
+	op := protojson.MarshalOptions{
+		EmitUnpopulated: true,
+		UseProtoNames:   true,
 	}
-	return utils.SendProtoJsonResponse(c, r)
+	marshal, err := op.Marshal(result)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	// keep the result same as FetchRecordingsRes & add the metadata of the recordings
+	return c.JSON(fiber.Map{
+		"status":              true,
+		"msg":                 "success",
+		"result":              json.RawMessage(marshal),
+		"recordings_metadata": metadata,
+	})
+}
+
+// HandlePublishRecording handles publishing or unpublishing a recording.
+func (rc *RecordingController) HandlePublishRecording(c *fiber.Ctx) error {
+	req := new(models.PublishRecordingReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.RecordId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "record_id is required")
+	}
+
+	if !rc.TenantModel.CanAccessRecording(getTenantId(c), req.RecordId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "recording not found")
+	}
+
+	if err := rc.RecordingModel.PublishRecordings([]string{req.RecordId}, req.Publish); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
+
+// HandleUpdateRecording handles updating the title, description, tags & metadata of a recording.
+func (rc *RecordingController) HandleUpdateRecording(c *fiber.Ctx) error {
+	req := new(models.UpdateRecordingReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.RecordId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "record_id is required")
+	}
+
+	if !rc.TenantModel.CanAccessRecording(getTenantId(c), req.RecordId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "recording not found")
+	}
+
+	if err := rc.RecordingModel.UpdateRecording(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
 }
 
 // HandleRecordingInfo handles fetching information for a single recording.
@@ -49,6 +116,10 @@ func (rc *RecordingController) HandleRecordingInfo(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
+	if !rc.TenantModel.CanAccessRecording(getTenantId(c), req.RecordId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "recording not found")
+	}
+
 	result, err := rc.RecordingModel.RecordingInfo(req)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
@@ -64,6 +135,10 @@ func (rc *RecordingController) HandleDeleteRecording(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
+	if !rc.TenantModel.CanAccessRecording(getTenantId(c), req.RecordId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "recording not found")
+	}
+
 	err := rc.RecordingModel.DeleteRecording(req)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
@@ -72,6 +147,26 @@ func (rc *RecordingController) HandleDeleteRecording(c *fiber.Ctx) error {
 	return utils.SendCommonProtoJsonResponse(c, true, "success")
 }
 
+// HandleExpiredRecordings handles deleting the recordings which have passed their retention.
+// With dry_run, it will only report the recordings which will be deleted.
+func (rc *RecordingController) HandleExpiredRecordings(c *fiber.Ctx) error {
+	req := new(models.ExpiredRecordingsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := rc.RecordingModel.ExpireRecordings(getTenantId(c), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
 // HandleGetDownloadToken handles generating a download token for a recording.
 func (rc *RecordingController) HandleGetDownloadToken(c *fiber.Ctx) error {
 	req := new(plugnmeet.GetDownloadTokenReq)
@@ -79,7 +174,17 @@ func (rc *RecordingController) HandleGetDownloadToken(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
-	token, err := rc.RecordingModel.GetDownloadToken(req)
+	if !rc.TenantModel.CanAccessRecording(getTenantId(c), req.RecordId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "recording not found")
+	}
+
+	// token restrictions aren't part of the protocol, so those will be read separately
+	opts := new(models.DownloadTokenOptions)
+	if err := json.Unmarshal(c.Body(), opts); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	token, err := rc.RecordingModel.GetDownloadToken(req, opts)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
@@ -100,11 +205,43 @@ func (rc *RecordingController) HandleDownloadRecording(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).SendString("token require or invalid url")
 	}
 
-	file, status, err := rc.RecordingModel.VerifyRecordingToken(token)
+	info, status, err := rc.RecordingModel.VerifyRecordingToken(c.UserContext(), token, c.IP())
+	if err != nil {
+		return c.Status(status).SendString(err.Error())
+	}
+
+	audit := newDownloadAudit(c, rc.DownloadAuditModel, models.DownloadFileTypeRecording, info.Key, info)
+	return sendStorageFile(c, rc.StorageService, rc.StorageService.Recordings(), info.Key, !info.Restricted, audit)
+}
+
+// HandleDownloadRecordingThumbnail sends the thumbnail of the recording by index
+func (rc *RecordingController) HandleDownloadRecordingThumbnail(c *fiber.Ctx) error {
+	token := c.Params("token")
+
+	if len(token) == 0 {
+		return c.Status(fiber.StatusUnauthorized).SendString("token require or invalid url")
+	}
+
+	info, status, err := rc.RecordingModel.VerifyThumbnailToken(c.UserContext(), token, c.Params("index"), c.IP())
+	if err != nil {
+		return c.Status(status).SendString(err.Error())
+	}
+
+	return sendStorageFile(c, rc.StorageService, rc.StorageService.Recordings(), info.Key, !info.Restricted, nil)
+}
+
+// HandleDownloadRecordingTextTrack sends the text track of the recording by name
+func (rc *RecordingController) HandleDownloadRecordingTextTrack(c *fiber.Ctx) error {
+	token := c.Params("token")
+
+	if len(token) == 0 {
+		return c.Status(fiber.StatusUnauthorized).SendString("token require or invalid url")
+	}
+
+	info, status, err := rc.RecordingModel.VerifyTextTrackToken(c.UserContext(), token, c.Params("name"), c.IP())
 	if err != nil {
 		return c.Status(status).SendString(err.Error())
 	}
 
-	c.Attachment(file)
-	return c.SendFile(file, false)
+	return sendStorageFile(c, rc.StorageService, rc.StorageService.Recordings(), info.Key, !info.Restricted, nil)
 }
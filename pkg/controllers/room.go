@@ -10,13 +10,15 @@ import (
 
 // RoomController holds dependencies for room-related handlers.
 type RoomController struct {
-	RoomModel *models.RoomModel
+	RoomModel   *models.RoomModel
+	TenantModel *models.TenantModel
 }
 
 // NewRoomController creates a new RoomController.
-func NewRoomController(m *models.RoomModel) *RoomController {
+func NewRoomController(m *models.RoomModel, tm *models.TenantModel) *RoomController {
 	return &RoomController{
-		RoomModel: m,
+		RoomModel:   m,
+		TenantModel: tm,
 	}
 }
 
@@ -27,7 +29,7 @@ func (rc *RoomController) HandleRoomCreate(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
-	room, err := rc.RoomModel.CreateRoom(req)
+	room, err := rc.RoomModel.CreateRoom(getTenantId(c), req)
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
@@ -48,6 +50,10 @@ func (rc *RoomController) HandleIsRoomActive(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
+	if !rc.TenantModel.CanAccessRoom(getTenantId(c), req.RoomId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "room not found")
+	}
+
 	res, _, _, _ := rc.RoomModel.IsRoomActive(c.UserContext(), req)
 	return utils.SendProtoJsonResponse(c, res)
 }
@@ -59,6 +65,10 @@ func (rc *RoomController) HandleGetActiveRoomInfo(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
+	if !rc.TenantModel.CanAccessRoom(getTenantId(c), req.RoomId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "room not found")
+	}
+
 	status, msg, res := rc.RoomModel.GetActiveRoomInfo(c.UserContext(), req)
 
 	r := &plugnmeet.GetActiveRoomInfoRes{
@@ -72,7 +82,7 @@ func (rc *RoomController) HandleGetActiveRoomInfo(c *fiber.Ctx) error {
 
 // HandleGetActiveRoomsInfo gets information about all active rooms.
 func (rc *RoomController) HandleGetActiveRoomsInfo(c *fiber.Ctx) error {
-	status, msg, res := rc.RoomModel.GetActiveRoomsInfo()
+	status, msg, res := rc.RoomModel.GetActiveRoomsInfo(getTenantId(c))
 
 	r := &plugnmeet.GetActiveRoomsInfoRes{
 		Status: status,
@@ -90,6 +100,10 @@ func (rc *RoomController) HandleEndRoom(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
+	if !rc.TenantModel.CanAccessRoom(getTenantId(c), req.RoomId) {
+		return utils.SendCommonProtoJsonResponse(c, false, "room not found")
+	}
+
 	status, msg := rc.RoomModel.EndRoom(c.UserContext(), req)
 
 	return utils.SendCommonProtoJsonResponse(c, status, msg)
@@ -102,7 +116,7 @@ func (rc *RoomController) HandleFetchPastRooms(c *fiber.Ctx) error {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
 	}
 
-	result, err := rc.RoomModel.FetchPastRooms(req)
+	result, err := rc.RoomModel.FetchPastRooms(getTenantId(c), req)
 
 	if err != nil {
 		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// ScheduleController holds dependencies for scheduled meeting related handlers.
+type ScheduleController struct {
+	ScheduleModel *models.ScheduleModel
+}
+
+// NewScheduleController creates a new ScheduleController.
+func NewScheduleController(m *models.ScheduleModel) *ScheduleController {
+	return &ScheduleController{
+		ScheduleModel: m,
+	}
+}
+
+// HandleCreateSchedule handles creating a new scheduled meeting.
+func (sc *ScheduleController) HandleCreateSchedule(c *fiber.Ctx) error {
+	req := new(models.ScheduleMeetingReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	tmpl := new(plugnmeet.CreateRoomReq)
+	if err := parseAndValidateRequest(req.RoomTemplate, tmpl); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, "invalid room_template: "+err.Error())
+	}
+
+	info, err := sc.ScheduleModel.CreateSchedule(getTenantId(c), req, tmpl)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"schedule": info,
+	})
+}
+
+// HandleUpdateSchedule handles updating an existing scheduled meeting.
+func (sc *ScheduleController) HandleUpdateSchedule(c *fiber.Ctx) error {
+	req := new(models.UpdateScheduleReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ScheduleId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "schedule_id required")
+	}
+
+	var tmpl *plugnmeet.CreateRoomReq
+	if len(req.RoomTemplate) > 0 {
+		tmpl = new(plugnmeet.CreateRoomReq)
+		if err := parseAndValidateRequest(req.RoomTemplate, tmpl); err != nil {
+			return utils.SendCommonProtoJsonResponse(c, false, "invalid room_template: "+err.Error())
+		}
+	}
+
+	info, err := sc.ScheduleModel.UpdateSchedule(getTenantId(c), req, tmpl)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":   true,
+		"msg":      "success",
+		"schedule": info,
+	})
+}
+
+// HandleFetchSchedules handles listing scheduled meetings.
+func (sc *ScheduleController) HandleFetchSchedules(c *fiber.Ctx) error {
+	req := new(models.FetchSchedulesReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := sc.ScheduleModel.FetchSchedules(getTenantId(c), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalSchedules == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no schedules found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleGetScheduleOccurrences handles listing past and upcoming occurrences of a schedule.
+func (sc *ScheduleController) HandleGetScheduleOccurrences(c *fiber.Ctx) error {
+	req := new(models.ScheduleOccurrencesReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ScheduleId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "schedule_id required")
+	}
+
+	occurrences, err := sc.ScheduleModel.GetScheduleOccurrences(getTenantId(c), req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":      true,
+		"msg":         "success",
+		"occurrences": occurrences,
+	})
+}
+
+// HandleCancelSchedule handles cancelling a schedule or one of its occurrences.
+func (sc *ScheduleController) HandleCancelSchedule(c *fiber.Ctx) error {
+	req := new(models.CancelScheduleReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.ScheduleId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "schedule_id required")
+	}
+
+	if err := sc.ScheduleModel.CancelSchedule(getTenantId(c), req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
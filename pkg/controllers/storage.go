@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gofiber/fiber/v2"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
+)
+
+// downloadAuditFunc will be called with the status & the number of bytes
+// which were sent to the client after the file has been served
+type downloadAuditFunc func(status int, bytesServed int64)
+
+// newDownloadAudit returns the function to log the download,
+// or nil if download audit isn't enabled. info can be nil for downloads without token.
+func newDownloadAudit(c *fiber.Ctx, am *models.DownloadAuditModel, fileType, key string, info *models.DownloadTokenInfo) downloadAuditFunc {
+	if am == nil || !am.IsEnabled() {
+		return nil
+	}
+
+	// values of fiber are only valid within the handler, so we'll need to copy them
+	audit := &dbmodels.DownloadAudit{
+		FileType:    fileType,
+		FileKey:     key,
+		Ip:          fiberutils.CopyString(c.IP()),
+		UserAgent:   truncateString(fiberutils.CopyString(c.Get(fiber.HeaderUserAgent)), 255),
+		RangeHeader: truncateString(fiberutils.CopyString(c.Get(fiber.HeaderRange)), 100),
+	}
+	if info != nil {
+		audit.TenantID = info.TenantId
+		audit.FileID = info.FileId
+		audit.TokenID = info.TokenId
+		audit.RequestedBy = info.RequestedBy
+	}
+
+	return func(status int, bytesServed int64) {
+		audit.Status = status
+		audit.BytesServed = bytesServed
+		go am.AddDownloadAudit(audit)
+	}
+}
+
+// sendStorageFile sends the file from storage to the client.
+// If redirect is allowed & the storage supports presigned url then the client will be redirected,
+// otherwise the content will be streamed with the support of Range requests, so large files can be resumed.
+// audit can be nil.
+func sendStorageFile(c *fiber.Ctx, ss *storageservice.StorageService, store storageservice.Store, key string, redirect bool, audit downloadAuditFunc) error {
+	if audit == nil {
+		audit = func(int, int64) {}
+	}
+	fileName := path.Base(key)
+
+	if redirect && store.LocalPath(key) == "" {
+		if ok, expiry := ss.PresignedDownload(); ok {
+			u, err := store.PresignedURL(key, fileName, expiry)
+			if err == nil {
+				// the storage will handle the range requests
+				audit(fiber.StatusFound, 0)
+				return c.Redirect(u, fiber.StatusFound)
+			}
+			// we'll try to stream it
+		}
+	}
+
+	info, err := store.Stat(key)
+	if err != nil {
+		if errors.Is(err, storageservice.ErrNotFound) {
+			audit(fiber.StatusNotFound, 0)
+			return c.Status(fiber.StatusNotFound).SendString(err.Error())
+		}
+		audit(fiber.StatusInternalServerError, 0)
+		return c.Status(fiber.StatusInternalServerError).SendString("failed to read file from storage")
+	}
+
+	etag := helpers.FileETag(info.Size, info.ModTime)
+	c.Attachment(fileName)
+	c.Set(fiber.HeaderContentType, detectContentType(store, key, info))
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, info.ModTime.UTC().Format(http.TimeFormat))
+
+	var br *helpers.ByteRange
+	if rh := c.Get(fiber.HeaderRange); rh != "" && helpers.CheckIfRange(c.Get(fiber.HeaderIfRange), etag, info.ModTime) {
+		br, err = helpers.ParseByteRange(rh, info.Size)
+		if err != nil {
+			audit(fiber.StatusRequestedRangeNotSatisfiable, 0)
+			c.Set(fiber.HeaderContentRange, "bytes */"+strconv.FormatInt(info.Size, 10))
+			return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+		}
+	}
+
+	status := fiber.StatusOK
+	if br == nil {
+		br = &helpers.ByteRange{Start: 0, Length: info.Size}
+	} else {
+		status = fiber.StatusPartialContent
+		c.Set(fiber.HeaderContentRange, br.ContentRange(info.Size))
+	}
+
+	rc, _, err := store.GetRange(key, br.Start, br.Length)
+	if err != nil {
+		audit(fiber.StatusInternalServerError, 0)
+		return c.Status(fiber.StatusInternalServerError).SendString("failed to read file from storage")
+	}
+
+	c.Status(status)
+	// fasthttp will close the reader after sending, even if the client has gone
+	return c.SendStream(&auditReadCloser{
+		ReadCloser: rc,
+		onClose: func(n int64) {
+			audit(status, n)
+		},
+	}, int(br.Length))
+}
+
+// detectContentType returns the content type of the file,
+// local files without known extension will be detected by their content
+func detectContentType(store storageservice.Store, key string, info *storageservice.ObjectInfo) string {
+	if info.ContentType != "" {
+		return info.ContentType
+	}
+	if file := store.LocalPath(key); file != "" {
+		if mtype, err := mimetype.DetectFile(file); err == nil {
+			return mtype.String()
+		}
+	}
+	return fiber.MIMEOctetStream
+}
+
+// auditReadCloser counts the bytes which were read & reports them once on close
+type auditReadCloser struct {
+	io.ReadCloser
+	n       int64
+	once    sync.Once
+	onClose func(n int64)
+}
+
+func (r *auditReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *auditReadCloser) Close() error {
+	r.once.Do(func() {
+		r.onClose(r.n)
+	})
+	return r.ReadCloser.Close()
+}
+
+func truncateString(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+)
+
+// TenantController holds dependencies for tenant related handlers.
+type TenantController struct {
+	TenantModel *models.TenantModel
+}
+
+// NewTenantController creates a new TenantController.
+func NewTenantController(m *models.TenantModel) *TenantController {
+	return &TenantController{
+		TenantModel: m,
+	}
+}
+
+// getTenantId returns the tenant of the API key used for the request.
+// It will be empty for the default API key from config.
+func getTenantId(c *fiber.Ctx) string {
+	if tenantId, ok := c.Locals("tenantId").(string); ok {
+		return tenantId
+	}
+	return ""
+}
+
+// HandleCreateTenant handles creating a new tenant with its first API key.
+func (tc *TenantController) HandleCreateTenant(c *fiber.Ctx) error {
+	req := new(models.CreateTenantReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	details, err := tc.TenantModel.CreateTenant(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": details,
+	})
+}
+
+// HandleUpdateTenant handles updating name, quotas or status of a tenant.
+func (tc *TenantController) HandleUpdateTenant(c *fiber.Ctx) error {
+	req := new(models.UpdateTenantReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.TenantId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "tenant_id required")
+	}
+
+	info, err := tc.TenantModel.UpdateTenant(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"tenant": info,
+	})
+}
+
+// HandleFetchTenants handles listing tenants.
+func (tc *TenantController) HandleFetchTenants(c *fiber.Ctx) error {
+	req := new(models.FetchTenantsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := tc.TenantModel.FetchTenants(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalTenants == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no tenants found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleGetTenantInfo handles fetching a tenant with its API keys & current usage.
+func (tc *TenantController) HandleGetTenantInfo(c *fiber.Ctx) error {
+	req := new(models.TenantReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.TenantId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "tenant_id required")
+	}
+
+	details, err := tc.TenantModel.GetTenantDetails(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": details,
+	})
+}
+
+// HandleDeleteTenant handles deleting a tenant & all of its API keys.
+func (tc *TenantController) HandleDeleteTenant(c *fiber.Ctx) error {
+	req := new(models.TenantReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.TenantId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "tenant_id required")
+	}
+
+	if err := tc.TenantModel.DeleteTenant(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
+
+// HandleCreateTenantApiKey handles generating a new API key for a tenant.
+func (tc *TenantController) HandleCreateTenantApiKey(c *fiber.Ctx) error {
+	req := new(models.TenantReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.TenantId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "tenant_id required")
+	}
+
+	key, err := tc.TenantModel.CreateTenantApiKey(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  true,
+		"msg":     "success",
+		"api_key": key,
+	})
+}
+
+// HandleRevokeTenantApiKey handles revoking an API key of a tenant.
+func (tc *TenantController) HandleRevokeTenantApiKey(c *fiber.Ctx) error {
+	req := new(models.RevokeTenantApiKeyReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.TenantId == "" || req.ApiKey == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "tenant_id & api_key required")
+	}
+
+	if err := tc.TenantModel.RevokeTenantApiKey(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
@@ -47,7 +47,7 @@ func (uc *UserController) HandleGenerateJoinToken(c *fiber.Ctx) error {
 	}
 
 	ri, _ := uc.ds.GetRoomInfoByRoomId(req.RoomId, 1)
-	if ri == nil || ri.ID == 0 {
+	if ri == nil || ri.ID == 0 || (getTenantId(c) != "" && ri.TenantID != getTenantId(c)) {
 		return utils.SendCommonProtoJsonResponse(c, false, "room is not active. create room first")
 	}
 
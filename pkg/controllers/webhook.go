@@ -3,6 +3,7 @@ package controllers
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/livekit/protocol/livekit"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
 )
 
@@ -47,3 +48,155 @@ func (wc *WebhookController) HandleWebhook(c *fiber.Ctx) error {
 
 	return c.SendStatus(fiber.StatusOK)
 }
+
+// HandleFetchWebhookDeliveries handles listing failed webhook deliveries from the dead-letter queue.
+func (wc *WebhookController) HandleFetchWebhookDeliveries(c *fiber.Ctx) error {
+	req := new(models.FetchWebhookDeliveriesReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := wc.WebhookModel.FetchFailedWebhookDeliveries(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalDeliveries == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no failed deliveries found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleReplayWebhookDeliveries handles sending failed webhook deliveries again.
+func (wc *WebhookController) HandleReplayWebhookDeliveries(c *fiber.Ctx) error {
+	req := new(models.WebhookDeliveriesReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	if err := wc.WebhookModel.ReplayFailedWebhookDeliveries(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
+
+// HandleDeleteWebhookDeliveries handles removing failed webhook deliveries from the dead-letter queue.
+func (wc *WebhookController) HandleDeleteWebhookDeliveries(c *fiber.Ctx) error {
+	req := new(models.WebhookDeliveriesReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	if err := wc.WebhookModel.DeleteFailedWebhookDeliveries(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
+
+// HandleCreateWebhookSubscription handles creating a new webhook subscription.
+func (wc *WebhookController) HandleCreateWebhookSubscription(c *fiber.Ctx) error {
+	req := new(models.CreateWebhookSubscriptionReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	info, err := wc.WebhookModel.CreateWebhookSubscription(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":       true,
+		"msg":          "success",
+		"subscription": info,
+	})
+}
+
+// HandleUpdateWebhookSubscription handles updating an existing webhook subscription.
+func (wc *WebhookController) HandleUpdateWebhookSubscription(c *fiber.Ctx) error {
+	req := new(models.UpdateWebhookSubscriptionReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.SubscriptionId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "subscription_id required")
+	}
+
+	info, err := wc.WebhookModel.UpdateWebhookSubscription(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":       true,
+		"msg":          "success",
+		"subscription": info,
+	})
+}
+
+// HandleFetchWebhookSubscriptions handles listing webhook subscriptions.
+func (wc *WebhookController) HandleFetchWebhookSubscriptions(c *fiber.Ctx) error {
+	req := new(models.FetchWebhookSubscriptionsReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	result, err := wc.WebhookModel.FetchWebhookSubscriptions(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if result.TotalSubscriptions == 0 {
+		return utils.SendCommonProtoJsonResponse(c, false, "no subscriptions found")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": true,
+		"msg":    "success",
+		"result": result,
+	})
+}
+
+// HandleGetWebhookSubscription handles fetching a single webhook subscription.
+func (wc *WebhookController) HandleGetWebhookSubscription(c *fiber.Ctx) error {
+	req := new(models.WebhookSubscriptionReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.SubscriptionId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "subscription_id required")
+	}
+
+	info, err := wc.WebhookModel.GetWebhookSubscriptionInfo(req)
+	if err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"status":       true,
+		"msg":          "success",
+		"subscription": info,
+	})
+}
+
+// HandleDeleteWebhookSubscription handles deleting a webhook subscription.
+func (wc *WebhookController) HandleDeleteWebhookSubscription(c *fiber.Ctx) error {
+	req := new(models.WebhookSubscriptionReq)
+	if err := c.BodyParser(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+	if req.SubscriptionId == "" {
+		return utils.SendCommonProtoJsonResponse(c, false, "subscription_id required")
+	}
+
+	if err := wc.WebhookModel.DeleteWebhookSubscription(req); err != nil {
+		return utils.SendCommonProtoJsonResponse(c, false, err.Error())
+	}
+
+	return utils.SendCommonProtoJsonResponse(c, true, "success")
+}
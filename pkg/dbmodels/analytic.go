@@ -11,6 +11,7 @@ type Analytics struct {
 	FileSize         float64 `gorm:"column:file_size;NOT NULL"`
 	RoomCreationTime int64   `gorm:"column:room_creation_time;NOT NULL"`
 	CreationTime     int64   `gorm:"column:creation_time;autoCreateTime;NOT NULL"`
+	TenantID         string  `gorm:"column:tenant_id;NOT NULL"`
 }
 
 func (m *Analytics) TableName() string {
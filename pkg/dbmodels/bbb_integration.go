@@ -0,0 +1,29 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// BBBIntegration is a BBB compatible client with its own api key & shared secret
+type BBBIntegration struct {
+	ID     uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	ApiKey string `gorm:"column:api_key;unique;NOT NULL"`
+	Secret string `gorm:"column:secret;NOT NULL"`
+	Name   string `gorm:"column:name;NOT NULL"`
+	// TenantID empty means the rooms will belong to the server itself
+	TenantID string `gorm:"column:tenant_id;NOT NULL"`
+	// DefaultParams is JSON encoded parameters of the create API,
+	// which will be used if the request didn't send them
+	DefaultParams string `gorm:"column:default_params;NOT NULL"`
+	// WebhookUrl will be used for the rooms created without webhook url
+	WebhookUrl string    `gorm:"column:webhook_url;NOT NULL"`
+	Enabled    bool      `gorm:"column:enabled;default:1;NOT NULL"`
+	Created    time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified   time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *BBBIntegration) TableName() string {
+	return config.FormatDBTable("bbb_integrations")
+}
@@ -0,0 +1,31 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// ChatMessage is an archived chat message of an ended session
+type ChatMessage struct {
+	ID          uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	RoomTableID uint64 `gorm:"column:room_table_id;NOT NULL"`
+	RoomID      string `gorm:"column:room_id;NOT NULL"`
+	RoomSid     string `gorm:"column:room_sid;NOT NULL"`
+	TenantID    string `gorm:"column:tenant_id;NOT NULL"`
+	MessageID   string `gorm:"column:message_id;NOT NULL"`
+	FromUserID  string `gorm:"column:from_user_id;NOT NULL"`
+	FromName    string `gorm:"column:from_name;NOT NULL"`
+	FromAdmin   bool   `gorm:"column:from_admin;NOT NULL"`
+	// ToUserID will be empty for public messages
+	ToUserID  string `gorm:"column:to_user_id;NOT NULL"`
+	IsPrivate bool   `gorm:"column:is_private;NOT NULL"`
+	Message   string `gorm:"column:message;NOT NULL"`
+	// SentAt in milliseconds
+	SentAt  int64     `gorm:"column:sent_at;NOT NULL"`
+	Created time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+}
+
+func (m *ChatMessage) TableName() string {
+	return config.FormatDBTable("chat_messages")
+}
@@ -0,0 +1,31 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// DownloadAudit is a log of a file download request
+type DownloadAudit struct {
+	ID       uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	TenantID string `gorm:"column:tenant_id;NOT NULL"`
+	// FileType can be recording, analytics or uploaded_file
+	FileType string `gorm:"column:file_type;NOT NULL"`
+	// FileID is the record id or the file id of analytics, empty for uploaded files
+	FileID  string `gorm:"column:file_id;NOT NULL"`
+	FileKey string `gorm:"column:file_key;NOT NULL"`
+	TokenID string `gorm:"column:token_id;NOT NULL"`
+	// RequestedBy is the subject the token was issued for
+	RequestedBy string    `gorm:"column:requested_by;NOT NULL"`
+	Ip          string    `gorm:"column:ip;NOT NULL"`
+	UserAgent   string    `gorm:"column:user_agent;NOT NULL"`
+	RangeHeader string    `gorm:"column:range_header;NOT NULL"`
+	Status      int       `gorm:"column:status;NOT NULL"`
+	BytesServed int64     `gorm:"column:bytes_served;NOT NULL"`
+	Created     time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+}
+
+func (m *DownloadAudit) TableName() string {
+	return config.FormatDBTable("download_audits")
+}
@@ -0,0 +1,25 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// LtiConsumer is an LMS using LTI 1.1 with its own consumer key & secret
+type LtiConsumer struct {
+	ID          uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	ConsumerKey string `gorm:"column:consumer_key;unique;NOT NULL"`
+	Secret      string `gorm:"column:secret;NOT NULL"`
+	Name        string `gorm:"column:name;NOT NULL"`
+	// DefaultParams is JSON encoded custom parameters,
+	// which will be used if the launch didn't send them
+	DefaultParams string    `gorm:"column:default_params;NOT NULL"`
+	Enabled       bool      `gorm:"column:enabled;default:1;NOT NULL"`
+	Created       time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified      time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *LtiConsumer) TableName() string {
+	return config.FormatDBTable("lti_consumers")
+}
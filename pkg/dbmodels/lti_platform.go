@@ -0,0 +1,50 @@
+package dbmodels
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// LtiPlatform is the registration of an LMS for LTI 1.3
+type LtiPlatform struct {
+	ID         uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	PlatformID string `gorm:"column:platform_id;unique;NOT NULL"`
+	Name       string `gorm:"column:name;NOT NULL"`
+	Issuer     string `gorm:"column:issuer;NOT NULL"`
+	ClientID   string `gorm:"column:client_id;NOT NULL"`
+	// DeploymentIDs is comma separated list, empty means all deployments of the client
+	DeploymentIDs string    `gorm:"column:deployment_ids;NOT NULL"`
+	AuthLoginUrl  string    `gorm:"column:auth_login_url;NOT NULL"`
+	AuthTokenUrl  string    `gorm:"column:auth_token_url;NOT NULL"`
+	JwksUrl       string    `gorm:"column:jwks_url;NOT NULL"`
+	Enabled       bool      `gorm:"column:enabled;default:1;NOT NULL"`
+	Created       time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified      time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *LtiPlatform) TableName() string {
+	return config.FormatDBTable("lti_platforms")
+}
+
+// DeploymentList returns the list of allowed deployments
+func (m *LtiPlatform) DeploymentList() []string {
+	if m.DeploymentIDs == "" {
+		return nil
+	}
+	return strings.Split(m.DeploymentIDs, ",")
+}
+
+// HasDeployment returns true if launches from the deployment are allowed
+func (m *LtiPlatform) HasDeployment(deploymentId string) bool {
+	if m.DeploymentIDs == "" {
+		return true
+	}
+	for _, d := range strings.Split(m.DeploymentIDs, ",") {
+		if d == deploymentId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// Poll is a poll of an ended session
+type Poll struct {
+	ID          uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	RoomTableID uint64 `gorm:"column:room_table_id;NOT NULL"`
+	RoomID      string `gorm:"column:room_id;NOT NULL"`
+	RoomSid     string `gorm:"column:room_sid;NOT NULL"`
+	TenantID    string `gorm:"column:tenant_id;NOT NULL"`
+	PollID      string `gorm:"column:poll_id;NOT NULL"`
+	Question    string `gorm:"column:question;NOT NULL"`
+	// Options is a JSON array of options with vote counts
+	Options        string `gorm:"column:options;NOT NULL"`
+	TotalResponses uint64 `gorm:"column:total_responses;NOT NULL"`
+	CreatedBy      string `gorm:"column:created_by;NOT NULL"`
+	ClosedBy       string `gorm:"column:closed_by;NOT NULL"`
+	// PollCreatedAt is the unix time when the poll was created in the session
+	PollCreatedAt  int64 `gorm:"column:poll_created_at;NOT NULL"`
+	IsQuiz         bool  `gorm:"column:is_quiz;NOT NULL"`
+	IsAnonymous    bool  `gorm:"column:is_anonymous;NOT NULL"`
+	MultipleChoice bool  `gorm:"column:multiple_choice;NOT NULL"`
+	// CorrectOptions is a JSON array of option ids for quiz
+	CorrectOptions string         `gorm:"column:correct_options;NOT NULL"`
+	Created        time.Time      `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Responses      []PollResponse `gorm:"foreignKey:PollTableID;references:ID"`
+}
+
+func (m *Poll) TableName() string {
+	return config.FormatDBTable("polls")
+}
+
+// PollResponse is the choice of a respondent of a poll,
+// there will be a row for each selected option of multiple choice poll
+type PollResponse struct {
+	ID             uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	PollTableID    uint64    `gorm:"column:poll_table_id;NOT NULL"`
+	UserID         string    `gorm:"column:user_id;NOT NULL"`
+	Name           string    `gorm:"column:name;NOT NULL"`
+	SelectedOption uint64    `gorm:"column:selected_option;NOT NULL"`
+	Created        time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+}
+
+func (m *PollResponse) TableName() string {
+	return config.FormatDBTable("poll_responses")
+}
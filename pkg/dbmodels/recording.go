@@ -18,6 +18,19 @@ type Recording struct {
 	Published        int64          `gorm:"column:published;default:1;NOT NULL"`
 	CreationTime     int64          `gorm:"column:creation_time;autoCreateTime;NOT NULL"`
 	RoomCreationTime int64          `gorm:"column:room_creation_time;default:0;NOT NULL"`
+	TenantID         string         `gorm:"column:tenant_id;NOT NULL"`
+	ParentRoomID     string         `gorm:"column:parent_room_id;NOT NULL"`
+	Title            string         `gorm:"column:title;NOT NULL"`
+	Description      string         `gorm:"column:description;NOT NULL"`
+	Tags             string         `gorm:"column:tags;NOT NULL"`
+	Metadata         string         `gorm:"column:metadata;NOT NULL"`
+	ExpireAt         int64          `gorm:"column:expire_at;default:0;NOT NULL"`
+	Duration         float64        `gorm:"column:duration;default:0;NOT NULL"`
+	VideoCodec       string         `gorm:"column:video_codec;NOT NULL"`
+	AudioCodec       string         `gorm:"column:audio_codec;NOT NULL"`
+	Width            int            `gorm:"column:width;default:0;NOT NULL"`
+	Height           int            `gorm:"column:height;default:0;NOT NULL"`
+	Thumbnails       int            `gorm:"column:thumbnails;default:0;NOT NULL"`
 	Created          time.Time      `gorm:"column:created;autoCreateTime;NOT NULL"`
 	Modified         time.Time      `gorm:"column:modified;autoUpdateTime;NOT NULL"`
 }
@@ -20,6 +20,9 @@ type RoomInfo struct {
 	WebhookUrl         string    `gorm:"column:webhook_url;NOT NULL"`
 	IsBreakoutRoom     int       `gorm:"column:is_breakout_room;default:0;NOT NULL"`
 	ParentRoomID       string    `gorm:"column:parent_room_id;NOT NULL"`
+	TenantID           string    `gorm:"column:tenant_id;NOT NULL"`
+	RecordingStartedAt int64     `gorm:"column:recording_started_at;default:0;NOT NULL"`
+	RecordingRetention int64     `gorm:"column:recording_retention;default:0;NOT NULL"`
 	CreationTime       int64     `gorm:"column:creation_time;autoCreateTime;NOT NULL"`
 	Created            time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
 	Ended              time.Time `gorm:"column:ended;default:0000-00-00 00:00:00;NOT NULL"`
@@ -0,0 +1,52 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+const (
+	ScheduleStatusActive    = "active"
+	ScheduleStatusCancelled = "cancelled"
+	ScheduleStatusCompleted = "completed"
+
+	OccurrenceStatusCreated   = "created"
+	OccurrenceStatusCancelled = "cancelled"
+	OccurrenceStatusMissed    = "missed"
+)
+
+type ScheduledMeeting struct {
+	ID             uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	ScheduleID     string    `gorm:"column:schedule_id;unique;NOT NULL"`
+	RoomID         string    `gorm:"column:room_id;NOT NULL"`
+	Title          string    `gorm:"column:title;NOT NULL"`
+	RoomTemplate   string    `gorm:"column:room_template;NOT NULL"`
+	StartTime      int64     `gorm:"column:start_time;NOT NULL"`
+	RecurrenceRule string    `gorm:"column:recurrence_rule;NOT NULL"`
+	Timezone       string    `gorm:"column:timezone;NOT NULL"`
+	LeadTime       uint32    `gorm:"column:lead_time;default:5;NOT NULL"`
+	NextOccurrence int64     `gorm:"column:next_occurrence;default:0;NOT NULL"`
+	Status         string    `gorm:"column:status;default:active;NOT NULL"`
+	TenantID       string    `gorm:"column:tenant_id;NOT NULL"`
+	Created        time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified       time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *ScheduledMeeting) TableName() string {
+	return config.FormatDBTable("scheduled_meetings")
+}
+
+type ScheduledMeetingOccurrence struct {
+	ID             uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	ScheduleID     string    `gorm:"column:schedule_id;NOT NULL"`
+	OccurrenceTime int64     `gorm:"column:occurrence_time;NOT NULL"`
+	Status         string    `gorm:"column:status;NOT NULL"`
+	RoomSid        string    `gorm:"column:room_sid;NOT NULL"`
+	Created        time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified       time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *ScheduledMeetingOccurrence) TableName() string {
+	return config.FormatDBTable("scheduled_meeting_occurrences")
+}
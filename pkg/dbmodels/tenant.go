@@ -0,0 +1,54 @@
+package dbmodels
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+type Tenant struct {
+	ID       uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	TenantID string `gorm:"column:tenant_id;unique;NOT NULL"`
+	Name     string `gorm:"column:name;NOT NULL"`
+	// all the limits are unlimited when 0
+	MaxConcurrentRooms  uint32 `gorm:"column:max_concurrent_rooms;default:0;NOT NULL"`
+	MaxParticipants     uint32 `gorm:"column:max_participants;default:0;NOT NULL"`
+	MaxRecordingMinutes uint32 `gorm:"column:max_recording_minutes;default:0;NOT NULL"`
+	// RecordingRetentionDays will use the global retention when 0
+	RecordingRetentionDays uint32    `gorm:"column:recording_retention_days;default:0;NOT NULL"`
+	Enabled                bool      `gorm:"column:enabled;default:1;NOT NULL"`
+	Created                time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified               time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *Tenant) TableName() string {
+	return config.FormatDBTable("tenants")
+}
+
+type TenantApiKey struct {
+	ID       uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	TenantID string    `gorm:"column:tenant_id;NOT NULL"`
+	ApiKey   string    `gorm:"column:api_key;unique;NOT NULL"`
+	Secret   string    `gorm:"column:secret;NOT NULL"`
+	Revoked  bool      `gorm:"column:revoked;default:0;NOT NULL"`
+	Created  time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *TenantApiKey) TableName() string {
+	return config.FormatDBTable("tenant_api_keys")
+}
+
+type TenantUsage struct {
+	ID       uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	TenantID string `gorm:"column:tenant_id;NOT NULL"`
+	// Period in YYYY-MM format
+	Period           string    `gorm:"column:period;NOT NULL"`
+	RecordingSeconds int64     `gorm:"column:recording_seconds;default:0;NOT NULL"`
+	Created          time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified         time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *TenantUsage) TableName() string {
+	return config.FormatDBTable("tenant_usage")
+}
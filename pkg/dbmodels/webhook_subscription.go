@@ -0,0 +1,55 @@
+package dbmodels
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// WebhookSubscriptionFormatBBB is the format of the subscriptions created using the BBB hooks API,
+// the events will be sent in the payload format of the BBB webhooks
+const WebhookSubscriptionFormatBBB = "bbb"
+
+type WebhookSubscription struct {
+	ID             uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	SubscriptionID string `gorm:"column:subscription_id;unique;NOT NULL"`
+	// TenantID is set for the subscriptions created by the tenants using the BBB hooks API
+	TenantID string `gorm:"column:tenant_id;NOT NULL"`
+	// Format of the payload, empty means the webhook format of plugNmeet
+	Format string `gorm:"column:format;NOT NULL"`
+	// RoomID empty means subscription for all the rooms
+	RoomID string `gorm:"column:room_id;NOT NULL"`
+	Url    string `gorm:"column:url;NOT NULL"`
+	Secret string `gorm:"column:secret;NOT NULL"`
+	// Events is comma separated list of event names, empty means all events
+	Events   string    `gorm:"column:events;NOT NULL"`
+	Enabled  bool      `gorm:"column:enabled;default:1;NOT NULL"`
+	Created  time.Time `gorm:"column:created;autoCreateTime;NOT NULL"`
+	Modified time.Time `gorm:"column:modified;autoUpdateTime;NOT NULL"`
+}
+
+func (m *WebhookSubscription) TableName() string {
+	return config.FormatDBTable("webhook_subscriptions")
+}
+
+// EventList returns the list of subscribed events
+func (m *WebhookSubscription) EventList() []string {
+	if m.Events == "" {
+		return nil
+	}
+	return strings.Split(m.Events, ",")
+}
+
+// HasEvent returns true if the event should be sent to this subscription
+func (m *WebhookSubscription) HasEvent(event string) bool {
+	if m.Events == "" {
+		return true
+	}
+	for _, e := range strings.Split(m.Events, ",") {
+		if strings.EqualFold(e, event) {
+			return true
+		}
+	}
+	return false
+}
@@ -11,34 +11,40 @@ import (
 
 // ApplicationControllers holds all the controllers.
 type ApplicationControllers struct {
-	AnalyticsController    *controllers.AnalyticsController
-	AuthController         *controllers.AuthController
-	BBBController          *controllers.BBBController
-	BreakoutRoomController *controllers.BreakoutRoomController
-	EtherpadController     *controllers.EtherpadController
-	ExDisplayController    *controllers.ExDisplayController
-	ExMediaController      *controllers.ExMediaController
-	FileController         *controllers.FileController
-	IngressController      *controllers.IngressController
-	LtiV1Controller        *controllers.LtiV1Controller
-	PollsController        *controllers.PollsController
-	RecorderController     *controllers.RecorderController
-	RecordingController    *controllers.RecordingController
-	RoomController         *controllers.RoomController
-	SpeechToTextController *controllers.SpeechToTextController
-	UserController         *controllers.UserController
-	WaitingRoomController  *controllers.WaitingRoomController
-	WebhookController      *controllers.WebhookController
-	NatsController         *controllers.NatsController
-	HealthCheckController  *controllers.HealthCheckController
+	AnalyticsController     *controllers.AnalyticsController
+	AuthController          *controllers.AuthController
+	BBBController           *controllers.BBBController
+	BreakoutRoomController  *controllers.BreakoutRoomController
+	ChatController          *controllers.ChatController
+	DownloadAuditController *controllers.DownloadAuditController
+	EtherpadController      *controllers.EtherpadController
+	ExDisplayController     *controllers.ExDisplayController
+	ExMediaController       *controllers.ExMediaController
+	FileController          *controllers.FileController
+	IngressController       *controllers.IngressController
+	LtiV1Controller         *controllers.LtiV1Controller
+	PollsController         *controllers.PollsController
+	RecorderController      *controllers.RecorderController
+	RecordingController     *controllers.RecordingController
+	RoomController          *controllers.RoomController
+	ScheduleController      *controllers.ScheduleController
+	SpeechToTextController  *controllers.SpeechToTextController
+	TenantController        *controllers.TenantController
+	UserController          *controllers.UserController
+	WaitingRoomController   *controllers.WaitingRoomController
+	WebhookController       *controllers.WebhookController
+	NatsController          *controllers.NatsController
+	HealthCheckController   *controllers.HealthCheckController
 }
 
 // Application is the root struct holding all dependencies.
 type Application struct {
-	JanitorModel *models.JanitorModel
-	Controllers  *ApplicationControllers
-	AppConfig    *config.AppConfig
-	Ctx          context.Context
+	JanitorModel  *models.JanitorModel
+	FileModel     *models.FileModel
+	LtiGradeModel *models.LtiGradeModel
+	Controllers   *ApplicationControllers
+	AppConfig     *config.AppConfig
+	Ctx           context.Context
 }
 
 func (a *Application) Boot() {
@@ -52,8 +58,14 @@ func (a *Application) Boot() {
 	wg.Wait()
 	// start scheduler
 	go a.JanitorModel.StartJanitor()
+	// start converting the queued whiteboard files
+	a.FileModel.StartConversionWorker()
+	// start sending the attendance grades to the LMS
+	a.LtiGradeModel.StartGradePassbackWorker()
 }
 
 func (a *Application) Shutdown() {
 	a.JanitorModel.Shutdown()
+	a.FileModel.StopConversionWorker()
+	a.LtiGradeModel.StopGradePassbackWorker()
 }
@@ -11,10 +11,13 @@ import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/controllers"
 	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/converter"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/livekit"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/lti"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 )
 
 // build the dependency set for services
@@ -23,6 +26,9 @@ var serviceSet = wire.NewSet(
 	redisservice.New,
 	natsservice.New,
 	livekitservice.New,
+	storageservice.New,
+	converterservice.New,
+	ltiservice.New,
 )
 
 // build the dependency set for helpers
@@ -43,21 +49,26 @@ var modelSet = wire.NewSet(
 	models.NewAnalyticsModel,
 	models.NewAuthModel,
 	models.NewBBBApiWrapperModel,
+	models.NewChatArchiveModel,
 	models.NewRoomDurationModel,
+	models.NewDownloadAuditModel,
 	models.NewEtherpadModel,
 	models.NewExDisplayModel,
 	models.NewExMediaModel,
 	models.NewFileModel,
 	models.NewIngressModel,
 	models.NewLtiV1Model,
+	models.NewLtiGradeModel,
 	models.NewNatsModel,
 	models.NewPollModel,
 	models.NewRecorderModel,
 	models.NewRecordingModel,
 	models.NewRoomModel,
+	models.NewScheduleModel,
 	provideBreakoutRoomModel,
 	models.NewJanitorModel,
 	models.NewSpeechToTextModel,
+	models.NewTenantModel,
 	models.NewUserModel,
 	models.NewWaitingRoomModel,
 	models.NewWebhookModel,
@@ -69,6 +80,8 @@ var controllerSet = wire.NewSet(
 	controllers.NewAuthController,
 	controllers.NewBBBController,
 	controllers.NewBreakoutRoomController,
+	controllers.NewChatController,
+	controllers.NewDownloadAuditController,
 	controllers.NewHealthCheckController,
 	controllers.NewEtherpadController,
 	controllers.NewExDisplayController,
@@ -80,7 +93,9 @@ var controllerSet = wire.NewSet(
 	controllers.NewRecorderController,
 	controllers.NewRecordingController,
 	controllers.NewRoomController,
+	controllers.NewScheduleController,
 	controllers.NewSpeechToTextController,
+	controllers.NewTenantController,
 	controllers.NewUserController,
 	controllers.NewWaitingRoomController,
 	controllers.NewWebhookController,
@@ -13,10 +13,13 @@ import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/controllers"
 	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
 	"github.com/mynaparrot/plugnmeet-server/pkg/models"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/converter"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/livekit"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/lti"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 )
 
 // Injectors from wire.go:
@@ -30,75 +33,102 @@ func NewAppFactory(ctx context.Context, appConfig *config.AppConfig) (*Applicati
 	redisService := redisservice.New(ctx, client, logger)
 	natsService := natsservice.New(ctx, appConfig, logger)
 	livekitService := livekitservice.New(ctx, appConfig, logger)
+	storageService, err := storageservice.New(ctx, appConfig, logger)
+	if err != nil {
+		return nil, err
+	}
 	webhookNotifier := helpers.GetWebhookNotifier(ctx, appConfig, databaseService, natsService, logger)
-	analyticsModel := models.NewAnalyticsModel(ctx, appConfig, databaseService, redisService, natsService, webhookNotifier, logger)
-	userModel := models.NewUserModel(appConfig, databaseService, redisService, livekitService, natsService, analyticsModel, logger)
-	recorderModel := models.NewRecorderModel(appConfig, databaseService, redisService, natsService, userModel, logger)
-	fileModel := models.NewFileModel(ctx, appConfig, databaseService, natsService, logger)
+	ltiService, err := ltiservice.New(appConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	ltiGradeModel := models.NewLtiGradeModel(ctx, appConfig, databaseService, redisService, natsService, ltiService, logger)
+	analyticsModel := models.NewAnalyticsModel(ctx, appConfig, databaseService, redisService, natsService, webhookNotifier, storageService, ltiGradeModel, logger)
+	tenantModel := models.NewTenantModel(appConfig, databaseService, logger)
+	userModel := models.NewUserModel(appConfig, databaseService, redisService, livekitService, natsService, analyticsModel, tenantModel, logger)
+	recorderModel := models.NewRecorderModel(ctx, appConfig, databaseService, redisService, natsService, userModel, tenantModel, logger)
+	converterService, err := converterservice.New(appConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	fileModel := models.NewFileModel(ctx, appConfig, databaseService, natsService, storageService, converterService, logger)
 	roomDurationModel := models.NewRoomDurationModel(appConfig, redisService, natsService, logger)
 	etherpadModel := models.NewEtherpadModel(ctx, appConfig, databaseService, redisService, natsService, analyticsModel, logger)
 	pollModel := models.NewPollModel(appConfig, databaseService, redisService, natsService, analyticsModel, logger)
 	speechToTextModel := models.NewSpeechToTextModel(appConfig, databaseService, redisService, natsService, analyticsModel, webhookNotifier, logger)
-	roomModel := models.NewRoomModel(ctx, appConfig, databaseService, redisService, livekitService, natsService, webhookNotifier, userModel, recorderModel, fileModel, roomDurationModel, etherpadModel, pollModel, speechToTextModel, analyticsModel, logger)
-	janitorModel := models.NewJanitorModel(ctx, appConfig, databaseService, redisService, natsService, livekitService, roomModel, roomDurationModel, logger)
-	analyticsController := controllers.NewAnalyticsController(analyticsModel)
+	chatArchiveModel := models.NewChatArchiveModel(appConfig, databaseService, natsService, logger)
+	roomModel := models.NewRoomModel(ctx, appConfig, databaseService, redisService, livekitService, natsService, webhookNotifier, userModel, recorderModel, fileModel, roomDurationModel, etherpadModel, pollModel, speechToTextModel, analyticsModel, tenantModel, chatArchiveModel, logger)
+	scheduleModel := models.NewScheduleModel(appConfig, databaseService, roomModel, logger)
+	recordingModel := models.NewRecordingModel(appConfig, databaseService, redisService, natsService, analyticsModel, webhookNotifier, storageService, tenantModel, logger)
+	janitorModel := models.NewJanitorModel(ctx, appConfig, databaseService, redisService, natsService, livekitService, storageService, roomModel, scheduleModel, pollModel, recordingModel, roomDurationModel, logger)
+	downloadAuditModel := models.NewDownloadAuditModel(appConfig, databaseService, logger)
+	analyticsController := controllers.NewAnalyticsController(analyticsModel, tenantModel, downloadAuditModel, storageService)
 	authModel := models.NewAuthModel(appConfig, natsService, logger)
-	authController := controllers.NewAuthController(appConfig, natsService, authModel, roomModel)
-	recordingModel := models.NewRecordingModel(appConfig, databaseService, redisService, natsService, analyticsModel, webhookNotifier, logger)
-	bbbApiWrapperModel := models.NewBBBApiWrapperModel(appConfig, databaseService, redisService, recordingModel, logger)
-	bbbController := controllers.NewBBBController(appConfig, roomModel, userModel, bbbApiWrapperModel, recordingModel, natsService)
+	authController := controllers.NewAuthController(appConfig, natsService, authModel, roomModel, tenantModel)
+	bbbApiWrapperModel := models.NewBBBApiWrapperModel(appConfig, databaseService, redisService, recordingModel, fileModel, tenantModel, logger)
+	bbbController := controllers.NewBBBController(appConfig, roomModel, userModel, bbbApiWrapperModel, recordingModel, tenantModel, natsService)
 	breakoutRoomModel := provideBreakoutRoomModel(roomModel, natsService)
 	breakoutRoomController := controllers.NewBreakoutRoomController(breakoutRoomModel)
+	chatController := controllers.NewChatController(chatArchiveModel)
+	downloadAuditController := controllers.NewDownloadAuditController(downloadAuditModel)
 	etherpadController := controllers.NewEtherpadController(appConfig, etherpadModel, roomModel, databaseService)
 	exDisplayModel := models.NewExDisplayModel(appConfig, databaseService, redisService, natsService, analyticsModel, logger)
 	exDisplayController := controllers.NewExDisplayController(exDisplayModel)
 	exMediaModel := models.NewExMediaModel(appConfig, databaseService, redisService, natsService, analyticsModel, logger)
 	exMediaController := controllers.NewExMediaController(exMediaModel)
-	fileController := controllers.NewFileController(appConfig, fileModel, logger)
+	fileController := controllers.NewFileController(appConfig, fileModel, downloadAuditModel, storageService, logger)
 	ingressModel := models.NewIngressModel(appConfig, databaseService, redisService, livekitService, natsService, analyticsModel, logger)
 	ingressController := controllers.NewIngressController(ingressModel)
-	ltiV1Model := models.NewLtiV1Model(appConfig, roomModel, userModel)
+	ltiV1Model := models.NewLtiV1Model(appConfig, databaseService, redisService, ltiService, ltiGradeModel, roomModel, userModel)
 	ltiV1Controller := controllers.NewLtiV1Controller(ltiV1Model, roomModel, recordingModel)
 	pollsController := controllers.NewPollsController(pollModel, redisService)
-	recorderController := controllers.NewRecorderController(appConfig, databaseService, recorderModel, recordingModel, roomModel, logger)
-	recordingController := controllers.NewRecordingController(recordingModel)
-	roomController := controllers.NewRoomController(roomModel)
+	recorderController := controllers.NewRecorderController(appConfig, databaseService, recorderModel, recordingModel, roomModel, tenantModel, logger)
+	recordingController := controllers.NewRecordingController(recordingModel, tenantModel, downloadAuditModel, storageService)
+	roomController := controllers.NewRoomController(roomModel, tenantModel)
+	scheduleController := controllers.NewScheduleController(scheduleModel)
 	speechToTextController := controllers.NewSpeechToTextController(speechToTextModel)
+	tenantController := controllers.NewTenantController(tenantModel)
 	userController := controllers.NewUserController(appConfig, databaseService, natsService, userModel)
 	waitingRoomModel := models.NewWaitingRoomModel(appConfig, redisService, natsService, logger)
 	waitingRoomController := controllers.NewWaitingRoomController(waitingRoomModel)
-	natsModel := models.NewNatsModel(appConfig, databaseService, redisService, natsService, livekitService, analyticsModel, authModel, userModel, logger)
-	webhookModel := models.NewWebhookModel(ctx, appConfig, databaseService, redisService, natsService, livekitService, roomModel, analyticsModel, roomDurationModel, breakoutRoomModel, natsModel, speechToTextModel, webhookNotifier, logger)
+	natsModel := models.NewNatsModel(appConfig, databaseService, redisService, natsService, livekitService, analyticsModel, authModel, userModel, recorderModel, logger)
+	webhookModel := models.NewWebhookModel(ctx, appConfig, databaseService, redisService, natsService, livekitService, roomModel, analyticsModel, roomDurationModel, breakoutRoomModel, natsModel, speechToTextModel, recorderModel, webhookNotifier, logger)
 	webhookController := controllers.NewWebhookController(authModel, webhookModel)
 	natsController := controllers.NewNatsController(appConfig, natsService, authModel, natsModel, logger)
 	healthCheckController := controllers.NewHealthCheckController(appConfig)
 	applicationControllers := &ApplicationControllers{
-		AnalyticsController:    analyticsController,
-		AuthController:         authController,
-		BBBController:          bbbController,
-		BreakoutRoomController: breakoutRoomController,
-		EtherpadController:     etherpadController,
-		ExDisplayController:    exDisplayController,
-		ExMediaController:      exMediaController,
-		FileController:         fileController,
-		IngressController:      ingressController,
-		LtiV1Controller:        ltiV1Controller,
-		PollsController:        pollsController,
-		RecorderController:     recorderController,
-		RecordingController:    recordingController,
-		RoomController:         roomController,
-		SpeechToTextController: speechToTextController,
-		UserController:         userController,
-		WaitingRoomController:  waitingRoomController,
-		WebhookController:      webhookController,
-		NatsController:         natsController,
-		HealthCheckController:  healthCheckController,
+		AnalyticsController:     analyticsController,
+		AuthController:          authController,
+		BBBController:           bbbController,
+		BreakoutRoomController:  breakoutRoomController,
+		ChatController:          chatController,
+		DownloadAuditController: downloadAuditController,
+		EtherpadController:      etherpadController,
+		ExDisplayController:     exDisplayController,
+		ExMediaController:       exMediaController,
+		FileController:          fileController,
+		IngressController:       ingressController,
+		LtiV1Controller:         ltiV1Controller,
+		PollsController:         pollsController,
+		RecorderController:      recorderController,
+		RecordingController:     recordingController,
+		RoomController:          roomController,
+		ScheduleController:      scheduleController,
+		SpeechToTextController:  speechToTextController,
+		TenantController:        tenantController,
+		UserController:          userController,
+		WaitingRoomController:   waitingRoomController,
+		WebhookController:       webhookController,
+		NatsController:          natsController,
+		HealthCheckController:   healthCheckController,
 	}
 	application := &Application{
-		JanitorModel: janitorModel,
-		Controllers:  applicationControllers,
-		AppConfig:    appConfig,
-		Ctx:          ctx,
+		JanitorModel:  janitorModel,
+		FileModel:     fileModel,
+		LtiGradeModel: ltiGradeModel,
+		Controllers:   applicationControllers,
+		AppConfig:     appConfig,
+		Ctx:           ctx,
 	}
 	return application, nil
 }
@@ -106,7 +136,7 @@ func NewAppFactory(ctx context.Context, appConfig *config.AppConfig) (*Applicati
 // wire.go:
 
 // build the dependency set for services
-var serviceSet = wire.NewSet(dbservice.New, redisservice.New, natsservice.New, livekitservice.New)
+var serviceSet = wire.NewSet(dbservice.New, redisservice.New, natsservice.New, livekitservice.New, storageservice.New, converterservice.New, ltiservice.New)
 
 // build the dependency set for helpers
 var helperSet = wire.NewSet(helpers.GetWebhookNotifier)
@@ -120,7 +150,7 @@ func provideBreakoutRoomModel(rm *models.RoomModel, natsService *natsservice.Nat
 }
 
 // build the dependency set for models
-var modelSet = wire.NewSet(models.NewAnalyticsModel, models.NewAuthModel, models.NewBBBApiWrapperModel, models.NewRoomDurationModel, models.NewEtherpadModel, models.NewExDisplayModel, models.NewExMediaModel, models.NewFileModel, models.NewIngressModel, models.NewLtiV1Model, models.NewNatsModel, models.NewPollModel, models.NewRecorderModel, models.NewRecordingModel, models.NewRoomModel, provideBreakoutRoomModel, models.NewJanitorModel, models.NewSpeechToTextModel, models.NewUserModel, models.NewWaitingRoomModel, models.NewWebhookModel)
+var modelSet = wire.NewSet(models.NewAnalyticsModel, models.NewAuthModel, models.NewBBBApiWrapperModel, models.NewChatArchiveModel, models.NewRoomDurationModel, models.NewDownloadAuditModel, models.NewEtherpadModel, models.NewExDisplayModel, models.NewExMediaModel, models.NewFileModel, models.NewIngressModel, models.NewLtiV1Model, models.NewLtiGradeModel, models.NewNatsModel, models.NewPollModel, models.NewRecorderModel, models.NewRecordingModel, models.NewRoomModel, models.NewScheduleModel, provideBreakoutRoomModel, models.NewJanitorModel, models.NewSpeechToTextModel, models.NewTenantModel, models.NewUserModel, models.NewWaitingRoomModel, models.NewWebhookModel)
 
 // build the dependency set for controllers
-var controllerSet = wire.NewSet(controllers.NewAnalyticsController, controllers.NewAuthController, controllers.NewBBBController, controllers.NewBreakoutRoomController, controllers.NewHealthCheckController, controllers.NewEtherpadController, controllers.NewExDisplayController, controllers.NewExMediaController, controllers.NewFileController, controllers.NewIngressController, controllers.NewLtiV1Controller, controllers.NewPollsController, controllers.NewRecorderController, controllers.NewRecordingController, controllers.NewRoomController, controllers.NewSpeechToTextController, controllers.NewUserController, controllers.NewWaitingRoomController, controllers.NewWebhookController, controllers.NewNatsController)
+var controllerSet = wire.NewSet(controllers.NewAnalyticsController, controllers.NewAuthController, controllers.NewBBBController, controllers.NewBreakoutRoomController, controllers.NewChatController, controllers.NewDownloadAuditController, controllers.NewHealthCheckController, controllers.NewEtherpadController, controllers.NewExDisplayController, controllers.NewExMediaController, controllers.NewFileController, controllers.NewIngressController, controllers.NewLtiV1Controller, controllers.NewPollsController, controllers.NewRecorderController, controllers.NewRecordingController, controllers.NewRoomController, controllers.NewScheduleController, controllers.NewSpeechToTextController, controllers.NewTenantController, controllers.NewUserController, controllers.NewWaitingRoomController, controllers.NewWebhookController, controllers.NewNatsController)
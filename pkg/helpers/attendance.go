@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"sort"
+)
+
+// AttendanceDuration returns the total time the user was in the room between start & end.
+// All the values are unix milli. A join while the user is already in the room
+// (e.g. from another device) will be ignored & the user who didn't leave stays until the end.
+func AttendanceDuration(joins, leaves []int64, start, end int64) int64 {
+	if end <= start || len(joins) == 0 {
+		return 0
+	}
+
+	type event struct {
+		at   int64
+		join bool
+	}
+	events := make([]event, 0, len(joins)+len(leaves))
+	for _, t := range joins {
+		events = append(events, event{at: t, join: true})
+	}
+	for _, t := range leaves {
+		events = append(events, event{at: t})
+	}
+	// on the same time leave first, so that reconnects won't be counted twice
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].at == events[j].at {
+			return !events[i].join && events[j].join
+		}
+		return events[i].at < events[j].at
+	})
+
+	clamp := func(t int64) int64 {
+		return min(max(t, start), end)
+	}
+
+	var total, joinedAt int64
+	inRoom := false
+	for _, e := range events {
+		switch {
+		case e.join && !inRoom:
+			inRoom = true
+			joinedAt = clamp(e.at)
+		case !e.join && inRoom:
+			inRoom = false
+			total += clamp(e.at) - joinedAt
+		}
+	}
+	if inRoom {
+		total += end - joinedAt
+	}
+
+	return total
+}
+
+// AttendanceScore returns the attended part of the duration between 0 & 1
+func AttendanceScore(attended, duration int64) float64 {
+	if duration <= 0 || attended <= 0 {
+		return 0
+	}
+	if attended >= duration {
+		return 1
+	}
+	return float64(attended) / float64(duration)
+}
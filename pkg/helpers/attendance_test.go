@@ -0,0 +1,40 @@
+package helpers
+
+import "testing"
+
+func TestAttendanceDuration(t *testing.T) {
+	tests := []struct {
+		name          string
+		joins, leaves []int64
+		want          int64
+	}{
+		{name: "never joined", want: 0},
+		{name: "single session", joins: []int64{100}, leaves: []int64{400}, want: 300},
+		{name: "did not leave", joins: []int64{600}, want: 400},
+		{name: "reconnected", joins: []int64{100, 500}, leaves: []int64{300, 700}, want: 400},
+		{name: "same time reconnect", joins: []int64{100, 300}, leaves: []int64{300, 500}, want: 400},
+		{name: "joined from two devices", joins: []int64{100, 200}, leaves: []int64{400, 600}, want: 300},
+		{name: "outside of the room time", joins: []int64{-100}, leaves: []int64{1200}, want: 1000},
+		{name: "leave without join", joins: []int64{500}, leaves: []int64{200, 800}, want: 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AttendanceDuration(tt.joins, tt.leaves, 0, 1000); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttendanceScore(t *testing.T) {
+	if got := AttendanceScore(300, 1200); got != 0.25 {
+		t.Errorf("got %v, want 0.25", got)
+	}
+	if got := AttendanceScore(1500, 1200); got != 1 {
+		t.Errorf("got %v, want 1", got)
+	}
+	if got := AttendanceScore(100, 0); got != 0 {
+		t.Errorf("got %v, want 0", got)
+	}
+}
@@ -0,0 +1,22 @@
+package helpers
+
+import "time"
+
+// RetryBackoff returns the exponential delay after the number of consecutive failures,
+// which will be doubled for every failure up to max
+func RetryBackoff(initial, max time.Duration, failures int) time.Duration {
+	if failures < 1 {
+		return 0
+	}
+	d := initial
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= max || d <= 0 {
+			return max
+		}
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	initial := 5 * time.Second
+	max := 10 * time.Minute
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+		{8, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := RetryBackoff(initial, max, tt.failures); got != tt.want {
+			t.Errorf("RetryBackoff(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestRetryBackoffOverflow(t *testing.T) {
+	max := time.Duration(1<<62 - 1)
+	if got := RetryBackoff(time.Hour, max, 1000); got != max {
+		t.Errorf("RetryBackoff overflow = %s, want %s", got, max)
+	}
+}
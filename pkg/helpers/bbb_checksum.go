@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+)
+
+// Checksum algorithms of the BBB API
+const (
+	BBBChecksumSha1   = "sha1"
+	BBBChecksumSha256 = "sha256"
+	BBBChecksumSha384 = "sha384"
+	BBBChecksumSha512 = "sha512"
+)
+
+// BBBChecksumAlgorithm detects the algorithm from the length of the hex encoded checksum,
+// empty will be returned for an unknown length
+func BBBChecksumAlgorithm(checksum string) string {
+	switch len(checksum) {
+	case sha1.Size * 2:
+		return BBBChecksumSha1
+	case sha256.Size * 2:
+		return BBBChecksumSha256
+	case sha512.Size384 * 2:
+		return BBBChecksumSha384
+	case sha512.Size * 2:
+		return BBBChecksumSha512
+	}
+	return ""
+}
+
+// BBBChecksum calculates the checksum of the request as hash(method + query + secret),
+// empty will be returned for an unknown algorithm
+func BBBChecksum(algorithm, secret, method, query string) string {
+	var h hash.Hash
+	switch algorithm {
+	case BBBChecksumSha1:
+		h = sha1.New()
+	case BBBChecksumSha256:
+		h = sha256.New()
+	case BBBChecksumSha384:
+		h = sha512.New384()
+	case BBBChecksumSha512:
+		h = sha512.New()
+	default:
+		return ""
+	}
+	h.Write([]byte(method + query + secret))
+	return hex.EncodeToString(h.Sum(nil))
+}
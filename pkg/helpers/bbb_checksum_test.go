@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/mynaparrot/plugnmeet-protocol/bbbapiwrapper"
+)
+
+func TestBBBChecksum(t *testing.T) {
+	method, query, secret := "create", "name=Test+Meeting&meetingID=abc123", "639259d4-9dd8-4b25-bf01-95f9567eaf4b"
+
+	// sha1 must stay the same as the protocol
+	if got, want := BBBChecksum(BBBChecksumSha1, secret, method, query), bbbapiwrapper.CalculateCheckSum(secret, method, query); got != want {
+		t.Errorf("sha1: got %s, want %s", got, want)
+	}
+
+	for _, algo := range []string{BBBChecksumSha1, BBBChecksumSha256, BBBChecksumSha384, BBBChecksumSha512} {
+		sum := BBBChecksum(algo, secret, method, query)
+		if got := BBBChecksumAlgorithm(sum); got != algo {
+			t.Errorf("%s: detected %q", algo, got)
+		}
+		if sum == BBBChecksum(algo, secret, "join", query) {
+			t.Errorf("%s: method should change the checksum", algo)
+		}
+	}
+
+	if BBBChecksum("md5", secret, method, query) != "" {
+		t.Error("unknown algorithm should return empty checksum")
+	}
+	if BBBChecksumAlgorithm("abc") != "" {
+		t.Error("unknown length should return empty algorithm")
+	}
+}
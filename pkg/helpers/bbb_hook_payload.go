@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// BBBHookEvents maps the event IDs of the BBB hooks to the webhook events of plugNmeet,
+// other IDs will be used as they are, so the events of plugNmeet can be used too
+var BBBHookEvents = map[string]string{
+	"meeting-created":           "room_created",
+	"meeting-ended":             "room_finished",
+	"user-joined":               "participant_joined",
+	"user-left":                 "participant_left",
+	"meeting-recording-started": "start_recording",
+	"meeting-recording-stopped": "end_recording",
+	"rap-publish-ended":         "recording_proceeded",
+}
+
+type bbbHookMessage struct {
+	Data *bbbHookData `json:"data"`
+}
+
+type bbbHookData struct {
+	Type       string                 `json:"type"`
+	Id         string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Event      struct {
+		Ts int64 `json:"ts"`
+	} `json:"event"`
+}
+
+// toBBBHookId returns the event ID of the BBB hooks for the webhook event of plugNmeet
+func toBBBHookId(event string) string {
+	for id, e := range BBBHookEvents {
+		if e == event {
+			return id
+		}
+	}
+	return event
+}
+
+// toBBBHookPayload converts the event to the payload format of the BBB webhooks,
+// which is a JSON array with a single message
+func toBBBHookPayload(event *plugnmeet.CommonNotifyEvent) ([]byte, error) {
+	msg := &bbbHookData{
+		Type: "event",
+		Id:   toBBBHookId(event.GetEvent()),
+		Attributes: map[string]interface{}{
+			"meeting": map[string]string{
+				"internal-meeting-id": event.GetRoom().GetSid(),
+				"external-meeting-id": event.GetRoom().GetRoomId(),
+			},
+		},
+	}
+	msg.Event.Ts = event.GetCreatedAt() * 1000
+
+	if p := event.GetParticipant(); p != nil {
+		role := "VIEWER"
+		meta := new(plugnmeet.UserMetadata)
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(p.GetMetadata()), meta); err == nil && meta.GetIsAdmin() {
+			role = "MODERATOR"
+		}
+		msg.Attributes["user"] = map[string]string{
+			"internal-user-id": p.GetSid(),
+			"external-user-id": p.GetIdentity(),
+			"name":             p.GetName(),
+			"role":             role,
+		}
+	}
+	if r := event.GetRecordingInfo(); r != nil {
+		msg.Attributes["record-id"] = r.GetRecordId()
+		msg.Attributes["success"] = true
+	}
+
+	return json.Marshal([]*bbbHookMessage{{Data: msg}})
+}
+
+// bbbHookRequestBody returns the form encoded body, the same as the BBB webhooks send
+func bbbHookRequestBody(payload []byte, createdAt int64) []byte {
+	v := url.Values{}
+	v.Set("event", string(payload))
+	v.Set("timestamp", strconv.FormatInt(createdAt*1000, 10))
+	return []byte(v.Encode())
+}
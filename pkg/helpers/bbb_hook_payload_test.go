@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/livekit/protocol/livekit"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+)
+
+func newTestNotifyEvent(event string) *plugnmeet.CommonNotifyEvent {
+	roomId, sid := "room01", "RM_sid01"
+	createdAt := int64(1700000000)
+	return &plugnmeet.CommonNotifyEvent{
+		Event:     &event,
+		Room:      &plugnmeet.NotifyEventRoom{RoomId: &roomId, Sid: &sid},
+		CreatedAt: &createdAt,
+	}
+}
+
+func decodeTestBBBHookPayload(t *testing.T, payload []byte) *bbbHookData {
+	var msgs []*bbbHookMessage
+	if err := json.Unmarshal(payload, &msgs); err != nil {
+		t.Fatalf("payload must be a JSON array: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Data == nil {
+		t.Fatalf("expected single message, got %s", payload)
+	}
+	return msgs[0].Data
+}
+
+func TestToBBBHookPayload(t *testing.T) {
+	payload, err := toBBBHookPayload(newTestNotifyEvent("room_created"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := decodeTestBBBHookPayload(t, payload)
+	if d.Type != "event" || d.Id != "meeting-created" || d.Event.Ts != 1700000000000 {
+		t.Errorf("unexpected message %+v", d)
+	}
+	meeting, _ := d.Attributes["meeting"].(map[string]interface{})
+	if meeting["internal-meeting-id"] != "RM_sid01" || meeting["external-meeting-id"] != "room01" {
+		t.Errorf("unexpected meeting %v", d.Attributes["meeting"])
+	}
+
+	ev := newTestNotifyEvent("participant_joined")
+	ev.Participant = &livekit.ParticipantInfo{
+		Sid:      "PA_sid01",
+		Identity: "user01",
+		Name:     "User 01",
+		Metadata: `{"isAdmin":true}`,
+	}
+	payload, err = toBBBHookPayload(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d = decodeTestBBBHookPayload(t, payload)
+	user, _ := d.Attributes["user"].(map[string]interface{})
+	if d.Id != "user-joined" || user["internal-user-id"] != "PA_sid01" || user["external-user-id"] != "user01" || user["name"] != "User 01" || user["role"] != "MODERATOR" {
+		t.Errorf("unexpected user %v", d.Attributes)
+	}
+
+	ev = newTestNotifyEvent("participant_left")
+	ev.Participant = &livekit.ParticipantInfo{Identity: "user02"}
+	payload, _ = toBBBHookPayload(ev)
+	user, _ = decodeTestBBBHookPayload(t, payload).Attributes["user"].(map[string]interface{})
+	if user["role"] != "VIEWER" {
+		t.Errorf("expected viewer, got %v", user["role"])
+	}
+
+	ev = newTestNotifyEvent("recording_proceeded")
+	ev.RecordingInfo = &plugnmeet.RecordingInfoEvent{RecordId: "rec01"}
+	payload, _ = toBBBHookPayload(ev)
+	d = decodeTestBBBHookPayload(t, payload)
+	if d.Id != "rap-publish-ended" || d.Attributes["record-id"] != "rec01" {
+		t.Errorf("unexpected recording message %+v", d)
+	}
+
+	// events of plugNmeet without BBB ID will be sent with their names
+	payload, _ = toBBBHookPayload(newTestNotifyEvent("speech_service_started"))
+	if d = decodeTestBBBHookPayload(t, payload); d.Id != "speech_service_started" {
+		t.Errorf("unexpected id %q", d.Id)
+	}
+}
+
+func TestBBBHookRequestBody(t *testing.T) {
+	payload := []byte(`[{"data":{"id":"meeting-created"}}]`)
+	v, err := url.ParseQuery(string(bbbHookRequestBody(payload, 1700000000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Get("event") != string(payload) || v.Get("timestamp") != "1700000000000" {
+		t.Errorf("unexpected body %v", v)
+	}
+}
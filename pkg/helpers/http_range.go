@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// ByteRange is a single range of a file to send
+type ByteRange struct {
+	Start  int64
+	Length int64
+}
+
+// ContentRange returns the value of Content-Range header for the file size
+func (r *ByteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.Start+r.Length-1, size)
+}
+
+// ParseByteRange parses the Range header for the file size.
+// It will return nil if the whole file should be sent, e.g. the header is empty, invalid
+// or has multiple ranges, which we don't support, so the range will be ignored as allowed by RFC 9110.
+// ErrRangeNotSatisfiable will be returned if the range is outside the file.
+func ParseByteRange(header string, size int64) (*ByteRange, error) {
+	unit, spec, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok || unit != "bytes" || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, nil
+	}
+
+	if first == "" {
+		// suffix range, e.g. bytes=-500 for the last 500 bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return nil, nil
+		}
+		if n == 0 || size == 0 {
+			return nil, ErrRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return &ByteRange{Start: size - n, Length: n}, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return nil, nil
+	}
+	if start >= size {
+		return nil, ErrRangeNotSatisfiable
+	}
+
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return nil, nil
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+
+	return &ByteRange{Start: start, Length: end - start + 1}, nil
+}
+
+// FileETag returns a weak ETag of the file based on its size & modification time
+func FileETag(size int64, modTime time.Time) string {
+	return fmt.Sprintf(`W/"%x-%x"`, size, modTime.Unix())
+}
+
+// CheckIfRange returns true if the range can be used according to the If-Range header.
+// The value of If-Range can be either an ETag or an HTTP date.
+func CheckIfRange(ifRange, etag string, modTime time.Time) bool {
+	ifRange = strings.TrimSpace(ifRange)
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		// weak comparison is enough, as our ETag changes with the size & time
+		return strings.TrimPrefix(ifRange, "W/") == strings.TrimPrefix(etag, "W/")
+	}
+
+	t, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+	return modTime.Truncate(time.Second).Equal(t)
+}
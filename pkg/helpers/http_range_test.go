@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseByteRange(t *testing.T) {
+	const size = 1000
+
+	tests := []struct {
+		header string
+		want   *ByteRange
+		err    error
+	}{
+		{"", nil, nil},
+		{"bytes=0-499", &ByteRange{Start: 0, Length: 500}, nil},
+		{"bytes=500-", &ByteRange{Start: 500, Length: 500}, nil},
+		{"bytes=900-2000", &ByteRange{Start: 900, Length: 100}, nil},
+		{"bytes=-100", &ByteRange{Start: 900, Length: 100}, nil},
+		{"bytes=-5000", &ByteRange{Start: 0, Length: 1000}, nil},
+		{"bytes=1000-", nil, ErrRangeNotSatisfiable},
+		{"bytes=-0", nil, ErrRangeNotSatisfiable},
+		// ignored
+		{"bytes=0-1,5-10", nil, nil},
+		{"items=0-10", nil, nil},
+		{"bytes=10-5", nil, nil},
+		{"bytes=abc-", nil, nil},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteRange(tt.header, size)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseByteRange(%q) error = %v, want %v", tt.header, err, tt.err)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("ParseByteRange(%q) = %+v, want %+v", tt.header, got, tt.want)
+		}
+	}
+
+	r := &ByteRange{Start: 900, Length: 100}
+	if cr := r.ContentRange(size); cr != "bytes 900-999/1000" {
+		t.Errorf("unexpected content range %s", cr)
+	}
+}
+
+func TestCheckIfRange(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 10, 0, 0, 500, time.UTC)
+	etag := FileETag(1000, modTime)
+
+	if !CheckIfRange("", etag, modTime) {
+		t.Error("empty If-Range should allow the range")
+	}
+	if !CheckIfRange(etag, etag, modTime) {
+		t.Error("same ETag should allow the range")
+	}
+	if CheckIfRange(FileETag(1001, modTime), etag, modTime) {
+		t.Error("different ETag should not allow the range")
+	}
+	if !CheckIfRange(modTime.Format(http.TimeFormat), etag, modTime) {
+		t.Error("same date should allow the range")
+	}
+	if CheckIfRange(modTime.Add(-time.Hour).Format(http.TimeFormat), etag, modTime) {
+		t.Error("different date should not allow the range")
+	}
+}
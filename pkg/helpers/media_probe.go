@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// MediaProbeInfo is the information of a media file extracted by ffprobe
+type MediaProbeInfo struct {
+	// Duration in seconds
+	Duration   float64
+	VideoCodec string
+	AudioCodec string
+	Width      int
+	Height     int
+}
+
+type ffprobeOutput struct {
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		Duration  string `json:"duration"`
+	} `json:"streams"`
+}
+
+// ParseFFprobeOutput parses the JSON output of ffprobe with -show_format & -show_streams.
+// Only the first video & audio streams will be used.
+func ParseFFprobeOutput(data []byte) (*MediaProbeInfo, error) {
+	out := new(ffprobeOutput)
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+
+	info := new(MediaProbeInfo)
+	// webm files written by a live recorder may not have the duration in the format,
+	// so we'll use the longest stream then
+	info.Duration, _ = strconv.ParseFloat(out.Format.Duration, 64)
+	for _, s := range out.Streams {
+		switch s.CodecType {
+		case "video":
+			if info.VideoCodec == "" {
+				info.VideoCodec = s.CodecName
+				info.Width = s.Width
+				info.Height = s.Height
+			}
+		case "audio":
+			if info.AudioCodec == "" {
+				info.AudioCodec = s.CodecName
+			}
+		default:
+			continue
+		}
+		if d, err := strconv.ParseFloat(s.Duration, 64); err == nil && d > info.Duration {
+			info.Duration = d
+		}
+	}
+
+	if info.VideoCodec == "" && info.AudioCodec == "" {
+		return nil, errors.New("no audio or video stream found")
+	}
+	info.Duration = ToFixed(info.Duration, 3)
+
+	return info, nil
+}
+
+// ThumbnailTimestamps returns the positions in seconds to take count thumbnails evenly,
+// without the very beginning & end, which are mostly blank in the recordings
+func ThumbnailTimestamps(duration float64, count int) []float64 {
+	if duration <= 0 || count <= 0 {
+		return nil
+	}
+	list := make([]float64, 0, count)
+	for i := 1; i <= count; i++ {
+		list = append(list, ToFixed(duration*float64(i)/float64(count+1), 3))
+	}
+	return list
+}
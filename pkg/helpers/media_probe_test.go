@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFFprobeOutput(t *testing.T) {
+	data := []byte(`{
+	"streams": [
+		{"index": 0, "codec_name": "h264", "codec_type": "video", "width": 1920, "height": 1080},
+		{"index": 1, "codec_name": "aac", "codec_type": "audio", "duration": "125.504000"},
+		{"index": 2, "codec_name": "vp8", "codec_type": "video", "width": 640, "height": 480}
+	],
+	"format": {"filename": "test.mp4", "duration": "125.4811"}
+}`)
+	info, err := ParseFFprobeOutput(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &MediaProbeInfo{Duration: 125.504, VideoCodec: "h264", AudioCodec: "aac", Width: 1920, Height: 1080}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("got %+v, want %+v", info, want)
+	}
+
+	if _, err = ParseFFprobeOutput([]byte(`{"streams": [{"codec_type": "data"}], "format": {}}`)); err == nil {
+		t.Error("expected error without audio or video stream")
+	}
+	if _, err = ParseFFprobeOutput([]byte(`invalid`)); err == nil {
+		t.Error("expected error for invalid output")
+	}
+}
+
+func TestThumbnailTimestamps(t *testing.T) {
+	if got := ThumbnailTimestamps(0, 3); got != nil {
+		t.Errorf("expected nil for zero duration, got %v", got)
+	}
+	got := ThumbnailTimestamps(100, 3)
+	want := []float64{25, 50, 75}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,221 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurrenceRule is a small subset of RFC 5545 RRULE
+// supported values: FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, COUNT, UNTIL, WKST & BYDAY (only with WEEKLY)
+// example: FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE;COUNT=10
+type RecurrenceRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    *time.Time
+	ByDay    []time.Weekday
+	// WeekStart is WKST, default monday as per RFC 5545
+	WeekStart time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// ParseRecurrenceRule will parse rule string
+// empty rule means no recurrence, in that case nil will return
+func ParseRecurrenceRule(rule string) (*RecurrenceRule, error) {
+	return ParseRecurrenceRuleInLocation(rule, time.UTC)
+}
+
+// ParseRecurrenceRuleInLocation is same as ParseRecurrenceRule,
+// but UNTIL without Z (local time) will be parsed in the given location
+func ParseRecurrenceRuleInLocation(rule string, loc *time.Location) (*RecurrenceRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, nil
+	}
+
+	r := &RecurrenceRule{
+		Interval:  1,
+		WeekStart: time.Monday,
+	}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid recurrence rule part: %s", part)
+		}
+		key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			if val != "DAILY" && val != "WEEKLY" && val != "MONTHLY" {
+				return nil, fmt.Errorf("unsupported recurrence frequency: %s", val)
+			}
+			r.Freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence interval: %s", val)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence count: %s", val)
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := parseUntil(val, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recurrence until: %s", val)
+			}
+			r.Until = &t
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, ok := weekdays[d]
+				if !ok {
+					return nil, fmt.Errorf("invalid recurrence day: %s", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "WKST":
+			wd, ok := weekdays[val]
+			if !ok {
+				return nil, fmt.Errorf("invalid recurrence week start: %s", val)
+			}
+			r.WeekStart = wd
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part: %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("recurrence rule requires FREQ")
+	}
+	if len(r.ByDay) > 0 && r.Freq != "WEEKLY" {
+		return nil, errors.New("BYDAY is supported only with FREQ=WEEKLY")
+	}
+
+	return r, nil
+}
+
+func parseUntil(val string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("20060102T150405Z", val, time.UTC); err == nil {
+		return t, nil
+	}
+	for _, l := range []string{"20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(l, val, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unknown format")
+}
+
+// NextOccurrence returns the first occurrence of the series starting at start
+// that is strictly after the given time. It returns false when the series has ended.
+// The occurrences keep the wall clock time of start in its location,
+// so start should be in the time zone of the schedule to handle DST correctly.
+func (r *RecurrenceRule) NextOccurrence(start, after time.Time) (time.Time, bool) {
+	if r == nil {
+		if start.After(after) {
+			return start, true
+		}
+		return time.Time{}, false
+	}
+
+	n := 0
+	var found time.Time
+	ok := false
+	r.iterate(start, func(t time.Time) bool {
+		n++
+		if r.Count > 0 && n > r.Count {
+			return false
+		}
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		if t.After(after) {
+			found, ok = t, true
+			return false
+		}
+		return true
+	})
+
+	return found, ok
+}
+
+// iterate walks through the candidate occurrences in ascending order
+// until fn returns false
+func (r *RecurrenceRule) iterate(start time.Time, fn func(t time.Time) bool) {
+	// we'll keep a hard limit to avoid endless loop for invalid data
+	const maxIterations = 100000
+
+	switch r.Freq {
+	case "DAILY":
+		for i := 0; i < maxIterations; i++ {
+			if !fn(start.AddDate(0, 0, i*r.Interval)) {
+				return
+			}
+		}
+	case "MONTHLY":
+		for i := 0; i < maxIterations; i++ {
+			t := start.AddDate(0, i*r.Interval, 0)
+			// skip months which do not have this day, e.g. 31st
+			if t.Day() != start.Day() {
+				continue
+			}
+			if !fn(t) {
+				return
+			}
+		}
+	case "WEEKLY":
+		if len(r.ByDay) == 0 {
+			for i := 0; i < maxIterations; i++ {
+				if !fn(start.AddDate(0, 0, i*7*r.Interval)) {
+					return
+				}
+			}
+			return
+		}
+		// beginning of the week (WKST) of the start date,
+		// it matters when INTERVAL is more than 1
+		weekStart := start.AddDate(0, 0, -((int(start.Weekday()) - int(r.WeekStart) + 7) % 7))
+		for i := 0; i < maxIterations; i++ {
+			ws := weekStart.AddDate(0, 0, i*7*r.Interval)
+			for offset := 0; offset < 7; offset++ {
+				if !r.hasDay(time.Weekday((int(r.WeekStart) + offset) % 7)) {
+					continue
+				}
+				t := ws.AddDate(0, 0, offset)
+				if t.Before(start) {
+					continue
+				}
+				if !fn(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *RecurrenceRule) hasDay(d time.Weekday) bool {
+	for _, wd := range r.ByDay {
+		if wd == d {
+			return true
+		}
+	}
+	return false
+}
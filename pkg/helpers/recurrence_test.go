@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	r, err := ParseRecurrenceRule("")
+	if err != nil || r != nil {
+		t.Errorf("empty rule should return nil without error, got %+v, %v", r, err)
+	}
+
+	invalid := []string{"FREQ=YEARLY", "INTERVAL=2", "FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;COUNT=0", "FREQ=WEEKLY;WKST=XX"}
+	for _, rule := range invalid {
+		if _, err := ParseRecurrenceRule(rule); err == nil {
+			t.Errorf("expected error for rule %s", rule)
+		}
+	}
+
+	r, err = ParseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20261231T000000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Freq != "WEEKLY" || r.Interval != 2 || len(r.ByDay) != 2 || r.Until == nil || r.WeekStart != time.Monday {
+		t.Errorf("unexpected parsed rule: %+v", r)
+	}
+
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	r, err = ParseRecurrenceRuleInLocation("FREQ=DAILY;UNTIL=20260301T090000;WKST=SU", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Until.Equal(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)) || r.WeekStart != time.Sunday {
+		t.Errorf("unexpected parsed rule: %+v", r)
+	}
+}
+
+func TestRecurrenceRule_NextOccurrence(t *testing.T) {
+	// Monday
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	r, _ := ParseRecurrenceRule("FREQ=DAILY;COUNT=3")
+	next, ok := r.NextOccurrence(start, start)
+	if !ok || !next.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("expected next day, got %v", next)
+	}
+	if _, ok = r.NextOccurrence(start, start.AddDate(0, 0, 2)); ok {
+		t.Error("series should end after 3 occurrences")
+	}
+
+	r, _ = ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO,WE")
+	next, ok = r.NextOccurrence(start, start)
+	if !ok || next.Weekday() != time.Wednesday || !next.Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("expected wednesday of the same week, got %v", next)
+	}
+
+	// sunday start with biweekly interval, the default WKST=MO
+	// puts sunday at the end of the first week
+	sunday := time.Date(2026, 1, 4, 9, 0, 0, 0, time.UTC)
+	r, _ = ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,MO")
+	next, ok = r.NextOccurrence(sunday, sunday)
+	if !ok || !next.Equal(sunday.AddDate(0, 0, 8)) {
+		t.Errorf("expected monday of the third week, got %v", next)
+	}
+	r, _ = ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,MO;WKST=SU")
+	next, ok = r.NextOccurrence(sunday, sunday)
+	if !ok || !next.Equal(sunday.AddDate(0, 0, 1)) {
+		t.Errorf("expected monday of the same week, got %v", next)
+	}
+
+	// the local time should be kept across DST
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	local := time.Date(2026, 3, 28, 9, 0, 0, 0, loc)
+	r, _ = ParseRecurrenceRule("FREQ=DAILY")
+	next, ok = r.NextOccurrence(local, local)
+	if !ok || next.Hour() != 9 || next.Sub(local) != 23*time.Hour {
+		t.Errorf("expected 9:00 local time after DST, got %v", next)
+	}
+
+	r, _ = ParseRecurrenceRule("FREQ=MONTHLY")
+	jan31 := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	next, ok = r.NextOccurrence(jan31, jan31)
+	if !ok || next.Month() != time.March || next.Day() != 31 {
+		t.Errorf("expected 31st march, got %v", next)
+	}
+
+	// without recurrence
+	var none *RecurrenceRule
+	next, ok = none.NextOccurrence(start, start.Add(-time.Minute))
+	if !ok || !next.Equal(start) {
+		t.Errorf("expected start time, got %v", next)
+	}
+	if _, ok = none.NextOccurrence(start, start); ok {
+		t.Error("single occurrence should not repeat")
+	}
+}
@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	// languageTagRegex is a simplified check of the BCP 47 language tags, e.g. en, en-US, zh-Hant-TW
+	languageTagRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+	// srtTimingRegex matches the timing lines of SRT, e.g. 00:00:01,600 --> 00:00:04,200
+	srtTimingRegex = regexp.MustCompile(`^(\d{2,}:\d{2}:\d{2}),(\d{3}) --> (\d{2,}:\d{2}:\d{2}),(\d{3})(.*)$`)
+)
+
+// IsValidLanguageTag returns true if the tag looks like a BCP 47 language tag
+func IsValidLanguageTag(tag string) bool {
+	return languageTagRegex.MatchString(tag)
+}
+
+// ToWebVTT returns the text track as WebVTT.
+// WebVTT will be returned as it is, after normalizing the line breaks & SRT will be converted.
+func ToWebVTT(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("empty text track")
+	}
+
+	if strings.HasPrefix(text, "WEBVTT") {
+		return []byte(text), nil
+	}
+
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	cues := 0
+	for _, line := range strings.Split(strings.TrimLeft(text, "\n"), "\n") {
+		if m := srtTimingRegex.FindStringSubmatch(line); m != nil {
+			line = m[1] + "." + m[2] + " --> " + m[3] + "." + m[4] + m[5]
+			cues++
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if cues == 0 {
+		return nil, errors.New("text track must be in WebVTT or SRT format")
+	}
+
+	return []byte(b.String()), nil
+}
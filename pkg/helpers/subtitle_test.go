@@ -0,0 +1,44 @@
+package helpers
+
+import "testing"
+
+func TestIsValidLanguageTag(t *testing.T) {
+	for _, tag := range []string{"en", "en-US", "pt-BR", "zh-Hant-TW", "fil"} {
+		if !IsValidLanguageTag(tag) {
+			t.Errorf("%q should be valid", tag)
+		}
+	}
+	for _, tag := range []string{"", "e", "english", "en_US", "en-", "../en"} {
+		if IsValidLanguageTag(tag) {
+			t.Errorf("%q should be invalid", tag)
+		}
+	}
+}
+
+func TestToWebVTT(t *testing.T) {
+	srt := "\xef\xbb\xbf1\r\n00:00:01,600 --> 00:00:04,200\r\nHello\r\n\r\n2\r\n00:00:05,000 --> 00:00:06,500\r\nWorld, again\r\n"
+	got, err := ToWebVTT([]byte(srt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "WEBVTT\n\n1\n00:00:01.600 --> 00:00:04.200\nHello\n\n2\n00:00:05.000 --> 00:00:06.500\nWorld, again\n\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	vtt := "WEBVTT\r\n\r\n00:01.000 --> 00:02.000\r\nHi\r\n"
+	got, err = ToWebVTT([]byte(vtt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want = "WEBVTT\n\n00:01.000 --> 00:02.000\nHi\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err = ToWebVTT([]byte(" \n")); err == nil {
+		t.Error("expected error for empty track")
+	}
+	if _, err = ToWebVTT([]byte("just some text")); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CleanExtractedText collapses all the whitespaces, including the line breaks & form feeds
+// written by the text extractors, into single spaces
+func CleanExtractedText(text string) string {
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}), " ")
+}
+
+// TextSnippet finds the query in the text case-insensitively & returns the match
+// with at most radius characters around it. false will be returned if the query wasn't found.
+func TextSnippet(text, query string, radius int) (string, bool) {
+	q := lowerRunes(strings.TrimSpace(query))
+	if len(q) == 0 {
+		return "", false
+	}
+	src := []rune(text)
+	lower := lowerRunes(text)
+
+	index := -1
+	for i := 0; i+len(q) <= len(lower); i++ {
+		if string(lower[i:i+len(q)]) == string(q) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return "", false
+	}
+
+	start := max(index-radius, 0)
+	end := min(index+len(q)+radius, len(src))
+	snippet := strings.TrimSpace(string(src[start:end]))
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(src) {
+		snippet += "…"
+	}
+	return snippet, true
+}
+
+// lowerRunes keeps the same number of runes as the input, so the indexes can be used for the source
+func lowerRunes(s string) []rune {
+	r := []rune(s)
+	for i := range r {
+		r[i] = unicode.ToLower(r[i])
+	}
+	return r
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import "testing"
+
+func TestCleanExtractedText(t *testing.T) {
+	got := CleanExtractedText("  Chapter 1\n\nIntro\tto  plugNmeet\f\n")
+	if want := "Chapter 1 Intro to plugNmeet"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got = CleanExtractedText("\n\f "); got != "" {
+		t.Errorf("expected empty text, got %q", got)
+	}
+}
+
+func TestTextSnippet(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog"
+
+	got, ok := TextSnippet(text, "FOX", 6)
+	if !ok {
+		t.Fatal("expected to find the query")
+	}
+	if want := "…brown fox jumps…"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, ok = TextSnippet(text, "the", 4)
+	if !ok || got != "The qui…" {
+		t.Errorf("got %q, %v", got, ok)
+	}
+
+	got, ok = TextSnippet("Größe über Ärger", "ÜBER", 100)
+	if !ok || got != "Größe über Ärger" {
+		t.Errorf("got %q, %v", got, ok)
+	}
+
+	if _, ok = TextSnippet(text, "cat", 10); ok {
+		t.Error("expected not to find the query")
+	}
+	if _, ok = TextSnippet(text, "  ", 10); ok {
+		t.Error("expected empty query to be ignored")
+	}
+}
@@ -0,0 +1,687 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/livekit/protocol/auth"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/metrics"
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	webhookAuthHeader = "Authorization"
+	// in various Apache modules will strip the Authorization header,
+	// so we'll use additional one
+	webhookHashTokenHeader = "Hash-Token"
+	// webhookProgressInterval is to extend the ack wait of the delivery
+	// while waiting for the backoff of the endpoint
+	webhookProgressInterval = 30 * time.Second
+	// webhookLaneSyncInterval is to check the lanes of the endpoints left by other servers
+	webhookLaneSyncInterval = time.Minute
+)
+
+var webhookHttpClient = &http.Client{
+	Timeout: config.WebhookDeliveryTimeout,
+}
+
+// WebhookDelivery is a single event to a single endpoint.
+// It stays in the delivery stream until it was delivered successfully
+// or moved to the dead-letter stream after the max attempts.
+type WebhookDelivery struct {
+	Id  string `json:"id"`
+	Url string `json:"url"`
+	// SubscriptionId will be empty for the urls from config or room info
+	SubscriptionId string `json:"subscription_id,omitempty"`
+	// Format is the format of the subscription, empty means the webhook format of plugNmeet
+	Format  string `json:"format,omitempty"`
+	Event   string `json:"event"`
+	RoomId  string `json:"room_id"`
+	RoomSid string `json:"room_sid"`
+	// Payload is the already encoded event, so that the same content will be sent in every attempt
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	CreatedAt      int64           `json:"created_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	FailedAt       int64           `json:"failed_at,omitempty"`
+}
+
+// FailedWebhookDelivery is a delivery from the dead-letter stream
+type FailedWebhookDelivery struct {
+	Seq uint64 `json:"seq"`
+	WebhookDelivery
+}
+
+// webhookTarget is an endpoint which should receive the event
+type webhookTarget struct {
+	url            string
+	subscriptionId string
+	format         string
+}
+
+// webhookEndpointState keeps track of consecutive failures of an endpoint,
+// it's shared by all the servers of the cluster using NATS KV
+type webhookEndpointState struct {
+	Failures int   `json:"failures"`
+	RetryAt  int64 `json:"retry_at"` // unix milli
+}
+
+// partitionKey keeps the order of the events of the room for each endpoint
+func (d *WebhookDelivery) partitionKey() string {
+	return d.RoomId + "|" + d.Url
+}
+
+// enqueueWebhookEvent will add one delivery for each of the targets to the delivery stream
+func (w *WebhookNotifier) enqueueWebhookEvent(event *plugnmeet.CommonNotifyEvent, targets []webhookTarget) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	// make sure the event name is lowercase
+	ev := strings.ToLower(event.GetEvent())
+	event.Event = &ev
+
+	now := time.Now().UTC()
+	if event.CreatedAt == nil {
+		createdAt := now.Unix()
+		event.CreatedAt = &createdAt
+	}
+	if event.Id == nil {
+		mId := uuid.NewString()
+		event.Id = &mId
+	}
+
+	op := protojson.MarshalOptions{
+		EmitUnpopulated: false,
+		UseProtoNames:   true,
+	}
+	payload, err := op.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var bbbPayload []byte
+
+	var errs []error
+	for _, t := range targets {
+		d := &WebhookDelivery{
+			Id:             uuid.NewString(),
+			Url:            t.url,
+			SubscriptionId: t.subscriptionId,
+			Format:         t.format,
+			Event:          ev,
+			RoomId:         event.GetRoom().GetRoomId(),
+			RoomSid:        event.GetRoom().GetSid(),
+			Payload:        payload,
+			CreatedAt:      now.Unix(),
+		}
+		if t.format == dbmodels.WebhookSubscriptionFormatBBB {
+			if bbbPayload == nil {
+				if bbbPayload, err = toBBBHookPayload(event); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+			d.Payload = bbbPayload
+		}
+		data, err := json.Marshal(d)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err = w.natsService.PublishWebhookDelivery(d.partitionKey(), data); err != nil {
+			w.logger.WithFields(logrus.Fields{
+				"url":    t.url,
+				"event":  ev,
+				"method": "enqueueWebhookEvent",
+			}).WithError(err).Errorln("failed to publish webhook delivery")
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// startDeliveryWorker will consume from the partitions of the shared delivery stream,
+// so deliveries will be continued by any server of the cluster even after restart.
+// Each partition will be handled one by one to keep the order of the events.
+// Deliveries of a failing endpoint will be moved to the lane of that endpoint,
+// so its backoff won't block the other endpoints of the same partition.
+func (w *WebhookNotifier) startDeliveryWorker() {
+	log := w.logger.WithField("method", "startDeliveryWorker")
+
+	consumers, err := w.natsService.CreateWebhookDeliveryStreams(w.app.Client.WebhookConf.DeadLetterMaxAge)
+	if err != nil {
+		log.WithError(err).Errorln("failed to create webhook delivery streams")
+		return
+	}
+
+	for _, cons := range consumers {
+		_, err = cons.Consume(w.handleDelivery, w.consumeOpts(log)...)
+		if err != nil {
+			log.WithError(err).Errorln("failed to consume webhook deliveries")
+		}
+	}
+
+	// lanes can be left by other servers, so we'll continue them too
+	w.syncLanes()
+	go w.runLaneSync()
+}
+
+func (w *WebhookNotifier) consumeOpts(log *logrus.Entry) []jetstream.PullConsumeOpt {
+	return []jetstream.PullConsumeOpt{
+		jetstream.PullMaxMessages(1),
+		jetstream.ConsumeErrHandler(func(consumeCtx jetstream.ConsumeContext, err error) {
+			if w.ctx.Err() == nil {
+				log.WithError(err).Warn("jetstream consume error")
+			}
+		}),
+	}
+}
+
+// handleDelivery handles the deliveries of the partitions
+func (w *WebhookNotifier) handleDelivery(msg jetstream.Msg) {
+	w.processDelivery(msg, false)
+}
+
+// handleLaneDelivery handles the deliveries which were moved to the lane of the endpoint
+func (w *WebhookNotifier) handleLaneDelivery(msg jetstream.Msg) {
+	w.processDelivery(msg, true)
+}
+
+func (w *WebhookNotifier) processDelivery(msg jetstream.Msg, inLane bool) {
+	d := new(WebhookDelivery)
+	if err := json.Unmarshal(msg.Data(), d); err != nil {
+		w.logger.WithError(err).Errorln("invalid webhook delivery, dropping")
+		metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookResultDropped).Inc()
+		_ = msg.Term()
+		return
+	}
+
+	// every redelivery is a new attempt, moved deliveries keep their previous attempts
+	if meta, err := msg.Metadata(); err == nil && meta.NumDelivered > 0 {
+		d.Attempts += int(meta.NumDelivered) - 1
+	}
+
+	// continue the trace of the one who queued this delivery
+	ctx := tracing.ExtractNatsHeader(w.ctx, msg.Headers())
+	natsService := w.natsService.WithContext(ctx)
+
+	log := w.logger.WithFields(logrus.Fields{
+		"deliveryId": d.Id,
+		"url":        d.Url,
+		"event":      d.Event,
+		"roomId":     d.RoomId,
+		"attempt":    d.Attempts + 1,
+		"inLane":     inLane,
+		"method":     "processDelivery",
+	})
+
+	if inLane {
+		// don't hit the endpoint which is still in backoff,
+		// only the lane of this endpoint will be waiting
+		if ok := w.waitForEndpoint(msg, d.Url); !ok {
+			return
+		}
+	} else if st, _, err := w.getEndpointState(d.Url); err != nil {
+		// we'll just log & try to deliver
+		log.WithError(err).Warnln("failed to get webhook endpoint state")
+	} else if st != nil {
+		// the endpoint has deliveries in its lane, this one must be sent after them
+		w.moveToLane(natsService, msg, d, log)
+		return
+	}
+	now := time.Now()
+
+	ctx, span := tracing.Tracer().Start(ctx, "webhook.deliver", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("pnm.webhook.event", d.Event),
+		attribute.Int("pnm.webhook.attempt", d.Attempts+1),
+		tracing.RoomIdKey.String(d.RoomId),
+		semconv.URLFull(d.Url),
+	))
+	defer span.End()
+
+	secret := w.app.Client.Secret
+	if d.SubscriptionId != "" {
+		// subscription can be changed or removed after the event was queued
+		sub, err := w.ds.GetWebhookSubscription(d.SubscriptionId)
+		if err != nil {
+			log.WithError(err).Errorln("failed to get webhook subscription")
+			_ = msg.NakWithDelay(w.app.Client.WebhookConf.InitialBackoff)
+			return
+		}
+		if sub == nil || !sub.Enabled || sub.Url != d.Url {
+			log.Infoln("webhook subscription was removed or changed, dropping delivery")
+			metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookResultDropped).Inc()
+			_ = msg.Ack()
+			return
+		}
+		if sub.Secret != "" {
+			secret = sub.Secret
+		}
+	}
+
+	statusCode, err := w.sendWebhookRequest(ctx, d, secret)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+	if err == nil {
+		if inLane {
+			w.releaseEndpoint(d.Url)
+		}
+		metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookResultSuccess).Inc()
+		_ = msg.Ack()
+		log.WithField("http_status_code", statusCode).Info("webhook sent successfully")
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	d.Attempts++
+	d.LastError = err.Error()
+	d.LastStatusCode = statusCode
+	retryAt := w.markEndpointFailed(d.Url, now)
+
+	if d.Attempts >= w.app.Client.WebhookConf.MaxAttempts {
+		d.FailedAt = now.Unix()
+		log.WithError(err).Errorln("failed to send webhook after max attempts, moving to dead-letter queue")
+		metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookResultDeadLetter).Inc()
+		if err = w.publishDelivery(d, natsService.PublishWebhookDeadLetter); err == nil {
+			_ = msg.Ack()
+			return
+		}
+		// keep the original one, so it will be tried again
+		log.WithError(err).Errorln("failed to publish webhook delivery to dead-letter queue")
+	} else {
+		log.WithError(err).WithField("retryAt", retryAt).Warnln("failed to send webhook, will retry")
+		metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookResultRetry).Inc()
+	}
+
+	if inLane {
+		// the same message will be redelivered, so the next deliveries
+		// of this endpoint will keep waiting & the order won't be changed
+		_ = msg.NakWithDelay(retryAt.Sub(now))
+		return
+	}
+	// the partition shouldn't wait for the backoff of this endpoint
+	w.moveToLane(natsService, msg, d, log)
+}
+
+// moveToLane will publish the delivery to the lane of the endpoint & acknowledge the original one.
+// The endpoint state will be updated after publishing, so releaseEndpoint won't remove it
+// while this delivery is still in the lane.
+func (w *WebhookNotifier) moveToLane(natsService *natsservice.NatsService, msg jetstream.Msg, d *WebhookDelivery, log *logrus.Entry) {
+	key := webhookEndpointKey(d.Url)
+	if err := w.publishDelivery(d, func(data []byte) error {
+		return natsService.PublishWebhookLaneDelivery(key, data)
+	}); err != nil {
+		log.WithError(err).Errorln("failed to move webhook delivery to the endpoint lane")
+		_ = msg.NakWithDelay(w.app.Client.WebhookConf.InitialBackoff)
+		return
+	}
+
+	w.touchEndpoint(d.Url)
+	w.consumeLane(key)
+	_ = msg.Ack()
+}
+
+func (w *WebhookNotifier) publishDelivery(d *WebhookDelivery, publish func([]byte) error) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return publish(data)
+}
+
+// waitForEndpoint will block until the backoff of the endpoint is over,
+// it returns false if the server is shutting down.
+func (w *WebhookNotifier) waitForEndpoint(msg jetstream.Msg, url string) bool {
+	for {
+		st, _, err := w.getEndpointState(url)
+		if err != nil {
+			// we'll just log & try to deliver
+			w.logger.WithError(err).WithField("url", url).Warnln("failed to get webhook endpoint state")
+			return true
+		}
+		if st == nil {
+			return true
+		}
+		wait := time.Until(time.UnixMilli(st.RetryAt))
+		if wait <= 0 {
+			return true
+		}
+		if wait > webhookProgressInterval {
+			wait = webhookProgressInterval
+		}
+
+		select {
+		case <-w.ctx.Done():
+			_ = msg.Nak()
+			return false
+		case <-time.After(wait):
+			// otherwise it will be redelivered after the ack wait
+			_ = msg.InProgress()
+		}
+	}
+}
+
+// consumeLane will start consuming the lane of the endpoint,
+// if this server isn't consuming it already
+func (w *WebhookNotifier) consumeLane(key string) {
+	w.lanesLock.Lock()
+	defer w.lanesLock.Unlock()
+	if _, ok := w.lanes[key]; ok {
+		return
+	}
+
+	log := w.logger.WithFields(logrus.Fields{
+		"lane":   key,
+		"method": "consumeLane",
+	})
+	cons, err := w.natsService.CreateWebhookLaneConsumer(key)
+	if err != nil {
+		log.WithError(err).Errorln("failed to create webhook lane consumer")
+		return
+	}
+	cc, err := cons.Consume(w.handleLaneDelivery, w.consumeOpts(log)...)
+	if err != nil {
+		log.WithError(err).Errorln("failed to consume webhook lane")
+		return
+	}
+	w.lanes[key] = cc
+}
+
+// syncLanes will consume the lanes which have deliveries & stop the empty ones,
+// unused consumers will be removed by NATS after being inactive
+func (w *WebhookNotifier) syncLanes() {
+	sizes, err := w.natsService.GetWebhookLaneSizes()
+	if err != nil {
+		w.logger.WithError(err).Warnln("failed to get webhook lane sizes")
+		return
+	}
+
+	w.lanesLock.Lock()
+	for key, cc := range w.lanes {
+		if sizes[key] == 0 {
+			cc.Stop()
+			delete(w.lanes, key)
+		}
+	}
+	w.lanesLock.Unlock()
+
+	for key := range sizes {
+		w.consumeLane(key)
+	}
+}
+
+func (w *WebhookNotifier) runLaneSync() {
+	ticker := time.NewTicker(webhookLaneSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.syncLanes()
+		}
+	}
+}
+
+// sendWebhookRequest sends a single delivery synchronously signed by the secret.
+// Any non 2xx response will be treated as failure.
+func (w *WebhookNotifier) sendWebhookRequest(ctx context.Context, d *WebhookDelivery, secret string) (int, error) {
+	body := []byte(d.Payload)
+	contentType := "application/webhook+json"
+	if d.Format == dbmodels.WebhookSubscriptionFormatBBB {
+		body = bbbHookRequestBody(d.Payload, d.CreatedAt)
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	// sign payload
+	sum := sha256.Sum256(body)
+	b64 := base64.StdEncoding.EncodeToString(sum[:])
+
+	at := auth.NewAccessToken(w.app.Client.ApiKey, secret).
+		SetValidFor(5 * time.Minute).
+		SetSha256(b64)
+	token, err := at.ToJWT()
+	if err != nil {
+		return 0, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	tracing.InjectHttpHeader(ctx, r.Header)
+	r.Header.Set(webhookAuthHeader, token)
+	r.Header.Set(webhookHashTokenHeader, token)
+	r.Header.Set("content-type", contentType)
+
+	res, err := webhookHttpClient.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// Read and discard the body to allow connection reuse
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, fmt.Errorf("unexpected http status code %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// webhookEndpointKey returns the NATS KV key of the url
+func webhookEndpointKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+func (w *WebhookNotifier) getEndpointState(url string) (*webhookEndpointState, uint64, error) {
+	data, revision, err := w.natsService.GetWebhookEndpointState(webhookEndpointKey(url))
+	if err != nil || data == nil {
+		return nil, 0, err
+	}
+
+	st := new(webhookEndpointState)
+	if err = json.Unmarshal(data, st); err != nil {
+		return nil, 0, err
+	}
+	return st, revision, nil
+}
+
+// markEndpointFailed will increase the failures of the endpoint & returns the time of the next attempt.
+// Other servers may update the same endpoint at the same time, so it will retry on conflict.
+func (w *WebhookNotifier) markEndpointFailed(url string, now time.Time) time.Time {
+	cnf := w.app.Client.WebhookConf
+	retryAt := now.Add(cnf.InitialBackoff)
+
+	if err := w.updateEndpoint(url, true, func(st *webhookEndpointState) {
+		st.Failures++
+		retryAt = now.Add(RetryBackoff(cnf.InitialBackoff, cnf.MaxBackoff, st.Failures))
+		st.RetryAt = retryAt.UnixMilli()
+	}); err != nil {
+		w.logger.WithError(err).WithField("url", url).Warnln("failed to update webhook endpoint state")
+	}
+
+	return retryAt
+}
+
+// touchEndpoint will create or update the state of the endpoint,
+// so the revision will be changed after moving a delivery to its lane
+func (w *WebhookNotifier) touchEndpoint(url string) {
+	if err := w.updateEndpoint(url, true, func(st *webhookEndpointState) {}); err != nil {
+		w.logger.WithError(err).WithField("url", url).Warnln("failed to update webhook endpoint state")
+	}
+}
+
+// clearEndpointBackoff will keep the state of the endpoint,
+// but the next delivery from its lane will be sent without waiting
+func (w *WebhookNotifier) clearEndpointBackoff(url string) {
+	if err := w.updateEndpoint(url, false, func(st *webhookEndpointState) {
+		st.Failures = 0
+		st.RetryAt = 0
+	}); err != nil {
+		w.logger.WithError(err).WithField("url", url).Warnln("failed to clear webhook endpoint backoff")
+	}
+}
+
+// updateEndpoint will update the state of the endpoint & retry on conflict.
+// The state will be created only if create is true.
+func (w *WebhookNotifier) updateEndpoint(url string, create bool, update func(st *webhookEndpointState)) error {
+	var err error
+	for i := 0; i < 3; i++ {
+		var st *webhookEndpointState
+		var revision uint64
+		st, revision, err = w.getEndpointState(url)
+		if err != nil {
+			return err
+		}
+		if st == nil {
+			if !create {
+				return nil
+			}
+			st = new(webhookEndpointState)
+		}
+		update(st)
+
+		var data []byte
+		if data, err = json.Marshal(st); err != nil {
+			return err
+		}
+		if err = w.natsService.UpdateWebhookEndpointState(webhookEndpointKey(url), data, revision); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// releaseEndpoint will be called after a successful delivery from the lane of the endpoint.
+// The state will be removed if this was the last delivery of the lane, so the next deliveries
+// will be sent from the partitions again. Otherwise, only the backoff will be cleared.
+func (w *WebhookNotifier) releaseEndpoint(url string) {
+	key := webhookEndpointKey(url)
+
+	var err error
+	for i := 0; i < 3; i++ {
+		var st *webhookEndpointState
+		var revision uint64
+		st, revision, err = w.getEndpointState(url)
+		if err != nil || st == nil {
+			break
+		}
+
+		var size uint64
+		if size, err = w.natsService.GetWebhookLaneSize(key); err != nil {
+			break
+		}
+		// the current delivery stays in the lane until it was acknowledged
+		if size <= 1 {
+			// it will fail if another delivery was moved to the lane in the meantime
+			if err = w.natsService.DeleteWebhookEndpointStateAt(key, revision); err == nil {
+				break
+			}
+			continue
+		}
+
+		st.Failures = 0
+		st.RetryAt = 0
+		var data []byte
+		if data, err = json.Marshal(st); err != nil {
+			break
+		}
+		if err = w.natsService.UpdateWebhookEndpointState(key, data, revision); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		w.logger.WithError(err).WithField("url", url).Warnln("failed to release webhook endpoint")
+	}
+}
+
+// GetFailedDeliveries returns deliveries from the dead-letter queue with total number of them
+func (w *WebhookNotifier) GetFailedDeliveries(from uint64, limit int) ([]*FailedWebhookDelivery, uint64, error) {
+	msgs, total, err := w.natsService.GetWebhookDeadLetters(from, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*FailedWebhookDelivery, 0, len(msgs))
+	for _, msg := range msgs {
+		fd := &FailedWebhookDelivery{
+			Seq: msg.Sequence,
+		}
+		if err := json.Unmarshal(msg.Data, &fd.WebhookDelivery); err != nil {
+			w.logger.WithError(err).WithField("seq", msg.Sequence).Warnln("invalid webhook dead letter")
+			continue
+		}
+		list = append(list, fd)
+	}
+
+	return list, total, nil
+}
+
+// ReplayFailedDelivery will put the delivery back to the delivery stream
+// with a fresh attempt counter & remove it from the dead-letter queue
+func (w *WebhookNotifier) ReplayFailedDelivery(seq uint64) error {
+	msg, err := w.natsService.GetWebhookDeadLetter(seq)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("failed delivery %d not found", seq)
+	}
+
+	d := new(WebhookDelivery)
+	if err = json.Unmarshal(msg.Data, d); err != nil {
+		return err
+	}
+	d.Attempts = 0
+	d.FailedAt = 0
+
+	// the operator expects it to be sent now, but if the endpoint has a lane
+	// it will be moved there to keep the order with the other deliveries
+	w.clearEndpointBackoff(d.Url)
+	if err = w.publishDelivery(d, func(data []byte) error {
+		return w.natsService.PublishWebhookDelivery(d.partitionKey(), data)
+	}); err != nil {
+		return err
+	}
+
+	return w.natsService.DeleteWebhookDeadLetter(seq)
+}
+
+// DeleteFailedDelivery will remove the delivery from the dead-letter queue
+func (w *WebhookNotifier) DeleteFailedDelivery(seq uint64) error {
+	msg, err := w.natsService.GetWebhookDeadLetter(seq)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("failed delivery %d not found", seq)
+	}
+
+	return w.natsService.DeleteWebhookDeadLetter(seq)
+}
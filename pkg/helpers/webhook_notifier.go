@@ -7,12 +7,11 @@ import (
 
 	"github.com/goccy/go-json"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
-	"github.com/mynaparrot/plugnmeet-protocol/webhook"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
-	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/sirupsen/logrus"
 )
 
@@ -25,11 +24,11 @@ type WebhookNotifier struct {
 	isEnabled            bool
 	enabledForPerMeeting bool
 	defaultUrl           string
-	// notifiers will hold a queue for each room, local to this server instance
-	notifiers map[string]*webhook.Notifier
-	// mu will protect access to the notifiers map
-	mu     sync.Mutex
-	logger *logrus.Entry
+	logger               *logrus.Entry
+
+	// lanes are the endpoint lanes which are consumed by this server
+	lanesLock sync.Mutex
+	lanes     map[string]jetstream.ConsumeContext
 }
 
 type webhookRedisFields struct {
@@ -46,67 +45,16 @@ func newWebhookNotifier(ctx context.Context, app *config.AppConfig, ds *dbservic
 		isEnabled:            app.Client.WebhookConf.Enable,
 		enabledForPerMeeting: app.Client.WebhookConf.EnableForPerMeeting,
 		defaultUrl:           app.Client.WebhookConf.Url,
-		notifiers:            make(map[string]*webhook.Notifier),
 		logger:               logger.WithField("helper", "webhookNotifier"),
+		lanes:                make(map[string]jetstream.ConsumeContext),
 	}
 
-	// Subscribe to the cleanup broadcast channel for clustered environments.
-	w.subscribeToCleanup()
-
-	return w
-}
-
-// subscribeToCleanup listens for cleanup messages broadcast to all servers.
-func (w *WebhookNotifier) subscribeToCleanup() {
-	_, err := w.app.NatsConn.Subscribe(natsservice.WebhookCleanupSubject, func(m *nats.Msg) {
-		roomId := string(m.Data) // Make a copy of the data
-		w.logger.WithFields(logrus.Fields{
-			"room_id": roomId,
-			"method":  "subscribeToCleanup",
-		}).Info("received webhook cleanup signal")
-		w.cleanupNotifier(roomId)
-	})
-	if err != nil {
-		w.logger.WithFields(logrus.Fields{
-			"method": "subscribeToCleanup",
-		}).WithError(err).Error("failed to subscribe to webhook cleanup subject")
+	if w.isEnabled {
+		// deliveries are kept in JetStream, so every server will work on the same queue
+		w.startDeliveryWorker()
 	}
-}
 
-// getOrCreateNotifier returns a dedicated notifier for a given room.
-// If one doesn't exist, it creates and stores it.
-func (w *WebhookNotifier) getOrCreateNotifier(roomId string) *webhook.Notifier {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-
-	if notifier, ok := w.notifiers[roomId]; ok {
-		return notifier
-	}
-
-	// Create a new notifier for this room
-	notifier := webhook.NewNotifier(w.ctx, config.DefaultWebhookQueueSize, w.logger.Logger)
-	w.notifiers[roomId] = notifier
-	w.logger.WithFields(logrus.Fields{
-		"room_id": roomId,
-		"method":  "getOrCreateNotifier",
-	}).Info("created new webhook queue for room")
-
-	return notifier
-}
-
-// cleanupNotifier stops the worker and removes the notifier for a room from the local map.
-func (w *WebhookNotifier) cleanupNotifier(roomId string) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
-
-	if notifier, ok := w.notifiers[roomId]; ok {
-		notifier.Kill() // This will call Kill() on the worker.
-		delete(w.notifiers, roomId)
-		w.logger.WithFields(logrus.Fields{
-			"room_id": roomId,
-			"method":  "cleanupNotifier",
-		}).Info("cleaned up webhook queue for room")
-	}
+	return w
 }
 
 func (w *WebhookNotifier) RegisterWebhook(roomId, sid string) {
@@ -178,16 +126,8 @@ func (w *WebhookNotifier) DeleteWebhook(roomId string) error {
 		return nil
 	}
 
-	// Broadcast a cleanup message to all servers in the cluster.
-	// Only the server running the worker for this room will act on it.
-	err = w.app.NatsConn.Publish(natsservice.WebhookCleanupSubject, []byte(roomId))
-	if err != nil {
-		w.logger.WithFields(logrus.Fields{
-			"room_id": roomId,
-			"method":  "DeleteWebhook",
-		}).WithError(err).Error("failed to publish webhook cleanup")
-	}
-
+	// pending deliveries are already in the delivery stream,
+	// so we only need to remove the urls of this room
 	return w.natsService.DeleteWebhookData(roomId)
 }
 
@@ -202,7 +142,8 @@ func (w *WebhookNotifier) SendWebhookEvent(event *plugnmeet.CommonNotifyEvent) e
 		return err
 	}
 	if d == nil {
-		return nil
+		// no urls were registered for this room, but may have subscriptions
+		return w.enqueueWebhookEvent(event, w.getTargets(roomId, event.Room.GetSid(), event.GetEvent(), nil))
 	}
 
 	// it may happen that the room was created again before we delete the queue
@@ -226,13 +167,10 @@ func (w *WebhookNotifier) SendWebhookEvent(event *plugnmeet.CommonNotifyEvent) e
 		}
 	}
 
-	// Use the dedicated notifier for this room
-	notifier := w.getOrCreateNotifier(roomId)
-	notifier.AddInNotifyQueue(event, w.app.Client.ApiKey, w.app.Client.Secret, d.Urls)
-	return nil
+	return w.enqueueWebhookEvent(event, w.getTargets(roomId, event.Room.GetSid(), event.GetEvent(), d.Urls))
 }
 
-// ForceToPutInQueue sends a webhook event synchronously without using the room's queue.
+// ForceToPutInQueue adds a webhook event to the delivery queue without using the room's webhook data.
 // This method should be used for one-shot events outside the normal room lifecycle.
 // It directly queries the database for webhook URLs.
 func (w *WebhookNotifier) ForceToPutInQueue(event *plugnmeet.CommonNotifyEvent) {
@@ -256,13 +194,53 @@ func (w *WebhookNotifier) ForceToPutInQueue(event *plugnmeet.CommonNotifyEvent)
 		}
 	}
 
-	if len(urls) < 1 {
+	targets := w.getTargets(event.Room.GetRoomId(), event.Room.GetSid(), event.GetEvent(), urls)
+	if len(targets) < 1 {
 		return
 	}
 
-	notifier := webhook.NewNotifier(w.ctx, config.DefaultWebhookQueueSize, w.logger.Logger)
-	defer notifier.StopGracefully()
-	notifier.AddInNotifyQueue(event, w.app.Client.ApiKey, w.app.Client.Secret, urls)
+	if err := w.enqueueWebhookEvent(event, targets); err != nil {
+		w.logger.WithError(err).Errorln("failed to enqueue", event.GetEvent())
+	}
+}
+
+// getTargets returns the urls with the subscriptions of the room which want this event.
+// The same room id can be used by different tenants,
+// so the subscriptions will be filtered by the tenant of the room.
+func (w *WebhookNotifier) getTargets(roomId, roomSid, event string, urls []string) []webhookTarget {
+	targets := make([]webhookTarget, 0, len(urls))
+	for _, u := range urls {
+		targets = append(targets, webhookTarget{url: u})
+	}
+
+	roomInfo, err := w.ds.GetRoomInfoBySid(roomSid, nil)
+	if err != nil {
+		// we'll just log, so that other urls will still receive
+		w.logger.WithError(err).WithField("roomSid", roomSid).Errorln("failed to get room info")
+		return targets
+	}
+	tenantId := ""
+	if roomInfo != nil {
+		tenantId = roomInfo.TenantID
+	}
+
+	subscriptions, err := w.ds.GetEnabledWebhookSubscriptionsForRoom(tenantId, roomId)
+	if err != nil {
+		// we'll just log, so that other urls will still receive
+		w.logger.WithError(err).WithField("roomId", roomId).Errorln("failed to get webhook subscriptions")
+		return targets
+	}
+	for _, sub := range subscriptions {
+		if sub.HasEvent(event) {
+			targets = append(targets, webhookTarget{
+				url:            sub.Url,
+				subscriptionId: sub.SubscriptionID,
+				format:         sub.Format,
+			})
+		}
+	}
+
+	return targets
 }
 
 func (w *WebhookNotifier) saveData(roomId string, d *webhookRedisFields) error {
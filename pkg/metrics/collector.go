@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+var registerOnce sync.Once
+
+// stateCollector collects the values during scrape,
+// so that we don't need to keep them updated all the time
+type stateCollector struct {
+	natsService   *natsservice.NatsService
+	jobQueueDepth func() int
+	jobQueueSize  int
+	logger        *logrus.Entry
+
+	activeRooms     *prometheus.Desc
+	onlineUsers     *prometheus.Desc
+	recorderCurrent *prometheus.Desc
+	recorderMax     *prometheus.Desc
+	jobQueue        *prometheus.Desc
+	jobQueueCap     *prometheus.Desc
+	webhookQueue    *prometheus.Desc
+}
+
+// RegisterStateCollector will register the collector of the rooms, users, recorders & queues.
+// It will be registered only once & only if prometheus is enabled.
+func RegisterStateCollector(app *config.AppConfig, natsService *natsservice.NatsService, jobQueueDepth func() int, jobQueueSize int, logger *logrus.Logger) {
+	if !app.Client.PrometheusConf.Enable {
+		return
+	}
+
+	registerOnce.Do(func() {
+		c := &stateCollector{
+			natsService:   natsService,
+			jobQueueDepth: jobQueueDepth,
+			jobQueueSize:  jobQueueSize,
+			logger:        logger.WithField("service", "metrics"),
+
+			activeRooms: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_rooms"),
+				"Number of active rooms in the NATS cache of this instance.", nil, nil),
+			onlineUsers: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "online_users"),
+				"Number of online users in the NATS cache of this instance.", nil, nil),
+			recorderCurrent: prometheus.NewDesc(prometheus.BuildFQName(namespace, "recorder", "current_progress"),
+				"Number of tasks currently running in the recorder.", []string{"recorder_id"}, nil),
+			recorderMax: prometheus.NewDesc(prometheus.BuildFQName(namespace, "recorder", "max_limit"),
+				"Maximum number of tasks the recorder can run.", []string{"recorder_id"}, nil),
+			jobQueue: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nats", "job_queue_depth"),
+				"Number of NATS jobs waiting for a worker.", nil, nil),
+			jobQueueCap: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nats", "job_queue_capacity"),
+				"Capacity of the NATS job queue.", nil, nil),
+			webhookQueue: prometheus.NewDesc(prometheus.BuildFQName(namespace, "webhook", "queue_size"),
+				"Number of webhook deliveries in the queue.", []string{"queue"}, nil),
+		}
+
+		if err := prometheus.Register(c); err != nil {
+			c.logger.WithError(err).Errorln("failed to register state collector")
+		}
+	})
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activeRooms
+	ch <- c.onlineUsers
+	ch <- c.recorderCurrent
+	ch <- c.recorderMax
+	ch <- c.jobQueue
+	ch <- c.jobQueueCap
+	ch <- c.webhookQueue
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	rooms, users := c.natsService.GetCacheStats()
+	ch <- prometheus.MustNewConstMetric(c.activeRooms, prometheus.GaugeValue, float64(rooms))
+	ch <- prometheus.MustNewConstMetric(c.onlineUsers, prometheus.GaugeValue, float64(users))
+
+	for _, r := range c.natsService.GetAllActiveRecorders() {
+		ch <- prometheus.MustNewConstMetric(c.recorderCurrent, prometheus.GaugeValue, float64(r.CurrentProgress), r.RecorderId)
+		ch <- prometheus.MustNewConstMetric(c.recorderMax, prometheus.GaugeValue, float64(r.MaxLimit), r.RecorderId)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.jobQueue, prometheus.GaugeValue, float64(c.jobQueueDepth()))
+	ch <- prometheus.MustNewConstMetric(c.jobQueueCap, prometheus.GaugeValue, float64(c.jobQueueSize))
+
+	pending, deadLetters, err := c.natsService.GetWebhookQueueSizes()
+	if err != nil {
+		c.logger.WithError(err).Warnln("failed to get webhook queue sizes")
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.webhookQueue, prometheus.GaugeValue, float64(pending), "pending")
+	ch <- prometheus.MustNewConstMetric(c.webhookQueue, prometheus.GaugeValue, float64(deadLetters), "dead_letter")
+}
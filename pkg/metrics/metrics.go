@@ -0,0 +1,94 @@
+// Package metrics keeps the business metrics of plugNmeet for prometheus.
+// All the metrics are registered with the default registry,
+// which is also used by fiberprometheus to expose them on the metrics path.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "pnm"
+
+var (
+	// NatsJobWaitDuration is the time a job waited in the queue before a worker picked it
+	NatsJobWaitDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "nats",
+		Name:      "job_wait_seconds",
+		Help:      "Time spent by NATS jobs in the queue before processing.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"type"})
+
+	// NatsJobDuration is the time taken by the handler of a job
+	NatsJobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "nats",
+		Name:      "job_duration_seconds",
+		Help:      "Time taken by the handlers of NATS jobs.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type"})
+
+	// WebhookDeliveries counts webhook delivery attempts by result
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Number of webhook delivery attempts by result.",
+	}, []string{"result"})
+
+	// FileConversionDuration is the time taken to convert a file for whiteboard
+	FileConversionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "file",
+		Name:      "conversion_duration_seconds",
+		Help:      "Time taken to convert uploaded files for whiteboard.",
+		Buckets:   []float64{.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"status"})
+
+	// FileConversionQueueWait is the time a conversion job waited in the queue
+	FileConversionQueueWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "file",
+		Name:      "conversion_queue_wait_seconds",
+		Help:      "Time conversion jobs waited in the queue before being processed.",
+		Buckets:   []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	})
+
+	// LtiGradePassbacks counts the attempts to send the grades to the LMS by result
+	LtiGradePassbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "lti",
+		Name:      "grade_passbacks_total",
+		Help:      "Number of attempts to send the grades to the LMS by LTI version and result.",
+	}, []string{"version", "result"})
+
+	// JanitorLeader will be 1 if this instance is the janitor leader
+	JanitorLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "janitor",
+		Name:      "leader",
+		Help:      "Whether this instance holds the janitor leader lock.",
+	})
+)
+
+// Results of webhook deliveries
+const (
+	WebhookResultSuccess    = "success"
+	WebhookResultRetry      = "retry"
+	WebhookResultDeadLetter = "dead_letter"
+	WebhookResultDropped    = "dropped"
+)
+
+// Results of LTI grade passbacks
+const (
+	LtiGradeResultSuccess = "success"
+	LtiGradeResultRetry   = "retry"
+	LtiGradeResultFailed  = "failed"
+)
+
+// Status of file conversions
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
@@ -8,6 +8,7 @@ import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,10 +24,12 @@ type AnalyticsModel struct {
 	rs              *redisservice.RedisService
 	natsService     *natsservice.NatsService
 	webhookNotifier *helpers.WebhookNotifier
+	storage         *storageservice.StorageService
+	ltiGradeModel   *LtiGradeModel
 	logger          *logrus.Entry
 }
 
-func NewAnalyticsModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, webhookNotifier *helpers.WebhookNotifier, logger *logrus.Logger) *AnalyticsModel {
+func NewAnalyticsModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, webhookNotifier *helpers.WebhookNotifier, storage *storageservice.StorageService, ltiGradeModel *LtiGradeModel, logger *logrus.Logger) *AnalyticsModel {
 	return &AnalyticsModel{
 		ctx:             ctx,
 		app:             app,
@@ -34,6 +37,8 @@ func NewAnalyticsModel(ctx context.Context, app *config.AppConfig, ds *dbservice
 		rs:              rs,
 		natsService:     natsService,
 		webhookNotifier: webhookNotifier,
+		storage:         storage,
+		ltiGradeModel:   ltiGradeModel,
 		logger:          logger.WithField("model", "analytics"),
 	}
 }
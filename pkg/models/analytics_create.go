@@ -1,13 +1,12 @@
 package models
 
 import (
-	"os"
-
 	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 )
 
-func (m *AnalyticsModel) AddAnalyticsFileToDB(roomTableId uint64, roomCreationTime int64, roomId, fileId string, stat os.FileInfo) (int64, error) {
-	fSize := float64(stat.Size())
+func (m *AnalyticsModel) AddAnalyticsFileToDB(roomTableId uint64, roomCreationTime int64, tenantId, roomId, fileId string, stat *storageservice.ObjectInfo) (int64, error) {
+	fSize := float64(stat.Size)
 	// we'll convert bytes to KB
 	if fSize > 1000 {
 		fSize = fSize / 1000.0
@@ -22,6 +21,7 @@ func (m *AnalyticsModel) AddAnalyticsFileToDB(roomTableId uint64, roomCreationTi
 		FileName:         fileId + ".json",
 		FileSize:         fSize,
 		RoomCreationTime: roomCreationTime,
+		TenantID:         tenantId,
 	}
 
 	return m.ds.InsertAnalyticsData(info)
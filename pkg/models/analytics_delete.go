@@ -2,9 +2,6 @@ package models
 
 import (
 	"errors"
-	"fmt"
-	"os"
-	"strings"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 )
@@ -15,20 +12,17 @@ func (m *AnalyticsModel) DeleteAnalytics(r *plugnmeet.DeleteAnalyticsReq) error
 		return err
 	}
 
-	path := fmt.Sprintf("%s/%s", *m.app.AnalyticsSettings.FilesStorePath, analytic.FileName)
-
+	store := m.storage.Analytics()
 	// delete the main file
-	err = os.Remove(path)
+	// if file not exists then we can delete it from record without showing any error
+	err = store.Delete(analytic.FileName)
 	if err != nil {
-		// if file not exists then we can delete it from record without showing any error
-		if !os.IsNotExist(err) {
-			ms := strings.SplitN(err.Error(), "/", -1)
-			return errors.New(ms[len(ms)-1])
-		}
+		m.logger.WithError(err).WithField("fileName", analytic.FileName).Errorln("failed to delete analytics file")
+		return errors.New("failed to delete analytics file")
 	}
 
 	// delete compressed, if any
-	_ = os.Remove(path + ".fiber.gz")
+	_ = store.Delete(analytic.FileName + ".fiber.gz")
 
 	// no error, so we'll delete record from DB
 	_, err = m.ds.DeleteAnalyticByFileId(analytic.FileId)
@@ -1,16 +1,17 @@
 package models
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -81,13 +82,13 @@ func (m *AnalyticsModel) PrepareToExportAnalytics(roomId, sid, meta string) {
 	}
 
 	fileId := fmt.Sprintf("%s-%d", room.Sid, room.CreationTime)
-	path := fmt.Sprintf("%s/%s.json", *m.app.AnalyticsSettings.FilesStorePath, fileId)
-
-	stat, err := m.exportAnalyticsToFile(room, path, metadata, log)
+	stat, usersInfo, err := m.exportAnalyticsToFile(room, fileId+".json", metadata, log)
 	if err != nil {
 		log.WithError(err).Error("failed to export analytics to file")
 		return
 	}
+	// attendance of the LTI users will be sent as the grade
+	go m.ltiGradeModel.QueueRoomGradePassback(room, usersInfo)
 
 	// it's not possible to get room metadata as always
 	// so, if room didn't have activated analytics feature,
@@ -95,7 +96,7 @@ func (m *AnalyticsModel) PrepareToExportAnalytics(roomId, sid, meta string) {
 	// and won't record to DB
 	if metadata.RoomFeatures.EnableAnalytics {
 		// record in db
-		_, err = m.AddAnalyticsFileToDB(room.ID, room.CreationTime, room.RoomId, fileId, stat)
+		_, err = m.AddAnalyticsFileToDB(room.ID, room.CreationTime, room.TenantID, room.RoomId, fileId, stat)
 		if err != nil {
 			log.WithError(err).Error("failed to add analytics file to db")
 		}
@@ -106,7 +107,7 @@ func (m *AnalyticsModel) PrepareToExportAnalytics(roomId, sid, meta string) {
 	}
 }
 
-func (m *AnalyticsModel) exportAnalyticsToFile(room *dbmodels.RoomInfo, path string, metadata *plugnmeet.RoomMetadata, log *logrus.Entry) (os.FileInfo, error) {
+func (m *AnalyticsModel) exportAnalyticsToFile(room *dbmodels.RoomInfo, fileName string, metadata *plugnmeet.RoomMetadata, log *logrus.Entry) (*storageservice.ObjectInfo, []*plugnmeet.AnalyticsUserInfo, error) {
 	roomInfo := &plugnmeet.AnalyticsRoomInfo{
 		RoomId:       room.RoomId,
 		RoomTitle:    room.RoomTitle,
@@ -121,7 +122,7 @@ func (m *AnalyticsModel) exportAnalyticsToFile(room *dbmodels.RoomInfo, path str
 	allKeys, err := m.rs.AnalyticsScanKeys(scanPattern)
 	if err != nil {
 		log.WithError(err).Error("failed to scan analytics keys for room")
-		return nil, err
+		return nil, nil, err
 	}
 	if len(allKeys) == 0 {
 		log.Info("no analytics keys found, file will contain only basic room info")
@@ -147,7 +148,7 @@ func (m *AnalyticsModel) exportAnalyticsToFile(room *dbmodels.RoomInfo, path str
 	users, err := m.rs.AnalyticsGetAllUsers(k)
 	if err != nil {
 		log.WithError(err).Error("failed to get analytics users from redis")
-		return nil, err
+		return nil, nil, err
 	}
 	roomInfo.RoomTotalUsers = int64(len(users))
 	roomInfo.RoomDuration = roomInfo.RoomEnded - roomInfo.RoomCreation
@@ -178,7 +179,7 @@ func (m *AnalyticsModel) exportAnalyticsToFile(room *dbmodels.RoomInfo, path str
 		usersInfo = append(usersInfo, userInfo)
 	}
 
-	var stat os.FileInfo
+	var stat *storageservice.ObjectInfo
 	// it's not possible to get room metadata as always
 	// so, if room didn't have activated analytics feature,
 	// we will simply won't create the file & delete all records
@@ -194,18 +195,18 @@ func (m *AnalyticsModel) exportAnalyticsToFile(room *dbmodels.RoomInfo, path str
 		marshal, err := op.Marshal(result)
 		if err != nil {
 			log.WithError(err).Error("failed to marshal analytics result")
-			return nil, err
+			return nil, nil, err
 		}
 
-		err = os.WriteFile(path, marshal, 0644)
+		err = m.storage.Analytics().Put(fileName, bytes.NewReader(marshal), int64(len(marshal)), "application/json")
 		if err != nil {
 			log.WithError(err).Error("failed to write analytics file")
-			return nil, err
+			return nil, nil, err
 		}
-		stat, err = os.Stat(path)
+		stat, err = m.storage.Analytics().Stat(fileName)
 		if err != nil {
 			log.WithError(err).Error("failed to stat new analytics file")
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -218,7 +219,7 @@ func (m *AnalyticsModel) exportAnalyticsToFile(room *dbmodels.RoomInfo, path str
 		log.WithError(err).Error("failed to delete analytics keys from redis")
 	}
 
-	return stat, err
+	return stat, usersInfo, err
 }
 
 func (m *AnalyticsModel) processEventKey(key, prefix string, eventList *[]*plugnmeet.AnalyticsEventData) {
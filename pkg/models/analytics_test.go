@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
@@ -22,14 +23,14 @@ func TestAnalyticsAuthModel_AddAnalyticsFileToDB(t *testing.T) {
 		t.Error(err)
 	}
 
-	_, err = analyticsModel.AddAnalyticsFileToDB(roomTableId, roomCreationTime, roomId, fileId, stat)
+	_, err = analyticsModel.AddAnalyticsFileToDB(roomTableId, roomCreationTime, "", roomId, fileId, stat)
 	if err != nil {
 		t.Error(err)
 	}
 }
 
 func TestAnalyticsAuthModel_FetchAnalytics(t *testing.T) {
-	result, err := analyticsModel.FetchAnalytics(&plugnmeet.FetchAnalyticsReq{
+	result, err := analyticsModel.FetchAnalytics("", &plugnmeet.FetchAnalyticsReq{
 		RoomIds: []string{roomId},
 	})
 	if err != nil {
@@ -89,12 +90,12 @@ func TestAnalyticsAuthModel_DeleteAnalytics(t *testing.T) {
 }
 
 func TestAnalyticsAuthModel_generateTokenAndVerify(t *testing.T) {
-	token, err := analyticsModel.generateToken("test.json")
+	token, err := analyticsModel.generateToken("test.json", "", "", nil)
 	if err != nil {
 		t.Error(err)
 	}
 
-	_, res, err := analyticsModel.VerifyAnalyticsToken(token)
+	_, res, err := analyticsModel.VerifyAnalyticsToken(context.Background(), token, "")
 	if err == nil {
 		t.Error("should not found the file")
 		return
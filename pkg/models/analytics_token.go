@@ -1,70 +1,47 @@
 package models
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"os"
-	"strings"
-	"time"
 
-	"github.com/go-jose/go-jose/v4"
-	"github.com/go-jose/go-jose/v4/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 )
 
-// GetAnalyticsDownloadToken will use the same JWT token generator as plugNmeet is using
-func (m *AnalyticsModel) GetAnalyticsDownloadToken(r *plugnmeet.GetAnalyticsDownloadTokenReq) (string, error) {
-	analytic, err := m.fetchAnalytic(r.FileId)
+// GetAnalyticsDownloadToken will generate token to download the analytics file,
+// opts can be nil to use the defaults
+func (m *AnalyticsModel) GetAnalyticsDownloadToken(r *plugnmeet.GetAnalyticsDownloadTokenReq, opts *DownloadTokenOptions) (string, error) {
+	analytic, err := m.ds.GetAnalyticByFileId(r.FileId)
 	if err != nil {
 		return "", err
 	}
-
-	return m.generateToken(analytic.FileName)
-}
-
-func (m *AnalyticsModel) generateToken(fileName string) (string, error) {
-	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(m.app.Client.Secret)}, (&jose.SignerOptions{}).WithType("JWT"))
-
-	if err != nil {
-		return "", err
+	if analytic == nil {
+		return "", errors.New("no info found")
 	}
 
-	cl := jwt.Claims{
-		Issuer:    m.app.Client.ApiKey,
-		NotBefore: jwt.NewNumericDate(time.Now().UTC()),
-		Expiry:    jwt.NewNumericDate(time.Now().UTC().Add(*m.app.AnalyticsSettings.TokenValidity)),
-		Subject:   fileName,
-	}
+	return m.generateToken(analytic.FileName, analytic.TenantID, analytic.FileID, opts)
+}
 
-	return jwt.Signed(sig).Claims(cl).Serialize()
+func (m *AnalyticsModel) generateToken(fileName, tenantId, fileId string, opts *DownloadTokenOptions) (string, error) {
+	return generateDownloadToken(m.app, fileName, tenantId, fileId, *m.app.AnalyticsSettings.TokenValidity, opts)
 }
 
-// VerifyAnalyticsToken verify token & provide file path
-func (m *AnalyticsModel) VerifyAnalyticsToken(token string) (string, int, error) {
-	tok, err := jwt.ParseSigned(token, []jose.SignatureAlgorithm{jose.HS256})
+// VerifyAnalyticsToken verify token for the client & provide the info with the key of the file in analytics storage
+func (m *AnalyticsModel) VerifyAnalyticsToken(ctx context.Context, token, clientIp string) (*DownloadTokenInfo, int, error) {
+	info, status, err := verifyDownloadToken(ctx, m.app, m.rs, token, clientIp)
 	if err != nil {
-		return "", fiber.StatusUnauthorized, err
-	}
-
-	out := jwt.Claims{}
-	if err = tok.Claims([]byte(m.app.Client.Secret), &out); err != nil {
-		return "", fiber.StatusUnauthorized, err
-	}
-
-	if err = out.Validate(jwt.Expected{
-		Issuer: m.app.Client.ApiKey,
-		Time:   time.Now().UTC(),
-	}); err != nil {
-		return "", fiber.StatusUnauthorized, err
+		return nil, status, err
 	}
 
-	file := fmt.Sprintf("%s/%s", *m.app.AnalyticsSettings.FilesStorePath, out.Subject)
-	_, err = os.Lstat(file)
+	_, err = m.storage.Analytics().Stat(info.Key)
 	if err != nil {
-		ms := strings.SplitN(err.Error(), "/", -1)
-		return "", fiber.StatusNotFound, errors.New(ms[len(ms)-1])
+		if errors.Is(err, storageservice.ErrNotFound) {
+			return nil, fiber.StatusNotFound, err
+		}
+		m.logger.WithError(err).Errorln("failed to get analytics file info")
+		return nil, fiber.StatusInternalServerError, errors.New("failed to get analytics file info")
 	}
 
-	return file, fiber.StatusOK, nil
+	return info, fiber.StatusOK, nil
 }
@@ -12,15 +12,19 @@ type BBBApiWrapperModel struct {
 	ds     *dbservice.DatabaseService
 	rs     *redisservice.RedisService
 	rrm    *RecordingModel
+	fm     *FileModel
+	tm     *TenantModel
 	logger *logrus.Entry
 }
 
-func NewBBBApiWrapperModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, rrm *RecordingModel, logger *logrus.Logger) *BBBApiWrapperModel {
+func NewBBBApiWrapperModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, rrm *RecordingModel, fm *FileModel, tm *TenantModel, logger *logrus.Logger) *BBBApiWrapperModel {
 	return &BBBApiWrapperModel{
 		app:    app,
 		ds:     ds,
 		rs:     rs,
 		rrm:    rrm,
+		fm:     fm,
+		tm:     tm,
 		logger: logger.WithField("model", "bbb"),
 	}
 }
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+)
+
+// BBBInsertDocumentReq is the XML body of insertDocument,
+// the document can have the url or the base64 encoded content
+type BBBInsertDocumentReq struct {
+	XMLName xml.Name `xml:"modules"`
+	Modules []struct {
+		Name      string               `xml:"name,attr"`
+		Documents []*BBBInsertDocument `xml:"document"`
+	} `xml:"module"`
+}
+
+type BBBInsertDocument struct {
+	URL      string `xml:"url,attr"`
+	Filename string `xml:"filename,attr"`
+	Name     string `xml:"name,attr"`
+	Current  string `xml:"current,attr"`
+	Content  string `xml:",chardata"`
+}
+
+// InsertDocument adds the documents to the whiteboard of the active room.
+// Only the first document marked as current will be set as the active whiteboard file.
+func (m *BBBApiWrapperModel) InsertDocument(roomId string, body []byte) error {
+	docs, err := parseBBBInsertDocuments(body)
+	if err != nil {
+		return err
+	}
+	return m.fm.InsertWhiteboardDocuments(roomId, docs)
+}
+
+// parseBBBInsertDocuments returns the documents of all the modules of the request body
+func parseBBBInsertDocuments(body []byte) ([]*WhiteboardDocument, error) {
+	r := new(BBBInsertDocumentReq)
+	if err := xml.Unmarshal(body, r); err != nil {
+		return nil, err
+	}
+
+	var docs []*WhiteboardDocument
+	hasCurrent := false
+	for _, mod := range r.Modules {
+		for _, d := range mod.Documents {
+			doc := &WhiteboardDocument{
+				Url:      strings.TrimSpace(d.URL),
+				FileName: d.Filename,
+			}
+			if doc.Url == "" {
+				doc.FileName = d.Name
+				doc.Content = d.Content
+			}
+			if !hasCurrent && strings.EqualFold(d.Current, "true") {
+				doc.Current = true
+				hasCurrent = true
+			}
+			docs = append(docs, doc)
+		}
+	}
+	if len(docs) == 0 {
+		return nil, errors.New("no documents found in the request")
+	}
+	return docs, nil
+}
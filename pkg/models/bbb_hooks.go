@@ -0,0 +1,179 @@
+package models
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-protocol/bbbapiwrapper"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrBBBHookNotFound will be returned if the hook doesn't exist or belongs to another tenant
+var ErrBBBHookNotFound = errors.New("the hook informed was not found")
+
+type BBBCreateHookReq struct {
+	CallbackURL string `query:"callbackURL" form:"callbackURL"`
+	MeetingID   string `query:"meetingID" form:"meetingID"`
+	// EventID is comma separated list of the events, empty means all events
+	EventID string `query:"eventID" form:"eventID"`
+}
+
+type BBBDestroyHookReq struct {
+	HookID uint64 `query:"hookID" form:"hookID"`
+}
+
+type BBBListHooksReq struct {
+	MeetingID string `query:"meetingID" form:"meetingID"`
+}
+
+type BBBCreateHookRes struct {
+	XMLName    xml.Name `xml:"response"`
+	ReturnCode string   `xml:"returncode"`
+	HookID     uint64   `xml:"hookID"`
+	MessageKey string   `xml:"messageKey,omitempty"`
+	Message    string   `xml:"message,omitempty"`
+	// PermanentHook & RawData aren't supported, so those will be always false
+	PermanentHook bool `xml:"permanentHook"`
+	RawData       bool `xml:"rawData"`
+}
+
+type BBBDestroyHookRes struct {
+	XMLName    xml.Name `xml:"response"`
+	ReturnCode string   `xml:"returncode"`
+	Removed    bool     `xml:"removed"`
+}
+
+type BBBHookInfo struct {
+	HookID        uint64 `xml:"hookID"`
+	CallbackURL   string `xml:"callbackURL"`
+	MeetingID     string `xml:"meetingID,omitempty"`
+	PermanentHook bool   `xml:"permanentHook"`
+	RawData       bool   `xml:"rawData"`
+}
+
+type BBBListHooksRes struct {
+	XMLName    xml.Name `xml:"response"`
+	ReturnCode string   `xml:"returncode"`
+	Hooks      struct {
+		Hook []*BBBHookInfo `xml:"hook"`
+	} `xml:"hooks"`
+}
+
+// CreateHook registers the callback url as a webhook subscription.
+// The payload will be in the format of the BBB webhooks.
+// duplicate will be true if the same hook already exists, then its ID will be returned.
+func (m *BBBApiWrapperModel) CreateHook(tenantId string, r *BBBCreateHookReq) (id uint64, duplicate bool, err error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"tenantId":  tenantId,
+		"meetingId": r.MeetingID,
+		"url":       r.CallbackURL,
+		"method":    "CreateHook",
+	})
+
+	if err = validateWebhookSubscriptionUrl(r.CallbackURL); err != nil {
+		return 0, false, err
+	}
+	var roomId string
+	if r.MeetingID != "" {
+		roomId = bbbapiwrapper.CheckMeetingIdToMatchFormat(r.MeetingID)
+	} else if tenantId != "" {
+		// the events of the other tenants must not be sent
+		return 0, false, errors.New("meetingID is required")
+	}
+
+	var roomIds []string
+	if roomId != "" {
+		roomIds = []string{roomId}
+	}
+	existing, err := m.ds.GetWebhookSubscriptionsForBBB(tenantId, roomIds)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, s := range existing {
+		if s.RoomID == roomId && s.Url == r.CallbackURL {
+			return s.ID, true, nil
+		}
+	}
+
+	secret, err := generateRandomHex(32)
+	if err != nil {
+		return 0, false, err
+	}
+	info := &dbmodels.WebhookSubscription{
+		SubscriptionID: uuid.NewString(),
+		TenantID:       tenantId,
+		RoomID:         roomId,
+		Url:            r.CallbackURL,
+		Secret:         secret,
+		Events:         formatWebhookSubscriptionEvents(toWebhookEvents(r.EventID)),
+		Format:         dbmodels.WebhookSubscriptionFormatBBB,
+		Enabled:        true,
+	}
+	if _, err = m.ds.InsertOrUpdateWebhookSubscription(info); err != nil {
+		log.WithError(err).Errorln("failed to save webhook subscription")
+		return 0, false, err
+	}
+
+	log.WithField("hookId", info.ID).Infoln("successfully created hook")
+	return info.ID, false, nil
+}
+
+// ListHooks returns the hooks of the tenant, meetingID empty means all the hooks
+func (m *BBBApiWrapperModel) ListHooks(tenantId string, r *BBBListHooksReq) ([]*BBBHookInfo, error) {
+	var roomIds []string
+	if r.MeetingID != "" {
+		roomIds = []string{bbbapiwrapper.CheckMeetingIdToMatchFormat(r.MeetingID)}
+	}
+	subscriptions, err := m.ds.GetWebhookSubscriptionsForBBB(tenantId, roomIds)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]*BBBHookInfo, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		hooks = append(hooks, &BBBHookInfo{
+			HookID:      s.ID,
+			CallbackURL: s.Url,
+			MeetingID:   s.RoomID,
+		})
+	}
+	return hooks, nil
+}
+
+// DestroyHook removes the hook of the tenant
+func (m *BBBApiWrapperModel) DestroyHook(tenantId string, r *BBBDestroyHookReq) error {
+	info, err := m.ds.GetWebhookSubscriptionById(r.HookID)
+	if err != nil {
+		return err
+	}
+	if info == nil || info.TenantID != tenantId {
+		return ErrBBBHookNotFound
+	}
+
+	if _, err = m.ds.DeleteWebhookSubscription(info.SubscriptionID); err != nil {
+		return err
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"tenantId": tenantId,
+		"hookId":   r.HookID,
+	}).Infoln("successfully destroyed hook")
+	return nil
+}
+
+// toWebhookEvents converts the comma separated event IDs of BBB
+func toWebhookEvents(eventIds string) []string {
+	var events []string
+	for _, id := range strings.Split(eventIds, ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if e, ok := helpers.BBBHookEvents[id]; ok {
+			id = e
+		}
+		events = append(events, id)
+	}
+	return events
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestToWebhookEvents(t *testing.T) {
+	tests := []struct {
+		eventIds string
+		want     []string
+	}{
+		{"meeting-created", []string{"room_created"}},
+		{"user-joined, User-Left", []string{"participant_joined", "participant_left"}},
+		{"rap-publish-ended,room_finished", []string{"recording_proceeded", "room_finished"}},
+		{"meeting-recording-started,meeting-recording-stopped", []string{"start_recording", "end_recording"}},
+	}
+	for _, tt := range tests {
+		if got := toWebhookEvents(tt.eventIds); !slices.Equal(got, tt.want) {
+			t.Errorf("toWebhookEvents(%q) = %v, want %v", tt.eventIds, got, tt.want)
+		}
+	}
+}
+
+func TestParseBBBInsertDocuments(t *testing.T) {
+	body := []byte(`<modules>
+	<module name="presentation">
+		<document url="https://example.com/slides.pdf" filename="slides.pdf"/>
+		<document current="true" name="notes.pdf">JVBERi0xLjQ=</document>
+		<document current="true" url=" https://example.com/other.pdf "/>
+	</module>
+</modules>`)
+	docs, err := parseBBBInsertDocuments(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 documents, got %d", len(docs))
+	}
+	if docs[0].Url != "https://example.com/slides.pdf" || docs[0].FileName != "slides.pdf" || docs[0].Current {
+		t.Errorf("unexpected url document %+v", docs[0])
+	}
+	if docs[1].Url != "" || docs[1].FileName != "notes.pdf" || docs[1].Content != "JVBERi0xLjQ=" || !docs[1].Current {
+		t.Errorf("unexpected content document %+v", docs[1])
+	}
+	// only the first one can be current
+	if docs[2].Url != "https://example.com/other.pdf" || docs[2].Current {
+		t.Errorf("unexpected document %+v", docs[2])
+	}
+
+	if _, err = parseBBBInsertDocuments([]byte(`<modules><module name="presentation"></module></modules>`)); err == nil {
+		t.Error("expected error without documents")
+	}
+	if _, err = parseBBBInsertDocuments([]byte(`invalid`)); err == nil {
+		t.Error("expected error for invalid body")
+	}
+}
@@ -0,0 +1,399 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrBBBRequestExpired  = errors.New("timestamp of the request is missing or outside of the allowed window")
+	ErrBBBRequestReplayed = errors.New("the request was already used")
+)
+
+// bbbProtectedParams can't be used as the default parameters of the integrations
+var bbbProtectedParams = []string{"meetingID", "checksum"}
+
+type CreateBBBIntegrationReq struct {
+	Name string `json:"name"`
+	// ApiKey will be generated if empty
+	ApiKey string `json:"api_key"`
+	// TenantId empty means the rooms will belong to the server itself
+	TenantId string `json:"tenant_id"`
+	// DefaultParams are the parameters of the create API, e.g. record=true
+	DefaultParams map[string]string `json:"default_params"`
+	WebhookUrl    string            `json:"webhook_url"`
+	Enabled       *bool             `json:"enabled"`
+}
+
+type UpdateBBBIntegrationReq struct {
+	ApiKey        string             `json:"api_key"`
+	Name          *string            `json:"name"`
+	DefaultParams *map[string]string `json:"default_params"`
+	WebhookUrl    *string            `json:"webhook_url"`
+	Enabled       *bool              `json:"enabled"`
+}
+
+type FetchBBBIntegrationsReq struct {
+	From    uint32 `json:"from"`
+	Limit   uint32 `json:"limit"`
+	OrderBy string `json:"order_by"`
+}
+
+type BBBIntegrationReq struct {
+	ApiKey string `json:"api_key"`
+}
+
+type BBBIntegrationInfo struct {
+	ApiKey string `json:"api_key"`
+	// Secret will be only returned after creating or regenerating it
+	Secret        string            `json:"secret,omitempty"`
+	Name          string            `json:"name"`
+	TenantId      string            `json:"tenant_id,omitempty"`
+	DefaultParams map[string]string `json:"default_params,omitempty"`
+	WebhookUrl    string            `json:"webhook_url,omitempty"`
+	Enabled       bool              `json:"enabled"`
+	Created       string            `json:"created"`
+	Modified      string            `json:"modified"`
+}
+
+type FetchBBBIntegrationsResult struct {
+	TotalIntegrations int64                 `json:"total_integrations"`
+	From              uint32                `json:"from"`
+	Limit             uint32                `json:"limit"`
+	OrderBy           string                `json:"order_by"`
+	IntegrationsList  []*BBBIntegrationInfo `json:"integrations_list"`
+}
+
+func (m *BBBApiWrapperModel) CreateBBBIntegration(r *CreateBBBIntegrationReq) (*BBBIntegrationInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"name":     r.Name,
+		"tenantId": r.TenantId,
+		"method":   "CreateBBBIntegration",
+	})
+	log.Infoln("request to create bbb integration")
+
+	key := strings.TrimSpace(r.ApiKey)
+	if key == "" {
+		k, err := generateRandomHex(12)
+		if err != nil {
+			return nil, err
+		}
+		key = "bbb_" + k
+	}
+	if strings.ContainsAny(key, " /?#&=%") {
+		return nil, errors.New("api_key can't contain spaces, /, ?, #, &, = or %")
+	}
+	if key == m.app.Client.ApiKey {
+		return nil, errors.New("api_key can't be the same as the api key of the server")
+	}
+	if tk, err := m.ds.GetTenantApiKey(key); err != nil {
+		return nil, err
+	} else if tk != nil {
+		return nil, errors.New("api_key is already used by a tenant")
+	}
+	existing, err := m.ds.GetBBBIntegration(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("integration with the same api_key already exists")
+	}
+
+	if r.TenantId != "" {
+		tenant, err := m.ds.GetTenant(r.TenantId)
+		if err != nil {
+			return nil, err
+		}
+		if tenant == nil {
+			return nil, errors.New("tenant not found")
+		}
+	}
+	if r.WebhookUrl != "" {
+		if err = validateWebhookSubscriptionUrl(r.WebhookUrl); err != nil {
+			return nil, err
+		}
+	}
+	params, err := encodeBBBDefaultParams(r.DefaultParams)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateRandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	info := &dbmodels.BBBIntegration{
+		ApiKey:        key,
+		Secret:        secret,
+		Name:          r.Name,
+		TenantID:      r.TenantId,
+		DefaultParams: params,
+		WebhookUrl:    r.WebhookUrl,
+		Enabled:       enabled,
+	}
+	if _, err = m.ds.InsertOrUpdateBBBIntegration(info); err != nil {
+		log.WithError(err).Errorln("failed to save bbb integration")
+		return nil, err
+	}
+
+	log.WithField("apiKey", key).Infoln("successfully created bbb integration")
+	res := m.toBBBIntegrationInfo(info)
+	res.Secret = info.Secret
+	return res, nil
+}
+
+func (m *BBBApiWrapperModel) UpdateBBBIntegration(r *UpdateBBBIntegrationReq) (*BBBIntegrationInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"apiKey": r.ApiKey,
+		"method": "UpdateBBBIntegration",
+	})
+
+	info, err := m.ds.GetBBBIntegration(r.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("bbb integration not found")
+	}
+
+	if r.Name != nil {
+		info.Name = *r.Name
+	}
+	if r.DefaultParams != nil {
+		if info.DefaultParams, err = encodeBBBDefaultParams(*r.DefaultParams); err != nil {
+			return nil, err
+		}
+	}
+	if r.WebhookUrl != nil {
+		if *r.WebhookUrl != "" {
+			if err = validateWebhookSubscriptionUrl(*r.WebhookUrl); err != nil {
+				return nil, err
+			}
+		}
+		info.WebhookUrl = *r.WebhookUrl
+	}
+	if r.Enabled != nil {
+		info.Enabled = *r.Enabled
+	}
+
+	if _, err = m.ds.InsertOrUpdateBBBIntegration(info); err != nil {
+		log.WithError(err).Errorln("failed to update bbb integration")
+		return nil, err
+	}
+
+	log.Infoln("successfully updated bbb integration")
+	return m.toBBBIntegrationInfo(info), nil
+}
+
+func (m *BBBApiWrapperModel) FetchBBBIntegrations(r *FetchBBBIntegrationsReq) (*FetchBBBIntegrationsResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "DESC"
+	}
+
+	integrations, total, err := m.ds.GetBBBIntegrations(uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*BBBIntegrationInfo, 0, len(integrations))
+	for i := range integrations {
+		list = append(list, m.toBBBIntegrationInfo(&integrations[i]))
+	}
+
+	return &FetchBBBIntegrationsResult{
+		TotalIntegrations: total,
+		From:              r.From,
+		Limit:             r.Limit,
+		OrderBy:           r.OrderBy,
+		IntegrationsList:  list,
+	}, nil
+}
+
+func (m *BBBApiWrapperModel) GetBBBIntegrationInfo(r *BBBIntegrationReq) (*BBBIntegrationInfo, error) {
+	info, err := m.ds.GetBBBIntegration(r.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("bbb integration not found")
+	}
+
+	return m.toBBBIntegrationInfo(info), nil
+}
+
+// RegenerateBBBIntegrationSecret will replace the secret,
+// the requests signed by the old secret won't be accepted anymore
+func (m *BBBApiWrapperModel) RegenerateBBBIntegrationSecret(r *BBBIntegrationReq) (*BBBIntegrationInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"apiKey": r.ApiKey,
+		"method": "RegenerateBBBIntegrationSecret",
+	})
+
+	info, err := m.ds.GetBBBIntegration(r.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("bbb integration not found")
+	}
+
+	if info.Secret, err = generateRandomHex(32); err != nil {
+		return nil, err
+	}
+	if _, err = m.ds.InsertOrUpdateBBBIntegration(info); err != nil {
+		log.WithError(err).Errorln("failed to update bbb integration secret")
+		return nil, err
+	}
+
+	log.Infoln("successfully regenerated bbb integration secret")
+	res := m.toBBBIntegrationInfo(info)
+	res.Secret = info.Secret
+	return res, nil
+}
+
+func (m *BBBApiWrapperModel) DeleteBBBIntegration(r *BBBIntegrationReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"apiKey": r.ApiKey,
+		"method": "DeleteBBBIntegration",
+	})
+
+	affected, err := m.ds.DeleteBBBIntegration(r.ApiKey)
+	if err != nil {
+		log.WithError(err).Errorln("failed to delete bbb integration")
+		return err
+	}
+	if affected == 0 {
+		return errors.New("bbb integration not found")
+	}
+
+	log.Infoln("successfully deleted bbb integration")
+	return nil
+}
+
+// VerifyApiKey returns the tenant & secret of the api key of the BBB request.
+// The integration will be nil for the api keys of the server & the tenants.
+func (m *BBBApiWrapperModel) VerifyApiKey(apiKey string) (string, string, *dbmodels.BBBIntegration, error) {
+	if apiKey != "" && apiKey != m.app.Client.ApiKey {
+		info, err := m.ds.GetBBBIntegration(apiKey)
+		if err != nil {
+			m.logger.WithError(err).Errorln("failed to get bbb integration")
+			return "", "", nil, err
+		}
+		if info != nil {
+			if !info.Enabled {
+				return "", "", nil, ErrInvalidApiKey
+			}
+			if info.TenantID != "" {
+				tenant, err := m.ds.GetTenant(info.TenantID)
+				if err != nil {
+					return "", "", nil, err
+				}
+				if tenant == nil {
+					return "", "", nil, ErrInvalidApiKey
+				}
+				if !tenant.Enabled {
+					return "", "", nil, ErrTenantDisabled
+				}
+			}
+			return info.TenantID, info.Secret, info, nil
+		}
+	}
+
+	tenantId, secret, err := m.tm.VerifyApiKey(apiKey)
+	return tenantId, secret, nil, err
+}
+
+// VerifyJoinRequest rejects the captured join urls if the replay protection is enabled.
+// timestamp can be in seconds or milliseconds.
+func (m *BBBApiWrapperModel) VerifyJoinRequest(ctx context.Context, timestamp, checksum string) error {
+	cnf := m.app.BBBSettings.JoinReplayProtection
+	if !cnf.Enabled {
+		return nil
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrBBBRequestExpired
+	}
+	t := time.Unix(ts, 0)
+	if ts > 1e12 {
+		t = time.UnixMilli(ts)
+	}
+	if diff := time.Since(t); diff > cnf.Window || diff < -cnf.Window {
+		return ErrBBBRequestExpired
+	}
+
+	// should be kept until the timestamp is outside the window
+	ttl := time.Until(t) + cnf.Window
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	ok, err := m.rs.ClaimBBBRequestChecksum(ctx, checksum, ttl)
+	if err != nil {
+		m.logger.WithError(err).Errorln("failed to claim bbb request checksum")
+		return err
+	}
+	if !ok {
+		return ErrBBBRequestReplayed
+	}
+	return nil
+}
+
+// BBBIntegrationDefaultParams returns the default parameters of the create API
+func BBBIntegrationDefaultParams(info *dbmodels.BBBIntegration) map[string]string {
+	if info == nil || info.DefaultParams == "" {
+		return nil
+	}
+	params := make(map[string]string)
+	if err := json.Unmarshal([]byte(info.DefaultParams), &params); err != nil {
+		return nil
+	}
+	return params
+}
+
+func (m *BBBApiWrapperModel) toBBBIntegrationInfo(info *dbmodels.BBBIntegration) *BBBIntegrationInfo {
+	return &BBBIntegrationInfo{
+		ApiKey:        info.ApiKey,
+		Name:          info.Name,
+		TenantId:      info.TenantID,
+		DefaultParams: BBBIntegrationDefaultParams(info),
+		WebhookUrl:    info.WebhookUrl,
+		Enabled:       info.Enabled,
+		Created:       info.Created.Format("2006-01-02 15:04:05"),
+		Modified:      info.Modified.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func encodeBBBDefaultParams(params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return "", nil
+	}
+	for _, p := range bbbProtectedParams {
+		if _, ok := params[p]; ok {
+			return "", errors.New(p + " can't be used as default parameter")
+		}
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -1,14 +1,51 @@
 package models
 
 import (
+	"encoding/xml"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"github.com/mynaparrot/plugnmeet-protocol/bbbapiwrapper"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
 )
 
-func (m *BBBApiWrapperModel) GetRecordings(host string, r *bbbapiwrapper.GetRecordingsReq) ([]*bbbapiwrapper.RecordingInfo, *bbbapiwrapper.Pagination, error) {
+// BBBGetRecordingsRes is the same as bbbapiwrapper.GetRecordingsRes,
+// but with BBBRecordingInfo to have the preview images
+type BBBGetRecordingsRes struct {
+	XMLName        xml.Name `xml:"response"`
+	ReturnCode     string   `xml:"returncode"`
+	RecordingsInfo struct {
+		Recordings []*BBBRecordingInfo
+	} `xml:"recordings"`
+	Pagination *bbbapiwrapper.Pagination
+}
+
+// BBBRecordingInfo overrides the playback of bbbapiwrapper.RecordingInfo,
+// because the preview image of the protocol can't have the url
+type BBBRecordingInfo struct {
+	bbbapiwrapper.RecordingInfo
+	Playback struct {
+		PlayBackFormat []*BBBPlayBackFormat
+	} `xml:"playback"`
+}
+
+type BBBPlayBackFormat struct {
+	bbbapiwrapper.PlayBackFormat
+	Preview struct {
+		Images struct {
+			Image []*BBBPlayBackPreviewImage
+		} `xml:"images"`
+	} `xml:"preview"`
+}
+
+type BBBPlayBackPreviewImage struct {
+	bbbapiwrapper.PlayBackPreviewImage
+	URL string `xml:",chardata"`
+}
+
+func (m *BBBApiWrapperModel) GetRecordings(tenantId, host string, r *bbbapiwrapper.GetRecordingsReq, filter *FetchRecordingsFilter) ([]*BBBRecordingInfo, *bbbapiwrapper.Pagination, error) {
 	oriIds := make(map[string]string)
 	if r.Limit == 0 {
 		// let's make it 50 for BBB as not all plugin still support pagination
@@ -26,18 +63,25 @@ func (m *BBBApiWrapperModel) GetRecordings(host string, r *bbbapiwrapper.GetReco
 		}
 	}
 
-	data, total, err := m.ds.GetRecordingsForBBB(rIds, mIds, r.Offset, r.Limit)
+	data, total, err := m.ds.GetRecordingsForBBB(tenantId, rIds, mIds, r.Offset, r.Limit, filter.toDbFilter())
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var recordings []*bbbapiwrapper.RecordingInfo
+	var recordings []*BBBRecordingInfo
 	for _, v := range data {
-		recording := &bbbapiwrapper.RecordingInfo{
-			RecordID:          v.RecordID,
-			InternalMeetingID: v.RoomSid.String,
-			Published:         true,
-			State:             "published",
+		meta := m.rrm.ToRecordingMetadata(&v)
+		recording := &BBBRecordingInfo{
+			RecordingInfo: bbbapiwrapper.RecordingInfo{
+				RecordID:          v.RecordID,
+				InternalMeetingID: v.RoomSid.String,
+				Published:         meta.Published,
+				State:             "published",
+				Metadata:          meta.Metadata,
+			},
+		}
+		if !meta.Published {
+			recording.State = "unpublished"
 		}
 
 		if oriIds[v.RoomID] != "" {
@@ -52,12 +96,16 @@ func (m *BBBApiWrapperModel) GetRecordings(host string, r *bbbapiwrapper.GetReco
 			m.logger.Errorln(err)
 			continue
 		}
-		recording.Playback.PlayBackFormat = []bbbapiwrapper.PlayBackFormat{
-			{
+		format := &BBBPlayBackFormat{
+			PlayBackFormat: bbbapiwrapper.PlayBackFormat{
 				Type: "presentation",
 				URL:  url,
+				// BBB uses minutes for the length
+				Length: int64(math.Round(v.Duration / 60)),
 			},
 		}
+		format.Preview.Images.Image = m.createPreviewImages(host, &v)
+		recording.Playback.PlayBackFormat = []*BBBPlayBackFormat{format}
 
 		if mInfo, err := m.ds.GetRoomInfoBySid(v.RoomSid.String, nil); err == nil && mInfo != nil {
 			recording.Name = mInfo.RoomTitle
@@ -67,6 +115,9 @@ func (m *BBBApiWrapperModel) GetRecordings(host string, r *bbbapiwrapper.GetReco
 			}
 			recording.Participants = uint64(mInfo.JoinedParticipants)
 		}
+		if meta.Title != "" {
+			recording.Name = meta.Title
+		}
 
 		if v.Size > 0 {
 			recording.RawSize = int64(v.Size * 1000000)
@@ -98,3 +149,35 @@ func (m *BBBApiWrapperModel) createPlayBackURL(host, path string) (string, error
 	url := fmt.Sprintf("%s/download/recording/%s", host, token)
 	return url, nil
 }
+
+// createPreviewImages returns the thumbnails of the recording as preview images
+func (m *BBBApiWrapperModel) createPreviewImages(host string, v *dbmodels.Recording) []*BBBPlayBackPreviewImage {
+	if v.Thumbnails == 0 {
+		return nil
+	}
+	token, err := m.rrm.GetThumbnailToken(v)
+	if err != nil {
+		m.logger.WithError(err).WithField("recordId", v.RecordID).Errorln("failed to generate thumbnail token")
+		return nil
+	}
+
+	// the thumbnails were scaled by ffmpeg to the width with even height
+	width, height := 0, 0
+	if cnf := m.app.RecorderInfo.MediaInfo; cnf != nil && v.Width > 0 {
+		width = cnf.ThumbnailWidth
+		height = int(math.Round(float64(width)*float64(v.Height)/float64(v.Width)/2)) * 2
+	}
+
+	images := make([]*BBBPlayBackPreviewImage, 0, v.Thumbnails)
+	for i := 0; i < v.Thumbnails; i++ {
+		images = append(images, &BBBPlayBackPreviewImage{
+			PlayBackPreviewImage: bbbapiwrapper.PlayBackPreviewImage{
+				Alt:    fmt.Sprintf("Thumbnail %d", i+1),
+				Width:  fmt.Sprint(width),
+				Height: fmt.Sprint(height),
+			},
+			URL: fmt.Sprintf("%s/download/recording/thumbnail/%s/%d", host, token, i),
+		})
+	}
+	return images
+}
@@ -0,0 +1,84 @@
+package models
+
+import "fmt"
+
+// BBBTextTrackResponse is the JSON response of getRecordingTextTracks & putRecordingTextTrack,
+// unlike the other BBB APIs these don't use XML
+type BBBTextTrackResponse struct {
+	Response *BBBTextTrackResponseBody `json:"response"`
+}
+
+type BBBTextTrackResponseBody struct {
+	ReturnCode string          `json:"returncode"`
+	MessageKey string          `json:"messageKey,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	RecordId   string          `json:"recordId,omitempty"`
+	Tracks     []*BBBTextTrack `json:"tracks,omitempty"`
+}
+
+type BBBTextTrack struct {
+	Href   string `json:"href"`
+	Kind   string `json:"kind"`
+	Label  string `json:"label"`
+	Lang   string `json:"lang"`
+	Source string `json:"source"`
+}
+
+type BBBGetRecordingTextTracksReq struct {
+	RecordID string `query:"recordID" form:"recordID"`
+}
+
+type BBBPutRecordingTextTrackReq struct {
+	RecordID string `query:"recordID" form:"recordID"`
+	Kind     string `query:"kind" form:"kind"`
+	Lang     string `query:"lang" form:"lang"`
+	Label    string `query:"label" form:"label"`
+}
+
+// BBBTextTrackRes returns the common response of the text track APIs
+func BBBTextTrackRes(returnCode, messageKey, message string) *BBBTextTrackResponse {
+	return &BBBTextTrackResponse{
+		Response: &BBBTextTrackResponseBody{
+			ReturnCode: returnCode,
+			MessageKey: messageKey,
+			Message:    message,
+		},
+	}
+}
+
+// GetRecordingTextTracks returns the text tracks of the recording with the download links
+func (m *BBBApiWrapperModel) GetRecordingTextTracks(host, recordId string) ([]*BBBTextTrack, error) {
+	recording, tracks, err := m.rrm.GetRecordingTextTracks(recordId)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*BBBTextTrack, 0, len(tracks))
+	if len(tracks) == 0 {
+		return list, nil
+	}
+	token, err := m.rrm.GetTextTrackToken(recording)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tracks {
+		list = append(list, &BBBTextTrack{
+			Href:   fmt.Sprintf("%s/download/recording/track/%s/%s.vtt", host, token, t.Name()),
+			Kind:   t.Kind,
+			Label:  t.Label,
+			Lang:   t.Lang,
+			Source: t.Source,
+		})
+	}
+	return list, nil
+}
+
+// PutRecordingTextTrack saves the uploaded text track of the recording
+func (m *BBBApiWrapperModel) PutRecordingTextTrack(r *BBBPutRecordingTextTrackReq, data []byte) error {
+	return m.rrm.PutRecordingTextTrack(r.RecordID, &RecordingTextTrack{
+		Kind:  r.Kind,
+		Lang:  r.Lang,
+		Label: r.Label,
+	}, data)
+}
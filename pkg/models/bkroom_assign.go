@@ -0,0 +1,396 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+
+	"github.com/goccy/go-json"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// BreakoutRoomAssignRandom shuffles the online users & splits them evenly
+	BreakoutRoomAssignRandom = "random"
+	// BreakoutRoomAssignRoundRobin assigns the online users one by one by their join order
+	BreakoutRoomAssignRoundRobin = "round_robin"
+	// BreakoutRoomAssignSelfSelect creates empty rooms & participants will choose by themselves
+	BreakoutRoomAssignSelfSelect = "self_select"
+
+	// maximum attempts to update a breakout room if it was modified concurrently
+	maxBreakoutRoomUpdateAttempts = 5
+)
+
+// AutoAssignBreakoutRoomsOptions are the assignment options of AutoAssignBreakoutRooms,
+// rooms, duration & welcome message will be used from the CreateBreakoutRoomsReq
+type AutoAssignBreakoutRoomsOptions struct {
+	Strategy string
+	// IncludeAdmins will assign other admins as well, by default only non-admin users will be assigned
+	IncludeAdmins bool
+	// MaxUsersPerRoom is used with self_select, 0 means no limit
+	MaxUsersPerRoom int
+}
+
+// BreakoutRoomAssignment keeps how users were assigned to the breakout rooms of a parent room
+type BreakoutRoomAssignment struct {
+	Strategy        string `json:"strategy"`
+	MaxUsersPerRoom int    `json:"max_users_per_room,omitempty"`
+}
+
+// AutoAssignBreakoutRooms will create breakout rooms with the online users of the parent room
+// assigned by the requested strategy.
+func (m *BreakoutRoomModel) AutoAssignBreakoutRooms(r *plugnmeet.CreateBreakoutRoomsReq, opts *AutoAssignBreakoutRoomsOptions) error {
+	roomId := r.RoomId
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId":   roomId,
+		"strategy": opts.Strategy,
+		"numRooms": len(r.Rooms),
+		"method":   "AutoAssignBreakoutRooms",
+	})
+	log.Infoln("request to create breakout rooms with auto assignment")
+
+	if len(r.Rooms) == 0 {
+		return errors.New("at least one room is required")
+	}
+	if opts.MaxUsersPerRoom < 0 {
+		return errors.New("max_users_per_room can't be negative")
+	}
+
+	rooms := make([]*plugnmeet.BreakoutRoom, len(r.Rooms))
+	for i, rr := range r.Rooms {
+		if rr.Id == "" {
+			return errors.New("room id is required")
+		}
+		rooms[i] = &plugnmeet.BreakoutRoom{
+			Id:    rr.Id,
+			Title: rr.Title,
+		}
+	}
+
+	switch opts.Strategy {
+	case BreakoutRoomAssignRandom, BreakoutRoomAssignRoundRobin:
+		users, err := m.getAssignableUsers(roomId, r.RequestedUserId, opts.IncludeAdmins)
+		if err != nil {
+			log.WithError(err).Errorln("failed to get online users")
+			return err
+		}
+		if len(users) == 0 {
+			return errors.New("no online users found to assign")
+		}
+		assignBreakoutRoomUsers(opts.Strategy, users, rooms)
+	case BreakoutRoomAssignSelfSelect:
+		// rooms will be empty, participants will choose
+	default:
+		return fmt.Errorf("unknown assignment strategy: %s", opts.Strategy)
+	}
+
+	err := m.CreateBreakoutRooms(&plugnmeet.CreateBreakoutRoomsReq{
+		RoomId:          roomId,
+		RequestedUserId: r.RequestedUserId,
+		Duration:        r.Duration,
+		WelcomeMsg:      r.WelcomeMsg,
+		Rooms:           rooms,
+	})
+	if err != nil {
+		return err
+	}
+
+	assignment, err := json.Marshal(&BreakoutRoomAssignment{
+		Strategy:        opts.Strategy,
+		MaxUsersPerRoom: opts.MaxUsersPerRoom,
+	})
+	if err != nil {
+		return err
+	}
+	if err = m.natsService.SetBreakoutRoomAssignment(roomId, assignment); err != nil {
+		log.WithError(err).Errorln("failed to store breakout room assignment")
+		return err
+	}
+
+	if opts.Strategy == BreakoutRoomAssignSelfSelect {
+		err = m.natsService.BroadcastSystemNotificationToRoom(roomId, "notifications.breakout-rooms-choose", plugnmeet.NatsSystemNotificationTypes_NATS_SYSTEM_NOTIFICATION_INFO, true, nil)
+		if err != nil {
+			log.WithError(err).Errorln("failed to notify users to choose breakout room")
+		}
+	}
+
+	log.Info("finished creating breakout rooms with auto assignment")
+	return nil
+}
+
+// ChooseBreakoutRoom adds the user to the chosen breakout room when rooms were created with self_select
+// & returns the join token of that room.
+func (m *BreakoutRoomModel) ChooseBreakoutRoom(ctx context.Context, r *plugnmeet.JoinBreakoutRoomReq) (string, error) {
+	roomId, userId := r.RoomId, r.UserId
+	log := m.logger.WithFields(logrus.Fields{
+		"parentRoomId":   roomId,
+		"breakoutRoomId": r.BreakoutRoomId,
+		"userId":         userId,
+		"method":         "ChooseBreakoutRoom",
+	})
+	log.Infoln("request to choose breakout room")
+
+	assignment, err := m.getBreakoutRoomAssignment(roomId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get breakout room assignment")
+		return "", err
+	}
+	if assignment == nil || assignment.Strategy != BreakoutRoomAssignSelfSelect {
+		return "", errors.New("breakout rooms can't be chosen by participants")
+	}
+
+	userInfo, err := m.natsService.GetUserInfo(roomId, userId)
+	if err != nil {
+		return "", err
+	}
+	if userInfo == nil {
+		return "", errors.New("user not found in the parent room")
+	}
+
+	rooms, err := m.fetchBreakoutRooms(roomId)
+	if err != nil {
+		return "", err
+	}
+	var current *plugnmeet.BreakoutRoom
+	found := false
+	for _, rr := range rooms {
+		if rr.Id == r.BreakoutRoomId {
+			found = true
+		}
+		if rr.Id != r.BreakoutRoomId && hasBreakoutRoomUser(rr, userId) {
+			current = rr
+		}
+	}
+	if !found {
+		return "", errors.New("breakout room not found")
+	}
+	if current != nil {
+		status, _ := m.natsService.GetRoomUserStatus(current.Id, userId)
+		if status == natsservice.UserStatusOnline {
+			return "", errors.New("please leave your current breakout room first")
+		}
+	}
+
+	err = m.updateBreakoutRoom(roomId, r.BreakoutRoomId, func(room *plugnmeet.BreakoutRoom) error {
+		if hasBreakoutRoomUser(room, userId) {
+			return nil
+		}
+		if assignment.MaxUsersPerRoom > 0 && len(room.Users) >= assignment.MaxUsersPerRoom {
+			return errors.New("breakout room is full")
+		}
+		room.Users = append(room.Users, &plugnmeet.BreakoutRoomUser{
+			Id:   userId,
+			Name: userInfo.Name,
+		})
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Warnln("failed to add user to the breakout room")
+		return "", err
+	}
+
+	if current != nil {
+		if err = m.removeBreakoutRoomUser(roomId, current.Id, userId); err != nil {
+			log.WithError(err).Errorln("failed to remove user from previous breakout room")
+		}
+	}
+
+	r.IsAdmin = false
+	return m.JoinBreakoutRoom(ctx, r)
+}
+
+// MoveBreakoutRoomUser will reassign a user to another breakout room.
+// If the user is in the previous room, they will be removed from there & invited to the new one.
+func (m *BreakoutRoomModel) MoveBreakoutRoomUser(r *plugnmeet.JoinBreakoutRoomReq) error {
+	roomId := r.RoomId
+	log := m.logger.WithFields(logrus.Fields{
+		"parentRoomId":     roomId,
+		"userId":           r.UserId,
+		"toBreakoutRoomId": r.BreakoutRoomId,
+		"method":           "MoveBreakoutRoomUser",
+	})
+	log.Infoln("request to move user to another breakout room")
+
+	if r.UserId == "" || r.BreakoutRoomId == "" {
+		return errors.New("user_id & breakout_room_id are required")
+	}
+
+	userInfo, err := m.natsService.GetUserInfo(roomId, r.UserId)
+	if err != nil {
+		return err
+	}
+	if userInfo == nil {
+		return errors.New("user not found in the parent room")
+	}
+
+	rooms, err := m.fetchBreakoutRooms(roomId)
+	if err != nil {
+		return err
+	}
+	var from []string
+	found := false
+	for _, rr := range rooms {
+		if rr.Id == r.BreakoutRoomId {
+			found = true
+			if hasBreakoutRoomUser(rr, r.UserId) {
+				return errors.New("user is already in this breakout room")
+			}
+		} else if hasBreakoutRoomUser(rr, r.UserId) {
+			from = append(from, rr.Id)
+		}
+	}
+	if !found {
+		return errors.New("breakout room not found")
+	}
+
+	err = m.updateBreakoutRoom(roomId, r.BreakoutRoomId, func(room *plugnmeet.BreakoutRoom) error {
+		if !hasBreakoutRoomUser(room, r.UserId) {
+			room.Users = append(room.Users, &plugnmeet.BreakoutRoomUser{
+				Id:   r.UserId,
+				Name: userInfo.Name,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Errorln("failed to add user to the breakout room")
+		return err
+	}
+
+	for _, id := range from {
+		if err = m.removeBreakoutRoomUser(roomId, id, r.UserId); err != nil {
+			log.WithError(err).WithField("fromBreakoutRoomId", id).Errorln("failed to remove user from breakout room")
+			continue
+		}
+		if status, _ := m.natsService.GetRoomUserStatus(id, r.UserId); status == natsservice.UserStatusOnline {
+			_ = m.um.RemoveParticipant(&plugnmeet.RemoveParticipantReq{
+				RoomId: id,
+				UserId: r.UserId,
+				Msg:    "notifications.breakout-room-user-moved",
+			})
+		}
+	}
+
+	err = m.natsService.BroadcastSystemEventToRoom(plugnmeet.NatsMsgServerToClientEvents_JOIN_BREAKOUT_ROOM, roomId, r.BreakoutRoomId, &r.UserId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to send breakout room invitation")
+	}
+
+	log.Info("successfully moved user to the breakout room")
+	return nil
+}
+
+// getAssignableUsers returns the online users of the parent room except
+// the requester, internal bots & optionally the admins
+func (m *BreakoutRoomModel) getAssignableUsers(roomId, requestedUserId string, includeAdmins bool) ([]*plugnmeet.NatsKvUserInfo, error) {
+	users, err := m.natsService.GetOnlineUsersList(roomId)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*plugnmeet.NatsKvUserInfo
+	for _, u := range users {
+		if u.UserId == requestedUserId || u.UserId == config.RecorderBot || u.UserId == config.RtmpBot {
+			continue
+		}
+		if u.IsAdmin && !includeAdmins {
+			continue
+		}
+		list = append(list, u)
+	}
+	return list, nil
+}
+
+func (m *BreakoutRoomModel) getBreakoutRoomAssignment(roomId string) (*BreakoutRoomAssignment, error) {
+	data, err := m.natsService.GetBreakoutRoomAssignment(roomId)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	assignment := new(BreakoutRoomAssignment)
+	if err = json.Unmarshal(data, assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// updateBreakoutRoom applies fn to the room & stores it only if no one else modified the room meanwhile,
+// otherwise it will try again with the latest data.
+func (m *BreakoutRoomModel) updateBreakoutRoom(roomId, bkRoomId string, fn func(room *plugnmeet.BreakoutRoom) error) error {
+	for i := 0; i < maxBreakoutRoomUpdateAttempts; i++ {
+		data, revision, err := m.natsService.GetBreakoutRoomWithRevision(roomId, bkRoomId)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			return errors.New("breakout room not found")
+		}
+
+		room := new(plugnmeet.BreakoutRoom)
+		if err = protojson.Unmarshal(data, room); err != nil {
+			return err
+		}
+		if err = fn(room); err != nil {
+			return err
+		}
+
+		marshal, err := protojson.Marshal(room)
+		if err != nil {
+			return err
+		}
+		err = m.natsService.UpdateBreakoutRoom(roomId, bkRoomId, marshal, revision)
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			continue
+		}
+		return err
+	}
+
+	return errors.New("breakout room was modified concurrently, please try again")
+}
+
+func (m *BreakoutRoomModel) removeBreakoutRoomUser(roomId, bkRoomId, userId string) error {
+	return m.updateBreakoutRoom(roomId, bkRoomId, func(room *plugnmeet.BreakoutRoom) error {
+		room.Users = slices.DeleteFunc(room.Users, func(u *plugnmeet.BreakoutRoomUser) bool {
+			return u.Id == userId
+		})
+		return nil
+	})
+}
+
+func hasBreakoutRoomUser(room *plugnmeet.BreakoutRoom, userId string) bool {
+	return slices.ContainsFunc(room.Users, func(u *plugnmeet.BreakoutRoomUser) bool {
+		return u.Id == userId
+	})
+}
+
+// assignBreakoutRoomUsers distributes the users to the rooms,
+// so the difference between the number of users in any two rooms won't be more than one.
+func assignBreakoutRoomUsers(strategy string, users []*plugnmeet.NatsKvUserInfo, rooms []*plugnmeet.BreakoutRoom) {
+	list := slices.Clone(users)
+	switch strategy {
+	case BreakoutRoomAssignRandom:
+		rand.Shuffle(len(list), func(i, j int) {
+			list[i], list[j] = list[j], list[i]
+		})
+	case BreakoutRoomAssignRoundRobin:
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].JoinedAt < list[j].JoinedAt
+		})
+	}
+
+	for i, u := range list {
+		room := rooms[i%len(rooms)]
+		room.Users = append(room.Users, &plugnmeet.BreakoutRoomUser{
+			Id:   u.UserId,
+			Name: u.Name,
+		})
+	}
+}
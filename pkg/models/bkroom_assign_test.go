@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+)
+
+func TestAssignBreakoutRoomUsers(t *testing.T) {
+	newUsers := func(n int) []*plugnmeet.NatsKvUserInfo {
+		users := make([]*plugnmeet.NatsKvUserInfo, n)
+		for i := range users {
+			// reversed join order, so that sorting will be visible
+			users[i] = &plugnmeet.NatsKvUserInfo{
+				UserId:   fmt.Sprintf("u%d", i),
+				Name:     fmt.Sprintf("User %d", i),
+				JoinedAt: uint64(n - i),
+			}
+		}
+		return users
+	}
+	newRooms := func(n int) []*plugnmeet.BreakoutRoom {
+		rooms := make([]*plugnmeet.BreakoutRoom, n)
+		for i := range rooms {
+			rooms[i] = &plugnmeet.BreakoutRoom{Id: fmt.Sprintf("r%d", i)}
+		}
+		return rooms
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		numUsers int
+		numRooms int
+		// expected users of each room, nil means any order
+		want [][]string
+	}{
+		{"random even", BreakoutRoomAssignRandom, 6, 3, nil},
+		{"random uneven", BreakoutRoomAssignRandom, 7, 3, nil},
+		{"random less users than rooms", BreakoutRoomAssignRandom, 2, 3, nil},
+		{"round robin by join order", BreakoutRoomAssignRoundRobin, 5, 2, [][]string{{"u4", "u2", "u0"}, {"u3", "u1"}}},
+		{"round robin single room", BreakoutRoomAssignRoundRobin, 3, 1, [][]string{{"u2", "u1", "u0"}}},
+		{"round robin less users than rooms", BreakoutRoomAssignRoundRobin, 1, 3, [][]string{{"u0"}, nil, nil}},
+	}
+
+	for _, tt := range tests {
+		users := newUsers(tt.numUsers)
+		original := slices.Clone(users)
+		rooms := newRooms(tt.numRooms)
+		assignBreakoutRoomUsers(tt.strategy, users, rooms)
+
+		if !slices.Equal(users, original) {
+			t.Errorf("%s: users list of the caller was modified", tt.name)
+		}
+
+		var assigned []string
+		minUsers, maxUsers := tt.numUsers, 0
+		for i, room := range rooms {
+			var ids []string
+			for _, u := range room.Users {
+				ids = append(ids, u.Id)
+			}
+			assigned = append(assigned, ids...)
+			minUsers = min(minUsers, len(ids))
+			maxUsers = max(maxUsers, len(ids))
+
+			if tt.want != nil && !slices.Equal(ids, tt.want[i]) {
+				t.Errorf("%s: room %s got %v, want %v", tt.name, room.Id, ids, tt.want[i])
+			}
+		}
+
+		if maxUsers-minUsers > 1 {
+			t.Errorf("%s: rooms are not balanced, min %d, max %d", tt.name, minUsers, maxUsers)
+		}
+		slices.Sort(assigned)
+		if len(slices.Compact(slices.Clone(assigned))) != tt.numUsers || len(assigned) != tt.numUsers {
+			t.Errorf("%s: every user should be assigned exactly once, got %v", tt.name, assigned)
+		}
+	}
+}
@@ -52,14 +52,21 @@ func (m *BreakoutRoomModel) CreateBreakoutRooms(r *plugnmeet.CreateBreakoutRooms
 	meta.RoomFeatures.BreakoutRoomFeatures.IsAllow = false
 	meta.RoomFeatures.WaitingRoomFeatures.IsActive = false
 
-	// we'll disable now. in the future, we can think about those
-	meta.RoomFeatures.RecordingFeatures.IsAllow = false
-	meta.RoomFeatures.AllowRtmp = false
+	// recording & rtmp will follow the parent room settings,
+	// but status of the parent room shouldn't be copied
+	meta.IsRecording = false
+	meta.IsActiveRtmp = false
 
 	// clear few main room data
 	meta.RoomFeatures.DisplayExternalLinkFeatures.IsActive = false
 	meta.RoomFeatures.ExternalMediaPlayerFeatures.IsActive = false
 
+	// breakout rooms will belong to the same tenant as the parent room
+	tenantId := ""
+	if parent, err := m.rm.ds.GetRoomInfoByRoomId(r.RoomId, 1); err == nil && parent != nil {
+		tenantId = parent.TenantID
+	}
+
 	e := make(map[string]bool)
 
 	for _, room := range r.Rooms {
@@ -73,7 +80,7 @@ func (m *BreakoutRoomModel) CreateBreakoutRooms(r *plugnmeet.CreateBreakoutRooms
 		bRoom.RoomId = bRoomId
 		meta.RoomTitle = room.Title
 		bRoom.Metadata = meta
-		_, err := m.rm.CreateRoom(bRoom)
+		_, err := m.rm.CreateRoom(tenantId, bRoom)
 
 		if err != nil {
 			roomLog.WithError(err).Error("failed to create breakout room")
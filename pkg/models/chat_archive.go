@@ -0,0 +1,69 @@
+package models
+
+import (
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ChatExportFormatTxt  = "txt"
+	ChatExportFormatJson = "json"
+)
+
+// ChatArchiveModel keeps chat messages of the ended sessions in DB
+type ChatArchiveModel struct {
+	app         *config.AppConfig
+	ds          *dbservice.DatabaseService
+	natsService *natsservice.NatsService
+	logger      *logrus.Entry
+}
+
+func NewChatArchiveModel(app *config.AppConfig, ds *dbservice.DatabaseService, natsService *natsservice.NatsService, logger *logrus.Logger) *ChatArchiveModel {
+	return &ChatArchiveModel{
+		app:         app,
+		ds:          ds,
+		natsService: natsService,
+		logger:      logger.WithField("model", "chat_archive"),
+	}
+}
+
+type FetchChatMessagesReq struct {
+	RoomSid string `json:"room_sid"`
+	// UserId & IsAdmin are used to decide visibility of private messages
+	UserId  string `json:"user_id"`
+	IsAdmin bool   `json:"is_admin"`
+	From    uint32 `json:"from"`
+	Limit   uint32 `json:"limit"`
+	OrderBy string `json:"order_by"`
+}
+
+type ChatDownloadTokenReq struct {
+	RoomSid string `json:"room_sid"`
+	UserId  string `json:"user_id"`
+	IsAdmin bool   `json:"is_admin"`
+	// Format can be txt or json, default txt
+	Format string `json:"format"`
+}
+
+type ChatMessageInfo struct {
+	MessageId  string `json:"message_id"`
+	FromUserId string `json:"from_user_id"`
+	FromName   string `json:"from_name"`
+	FromAdmin  bool   `json:"from_admin"`
+	ToUserId   string `json:"to_user_id,omitempty"`
+	IsPrivate  bool   `json:"is_private"`
+	Message    string `json:"message"`
+	SentAt     int64  `json:"sent_at"`
+}
+
+type FetchChatMessagesResult struct {
+	RoomId        string             `json:"room_id"`
+	RoomSid       string             `json:"room_sid"`
+	TotalMessages int64              `json:"total_messages"`
+	From          uint32             `json:"from"`
+	Limit         uint32             `json:"limit"`
+	OrderBy       string             `json:"order_by"`
+	MessagesList  []*ChatMessageInfo `json:"messages_list"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+// ArchiveRoomChat will store chat messages from the room stream to DB.
+// It must be called before the stream is deleted.
+func (m *ChatArchiveModel) ArchiveRoomChat(roomId, roomSid, metadata string) {
+	if !m.isArchiveEnabled(metadata) {
+		return
+	}
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId":  roomId,
+		"roomSid": roomSid,
+		"method":  "ArchiveRoomChat",
+	})
+
+	roomInfo, err := m.ds.GetRoomInfoBySid(roomSid, nil)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get room info")
+		return
+	}
+	if roomInfo == nil {
+		log.Warnln("room not found in db, skipping chat archive")
+		return
+	}
+
+	msgs, err := m.natsService.GetRoomChatMessages(roomId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get chat messages from stream")
+		return
+	}
+	if len(msgs) == 0 {
+		return
+	}
+
+	list := make([]*dbmodels.ChatMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		cm := new(plugnmeet.ChatMessage)
+		if err = proto.Unmarshal(msg.Data, cm); err != nil {
+			log.WithError(err).WithField("seq", msg.Sequence).Warnln("failed to unmarshal chat message, skipping")
+			continue
+		}
+
+		messageId := cm.GetId()
+		if messageId == "" {
+			messageId = strconv.FormatUint(msg.Sequence, 10)
+		}
+		sentAt := cm.GetSentAt()
+		if sentAt == 0 {
+			sentAt = msg.Time.UnixMilli()
+		}
+
+		list = append(list, &dbmodels.ChatMessage{
+			RoomTableID: roomInfo.ID,
+			RoomID:      roomInfo.RoomId,
+			RoomSid:     roomInfo.Sid,
+			TenantID:    roomInfo.TenantID,
+			MessageID:   messageId,
+			FromUserID:  cm.GetFromUserId(),
+			FromName:    cm.GetFromName(),
+			FromAdmin:   cm.GetFromAdmin(),
+			ToUserID:    cm.GetToUserId(),
+			IsPrivate:   cm.GetIsPrivate(),
+			Message:     cm.GetMessage(),
+			SentAt:      sentAt,
+		})
+	}
+
+	inserted, err := m.ds.InsertChatMessages(list)
+	if err != nil {
+		log.WithError(err).Errorln("failed to archive chat messages")
+		return
+	}
+	log.WithField("messages", inserted).Infoln("successfully archived chat messages")
+}
+
+// isArchiveEnabled checks config & opt in of the room
+func (m *ChatArchiveModel) isArchiveEnabled(metadata string) bool {
+	cnf := m.app.ChatArchiveSettings
+	if cnf == nil || !cnf.Enabled {
+		return false
+	}
+	if cnf.ArchiveAllRooms {
+		return true
+	}
+	if metadata == "" {
+		return false
+	}
+
+	meta, err := m.natsService.UnmarshalRoomMetadata(metadata)
+	if err != nil || meta.GetExtraData() == "" {
+		return false
+	}
+	extra := struct {
+		ArchiveChat bool `json:"archive_chat"`
+	}{}
+	if err = json.Unmarshal([]byte(meta.GetExtraData()), &extra); err != nil {
+		return false
+	}
+
+	return extra.ArchiveChat
+}
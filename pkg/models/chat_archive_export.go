@@ -0,0 +1,148 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+)
+
+const chatExportBatchSize = 500
+
+type chatDownloadClaims struct {
+	TenantId string `json:"tenant_id,omitempty"`
+	RoomSid  string `json:"room_sid"`
+	UserId   string `json:"user_id,omitempty"`
+	IsAdmin  bool   `json:"is_admin"`
+	Format   string `json:"format"`
+}
+
+// GetChatDownloadToken will generate a token to download the transcript of the session
+func (m *ChatArchiveModel) GetChatDownloadToken(tenantId string, r *ChatDownloadTokenReq) (string, error) {
+	if m.app.ChatArchiveSettings == nil {
+		return "", errors.New("chat archive is not enabled")
+	}
+	if _, err := m.getRoomInfo(tenantId, r.RoomSid); err != nil {
+		return "", err
+	}
+
+	switch r.Format {
+	case "":
+		r.Format = ChatExportFormatTxt
+	case ChatExportFormatTxt, ChatExportFormatJson:
+	default:
+		return "", errors.New("invalid format, supported formats: txt, json")
+	}
+
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(m.app.Client.Secret)}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", err
+	}
+
+	cl := jwt.Claims{
+		Issuer:    m.app.Client.ApiKey,
+		NotBefore: jwt.NewNumericDate(time.Now().UTC()),
+		Expiry:    jwt.NewNumericDate(time.Now().UTC().Add(m.app.ChatArchiveSettings.TokenValidity)),
+		Subject:   r.RoomSid,
+	}
+	custom := &chatDownloadClaims{
+		TenantId: tenantId,
+		RoomSid:  r.RoomSid,
+		UserId:   r.UserId,
+		IsAdmin:  r.IsAdmin,
+		Format:   r.Format,
+	}
+
+	return jwt.Signed(sig).Claims(cl).Claims(custom).Serialize()
+}
+
+// ExportChatTranscript verifies the token & returns the file name & content of the transcript
+func (m *ChatArchiveModel) ExportChatTranscript(token string) (string, []byte, int, error) {
+	tok, err := jwt.ParseSigned(token, []jose.SignatureAlgorithm{jose.HS256})
+	if err != nil {
+		return "", nil, fiber.StatusUnauthorized, err
+	}
+
+	out := jwt.Claims{}
+	custom := new(chatDownloadClaims)
+	if err = tok.Claims([]byte(m.app.Client.Secret), &out, custom); err != nil {
+		return "", nil, fiber.StatusUnauthorized, err
+	}
+
+	if err = out.Validate(jwt.Expected{
+		Issuer: m.app.Client.ApiKey,
+		Time:   time.Now().UTC(),
+	}); err != nil {
+		return "", nil, fiber.StatusUnauthorized, err
+	}
+
+	roomInfo, err := m.getRoomInfo(custom.TenantId, custom.RoomSid)
+	if err != nil {
+		return "", nil, fiber.StatusNotFound, err
+	}
+
+	var messages []dbmodels.ChatMessage
+	orderBy := "ASC"
+	for {
+		list, total, err := m.ds.GetChatMessages(custom.TenantId, custom.RoomSid, custom.UserId, custom.IsAdmin, uint64(len(messages)), chatExportBatchSize, &orderBy)
+		if err != nil {
+			m.logger.WithError(err).Errorln("failed to get chat messages")
+			return "", nil, fiber.StatusInternalServerError, errors.New("failed to get chat messages")
+		}
+		messages = append(messages, list...)
+		if len(list) == 0 || int64(len(messages)) >= total {
+			break
+		}
+	}
+	if len(messages) == 0 {
+		return "", nil, fiber.StatusNotFound, errors.New("no chat messages found")
+	}
+
+	fileName := fmt.Sprintf("%s-%s-chat.%s", roomInfo.RoomId, roomInfo.Sid, custom.Format)
+	if custom.Format == ChatExportFormatJson {
+		list := make([]*ChatMessageInfo, 0, len(messages))
+		for i := range messages {
+			list = append(list, toChatMessageInfo(&messages[i]))
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return "", nil, fiber.StatusInternalServerError, err
+		}
+		return fileName, data, fiber.StatusOK, nil
+	}
+
+	return fileName, formatChatTranscript(roomInfo, messages), fiber.StatusOK, nil
+}
+
+// formatChatTranscript prepares plain text transcript
+// format: [time] name (private to name): message
+func formatChatTranscript(roomInfo *dbmodels.RoomInfo, messages []dbmodels.ChatMessage) []byte {
+	// to show name of the receiver of private messages
+	names := make(map[string]string)
+	for _, msg := range messages {
+		names[msg.FromUserID] = msg.FromName
+	}
+
+	var b bytes.Buffer
+	_, _ = fmt.Fprintf(&b, "Room: %s (%s)\nSession: %s\n\n", roomInfo.RoomTitle, roomInfo.RoomId, roomInfo.Sid)
+	for _, msg := range messages {
+		sentAt := time.UnixMilli(msg.SentAt).UTC().Format("2006-01-02 15:04:05")
+		if msg.IsPrivate {
+			to := msg.ToUserID
+			if name, ok := names[to]; ok && name != "" {
+				to = name
+			}
+			_, _ = fmt.Fprintf(&b, "[%s] %s (private to %s): %s\n", sentAt, msg.FromName, to, msg.Message)
+		} else {
+			_, _ = fmt.Fprintf(&b, "[%s] %s: %s\n", sentAt, msg.FromName, msg.Message)
+		}
+	}
+
+	return b.Bytes()
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+)
+
+// FetchChatMessages returns archived chat of the session, private messages
+// will be visible only for admin or the sender & the receiver.
+func (m *ChatArchiveModel) FetchChatMessages(tenantId string, r *FetchChatMessagesReq) (*FetchChatMessagesResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 50
+	}
+	if r.Limit > 500 {
+		r.Limit = 500
+	}
+	// chat transcript is more readable in ascending order
+	if r.OrderBy == "" {
+		r.OrderBy = "ASC"
+	}
+
+	roomInfo, err := m.getRoomInfo(tenantId, r.RoomSid)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, total, err := m.ds.GetChatMessages(tenantId, r.RoomSid, r.UserId, r.IsAdmin, uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*ChatMessageInfo, 0, len(messages))
+	for i := range messages {
+		list = append(list, toChatMessageInfo(&messages[i]))
+	}
+
+	return &FetchChatMessagesResult{
+		RoomId:        roomInfo.RoomId,
+		RoomSid:       roomInfo.Sid,
+		TotalMessages: total,
+		From:          r.From,
+		Limit:         r.Limit,
+		OrderBy:       r.OrderBy,
+		MessagesList:  list,
+	}, nil
+}
+
+// getRoomInfo returns the room of the session if it belongs to the tenant
+func (m *ChatArchiveModel) getRoomInfo(tenantId, roomSid string) (*dbmodels.RoomInfo, error) {
+	if roomSid == "" {
+		return nil, errors.New("room_sid required")
+	}
+
+	roomInfo, err := m.ds.GetRoomInfoBySid(roomSid, nil)
+	if err != nil {
+		return nil, err
+	}
+	if roomInfo == nil || (tenantId != "" && roomInfo.TenantID != tenantId) {
+		return nil, errors.New("no room found")
+	}
+
+	return roomInfo, nil
+}
+
+func toChatMessageInfo(v *dbmodels.ChatMessage) *ChatMessageInfo {
+	return &ChatMessageInfo{
+		MessageId:  v.MessageID,
+		FromUserId: v.FromUserID,
+		FromName:   v.FromName,
+		FromAdmin:  v.FromAdmin,
+		ToUserId:   v.ToUserID,
+		IsPrivate:  v.IsPrivate,
+		Message:    v.Message,
+		SentAt:     v.SentAt,
+	}
+}
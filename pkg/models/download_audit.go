@@ -0,0 +1,118 @@
+package models
+
+import (
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
+	"github.com/sirupsen/logrus"
+)
+
+// DownloadAuditModel keeps the logs of the downloads of recordings, analytics & uploaded files
+type DownloadAuditModel struct {
+	app    *config.AppConfig
+	ds     *dbservice.DatabaseService
+	logger *logrus.Entry
+}
+
+func NewDownloadAuditModel(app *config.AppConfig, ds *dbservice.DatabaseService, logger *logrus.Logger) *DownloadAuditModel {
+	return &DownloadAuditModel{
+		app:    app,
+		ds:     ds,
+		logger: logger.WithField("model", "download_audit"),
+	}
+}
+
+type FetchDownloadAuditsReq struct {
+	// FileType can be recording, analytics or uploaded_file
+	FileType string `json:"file_type"`
+	// FileId is the record id of recordings or the file id of analytics
+	FileId  string `json:"file_id"`
+	From    uint32 `json:"from"`
+	Limit   uint32 `json:"limit"`
+	OrderBy string `json:"order_by"`
+}
+
+type DownloadAuditInfo struct {
+	FileType    string `json:"file_type"`
+	FileId      string `json:"file_id"`
+	FileKey     string `json:"file_key"`
+	TokenId     string `json:"token_id"`
+	RequestedBy string `json:"requested_by"`
+	Ip          string `json:"ip"`
+	UserAgent   string `json:"user_agent"`
+	RangeHeader string `json:"range_header"`
+	Status      int    `json:"status"`
+	BytesServed int64  `json:"bytes_served"`
+	Created     string `json:"created"`
+}
+
+type FetchDownloadAuditsResult struct {
+	TotalAudits int64                `json:"total_audits"`
+	From        uint32               `json:"from"`
+	Limit       uint32               `json:"limit"`
+	OrderBy     string               `json:"order_by"`
+	AuditsList  []*DownloadAuditInfo `json:"audits_list"`
+}
+
+// IsEnabled returns true if the downloads should be logged
+func (m *DownloadAuditModel) IsEnabled() bool {
+	return m.app.DownloadSettings != nil && m.app.DownloadSettings.EnableAudit
+}
+
+// AddDownloadAudit will save the log if audit is enabled
+func (m *DownloadAuditModel) AddDownloadAudit(audit *dbmodels.DownloadAudit) {
+	if !m.IsEnabled() {
+		return
+	}
+
+	if _, err := m.ds.InsertDownloadAudit(audit); err != nil {
+		m.logger.WithError(err).WithFields(logrus.Fields{
+			"fileType": audit.FileType,
+			"fileKey":  audit.FileKey,
+			"method":   "AddDownloadAudit",
+		}).Errorln("failed to save download audit")
+	}
+}
+
+func (m *DownloadAuditModel) FetchDownloadAudits(tenantId string, r *FetchDownloadAuditsReq) (*FetchDownloadAuditsResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "DESC"
+	}
+
+	audits, total, err := m.ds.GetDownloadAudits(tenantId, r.FileType, r.FileId, uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*DownloadAuditInfo, 0, len(audits))
+	for _, a := range audits {
+		list = append(list, &DownloadAuditInfo{
+			FileType:    a.FileType,
+			FileId:      a.FileID,
+			FileKey:     a.FileKey,
+			TokenId:     a.TokenID,
+			RequestedBy: a.RequestedBy,
+			Ip:          a.Ip,
+			UserAgent:   a.UserAgent,
+			RangeHeader: a.RangeHeader,
+			Status:      a.Status,
+			BytesServed: a.BytesServed,
+			Created:     a.Created.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &FetchDownloadAuditsResult{
+		TotalAudits: total,
+		From:        r.From,
+		Limit:       r.Limit,
+		OrderBy:     r.OrderBy,
+		AuditsList:  list,
+	}, nil
+}
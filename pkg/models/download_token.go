@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+)
+
+const (
+	DownloadFileTypeRecording    = "recording"
+	DownloadFileTypeAnalytics    = "analytics"
+	DownloadFileTypeUploadedFile = "uploaded_file"
+)
+
+// DownloadTokenOptions are the optional restrictions of a download token.
+// Those aren't part of the protocol, so will be read separately from the token request.
+type DownloadTokenOptions struct {
+	// SingleUse will override single_use_tokens of download_settings.
+	// A single use token will be claimed by the first client,
+	// after that only the requests from the same IP will be accepted, so the download can be resumed.
+	SingleUse *bool `json:"single_use"`
+	// BindIp will accept the token only from this IP
+	BindIp string `json:"bind_ip"`
+	// RequestedBy is to identify who will download, e.g. user id.
+	// It will be kept in the download audit.
+	RequestedBy string `json:"requested_by"`
+}
+
+type downloadTokenClaims struct {
+	TenantId    string `json:"tenant_id,omitempty"`
+	FileId      string `json:"file_id,omitempty"`
+	Ip          string `json:"ip,omitempty"`
+	SingleUse   bool   `json:"single_use,omitempty"`
+	RequestedBy string `json:"requested_by,omitempty"`
+}
+
+// DownloadTokenInfo is the info of a verified download token
+type DownloadTokenInfo struct {
+	// Key of the file in the storage
+	Key         string
+	TokenId     string
+	TenantId    string
+	FileId      string
+	RequestedBy string
+	// Restricted is true for the single use or IP bound tokens,
+	// those files shouldn't be redirected as the presigned url can be used by anyone
+	Restricted bool
+}
+
+// generateDownloadToken will generate token for the key of the file in the storage
+func generateDownloadToken(app *config.AppConfig, key, tenantId, fileId string, validity time.Duration, opts *DownloadTokenOptions) (string, error) {
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(app.Client.Secret)}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", err
+	}
+
+	cl := jwt.Claims{
+		ID:        uuid.NewString(),
+		Issuer:    app.Client.ApiKey,
+		NotBefore: jwt.NewNumericDate(time.Now().UTC()),
+		Expiry:    jwt.NewNumericDate(time.Now().UTC().Add(validity)),
+		Subject:   key,
+	}
+	custom := &downloadTokenClaims{
+		TenantId:  tenantId,
+		FileId:    fileId,
+		SingleUse: app.DownloadSettings != nil && app.DownloadSettings.SingleUseTokens,
+	}
+	if opts != nil {
+		if opts.SingleUse != nil {
+			custom.SingleUse = *opts.SingleUse
+		}
+		custom.Ip = opts.BindIp
+		custom.RequestedBy = opts.RequestedBy
+	}
+
+	return jwt.Signed(sig).Claims(cl).Claims(custom).Serialize()
+}
+
+// verifyDownloadToken verifies the token for the client & returns the info of it.
+// Tokens without the custom claims will be accepted too, which are generated for BBB.
+func verifyDownloadToken(ctx context.Context, app *config.AppConfig, rs *redisservice.RedisService, token, clientIp string) (*DownloadTokenInfo, int, error) {
+	tok, err := jwt.ParseSigned(token, []jose.SignatureAlgorithm{jose.HS256})
+	if err != nil {
+		return nil, fiber.StatusUnauthorized, err
+	}
+
+	out := jwt.Claims{}
+	custom := new(downloadTokenClaims)
+	if err = tok.Claims([]byte(app.Client.Secret), &out, custom); err != nil {
+		return nil, fiber.StatusUnauthorized, err
+	}
+
+	if err = out.Validate(jwt.Expected{
+		Issuer: app.Client.ApiKey,
+		Time:   time.Now().UTC(),
+	}); err != nil {
+		return nil, fiber.StatusUnauthorized, err
+	}
+
+	if custom.Ip != "" && custom.Ip != clientIp {
+		return nil, fiber.StatusForbidden, errors.New("token is not valid for this IP")
+	}
+
+	if custom.SingleUse {
+		if out.ID == "" || out.Expiry == nil {
+			return nil, fiber.StatusUnauthorized, errors.New("invalid single use token")
+		}
+		ttl := time.Until(out.Expiry.Time())
+		if ttl < time.Second {
+			ttl = time.Second
+		}
+		claimedBy, err := rs.ClaimDownloadToken(ctx, out.ID, clientIp, ttl)
+		if err != nil {
+			return nil, fiber.StatusInternalServerError, errors.New("failed to verify token")
+		}
+		if claimedBy != clientIp {
+			return nil, fiber.StatusForbidden, errors.New("token has already been used")
+		}
+	}
+
+	return &DownloadTokenInfo{
+		Key:         out.Subject,
+		TokenId:     out.ID,
+		TenantId:    custom.TenantId,
+		FileId:      custom.FileId,
+		RequestedBy: custom.RequestedBy,
+		Restricted:  custom.SingleUse || custom.Ip != "",
+	}, fiber.StatusOK, nil
+}
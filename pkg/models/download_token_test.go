@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+func TestVerifyDownloadTokenRestricted(t *testing.T) {
+	app := &config.AppConfig{
+		Client: config.ClientInfo{ApiKey: "plugnmeet", Secret: "a-server-secret-of-32-bytes-long"},
+	}
+	tests := []struct {
+		name     string
+		opts     *DownloadTokenOptions
+		clientIp string
+		status   int
+		want     bool
+	}{
+		{"without restriction", nil, "10.0.0.1", fiber.StatusOK, false},
+		{"bound to ip", &DownloadTokenOptions{BindIp: "10.0.0.1"}, "10.0.0.1", fiber.StatusOK, true},
+		{"bound to other ip", &DownloadTokenOptions{BindIp: "10.0.0.2"}, "10.0.0.1", fiber.StatusForbidden, false},
+	}
+	for _, tt := range tests {
+		token, err := generateDownloadToken(app, "room/file.mp4", "", "file", time.Minute, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, status, err := verifyDownloadToken(context.Background(), app, nil, token, tt.clientIp)
+		if status != tt.status {
+			t.Errorf("%s: got status %d, want %d (%v)", tt.name, status, tt.status, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if info.Restricted != tt.want {
+			t.Errorf("%s: got restricted %v, want %v", tt.name, info.Restricted, tt.want)
+		}
+	}
+}
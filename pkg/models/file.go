@@ -2,10 +2,15 @@ package models
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/converter"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/sirupsen/logrus"
 )
 
@@ -14,15 +19,47 @@ type FileModel struct {
 	app         *config.AppConfig
 	ds          *dbservice.DatabaseService
 	natsService *natsservice.NatsService
+	storage     *storageservice.StorageService
+	converter   *converterservice.ConverterService
 	logger      *logrus.Entry
+
+	conversionConsumer jetstream.ConsumeContext
 }
 
-func NewFileModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, natsService *natsservice.NatsService, logger *logrus.Logger) *FileModel {
+func NewFileModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, natsService *natsservice.NatsService, storage *storageservice.StorageService, converter *converterservice.ConverterService, logger *logrus.Logger) *FileModel {
 	return &FileModel{
 		ctx:         ctx,
 		app:         app,
 		ds:          ds,
 		natsService: natsService,
+		storage:     storage,
+		converter:   converter,
 		logger:      logger.WithField("model", "file"),
 	}
 }
+
+// localUploadPath returns the path of the file inside upload directory.
+// If the file isn't available locally, then it will be fetched from the storage.
+func (m *FileModel) localUploadPath(filePath string) (string, error) {
+	fullPath := filepath.Join(m.app.UploadFileSettings.Path, filePath)
+	if _, err := os.Stat(fullPath); err == nil {
+		return fullPath, nil
+	}
+
+	if err := m.storage.Uploads().GetFile(filePath, fullPath); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
+// persistUploadedFile will save the local file to the storage.
+// For non-local storage, the local copy will be removed afterward.
+func (m *FileModel) persistUploadedFile(filePath, localPath string) error {
+	if err := m.storage.Uploads().PutFile(filePath, localPath); err != nil {
+		return err
+	}
+	if !m.storage.IsLocal() {
+		_ = os.Remove(localPath)
+	}
+	return nil
+}
@@ -4,15 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/google/uuid"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/metrics"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,6 +23,9 @@ type ConvertWhiteboardFileReq struct {
 	RoomId   string `json:"roomId" query:"roomId"`
 	UserId   string `json:"userId" query:"userId"`
 	FilePath string `json:"filePath" query:"filePath"`
+	// Async will return the job id immediately instead of waiting for the result,
+	// the progress & the result will be broadcast to the room
+	Async bool `json:"async" query:"async"`
 }
 
 // ConvertWhiteboardFileRes represents the response structure after converting a whiteboard file.
@@ -32,10 +36,15 @@ type ConvertWhiteboardFileRes struct {
 	FileId     string `json:"fileId"`
 	FilePath   string `json:"filePath"`
 	TotalPages int    `json:"totalPages"`
+	// Variants of the rendered pages, details can be found in WhiteboardPageVariant
+	Variants []*WhiteboardPageVariant `json:"variants"`
+	// HasText is true if the text of the pages is available for search
+	HasText bool `json:"hasText"`
 }
 
 // ConvertAndBroadcastWhiteboardFile will convert & broadcast files for whiteboard.
-func (m *FileModel) ConvertAndBroadcastWhiteboardFile(roomId, roomSid, filePath string) (*ConvertWhiteboardFileRes, error) {
+// It's a long-running task, which should be done by the conversion worker. progress can be nil.
+func (m *FileModel) ConvertAndBroadcastWhiteboardFile(ctx context.Context, roomId, roomSid, filePath string, progress func(status string, percent int)) (*ConvertWhiteboardFileRes, error) {
 	log := m.logger.WithFields(logrus.Fields{
 		"roomId":   roomId,
 		"roomSid":  roomSid,
@@ -44,18 +53,32 @@ func (m *FileModel) ConvertAndBroadcastWhiteboardFile(roomId, roomSid, filePath
 	})
 	log.Infoln("request to convert and broadcast whiteboard file")
 
+	start := time.Now()
+	status := metrics.StatusFailed
+	defer func() {
+		metrics.FileConversionDuration.WithLabelValues(status).Observe(time.Since(start).Seconds())
+	}()
+	if progress == nil {
+		progress = func(string, int) {}
+	}
+
 	if roomId == "" || filePath == "" {
 		err := errors.New("roomId or filePath is empty")
 		log.WithError(err).Error()
 		return nil, err
 	}
 
-	if err := checkDependencies(); err != nil {
+	if err := m.converter.CheckDependencies(); err != nil {
 		log.WithError(err).Error("dependency check failed")
 		return nil, err
 	}
 
-	fullPath := filepath.Join(m.app.UploadFileSettings.Path, filePath)
+	fullPath, err := m.localUploadPath(filePath)
+	if err != nil {
+		err = fmt.Errorf("failed to get file: %w", err)
+		log.WithError(err).Error()
+		return nil, err
+	}
 	info, err := os.Stat(fullPath)
 	if err != nil {
 		err = fmt.Errorf("failed to stat file: %w", err)
@@ -83,22 +106,29 @@ func (m *FileModel) ConvertAndBroadcastWhiteboardFile(roomId, roomSid, filePath
 		return nil, err
 	}
 
-	convertedFile, err := m.convertToPDFIfNeeded(fullPath, info.Name(), roomId, mType, outputDir)
-	if err != nil {
-		log.WithError(err).Error("failed to convert file to PDF")
-		return nil, err
+	convertedFile := fullPath
+	if !mType.Is("application/pdf") {
+		progress(FileConversionStatusConverting, 10)
+		convertedFile, err = m.converter.ConvertToPDF(ctx, fullPath, mType.String(), outputDir)
+		if err != nil {
+			log.WithError(err).Error("failed to convert file to PDF")
+			return nil, fmt.Errorf("converting to PDF failed")
+		}
 	}
 
-	if err := convertPDFToImages(m.ctx, convertedFile, outputDir, roomId, m.logger); err != nil {
+	progress(FileConversionStatusRendering, 40)
+	variants := m.whiteboardPageVariants()
+	if err := m.renderPageVariants(ctx, convertedFile, outputDir, variants); err != nil {
 		log.WithError(err).Error("failed to convert PDF to images")
-		return nil, err
+		return nil, fmt.Errorf("mutool: converting to images failed")
 	}
 
-	totalPages, err := countPages(outputDir)
+	totalPages, err := countPages(outputDir, variants)
 	if err != nil {
 		log.WithError(err).Error("failed to count pages")
 		return nil, err
 	}
+	hasText := m.extractPageText(ctx, log, convertedFile, outputDir, fileId, totalPages)
 
 	res := &ConvertWhiteboardFileRes{
 		Status:     true,
@@ -107,6 +137,18 @@ func (m *FileModel) ConvertAndBroadcastWhiteboardFile(roomId, roomSid, filePath
 		FilePath:   filepath.Join(roomSid, fileId),
 		FileId:     fileId,
 		TotalPages: totalPages,
+		Variants:   variants,
+		HasText:    hasText,
+	}
+	progress(FileConversionStatusSaving, 80)
+	if err := m.persistConvertedFiles(filepath.Join(roomSid, fileId), outputDir); err != nil {
+		log.WithError(err).Error("failed to save converted files to storage")
+		return nil, err
+	}
+	if !m.storage.IsLocal() {
+		// everything is in the storage now
+		_ = os.RemoveAll(outputDir)
+		_ = os.Remove(fullPath)
 	}
 
 	if err := m.addFileToNatsStore(roomId, res); err != nil {
@@ -114,11 +156,13 @@ func (m *FileModel) ConvertAndBroadcastWhiteboardFile(roomId, roomSid, filePath
 		// Don't return the error, as the file conversion was successful.
 	}
 
+	status = metrics.StatusSuccess
 	log.WithField("totalPages", totalPages).Info("successfully converted and broadcasted whiteboard file")
 	return res, nil
 }
 
 // addFileToNatsStore stores the metadata of a converted file into the dedicated NATS KV bucket.
+// The variants will be stored in the same record, clients can get them using GetWhiteboardFileInfo.
 func (m *FileModel) addFileToNatsStore(roomId string, fileInfo *ConvertWhiteboardFileRes) error {
 	pages := int32(fileInfo.TotalPages)
 	meta := plugnmeet.RoomUploadedFileMetadata{
@@ -128,17 +172,24 @@ func (m *FileModel) addFileToNatsStore(roomId string, fileInfo *ConvertWhiteboar
 		FileType:   plugnmeet.RoomUploadedFileType_WHITEBOARD_CONVERTED_FILE,
 		TotalPages: &pages,
 	}
-	return m.natsService.AddRoomFile(roomId, &meta)
+	return m.natsService.AddRoomFileWithExtra(roomId, &meta, &whiteboardFileExtra{
+		Variants: fileInfo.Variants,
+		HasText:  fileInfo.HasText,
+	})
 }
 
-// checkDependencies verifies that required external tools are installed.
-func checkDependencies() error {
-	for _, bin := range []string{"mutool", "soffice"} {
-		if _, err := exec.LookPath(bin); err != nil {
-			return fmt.Errorf("required binary not found in PATH: %s", bin)
+// persistConvertedFiles saves all the files of outputDir, including the variant directories, to the storage
+func (m *FileModel) persistConvertedFiles(prefix, outputDir string) error {
+	return filepath.WalkDir(outputDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
 		}
-	}
-	return nil
+		rel, err := filepath.Rel(outputDir, p)
+		if err != nil {
+			return err
+		}
+		return m.storage.Uploads().PutFile(filepath.ToSlash(filepath.Join(prefix, rel)), p)
+	})
 }
 
 // executeCommand runs a command with a timeout and handles common error cases.
@@ -155,69 +206,22 @@ func executeCommand(ctx context.Context, logger *logrus.Entry, name string, arg
 	return nil
 }
 
-// convertToPDFIfNeeded checks if the file needs to be converted to PDF based on its MIME type.
-// It returns the path to the PDF and an error.
-func (m *FileModel) convertToPDFIfNeeded(filePath, fileName, roomId string, mType *mimetype.MIME, outputDir string) (string, error) {
-	if mType.Is("application/pdf") {
-		return filePath, nil
-	}
-
-	conversionMap := map[string]string{
-		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "pdf:writer_pdf_Export",
-		"application/msword":                      "pdf:writer_pdf_Export",
-		"application/vnd.oasis.opendocument.text": "pdf:writer_pdf_Export",
-		"text/plain":                              "pdf:writer_pdf_Export",
-		"application/rtf":                         "pdf:writer_pdf_Export",
-		"application/xml":                         "pdf:writer_pdf_Export",
-		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": "pdf:calc_pdf_Export",
-		"application/vnd.ms-excel":                       "pdf:calc_pdf_Export",
-		"application/vnd.oasis.opendocument.spreadsheet": "pdf:calc_pdf_Export",
-		"text/csv": "pdf:calc_pdf_Export",
-		"application/vnd.openxmlformats-officedocument.presentationml.presentation": "pdf:impress_pdf_Export",
-		"application/vnd.ms-powerpoint":                                             "pdf:impress_pdf_Export",
-		"application/vnd.oasis.opendocument.presentation":                           "pdf:impress_pdf_Export",
-		"application/vnd.visio":                                                     "pdf:draw_pdf_Export",
-		"application/vnd.oasis.opendocument.graphics":                               "pdf:draw_pdf_Export",
-		"text/html": "pdf:writer_web_pdf_Export",
-	}
-
-	variant, supported := conversionMap[mType.String()]
-	if !supported {
-		// This case should ideally not be reached if ValidateMimeType is comprehensive
-		return "", fmt.Errorf("unsupported file type for conversion: %s", mType.String())
-	}
-
-	ctx, cancel := context.WithTimeout(m.ctx, 2*time.Minute)
-	defer cancel()
-
-	err := executeCommand(ctx, m.logger, "soffice", "--headless", "--invisible", "--nologo", "--nolockcheck", "--convert-to", variant, "--outdir", outputDir, filePath)
-	if err != nil {
-		m.logger.Errorf("soffice conversion failed for roomId: %s; file: %s; msg: %s", roomId, fileName, err)
-		return "", fmt.Errorf("soffice: converting to PDF failed")
-	}
-
-	newFile := strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".pdf"
-	return filepath.Join(outputDir, newFile), nil
-}
-
-// convertPDFToImages uses mutool to convert a PDF file into PNG images.
-func convertPDFToImages(ctx context.Context, pdfPath, outputDir, roomId string, logger *logrus.Entry) error {
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
-	defer cancel()
-
-	err := executeCommand(ctx, logger, "mutool", "convert", "-O", "resolution=300", "-o", filepath.Join(outputDir, "page_%d.png"), pdfPath)
-	if err != nil {
-		logger.Errorf("mutool conversion failed for roomId: %s; file: %s; msg: %s", roomId, pdfPath, err)
-		return fmt.Errorf("mutool: converting to images failed")
+// countPages counts the number of PNG files generated for every variant in the output directory.
+// All the variants must have the same number of pages.
+func countPages(outputDir string, variants []*WhiteboardPageVariant) (int, error) {
+	total := -1
+	for _, v := range variants {
+		files, err := filepath.Glob(filepath.Join(outputDir, v.Dir, "page_*.png"))
+		if err != nil {
+			return 0, fmt.Errorf("failed to count pages: %w", err)
+		}
+		if total >= 0 && len(files) != total {
+			return 0, fmt.Errorf("%s variant has %d pages, expected %d", v.Name, len(files), total)
+		}
+		total = len(files)
 	}
-	return nil
-}
-
-// countPages counts the number of PNG files generated in the output directory.
-func countPages(outputDir string) (int, error) {
-	files, err := filepath.Glob(filepath.Join(outputDir, "page_*.png"))
-	if err != nil {
-		return 0, fmt.Errorf("failed to count pages: %w", err)
+	if total <= 0 {
+		return 0, errors.New("no pages were generated")
 	}
-	return len(files), nil
+	return total, nil
 }
@@ -0,0 +1,347 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-server/pkg/metrics"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sirupsen/logrus"
+)
+
+// Status of the file conversion jobs, which will be sent with the progress
+const (
+	FileConversionStatusQueued     = "queued"
+	FileConversionStatusConverting = "converting"
+	FileConversionStatusRendering  = "rendering"
+	FileConversionStatusSaving     = "saving"
+	FileConversionStatusCompleted  = "completed"
+	FileConversionStatusFailed     = "failed"
+
+	// fileConversionQueueWait is how long a job may wait in the queue,
+	// when the result of the conversion is waited for
+	fileConversionQueueWait = time.Minute
+)
+
+// FileConversionJob will be kept in the JetStream work-queue,
+// so any server of the cluster can convert the file
+type FileConversionJob struct {
+	JobId    string `json:"job_id"`
+	RoomId   string `json:"room_id"`
+	RoomSid  string `json:"room_sid"`
+	UserId   string `json:"user_id,omitempty"`
+	FilePath string `json:"file_path"`
+	// Preload is for the whiteboard file of the room creation.
+	// Room metadata will be updated after conversion & the source file will be removed.
+	Preload  bool  `json:"preload,omitempty"`
+	QueuedAt int64 `json:"queued_at"`
+}
+
+// FileConversionProgress will be broadcast to the room with natsservice.FileConversionProgressEvent
+type FileConversionProgress struct {
+	JobId    string `json:"job_id"`
+	RoomId   string `json:"room_id"`
+	UserId   string `json:"user_id,omitempty"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	// available when completed
+	FileId     string `json:"file_id,omitempty"`
+	FileName   string `json:"file_name,omitempty"`
+	FilePath   string `json:"file_path,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	// Variants of the rendered pages
+	Variants []*WhiteboardPageVariant `json:"variants,omitempty"`
+	HasText  bool                     `json:"has_text,omitempty"`
+	// Msg has the reason when failed
+	Msg string `json:"msg,omitempty"`
+}
+
+// QueueWhiteboardFileConversion will add the file to the conversion queue & returns the job id.
+// The progress & the result will be broadcast to the room.
+func (m *FileModel) QueueWhiteboardFileConversion(roomId, roomSid, userId, filePath string, preload bool) (string, error) {
+	job, err := newFileConversionJob(roomId, roomSid, userId, filePath, preload)
+	if err != nil {
+		return "", err
+	}
+	if err = m.queueConversionJob(job); err != nil {
+		return "", err
+	}
+	return job.JobId, nil
+}
+
+// ConvertWhiteboardFile will add the file to the conversion queue & wait for the result.
+// The progress will be broadcast to the room as well.
+func (m *FileModel) ConvertWhiteboardFile(ctx context.Context, roomId, roomSid, userId, filePath string) (*ConvertWhiteboardFileRes, error) {
+	job, err := newFileConversionJob(roomId, roomSid, userId, filePath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// need to subscribe before queueing, otherwise we may miss the result
+	sub, err := m.natsService.SubscribeFileConversionResult(job.JobId)
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	if err = m.queueConversionJob(job); err != nil {
+		return nil, err
+	}
+
+	// the job may need to wait in the queue too
+	ctx, cancel := context.WithTimeout(ctx, m.app.FileConversionSettings.Timeout+fileConversionQueueWait)
+	defer cancel()
+	msg, err := sub.NextMsgWithContext(ctx)
+	if err != nil {
+		m.logger.WithError(err).WithFields(logrus.Fields{
+			"jobId":  job.JobId,
+			"roomId": roomId,
+			"method": "ConvertWhiteboardFile",
+		}).Errorln("failed to get the result of file conversion")
+		return nil, errors.New("file conversion didn't finish in time")
+	}
+
+	return parseFileConversionResult(msg.Data)
+}
+
+func newFileConversionJob(roomId, roomSid, userId, filePath string, preload bool) (*FileConversionJob, error) {
+	if roomId == "" || filePath == "" {
+		return nil, errors.New("roomId or filePath is empty")
+	}
+
+	return &FileConversionJob{
+		JobId:    uuid.NewString(),
+		RoomId:   roomId,
+		RoomSid:  roomSid,
+		UserId:   userId,
+		FilePath: filePath,
+		Preload:  preload,
+		QueuedAt: time.Now().UnixMilli(),
+	}, nil
+}
+
+func (m *FileModel) queueConversionJob(job *FileConversionJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if err = m.natsService.PublishFileConversionJob(data); err != nil {
+		m.logger.WithError(err).WithFields(logrus.Fields{
+			"roomId":   job.RoomId,
+			"filePath": job.FilePath,
+			"method":   "queueConversionJob",
+		}).Errorln("failed to publish file conversion job")
+		return err
+	}
+
+	m.broadcastConversionProgress(job, &FileConversionProgress{
+		Status: FileConversionStatusQueued,
+	})
+	return nil
+}
+
+// parseFileConversionResult converts the final progress of a job to the response of the conversion
+func parseFileConversionResult(data []byte) (*ConvertWhiteboardFileRes, error) {
+	p := new(FileConversionProgress)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+
+	switch p.Status {
+	case FileConversionStatusCompleted:
+		return &ConvertWhiteboardFileRes{
+			Status:     true,
+			Msg:        "success",
+			FileName:   p.FileName,
+			FileId:     p.FileId,
+			FilePath:   p.FilePath,
+			TotalPages: p.TotalPages,
+			Variants:   p.Variants,
+			HasText:    p.HasText,
+		}, nil
+	case FileConversionStatusFailed:
+		return nil, errors.New(p.Msg)
+	}
+	return nil, fmt.Errorf("unexpected file conversion status %s", p.Status)
+}
+
+// StartConversionWorker will consume the conversion jobs,
+// at most file_conversion_settings.concurrency jobs will be processed by this server at the same time
+func (m *FileModel) StartConversionWorker() {
+	log := m.logger.WithField("method", "StartConversionWorker")
+	cnf := m.app.FileConversionSettings
+
+	// the job will be given to another server only if this one has gone
+	cons, err := m.natsService.CreateFileConversionStream(cnf.Timeout + time.Minute)
+	if err != nil {
+		log.WithError(err).Errorln("failed to create file conversion stream")
+		return
+	}
+
+	sem := make(chan struct{}, cnf.Concurrency)
+	m.conversionConsumer, err = cons.Consume(func(msg jetstream.Msg) {
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			m.handleConversionJob(msg)
+		}()
+	}, jetstream.PullMaxMessages(cnf.Concurrency), jetstream.ConsumeErrHandler(func(consumeCtx jetstream.ConsumeContext, err error) {
+		if m.ctx.Err() == nil {
+			log.WithError(err).Warn("jetstream consume error")
+		}
+	}))
+	if err != nil {
+		log.WithError(err).Errorln("failed to consume file conversion jobs")
+	}
+}
+
+// StopConversionWorker will stop receiving new jobs
+func (m *FileModel) StopConversionWorker() {
+	if m.conversionConsumer != nil {
+		m.conversionConsumer.Stop()
+	}
+}
+
+func (m *FileModel) handleConversionJob(msg jetstream.Msg) {
+	job := new(FileConversionJob)
+	if err := json.Unmarshal(msg.Data(), job); err != nil {
+		m.logger.WithError(err).Errorln("invalid file conversion job, dropping")
+		_ = msg.Term()
+		return
+	}
+	log := m.logger.WithFields(logrus.Fields{
+		"jobId":    job.JobId,
+		"roomId":   job.RoomId,
+		"filePath": job.FilePath,
+		"method":   "handleConversionJob",
+	})
+	metrics.FileConversionQueueWait.Observe(time.Since(time.UnixMilli(job.QueuedAt)).Seconds())
+
+	// the room may have ended while the job was waiting
+	if info, err := m.natsService.GetRoomInfo(job.RoomId); err == nil && info == nil {
+		log.Infoln("room has ended, dropping file conversion job")
+		_ = msg.Ack()
+		m.finishConversionJob(job, &FileConversionProgress{
+			Status: FileConversionStatusFailed,
+			Msg:    "room has ended",
+		})
+		return
+	}
+	if meta, err := msg.Metadata(); err == nil && meta.NumDelivered > 1 {
+		log.WithField("delivered", meta.NumDelivered).Warnln("retrying file conversion job")
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, m.app.FileConversionSettings.Timeout)
+	defer cancel()
+
+	res, err := m.ConvertAndBroadcastWhiteboardFile(ctx, job.RoomId, job.RoomSid, job.FilePath, func(status string, percent int) {
+		// so that the job won't be redelivered to another server
+		_ = msg.InProgress()
+		m.broadcastConversionProgress(job, &FileConversionProgress{
+			Status:   status,
+			Progress: percent,
+		})
+	})
+	if job.Preload {
+		m.removePreloadSourceFile(job.FilePath)
+	}
+
+	if err != nil {
+		// the same file will fail again, so no need to retry
+		_ = msg.Term()
+		m.finishConversionJob(job, &FileConversionProgress{
+			Status: FileConversionStatusFailed,
+			Msg:    err.Error(),
+		})
+		if job.Preload {
+			if notifyErr := m.natsService.NotifyErrorMsg(job.RoomId, "notifications.preloaded-whiteboard-file-processing-error", nil); notifyErr != nil {
+				log.WithError(notifyErr).Error("failed to send notification for whiteboard processing error")
+			}
+		}
+		return
+	}
+
+	_ = msg.Ack()
+	m.finishConversionJob(job, &FileConversionProgress{
+		Status:     FileConversionStatusCompleted,
+		Progress:   100,
+		FileId:     res.FileId,
+		FileName:   res.FileName,
+		FilePath:   res.FilePath,
+		TotalPages: res.TotalPages,
+		Variants:   res.Variants,
+		HasText:    res.HasText,
+	})
+	if job.Preload {
+		m.updatePreloadedWhiteboardFile(job.RoomId, res, log)
+	}
+}
+
+// finishConversionJob will broadcast the final progress of the job
+// & send the result to the server which may be waiting for it
+func (m *FileModel) finishConversionJob(job *FileConversionJob, p *FileConversionProgress) {
+	data := m.broadcastConversionProgress(job, p)
+	if data == nil {
+		return
+	}
+	if err := m.natsService.PublishFileConversionResult(job.JobId, data); err != nil {
+		m.logger.WithError(err).WithField("jobId", job.JobId).Warnln("failed to publish file conversion result")
+	}
+}
+
+// broadcastConversionProgress returns the broadcast data or nil if it couldn't be marshaled
+func (m *FileModel) broadcastConversionProgress(job *FileConversionJob, p *FileConversionProgress) []byte {
+	p.JobId = job.JobId
+	p.RoomId = job.RoomId
+	p.UserId = job.UserId
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil
+	}
+	if err = m.natsService.BroadcastFileConversionProgress(job.RoomId, data); err != nil {
+		m.logger.WithError(err).WithFields(logrus.Fields{
+			"jobId":  job.JobId,
+			"roomId": job.RoomId,
+			"status": p.Status,
+		}).Warnln("failed to broadcast file conversion progress")
+	}
+	return data
+}
+
+// updatePreloadedWhiteboardFile will set the converted file to the whiteboard features of the room metadata
+func (m *FileModel) updatePreloadedWhiteboardFile(roomId string, res *ConvertWhiteboardFileRes, log *logrus.Entry) {
+	meta, err := m.natsService.GetRoomMetadataStruct(roomId)
+	if err != nil || meta == nil {
+		log.WithError(err).Errorln("failed to get room metadata")
+		return
+	}
+	if meta.RoomFeatures == nil || meta.RoomFeatures.WhiteboardFeatures == nil {
+		return
+	}
+
+	wbf := meta.RoomFeatures.WhiteboardFeatures
+	wbf.PreloadFile = nil
+	wbf.WhiteboardFileId = res.FileId
+	wbf.FileName = res.FileName
+	wbf.FilePath = res.FilePath
+	wbf.TotalPages = uint32(res.TotalPages)
+
+	if err = m.natsService.UpdateAndBroadcastRoomMetadata(roomId, meta); err != nil {
+		log.WithError(err).Error("failed to update room metadata after whiteboard processing")
+		return
+	}
+	log.Info("preloaded whiteboard file processed successfully")
+}
+
+// removePreloadSourceFile removes the downloaded preload file, as it was only needed for the conversion
+func (m *FileModel) removePreloadSourceFile(filePath string) {
+	_ = m.storage.Uploads().Delete(filePath)
+	_ = os.Remove(filepath.Join(m.app.UploadFileSettings.Path, filePath))
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseFileConversionResult(t *testing.T) {
+	completed, _ := json.Marshal(&FileConversionProgress{
+		JobId:      "job-1",
+		Status:     FileConversionStatusCompleted,
+		Progress:   100,
+		FileId:     "file-1",
+		FileName:   "slides.pptx",
+		FilePath:   "sid/file-1",
+		TotalPages: 3,
+		HasText:    true,
+	})
+	res, err := parseFileConversionResult(completed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Status || res.FileId != "file-1" || res.FileName != "slides.pptx" || res.FilePath != "sid/file-1" || res.TotalPages != 3 || !res.HasText {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	failed, _ := json.Marshal(&FileConversionProgress{
+		Status: FileConversionStatusFailed,
+		Msg:    "converting to PDF failed",
+	})
+	if _, err = parseFileConversionResult(failed); err == nil || err.Error() != "converting to PDF failed" {
+		t.Errorf("expected failure reason, got %v", err)
+	}
+
+	progress, _ := json.Marshal(&FileConversionProgress{Status: FileConversionStatusRendering})
+	if _, err = parseFileConversionResult(progress); err == nil {
+		t.Error("expected error for unfinished job")
+	}
+
+	if _, err = parseFileConversionResult([]byte("invalid")); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
+
+func TestNewFileConversionJob(t *testing.T) {
+	if _, err := newFileConversionJob("", "sid", "", "file.pdf", false); err == nil {
+		t.Error("expected error without roomId")
+	}
+	if _, err := newFileConversionJob("room", "sid", "", "", false); err == nil {
+		t.Error("expected error without filePath")
+	}
+
+	a, err := newFileConversionJob("room", "sid", "user", "file.pdf", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := newFileConversionJob("room", "sid", "user", "file.pdf", true)
+	if a.JobId == "" || a.JobId == b.JobId {
+		t.Errorf("expected unique job ids, got %q & %q", a.JobId, b.JobId)
+	}
+	if !a.Preload || a.QueuedAt == 0 {
+		t.Errorf("unexpected job %+v", a)
+	}
+}
@@ -3,16 +3,24 @@ package models
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 func (m *FileModel) DeleteRoomUploadedDir(roomSid string) error {
 	if roomSid == "" {
 		return fmt.Errorf("empty sid")
 	}
-	path := fmt.Sprintf("%s/%s", m.app.UploadFileSettings.Path, roomSid)
-	err := os.RemoveAll(path)
+	err := m.storage.Uploads().DeletePrefix(roomSid)
 	if err != nil {
-		m.logger.WithField("path", path).WithError(err).Errorln("can't delete room uploaded dir")
+		m.logger.WithField("roomSid", roomSid).WithError(err).Errorln("can't delete room uploaded dir")
+	}
+
+	if !m.storage.IsLocal() {
+		// chunks & conversion leftovers in the local upload directory
+		path := filepath.Join(m.app.UploadFileSettings.Path, roomSid)
+		if e := os.RemoveAll(path); e != nil {
+			m.logger.WithField("path", path).WithError(e).Errorln("can't delete room local upload dir")
+		}
 	}
 	return err
 }
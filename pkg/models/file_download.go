@@ -16,21 +16,32 @@ import (
 )
 
 // DownloadAndProcessPreUploadWBfile downloads and processes a pre-uploaded whiteboard file.
-// It validates the file, saves it, and triggers conversion and broadcasting.
-// This should be run in a separate goroutine due to its potentially long execution time.
-func (m *FileModel) DownloadAndProcessPreUploadWBfile(roomId, roomSid, fileUrl string, log *logrus.Entry) (*ConvertWhiteboardFileRes, error) {
-	log.WithFields(logrus.Fields{
+// It validates the file, saves it, and queues it for conversion.
+// The room metadata will be updated by the conversion worker.
+func (m *FileModel) DownloadAndProcessPreUploadWBfile(roomId, roomSid, fileUrl string, log *logrus.Entry) (string, error) {
+	log = log.WithFields(logrus.Fields{
 		"sub-method": "DownloadAndProcessPreUploadWBfile",
 	})
+
+	localFile, err := m.downloadRemoteWBfile(roomSid, fileUrl, log)
+	if err != nil {
+		return "", err
+	}
+	return m.queueLocalWBfile(roomId, roomSid, localFile, true, log)
+}
+
+// downloadRemoteWBfile downloads the file into the upload directory of the room
+// & returns the local path of the file
+func (m *FileModel) downloadRemoteWBfile(roomSid, fileUrl string, log *logrus.Entry) (string, error) {
 	if err := m.validateRemoteFile(fileUrl); err != nil {
 		log.WithError(err).Errorln("file validation failed")
-		return nil, err
+		return "", err
 	}
 
 	downloadDir := filepath.Join(m.app.UploadFileSettings.Path, roomSid)
 	if err := os.MkdirAll(downloadDir, os.ModePerm); err != nil {
 		log.WithError(err).Errorln("failed to create download directory")
-		return nil, fmt.Errorf("failed to create download directory: %w", err)
+		return "", fmt.Errorf("failed to create download directory: %w", err)
 	}
 
 	// Create a new grab client
@@ -38,7 +49,7 @@ func (m *FileModel) DownloadAndProcessPreUploadWBfile(roomId, roomSid, fileUrl s
 	req, err := grab.NewRequest(downloadDir, fileUrl)
 	if err != nil {
 		log.WithError(err).Errorln("failed to create download request")
-		return nil, fmt.Errorf("failed to create download request: %w", err)
+		return "", fmt.Errorf("failed to create download request: %w", err)
 	}
 
 	// Create a context with a 3-minute timeout for the download.
@@ -48,31 +59,48 @@ func (m *FileModel) DownloadAndProcessPreUploadWBfile(roomId, roomSid, fileUrl s
 	// Run the download
 	resp := client.Do(req.WithContext(ctx))
 	<-resp.Done // Wait for the download to complete or be canceled.
+	if err = resp.Err(); err != nil {
+		log.WithError(err).Errorln("failed to download file")
+		_ = os.Remove(resp.Filename)
+		return "", fmt.Errorf("failed to download file: %w", err)
+	}
 
-	defer os.Remove(resp.Filename)
+	return resp.Filename, nil
+}
 
+// queueLocalWBfile validates the type of the local file of the room,
+// saves it to the storage & queues it for conversion
+func (m *FileModel) queueLocalWBfile(roomId, roomSid, localFile string, preload bool, log *logrus.Entry) (string, error) {
 	// Validate downloaded file type
-	mType, err := mimetype.DetectFile(resp.Filename)
+	mType, err := mimetype.DetectFile(localFile)
 	if err != nil {
 		log.WithError(err).Errorln("failed to detect file type")
-		return nil, fmt.Errorf("failed to detect file type: %w", err)
+		_ = os.Remove(localFile)
+		return "", fmt.Errorf("failed to detect file type: %w", err)
 	}
 	if err := m.ValidateMimeType(mType); err != nil {
 		log.WithError(err).Errorln("downloaded file mime type is not allowed")
-		return nil, err
+		_ = os.Remove(localFile)
+		return "", err
 	}
 
 	// Construct relative file path
-	filePath := filepath.Join(roomSid, filepath.Base(resp.Filename))
+	filePath := filepath.Join(roomSid, filepath.Base(localFile))
+	// the file may be converted by another server
+	if err := m.persistUploadedFile(filePath, localFile); err != nil {
+		log.WithError(err).Errorln("failed to save file to storage")
+		_ = os.Remove(localFile)
+		return "", fmt.Errorf("failed to save file to storage: %w", err)
+	}
 
-	// Convert and broadcast. This is a synchronous, long-running task.
-	res, err := m.ConvertAndBroadcastWhiteboardFile(roomId, roomSid, filePath)
+	jobId, err := m.QueueWhiteboardFileConversion(roomId, roomSid, "", filePath, preload)
 	if err != nil {
-		log.WithError(err).Errorln("conversion/broadcast failed")
-		return nil, fmt.Errorf("conversion/broadcast failed: %w", err)
+		log.WithError(err).Errorln("failed to queue file conversion")
+		m.removePreloadSourceFile(filePath)
+		return "", fmt.Errorf("failed to queue file conversion: %w", err)
 	}
 
-	return res, nil
+	return jobId, nil
 }
 
 // validateRemoteFile checks the file's headers for size and MIME type.
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// WhiteboardDocument is a document to add to the whiteboard of an active room.
+// Either Url or Content is required.
+type WhiteboardDocument struct {
+	Url string
+	// FileName will be used for Content, the name of the url will be used otherwise
+	FileName string
+	// Content is the base64 encoded file
+	Content string
+	// Current will make it the active whiteboard file after conversion
+	Current bool
+}
+
+// InsertWhiteboardDocuments validates the documents & processes them in the background,
+// the progress & the result of the conversions will be broadcast to the room
+func (m *FileModel) InsertWhiteboardDocuments(roomId string, docs []*WhiteboardDocument) error {
+	if len(docs) == 0 {
+		return errors.New("no documents to insert")
+	}
+	info, err := m.natsService.GetRoomInfo(roomId)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		return errors.New("room is not active")
+	}
+
+	for _, d := range docs {
+		if d.Url == "" && strings.TrimSpace(d.Content) == "" {
+			return errors.New("url or content of the document is required")
+		}
+	}
+
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId":  roomId,
+		"roomSid": info.RoomSid,
+		"method":  "InsertWhiteboardDocuments",
+	})
+	go func() {
+		for _, d := range docs {
+			jobId, err := m.insertWhiteboardDocument(roomId, info.RoomSid, d, log)
+			if err != nil {
+				log.WithError(err).WithField("fileName", d.FileName).Errorln("failed to insert document")
+				continue
+			}
+			log.WithField("jobId", jobId).Infoln("document queued for conversion")
+		}
+	}()
+
+	return nil
+}
+
+func (m *FileModel) insertWhiteboardDocument(roomId, roomSid string, d *WhiteboardDocument, log *logrus.Entry) (string, error) {
+	if d.Url != "" {
+		localFile, err := m.downloadRemoteWBfile(roomSid, d.Url, log)
+		if err != nil {
+			return "", err
+		}
+		return m.queueLocalWBfile(roomId, roomSid, localFile, d.Current, log)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(d.Content), ""))
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 content: %w", err)
+	}
+	if int64(len(data)) > config.MaxPreloadedWhiteboardFileSize {
+		return "", fmt.Errorf("file too large: allowed %d bytes, got %d", config.MaxPreloadedWhiteboardFileSize, len(data))
+	}
+
+	dir := filepath.Join(m.app.UploadFileSettings.Path, roomSid)
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	safeFilename := filepath.Base(d.FileName)
+	if safeFilename == "." || safeFilename == string(filepath.Separator) {
+		safeFilename = "document"
+	}
+	// documents can have the same name, so they shouldn't replace each other
+	localFile := filepath.Join(dir, uuid.NewString()+"_"+safeFilename)
+	if err = os.WriteFile(localFile, data, 0644); err != nil {
+		return "", err
+	}
+
+	return m.queueLocalWBfile(roomId, roomSid, localFile, d.Current, log)
+}
@@ -150,6 +150,15 @@ func (m *FileModel) UploadedFileMerge(req *plugnmeet.UploadedFileMergeReq) (*plu
 	}
 
 	finalPath := filepath.Join(req.RoomSid, safeFilename)
+	if err = m.persistUploadedFile(finalPath, combinedFile); err != nil {
+		m.logger.WithFields(logrus.Fields{
+			"roomId":   req.RoomId,
+			"roomSid":  req.RoomSid,
+			"filePath": finalPath,
+		}).WithError(err).Error("failed to save file to storage")
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+
 	fileId := uuid.NewString()
 	if req.FileType != plugnmeet.RoomUploadedFileType_WHITEBOARD_CONVERTED_FILE {
 		// we can save other files because this type file will process again
@@ -1,9 +1,9 @@
 package models
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -48,15 +48,9 @@ func (m *FileModel) UploadBase64EncodedData(req *plugnmeet.UploadBase64EncodedDa
 
 	safeFilename := filepath.Base(req.GetFileName())
 
-	saveDir := filepath.Join(m.app.UploadFileSettings.Path, roomInfo.Sid)
-	if err := os.MkdirAll(saveDir, 0755); err != nil {
-		log.WithError(err).Error("failed to create upload directory")
-		return nil, fmt.Errorf("failed to create upload directory: %w", err)
-	}
-
-	filePath := filepath.Join(saveDir, safeFilename)
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		log.WithError(err).Error("failed to write file to disk")
+	filePath := filepath.Join(roomInfo.Sid, safeFilename)
+	if err := m.storage.Uploads().Put(filePath, bytes.NewReader(data), int64(len(data)), mimeType.String()); err != nil {
+		log.WithError(err).Error("failed to write file to storage")
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -79,7 +73,7 @@ func (m *FileModel) UploadBase64EncodedData(req *plugnmeet.UploadBase64EncodedDa
 		Status:        true,
 		Msg:           "file uploaded successfully",
 		FileMimeType:  mimeType.String(),
-		FilePath:      filepath.Join(roomInfo.Sid, safeFilename),
+		FilePath:      filePath,
 		FileName:      safeFilename,
 		FileExtension: strings.TrimPrefix(mimeType.Extension(), "."),
 	}, nil
@@ -0,0 +1,269 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/sirupsen/logrus"
+)
+
+// Names of the rendered page variants of the whiteboard files
+const (
+	WhiteboardPageVariantThumbnail = "thumbnail"
+	WhiteboardPageVariantScreen    = "screen"
+	WhiteboardPageVariantHighDpi   = "high_dpi"
+)
+
+const (
+	// whiteboardFileTextName will be saved next to the pages
+	whiteboardFileTextName = "text.json"
+
+	whiteboardSearchSnippetRadius = 60
+	whiteboardSearchMaxResults    = 50
+)
+
+// WhiteboardPageVariant is a set of the pages rendered in the same resolution.
+// The pages can be downloaded from /download/uploadedFile/{file_path}/{dir}/page_{n}.png
+type WhiteboardPageVariant struct {
+	Name string `json:"name"`
+	// Dir is relative to the file path, high_dpi pages are in the file path itself
+	// to stay compatible with the existing clients
+	Dir string `json:"dir"`
+	// Resolution in DPI
+	Resolution int `json:"resolution"`
+}
+
+// WhiteboardFileInfo has the extra information of a converted whiteboard file
+type WhiteboardFileInfo struct {
+	FileId     string                   `json:"file_id"`
+	FileName   string                   `json:"file_name"`
+	FilePath   string                   `json:"file_path"`
+	TotalPages int                      `json:"total_pages"`
+	Variants   []*WhiteboardPageVariant `json:"variants"`
+	HasText    bool                     `json:"has_text"`
+}
+
+// whiteboardFileExtra will be stored with the RoomUploadedFileMetadata of the file
+// because the protocol message doesn't have these fields
+type whiteboardFileExtra struct {
+	Variants []*WhiteboardPageVariant `json:"variants,omitempty"`
+	HasText  bool                     `json:"has_text,omitempty"`
+}
+
+type WhiteboardPageText struct {
+	// Page starts from 1
+	Page int    `json:"page"`
+	Text string `json:"text"`
+}
+
+type WhiteboardFileText struct {
+	FileId string                `json:"file_id"`
+	Pages  []*WhiteboardPageText `json:"pages"`
+}
+
+type GetWhiteboardFileInfoReq struct {
+	FileId   string `json:"file_id"`
+	WithText bool   `json:"with_text"`
+}
+
+type GetWhiteboardFileInfoRes struct {
+	Status bool                `json:"status"`
+	Msg    string              `json:"msg"`
+	Info   *WhiteboardFileInfo `json:"info,omitempty"`
+	Text   *WhiteboardFileText `json:"text,omitempty"`
+}
+
+type SearchWhiteboardFilesReq struct {
+	Query string `json:"query"`
+}
+
+type WhiteboardSearchResult struct {
+	FileId   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	FilePath string `json:"file_path"`
+	Page     int    `json:"page"`
+	Snippet  string `json:"snippet"`
+}
+
+// whiteboardPageVariants returns the configured variants, high_dpi first
+func (m *FileModel) whiteboardPageVariants() []*WhiteboardPageVariant {
+	res := m.app.FileConversionSettings.PageResolutions
+	return []*WhiteboardPageVariant{
+		{Name: WhiteboardPageVariantHighDpi, Dir: "", Resolution: res.HighDpi},
+		{Name: WhiteboardPageVariantScreen, Dir: WhiteboardPageVariantScreen, Resolution: res.Screen},
+		{Name: WhiteboardPageVariantThumbnail, Dir: WhiteboardPageVariantThumbnail, Resolution: res.Thumbnail},
+	}
+}
+
+// renderPageVariants renders the PDF once for every variant inside outputDir
+func (m *FileModel) renderPageVariants(ctx context.Context, pdfPath, outputDir string, variants []*WhiteboardPageVariant) error {
+	for _, v := range variants {
+		dir := filepath.Join(outputDir, v.Dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := m.converter.RenderPDFToImages(ctx, pdfPath, dir, v.Resolution); err != nil {
+			return fmt.Errorf("%s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// extractPageText writes text.json of the PDF inside outputDir & returns true if any page has text.
+// Files can be used without the text, so errors will be only logged.
+func (m *FileModel) extractPageText(ctx context.Context, log *logrus.Entry, pdfPath, outputDir, fileId string, totalPages int) bool {
+	if m.app.FileConversionSettings.DisableTextExtraction {
+		return false
+	}
+
+	// should not be saved to the storage, so outside of outputDir
+	textDir, err := os.MkdirTemp(m.app.UploadFileSettings.Path, "wb-text-")
+	if err != nil {
+		log.WithError(err).Warnln("failed to create text directory")
+		return false
+	}
+	defer os.RemoveAll(textDir)
+
+	if err = m.converter.ExtractPDFText(ctx, pdfPath, textDir); err != nil {
+		log.WithError(err).Warnln("failed to extract text")
+		return false
+	}
+
+	ft := &WhiteboardFileText{
+		FileId: fileId,
+		Pages:  make([]*WhiteboardPageText, 0, totalPages),
+	}
+	for i := 1; i <= totalPages; i++ {
+		data, err := os.ReadFile(filepath.Join(textDir, fmt.Sprintf("page_%d.txt", i)))
+		if err != nil {
+			continue
+		}
+		if text := helpers.CleanExtractedText(string(data)); text != "" {
+			ft.Pages = append(ft.Pages, &WhiteboardPageText{Page: i, Text: text})
+		}
+	}
+	if len(ft.Pages) == 0 {
+		return false
+	}
+
+	if err = writeJSONFile(filepath.Join(outputDir, whiteboardFileTextName), ft); err != nil {
+		log.WithError(err).Warnln("failed to write text file")
+		return false
+	}
+	return true
+}
+
+// GetWhiteboardFileInfo returns the information of the converted file of the room.
+// Files converted by the older versions will only have the high_dpi variant.
+func (m *FileModel) GetWhiteboardFileInfo(roomId string, req *GetWhiteboardFileInfoReq) (*WhiteboardFileInfo, *WhiteboardFileText, error) {
+	extra := new(whiteboardFileExtra)
+	meta, err := m.natsService.GetRoomFileWithExtra(roomId, req.FileId, extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta == nil || meta.FileType != plugnmeet.RoomUploadedFileType_WHITEBOARD_CONVERTED_FILE {
+		return nil, nil, errors.New("file not found")
+	}
+
+	info := newWhiteboardFileInfo(meta, extra)
+	if !req.WithText || !info.HasText {
+		return info, nil, nil
+	}
+	text := new(WhiteboardFileText)
+	if err = m.readStorageJSON(path.Join(meta.FilePath, whiteboardFileTextName), text); err != nil {
+		return nil, nil, err
+	}
+	return info, text, nil
+}
+
+func newWhiteboardFileInfo(meta *plugnmeet.RoomUploadedFileMetadata, extra *whiteboardFileExtra) *WhiteboardFileInfo {
+	info := &WhiteboardFileInfo{
+		FileId:     meta.FileId,
+		FileName:   meta.FileName,
+		FilePath:   meta.FilePath,
+		TotalPages: int(meta.GetTotalPages()),
+		Variants:   extra.Variants,
+		HasText:    extra.HasText,
+	}
+	if len(info.Variants) == 0 {
+		// converted by the older versions
+		info.Variants = []*WhiteboardPageVariant{
+			{Name: WhiteboardPageVariantHighDpi, Resolution: 300},
+		}
+	}
+	return info
+}
+
+// SearchWhiteboardFiles searches the text of all the converted files of the room
+func (m *FileModel) SearchWhiteboardFiles(roomId, query string) ([]*WhiteboardSearchResult, error) {
+	if len([]rune(strings.TrimSpace(query))) < 2 {
+		return nil, errors.New("query must have at least 2 characters")
+	}
+	files, err := m.natsService.GetAllRoomFiles(roomId)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]*plugnmeet.RoomUploadedFileMetadata, 0, len(files))
+	for _, f := range files {
+		if f.FileType == plugnmeet.RoomUploadedFileType_WHITEBOARD_CONVERTED_FILE {
+			converted = append(converted, f)
+		}
+	}
+	sort.Slice(converted, func(i, j int) bool {
+		return converted[i].FileName < converted[j].FileName
+	})
+
+	results := make([]*WhiteboardSearchResult, 0)
+	for _, f := range converted {
+		text := new(WhiteboardFileText)
+		if err := m.readStorageJSON(path.Join(f.FilePath, whiteboardFileTextName), text); err != nil {
+			// files without text or converted by the older versions
+			continue
+		}
+		for _, p := range text.Pages {
+			snippet, ok := helpers.TextSnippet(p.Text, query, whiteboardSearchSnippetRadius)
+			if !ok {
+				continue
+			}
+			results = append(results, &WhiteboardSearchResult{
+				FileId:   f.FileId,
+				FileName: f.FileName,
+				FilePath: f.FilePath,
+				Page:     p.Page,
+				Snippet:  snippet,
+			})
+			if len(results) >= whiteboardSearchMaxResults {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (m *FileModel) readStorageJSON(key string, v interface{}) error {
+	rc, _, err := m.storage.Uploads().Get(key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return json.NewDecoder(rc).Decode(v)
+}
+
+func writeJSONFile(file string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+)
+
+func TestNewWhiteboardFileInfo(t *testing.T) {
+	pages := int32(2)
+	meta := &plugnmeet.RoomUploadedFileMetadata{
+		FileId:     "file-1",
+		FileName:   "slides.pdf",
+		FilePath:   "sid/file-1",
+		TotalPages: &pages,
+	}
+
+	info := newWhiteboardFileInfo(meta, &whiteboardFileExtra{
+		Variants: []*WhiteboardPageVariant{
+			{Name: WhiteboardPageVariantHighDpi, Resolution: 300},
+			{Name: WhiteboardPageVariantThumbnail, Dir: WhiteboardPageVariantThumbnail, Resolution: 36},
+		},
+		HasText: true,
+	})
+	if info.FileId != "file-1" || info.TotalPages != 2 || len(info.Variants) != 2 || !info.HasText {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	// converted by the older versions
+	info = newWhiteboardFileInfo(meta, new(whiteboardFileExtra))
+	if len(info.Variants) != 1 || info.Variants[0].Name != WhiteboardPageVariantHighDpi || info.HasText {
+		t.Errorf("expected only high_dpi variant, got %+v", info.Variants)
+	}
+}
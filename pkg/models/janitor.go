@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/metrics"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
 	livekitservice "github.com/mynaparrot/plugnmeet-server/pkg/services/livekit"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
@@ -25,10 +27,17 @@ type JanitorModel struct {
 	rs          *redisservice.RedisService
 	natsService *natsservice.NatsService
 	lk          *livekitservice.LivekitService
+	storage     *storageservice.StorageService
 	rm          *RoomModel
+	sm          *ScheduleModel
+	pm          *PollModel
 
-	rmDuration *RoomDurationModel
-	logger     *logrus.Entry
+	recordingModel *RecordingModel
+	rmDuration     *RoomDurationModel
+	logger         *logrus.Entry
+
+	// runningTasks holds the name of the async tasks which are still running
+	runningTasks sync.Map
 
 	// leader election for janitor
 	leaderLockVal string
@@ -37,7 +46,7 @@ type JanitorModel struct {
 }
 
 // NewJanitorModel creates a new JanitorModel.
-func NewJanitorModel(mainCtx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, lk *livekitservice.LivekitService, rm *RoomModel, rmDuration *RoomDurationModel, logger *logrus.Logger) *JanitorModel {
+func NewJanitorModel(mainCtx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, lk *livekitservice.LivekitService, storage *storageservice.StorageService, rm *RoomModel, sm *ScheduleModel, pm *PollModel, recordingModel *RecordingModel, rmDuration *RoomDurationModel, logger *logrus.Logger) *JanitorModel {
 	ctx, cancel := context.WithCancel(mainCtx)
 
 	return &JanitorModel{
@@ -47,11 +56,16 @@ func NewJanitorModel(mainCtx context.Context, app *config.AppConfig, ds *dbservi
 		ds:          ds,
 		rs:          rs,
 		lk:          lk,
+		storage:     storage,
 		rm:          rm,
+		sm:          sm,
+		pm:          pm,
 		rmDuration:  rmDuration,
 		natsService: natsService,
 		logger:      logger.WithField("model", "janitor"),
 
+		recordingModel: recordingModel,
+
 		leaderLockTTL: 1 * time.Minute,
 		leaderRenewal: 30 * time.Second,
 	}
@@ -83,8 +97,10 @@ func (m *JanitorModel) StartJanitor() {
 				m.mu.Lock()
 				m.leaderLockVal = lockVal
 				m.mu.Unlock()
+				metrics.JanitorLeader.Set(1)
 				// We are the leader. Run the tasks until we lose the lock or context is canceled.
 				m.runJanitorTasks()
+				metrics.JanitorLeader.Set(0)
 				m.logger.Warnln("Stopped being the janitor leader.")
 			} else {
 				// Not the leader, wait and try again later.
@@ -109,6 +125,10 @@ func (m *JanitorModel) runJanitorTasks() {
 	nextUserCheck := time.Now().Add(time.Minute)
 	nextRoomCheck := time.Now().Add(5 * time.Minute)
 	nextBackupCheck := time.Now().Add(time.Hour)
+	nextScheduleCheck := time.Now().Add(30 * time.Second)
+	nextChatArchiveCheck := time.Now().Add(time.Hour)
+	nextRecordingRetentionCheck := time.Now().Add(time.Hour)
+	nextDownloadAuditCheck := time.Now().Add(time.Hour)
 
 	for {
 		select {
@@ -119,6 +139,7 @@ func (m *JanitorModel) runJanitorTasks() {
 			// These tasks run on their own schedule.
 			// The individual locks inside each task ensure safety if the leader changes mid-operation.
 			m.checkRoomWithDuration()
+			m.checkPollDeadlines()
 
 			if now.After(nextUserCheck) {
 				m.checkOnlineUsersStatus()
@@ -132,6 +153,22 @@ func (m *JanitorModel) runJanitorTasks() {
 				m.checkDelRecordingBackupPath()
 				nextBackupCheck = time.Now().Add(time.Hour)
 			}
+			if now.After(nextScheduleCheck) {
+				m.runAsync("checkScheduledMeetings", m.checkScheduledMeetings)
+				nextScheduleCheck = time.Now().Add(30 * time.Second)
+			}
+			if now.After(nextChatArchiveCheck) {
+				m.checkChatArchiveRetention()
+				nextChatArchiveCheck = time.Now().Add(time.Hour)
+			}
+			if now.After(nextRecordingRetentionCheck) {
+				m.runAsync("checkRecordingRetention", m.checkRecordingRetention)
+				nextRecordingRetentionCheck = time.Now().Add(time.Hour)
+			}
+			if now.After(nextDownloadAuditCheck) {
+				m.checkDownloadAuditRetention()
+				nextDownloadAuditCheck = time.Now().Add(time.Hour)
+			}
 		case <-renewalTicker.C:
 			// Copy the lock value to a local var to avoid holding the lock during a network call.
 			m.mu.RLock()
@@ -151,6 +188,19 @@ func (m *JanitorModel) runJanitorTasks() {
 	}
 }
 
+// runAsync runs the task in its own goroutine, so that the slow tasks
+// won't delay the other tasks & the renewal of the leader lock.
+// The task will be skipped if the previous run hasn't finished yet.
+func (m *JanitorModel) runAsync(name string, task func()) {
+	if _, running := m.runningTasks.LoadOrStore(name, true); running {
+		return
+	}
+	go func() {
+		defer m.runningTasks.Delete(name)
+		task()
+	}()
+}
+
 func (m *JanitorModel) Shutdown() {
 	m.logger.Infoln("Janitor shutting down.")
 	// Copy the lock value to a local var to avoid holding the lock during a network call.
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// checkChatArchiveRetention will delete archived chat messages older than the retention
+func (m *JanitorModel) checkChatArchiveRetention() {
+	if m.app.ChatArchiveSettings == nil {
+		// nothing to do
+		return
+	}
+	log := m.logger.WithField("task", "checkChatArchiveRetention")
+
+	before := time.Now().Add(-m.app.ChatArchiveSettings.Retention)
+	deleted, err := m.ds.DeleteChatMessagesBefore(before)
+	if err != nil {
+		log.WithError(err).Errorln("failed to delete expired chat messages")
+		return
+	}
+	if deleted > 0 {
+		log.WithField("deleted", deleted).Infoln("deleted expired chat messages")
+	}
+}
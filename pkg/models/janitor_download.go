@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// checkDownloadAuditRetention will delete download audit logs older than the retention
+func (m *JanitorModel) checkDownloadAuditRetention() {
+	if m.app.DownloadSettings == nil || !m.app.DownloadSettings.EnableAudit {
+		// nothing to do
+		return
+	}
+	log := m.logger.WithField("task", "checkDownloadAuditRetention")
+
+	before := time.Now().Add(-m.app.DownloadSettings.AuditRetention)
+	deleted, err := m.ds.DeleteDownloadAuditsBefore(before)
+	if err != nil {
+		log.WithError(err).Errorln("failed to delete expired download audits")
+		return
+	}
+	if deleted > 0 {
+		log.WithField("deleted", deleted).Infoln("deleted expired download audits")
+	}
+}
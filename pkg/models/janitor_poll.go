@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+)
+
+// checkPollDeadlines will close the polls which time limit has been passed
+func (m *JanitorModel) checkPollDeadlines() {
+	log := m.logger.WithField("task", "checkPollDeadlines")
+
+	polls, err := m.rs.GetExpiredPollDeadlines(time.Now().Unix())
+	if err != nil {
+		log.WithError(err).Errorln("failed to get expired poll deadlines")
+		return
+	}
+
+	for pollId, roomId := range polls {
+		// only the process removed it will close the poll
+		removed, err := m.rs.RemovePollDeadline(roomId, pollId)
+		if err != nil || !removed {
+			continue
+		}
+
+		err = m.pm.ClosePoll(&plugnmeet.ClosePollReq{
+			RoomId: roomId,
+			PollId: pollId,
+			UserId: "system",
+		})
+		if err != nil {
+			log.WithError(err).WithField("pollId", pollId).Warnln("failed to close poll after deadline")
+		}
+	}
+}
@@ -1,8 +1,7 @@
 package models
 
 import (
-	"os"
-	"path"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -16,39 +15,63 @@ func (m *JanitorModel) checkDelRecordingBackupPath() {
 	log := m.logger.WithField("task", "checkDelRecordingBackupPath")
 
 	checkTime := time.Now().Add(-m.app.RecorderInfo.DelRecordingBackupDuration)
-	entries, err := os.ReadDir(m.app.RecorderInfo.DelRecordingBackupPath)
+	store := m.storage.RecordingBackups()
+	entries, err := store.List("")
 	if err != nil {
 		log.WithError(err).Errorln("failed to read recording backup directory")
 		return
 	}
 	for _, et := range entries {
-		if et.IsDir() {
-			continue
-		}
-		info, err := et.Info()
-		if err != nil {
+		if strings.HasSuffix(et.Key, ".json") {
+			// will be deleted with the video file
 			continue
 		}
 
-		if info.ModTime().Before(checkTime) {
+		if et.ModTime.Before(checkTime) {
 			// we can remove this file
-			fileToDelete := path.Join(m.app.RecorderInfo.DelRecordingBackupPath, et.Name())
 			log.WithFields(logrus.Fields{
-				"file":         fileToDelete,
-				"modTime":      info.ModTime().Format(time.RFC3339),
+				"file":         et.Key,
+				"modTime":      et.ModTime.Format(time.RFC3339),
 				"ageThreshold": checkTime.Format(time.RFC3339),
 				"backupMaxAge": m.app.RecorderInfo.DelRecordingBackupDuration.String(),
 			}).Warn("deleting expired recording backup file")
 			// video file
-			err = os.Remove(fileToDelete)
+			err = store.Delete(et.Key)
 			if err != nil {
 				m.logger.WithError(err).Errorln("error deleting file")
 			}
 			// info JSON file
-			err = os.Remove(fileToDelete + ".json")
+			err = store.Delete(et.Key + ".json")
 			if err != nil {
 				m.logger.WithError(err).Errorln("error deleting file")
 			}
 		}
 	}
 }
+
+// checkRecordingRetention will delete the recordings which have passed their retention
+func (m *JanitorModel) checkRecordingRetention() {
+	log := m.logger.WithField("task", "checkRecordingRetention")
+	req := &ExpiredRecordingsReq{Limit: 100}
+
+	for m.ctx.Err() == nil {
+		res, err := m.recordingModel.ExpireRecordings("", req)
+		if err != nil {
+			log.WithError(err).Errorln("failed to expire recordings")
+			return
+		}
+		if !hasMoreExpiredRecordings(res, req.Limit) {
+			return
+		}
+	}
+}
+
+// hasMoreExpiredRecordings checks if another batch should be processed.
+// Failed recordings were postponed, so they won't be in the next batch.
+// If nothing could be processed then we'll stop to avoid looping on the same batch.
+func hasMoreExpiredRecordings(res *ExpiredRecordingsResult, limit uint32) bool {
+	if len(res.Recordings) < int(limit) {
+		return false
+	}
+	return res.Deleted+res.Postponed > 0
+}
@@ -13,7 +13,7 @@ import (
 func (m *JanitorModel) activeRoomChecker() {
 	log := m.logger.WithField("task", "activeRoomChecker")
 
-	activeRooms, err := m.ds.GetActiveRoomsInfo()
+	activeRooms, err := m.ds.GetActiveRoomsInfo("")
 	if err != nil {
 		return
 	}
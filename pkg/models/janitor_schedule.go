@@ -0,0 +1,10 @@
+package models
+
+// checkScheduledMeetings will create rooms for upcoming scheduled meetings
+// based on their lead time
+func (m *JanitorModel) checkScheduledMeetings() {
+	if m.sm == nil {
+		return
+	}
+	m.sm.CreateDueScheduledRooms()
+}
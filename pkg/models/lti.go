@@ -2,11 +2,18 @@ package models
 
 import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/lti"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
 	"github.com/sirupsen/logrus"
 )
 
 type LtiV1Model struct {
 	app    *config.AppConfig
+	ds     *dbservice.DatabaseService
+	rs     *redisservice.RedisService
+	ls     *ltiservice.LtiService
+	gm     *LtiGradeModel
 	rm     *RoomModel
 	um     *UserModel
 	logger *logrus.Entry
@@ -47,9 +54,13 @@ type LTIV1FetchRecordingsReq struct {
 	OrderBy string `json:"order_by"`
 }
 
-func NewLtiV1Model(app *config.AppConfig, rm *RoomModel, um *UserModel) *LtiV1Model {
+func NewLtiV1Model(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, ls *ltiservice.LtiService, gm *LtiGradeModel, rm *RoomModel, um *UserModel) *LtiV1Model {
 	return &LtiV1Model{
 		app:    app,
+		ds:     ds,
+		rs:     rs,
+		ls:     ls,
+		gm:     gm,
 		rm:     rm,
 		um:     um,
 		logger: rm.logger.Logger.WithField("model", "lti_v1"),
@@ -13,15 +13,22 @@ import (
 	"github.com/jordic/lti"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
 	"github.com/sirupsen/logrus"
 )
 
-func (m *LtiV1Model) VerifyAuth(requests, signingURL string) (*url.Values, error) {
+// VerifyAuth verifies the signature of the launch using the secret of the consumer
+func (m *LtiV1Model) VerifyAuth(requests, signingURL string) (*url.Values, *dbmodels.LtiConsumer, error) {
+	return verifyLtiV1Launch(m.logger, requests, signingURL, func(consumerKey string) (*dbmodels.LtiConsumer, error) {
+		return findLtiConsumer(m.app, m.ds.GetLtiConsumer, consumerKey)
+	})
+}
+
+// verifyLtiV1Launch parses the launch & verifies its signature with the secret of the consumer returned by find
+func verifyLtiV1Launch(log *logrus.Entry, requests, signingURL string, find func(consumerKey string) (*dbmodels.LtiConsumer, error)) (*url.Values, *dbmodels.LtiConsumer, error) {
 	r := strings.Split(requests, "&")
-	p := lti.NewProvider(m.app.Client.Secret, signingURL)
-	p.Method = "POST"
-	p.ConsumerKey = m.app.Client.ApiKey
 	var providedSignature string
+	fields := url.Values{}
 
 	for _, f := range r {
 		t := strings.Split(f, "=")
@@ -29,29 +36,36 @@ func (m *LtiV1Model) VerifyAuth(requests, signingURL string) (*url.Values, error
 		if t[0] == "oauth_signature" {
 			providedSignature = b
 		} else {
-			p.Add(t[0], b)
+			fields.Set(t[0], b)
 		}
 	}
 
-	if p.Get("oauth_consumer_key") != p.ConsumerKey {
-		return nil, errors.New(config.InvalidConsumerKey)
+	consumer, err := find(fields.Get("oauth_consumer_key"))
+	if err != nil {
+		return nil, nil, err
 	}
 
+	p := lti.NewProvider(consumer.Secret, signingURL)
+	p.Method = "POST"
+	p.ConsumerKey = consumer.ConsumerKey
+	p.SetParams(fields)
+
 	sign, err := p.Sign()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	params := p.Params()
 
 	if sign != providedSignature {
-		m.logger.WithFields(logrus.Fields{
-			"calculated": sign,
-			"provided":   providedSignature,
-		}).WithError(err).Errorln("signature verification failed")
-		return nil, errors.New(config.VerificationFailed)
+		log.WithFields(logrus.Fields{
+			"consumerKey": consumer.ConsumerKey,
+			"calculated":  sign,
+			"provided":    providedSignature,
+		}).Errorln("signature verification failed")
+		return nil, nil, errors.New(config.VerificationFailed)
 	}
 
-	return &params, nil
+	return &params, consumer, nil
 }
 
 func (m *LtiV1Model) genHashId(id string) string {
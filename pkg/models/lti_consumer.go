@@ -0,0 +1,359 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+type CreateLtiConsumerReq struct {
+	Name string `json:"name"`
+	// ConsumerKey will be generated if empty
+	ConsumerKey   string               `json:"consumer_key"`
+	DefaultParams *LtiCustomParameters `json:"default_params"`
+	Enabled       *bool                `json:"enabled"`
+}
+
+type UpdateLtiConsumerReq struct {
+	ConsumerKey   string               `json:"consumer_key"`
+	Name          *string              `json:"name"`
+	DefaultParams *LtiCustomParameters `json:"default_params"`
+	Enabled       *bool                `json:"enabled"`
+}
+
+type FetchLtiConsumersReq struct {
+	From    uint32 `json:"from"`
+	Limit   uint32 `json:"limit"`
+	OrderBy string `json:"order_by"`
+}
+
+type LtiConsumerReq struct {
+	ConsumerKey string `json:"consumer_key"`
+}
+
+type LtiConsumerInfo struct {
+	ConsumerKey string `json:"consumer_key"`
+	// Secret will be only returned after creating or regenerating it
+	Secret        string               `json:"secret,omitempty"`
+	Name          string               `json:"name"`
+	DefaultParams *LtiCustomParameters `json:"default_params,omitempty"`
+	Enabled       bool                 `json:"enabled"`
+	Created       string               `json:"created"`
+	Modified      string               `json:"modified"`
+}
+
+type FetchLtiConsumersResult struct {
+	TotalConsumers int64              `json:"total_consumers"`
+	From           uint32             `json:"from"`
+	Limit          uint32             `json:"limit"`
+	OrderBy        string             `json:"order_by"`
+	ConsumersList  []*LtiConsumerInfo `json:"consumers_list"`
+}
+
+func (m *LtiV1Model) CreateLtiConsumer(r *CreateLtiConsumerReq) (*LtiConsumerInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"name":   r.Name,
+		"method": "CreateLtiConsumer",
+	})
+	log.Infoln("request to create lti consumer")
+
+	key := strings.TrimSpace(r.ConsumerKey)
+	if key == "" {
+		k, err := generateRandomHex(12)
+		if err != nil {
+			return nil, err
+		}
+		key = "lti_" + k
+	}
+	if strings.ContainsAny(key, " ,&=") {
+		return nil, errors.New("consumer_key can't contain spaces, commas, & or =")
+	}
+	if key == m.app.Client.ApiKey {
+		return nil, errors.New("consumer_key can't be the same as the api key of the server")
+	}
+
+	existing, err := m.ds.GetLtiConsumer(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("consumer with the same consumer_key already exists")
+	}
+
+	secret, err := generateRandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	params, err := encodeLtiDefaultParams(r.DefaultParams)
+	if err != nil {
+		return nil, err
+	}
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	info := &dbmodels.LtiConsumer{
+		ConsumerKey:   key,
+		Secret:        secret,
+		Name:          r.Name,
+		DefaultParams: params,
+		Enabled:       enabled,
+	}
+	if _, err = m.ds.InsertOrUpdateLtiConsumer(info); err != nil {
+		log.WithError(err).Errorln("failed to save lti consumer")
+		return nil, err
+	}
+
+	log.WithField("consumerKey", key).Infoln("successfully created lti consumer")
+	res := m.toLtiConsumerInfo(info)
+	res.Secret = info.Secret
+	return res, nil
+}
+
+func (m *LtiV1Model) UpdateLtiConsumer(r *UpdateLtiConsumerReq) (*LtiConsumerInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"consumerKey": r.ConsumerKey,
+		"method":      "UpdateLtiConsumer",
+	})
+
+	info, err := m.ds.GetLtiConsumer(r.ConsumerKey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("lti consumer not found")
+	}
+
+	if r.Name != nil {
+		info.Name = *r.Name
+	}
+	if r.DefaultParams != nil {
+		if info.DefaultParams, err = encodeLtiDefaultParams(r.DefaultParams); err != nil {
+			return nil, err
+		}
+	}
+	if r.Enabled != nil {
+		info.Enabled = *r.Enabled
+	}
+
+	if _, err = m.ds.InsertOrUpdateLtiConsumer(info); err != nil {
+		log.WithError(err).Errorln("failed to update lti consumer")
+		return nil, err
+	}
+
+	log.Infoln("successfully updated lti consumer")
+	return m.toLtiConsumerInfo(info), nil
+}
+
+func (m *LtiV1Model) FetchLtiConsumers(r *FetchLtiConsumersReq) (*FetchLtiConsumersResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "DESC"
+	}
+
+	consumers, total, err := m.ds.GetLtiConsumers(uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*LtiConsumerInfo, 0, len(consumers))
+	for i := range consumers {
+		list = append(list, m.toLtiConsumerInfo(&consumers[i]))
+	}
+
+	return &FetchLtiConsumersResult{
+		TotalConsumers: total,
+		From:           r.From,
+		Limit:          r.Limit,
+		OrderBy:        r.OrderBy,
+		ConsumersList:  list,
+	}, nil
+}
+
+func (m *LtiV1Model) GetLtiConsumerInfo(r *LtiConsumerReq) (*LtiConsumerInfo, error) {
+	info, err := m.ds.GetLtiConsumer(r.ConsumerKey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("lti consumer not found")
+	}
+
+	return m.toLtiConsumerInfo(info), nil
+}
+
+// RegenerateLtiConsumerSecret will replace the secret,
+// the launches signed by the old secret won't be accepted anymore
+func (m *LtiV1Model) RegenerateLtiConsumerSecret(r *LtiConsumerReq) (*LtiConsumerInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"consumerKey": r.ConsumerKey,
+		"method":      "RegenerateLtiConsumerSecret",
+	})
+
+	info, err := m.ds.GetLtiConsumer(r.ConsumerKey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("lti consumer not found")
+	}
+
+	if info.Secret, err = generateRandomHex(32); err != nil {
+		return nil, err
+	}
+	if _, err = m.ds.InsertOrUpdateLtiConsumer(info); err != nil {
+		log.WithError(err).Errorln("failed to update lti consumer secret")
+		return nil, err
+	}
+
+	log.Infoln("successfully regenerated lti consumer secret")
+	res := m.toLtiConsumerInfo(info)
+	res.Secret = info.Secret
+	return res, nil
+}
+
+func (m *LtiV1Model) DeleteLtiConsumer(r *LtiConsumerReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"consumerKey": r.ConsumerKey,
+		"method":      "DeleteLtiConsumer",
+	})
+
+	affected, err := m.ds.DeleteLtiConsumer(r.ConsumerKey)
+	if err != nil {
+		log.WithError(err).Errorln("failed to delete lti consumer")
+		return err
+	}
+	if affected == 0 {
+		return errors.New("lti consumer not found")
+	}
+
+	log.Infoln("successfully deleted lti consumer")
+	return nil
+}
+
+func (m *LtiV1Model) toLtiConsumerInfo(c *dbmodels.LtiConsumer) *LtiConsumerInfo {
+	return &LtiConsumerInfo{
+		ConsumerKey:   c.ConsumerKey,
+		Name:          c.Name,
+		DefaultParams: decodeLtiDefaultParams(c.DefaultParams),
+		Enabled:       c.Enabled,
+		Created:       c.Created.Format("2006-01-02 15:04:05"),
+		Modified:      c.Modified.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// findLtiConsumer returns the enabled consumer of the key.
+// The api key of the server will be returned as a consumer without ID,
+// so the existing integrations will keep working.
+func findLtiConsumer(app *config.AppConfig, getConsumer func(consumerKey string) (*dbmodels.LtiConsumer, error), consumerKey string) (*dbmodels.LtiConsumer, error) {
+	if consumerKey == "" {
+		return nil, errors.New(config.InvalidConsumerKey)
+	}
+	if consumerKey == app.Client.ApiKey {
+		return &dbmodels.LtiConsumer{
+			ConsumerKey: app.Client.ApiKey,
+			Secret:      app.Client.Secret,
+			Enabled:     true,
+		}, nil
+	}
+
+	consumer, err := getConsumer(consumerKey)
+	if err != nil {
+		return nil, err
+	}
+	if consumer == nil || !consumer.Enabled {
+		return nil, errors.New(config.InvalidConsumerKey)
+	}
+	return consumer, nil
+}
+
+// applyLtiConsumerDefaults sets the default parameters of the consumer
+// which weren't sent with the launch
+func applyLtiConsumerDefaults(claims *plugnmeet.LtiClaims, defaults *LtiCustomParameters) {
+	if defaults == nil {
+		return
+	}
+	if claims.LtiCustomParameters == nil {
+		claims.LtiCustomParameters = new(plugnmeet.LtiCustomParameters)
+	}
+	p := claims.LtiCustomParameters
+
+	if p.RoomDuration == nil && defaults.RoomDuration > 0 {
+		d := defaults.RoomDuration
+		p.RoomDuration = &d
+	}
+	for _, f := range []struct {
+		param *(*bool)
+		def   *bool
+	}{
+		{&p.AllowPolls, defaults.AllowPolls},
+		{&p.AllowSharedNotePad, defaults.AllowSharedNotePad},
+		{&p.AllowBreakoutRoom, defaults.AllowBreakoutRoom},
+		{&p.AllowRecording, defaults.AllowRecording},
+		{&p.AllowRtmp, defaults.AllowRTMP},
+		{&p.AllowViewOtherWebcams, defaults.AllowViewOtherWebcams},
+		{&p.AllowViewOtherUsersList, defaults.AllowViewOtherParticipants},
+		{&p.MuteOnStart, defaults.MuteOnStart},
+	} {
+		if *f.param == nil && f.def != nil {
+			v := *f.def
+			*f.param = &v
+		}
+	}
+
+	if defaults.LtiCustomDesign == nil {
+		return
+	}
+	if p.LtiCustomDesign == nil {
+		p.LtiCustomDesign = new(plugnmeet.LtiCustomDesign)
+	}
+	d := p.LtiCustomDesign
+	for _, f := range []struct {
+		param *(*string)
+		def   string
+	}{
+		{&d.PrimaryColor, defaults.LtiCustomDesign.PrimaryColor},
+		{&d.SecondaryColor, defaults.LtiCustomDesign.SecondaryColor},
+		{&d.BackgroundColor, defaults.LtiCustomDesign.BackgroundColor},
+		{&d.CustomLogo, defaults.LtiCustomDesign.CustomLogo},
+	} {
+		if *f.param == nil && f.def != "" {
+			v := f.def
+			*f.param = &v
+		}
+	}
+}
+
+func encodeLtiDefaultParams(p *LtiCustomParameters) (string, error) {
+	if p == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func decodeLtiDefaultParams(s string) *LtiCustomParameters {
+	if s == "" {
+		return nil
+	}
+	p := new(LtiCustomParameters)
+	if err := json.Unmarshal([]byte(s), p); err != nil {
+		return nil
+	}
+	return p
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/jordic/lti"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+const testLtiSigningURL = "https://plugnmeet.example.com/lti/v1"
+
+func newTestLtiConsumers() (*config.AppConfig, func(string) (*dbmodels.LtiConsumer, error)) {
+	app := &config.AppConfig{
+		Client: config.ClientInfo{ApiKey: "plugnmeet", Secret: "server-secret"},
+	}
+	consumers := map[string]*dbmodels.LtiConsumer{
+		"moodle":   {ID: 1, ConsumerKey: "moodle", Secret: "moodle-secret", Enabled: true},
+		"disabled": {ID: 2, ConsumerKey: "disabled", Secret: "disabled-secret"},
+	}
+	return app, func(consumerKey string) (*dbmodels.LtiConsumer, error) {
+		return consumers[consumerKey], nil
+	}
+}
+
+// signTestLtiLaunch returns the request body of a launch signed like the LMS would do
+func signTestLtiLaunch(t *testing.T, consumerKey, secret string) string {
+	p := lti.NewProvider(secret, testLtiSigningURL)
+	p.Method = "POST"
+	p.ConsumerKey = consumerKey
+	p.Add("context_id", "course-1")
+	p.Add("resource_link_id", "link-1")
+	p.Add("user_id", "user-1")
+	if _, err := p.Sign(); err != nil {
+		t.Fatal(err)
+	}
+	return p.Params().Encode()
+}
+
+func TestVerifyLtiV1Launch(t *testing.T) {
+	app, getConsumer := newTestLtiConsumers()
+	find := func(consumerKey string) (*dbmodels.LtiConsumer, error) {
+		return findLtiConsumer(app, getConsumer, consumerKey)
+	}
+	log := logrus.NewEntry(logrus.New())
+
+	tests := []struct {
+		name        string
+		consumerKey string
+		secret      string
+		wantErr     string
+		wantId      uint64
+	}{
+		{"consumer secret", "moodle", "moodle-secret", "", 1},
+		{"master key fallback", "plugnmeet", "server-secret", "", 0},
+		{"secret of another consumer", "moodle", "server-secret", config.VerificationFailed, 0},
+		{"master key with consumer secret", "plugnmeet", "moodle-secret", config.VerificationFailed, 0},
+		{"disabled consumer", "disabled", "disabled-secret", config.InvalidConsumerKey, 0},
+		{"unknown consumer", "unknown", "secret", config.InvalidConsumerKey, 0},
+	}
+	for _, tt := range tests {
+		requests := signTestLtiLaunch(t, tt.consumerKey, tt.secret)
+		params, consumer, err := verifyLtiV1Launch(log, requests, testLtiSigningURL, find)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: expected error %q, got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if consumer.ConsumerKey != tt.consumerKey || consumer.ID != tt.wantId || params.Get("user_id") != "user-1" {
+			t.Errorf("%s: unexpected consumer %+v", tt.name, consumer)
+		}
+	}
+
+	// signed for another url
+	requests := signTestLtiLaunch(t, "moodle", "moodle-secret")
+	if _, _, err := verifyLtiV1Launch(log, requests, "https://other.example.com/lti/v1", find); err == nil {
+		t.Error("expected error for another signing url")
+	}
+}
+
+func TestLtiV1RoomId(t *testing.T) {
+	params := &url.Values{}
+	params.Set("tool_consumer_instance_guid", "lms.example.com")
+	params.Set("context_id", "course-1")
+	params.Set("resource_link_id", "link-1")
+
+	master := ltiV1RoomId(&dbmodels.LtiConsumer{ConsumerKey: "plugnmeet"}, params)
+	if master != "lms.example.com_course-1_link-1" {
+		t.Errorf("room id of the api key must not change, got %q", master)
+	}
+
+	a := ltiV1RoomId(&dbmodels.LtiConsumer{ID: 1, ConsumerKey: "a:b"}, params)
+	b := ltiV1RoomId(&dbmodels.LtiConsumer{ID: 2, ConsumerKey: "a"}, params)
+	if a == master || b == master || a == b {
+		t.Errorf("rooms of the consumers must be separated, got %q, %q & %q", master, a, b)
+	}
+
+	// keys with the separator can't collide with the other consumers
+	params.Set("tool_consumer_instance_guid", "b:lms.example.com")
+	if c := ltiV1RoomId(&dbmodels.LtiConsumer{ID: 2, ConsumerKey: "a"}, params); c == a {
+		t.Errorf("room ids collided: %q", c)
+	}
+}
+
+func TestApplyLtiConsumerDefaults(t *testing.T) {
+	yes, no := true, false
+	defaults := &LtiCustomParameters{
+		RoomDuration:    60,
+		AllowPolls:      &no,
+		AllowRecording:  &yes,
+		MuteOnStart:     &yes,
+		LtiCustomDesign: &LtiCustomDesign{PrimaryColor: "#000", CustomLogo: "https://example.com/logo.png"},
+	}
+
+	duration := uint64(30)
+	color := "#fff"
+	claims := &plugnmeet.LtiClaims{
+		LtiCustomParameters: &plugnmeet.LtiCustomParameters{
+			RoomDuration:    &duration,
+			AllowPolls:      &yes,
+			LtiCustomDesign: &plugnmeet.LtiCustomDesign{PrimaryColor: &color},
+		},
+	}
+	applyLtiConsumerDefaults(claims, defaults)
+
+	p := claims.LtiCustomParameters
+	// sent with the launch
+	if p.GetRoomDuration() != 30 || !p.GetAllowPolls() || p.LtiCustomDesign.GetPrimaryColor() != "#fff" {
+		t.Errorf("launch parameters must not be replaced, got %v", p)
+	}
+	// from the defaults
+	if !p.GetAllowRecording() || !p.GetMuteOnStart() || p.LtiCustomDesign.GetCustomLogo() != "https://example.com/logo.png" {
+		t.Errorf("defaults were not applied, got %v", p)
+	}
+	// neither of them
+	if p.AllowRtmp != nil || p.AllowBreakoutRoom != nil || p.LtiCustomDesign.SecondaryColor != nil {
+		t.Errorf("unexpected parameters %v", p)
+	}
+
+	claims = new(plugnmeet.LtiClaims)
+	applyLtiConsumerDefaults(claims, defaults)
+	if claims.LtiCustomParameters.GetRoomDuration() != 60 || claims.LtiCustomParameters.GetAllowPolls() {
+		t.Errorf("defaults were not applied without custom parameters, got %v", claims.LtiCustomParameters)
+	}
+
+	claims = new(plugnmeet.LtiClaims)
+	applyLtiConsumerDefaults(claims, nil)
+	if claims.LtiCustomParameters != nil {
+		t.Error("nothing should be set without defaults")
+	}
+}
@@ -0,0 +1,322 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/mynaparrot/plugnmeet-server/pkg/metrics"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/lti"
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sirupsen/logrus"
+)
+
+// Versions of the LTI grade targets
+const (
+	LtiGradeVersion1p1 = "1.1"
+	LtiGradeVersion1p3 = "1.3"
+)
+
+const (
+	// ltiGradeTargetTTL should be longer than any session, so that the target of
+	// the users who launched at the beginning will still be available at the end
+	ltiGradeTargetTTL = time.Hour * 24 * 7
+	// ltiGradeRequestTimeout is the timeout of a single attempt
+	ltiGradeRequestTimeout = time.Second * 30
+	// ltiGradeWorkers is the number of grades this server will send at the same time
+	ltiGradeWorkers = 10
+)
+
+// LtiGradeTarget is where the grade of the user should be sent, which was captured during the launch
+type LtiGradeTarget struct {
+	Version string `json:"version"`
+	// LTI 1.1 Basic Outcomes
+	ServiceUrl  string `json:"service_url,omitempty"`
+	SourcedId   string `json:"sourced_id,omitempty"`
+	ConsumerKey string `json:"consumer_key,omitempty"`
+	// LTI 1.3 Assignment and Grade Services
+	PlatformId string `json:"platform_id,omitempty"`
+	LineItem   string `json:"line_item,omitempty"`
+}
+
+// LtiGradePassbackJob will be kept in the JetStream work-queue until the grade was accepted by the LMS
+type LtiGradePassbackJob struct {
+	JobId   string          `json:"job_id"`
+	RoomId  string          `json:"room_id"`
+	RoomSid string          `json:"room_sid"`
+	UserId  string          `json:"user_id"`
+	Target  *LtiGradeTarget `json:"target"`
+	// Score is between 0 & 1
+	Score           float64 `json:"score"`
+	AttendedSeconds int64   `json:"attended_seconds"`
+	DurationSeconds int64   `json:"duration_seconds"`
+	QueuedAt        int64   `json:"queued_at"`
+}
+
+type LtiGradeModel struct {
+	ctx         context.Context
+	app         *config.AppConfig
+	ds          *dbservice.DatabaseService
+	rs          *redisservice.RedisService
+	natsService *natsservice.NatsService
+	ls          *ltiservice.LtiService
+	logger      *logrus.Entry
+
+	gradeConsumer jetstream.ConsumeContext
+}
+
+func NewLtiGradeModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, ls *ltiservice.LtiService, logger *logrus.Logger) *LtiGradeModel {
+	return &LtiGradeModel{
+		ctx:         ctx,
+		app:         app,
+		ds:          ds,
+		rs:          rs,
+		natsService: natsService,
+		ls:          ls,
+		logger:      logger.WithField("model", "lti_grade"),
+	}
+}
+
+// IsEnabled returns true if the grades can be sent,
+// the attendance will be calculated from the analytics, so it's required too
+func (m *LtiGradeModel) IsEnabled() bool {
+	return m.app.LtiSettings.GradePassback.Enabled &&
+		m.app.AnalyticsSettings != nil && m.app.AnalyticsSettings.Enabled
+}
+
+// SaveGradeTarget keeps the target of the user until the room has ended
+func (m *LtiGradeModel) SaveGradeTarget(ctx context.Context, roomId, userId string, t *LtiGradeTarget) error {
+	if !m.IsEnabled() {
+		return nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return m.rs.AddLtiGradeTarget(ctx, roomId, userId, data, ltiGradeTargetTTL)
+}
+
+// QueueRoomGradePassback calculates the attendance of the users who have a grade target
+// & queues the jobs to send them. It should be called after the room has ended.
+func (m *LtiGradeModel) QueueRoomGradePassback(room *dbmodels.RoomInfo, users []*plugnmeet.AnalyticsUserInfo) {
+	if !m.IsEnabled() {
+		return
+	}
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId":  room.RoomId,
+		"roomSid": room.Sid,
+		"method":  "QueueRoomGradePassback",
+	})
+
+	targets, err := m.rs.ConsumeLtiGradeTargets(m.ctx, room.RoomId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get lti grade targets")
+		return
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	start, end := room.Created.UnixMilli(), room.Ended.UnixMilli()
+	duration := end - start
+	if duration <= 0 {
+		log.Warnln("invalid room duration, skipping lti grade passback")
+		return
+	}
+
+	var queued int
+	for _, u := range users {
+		data, ok := targets[u.UserId]
+		if !ok {
+			// didn't launch from LMS or launched only as instructor
+			continue
+		}
+		target := new(LtiGradeTarget)
+		if err = json.Unmarshal([]byte(data), target); err != nil {
+			log.WithError(err).WithField("userId", u.UserId).Warnln("invalid lti grade target")
+			continue
+		}
+
+		var joins, leaves []int64
+		for _, e := range u.Events {
+			for _, v := range e.Values {
+				switch e.Name {
+				case "joined":
+					joins = append(joins, v.Time)
+				case "left":
+					leaves = append(leaves, v.Time)
+				}
+			}
+		}
+		attended := helpers.AttendanceDuration(joins, leaves, start, end)
+
+		job := &LtiGradePassbackJob{
+			JobId:           uuid.NewString(),
+			RoomId:          room.RoomId,
+			RoomSid:         room.Sid,
+			UserId:          u.UserId,
+			Target:          target,
+			Score:           helpers.AttendanceScore(attended, duration),
+			AttendedSeconds: attended / 1000,
+			DurationSeconds: duration / 1000,
+			QueuedAt:        time.Now().UnixMilli(),
+		}
+		jd, err := json.Marshal(job)
+		if err != nil {
+			continue
+		}
+		if err = m.natsService.PublishLtiGradePassbackJob(jd); err != nil {
+			log.WithError(err).WithField("userId", u.UserId).Errorln("failed to publish lti grade passback job")
+			continue
+		}
+		queued++
+	}
+
+	log.Infof("queued lti grade passback for %d users", queued)
+}
+
+// StartGradePassbackWorker will consume the grade passback jobs
+func (m *LtiGradeModel) StartGradePassbackWorker() {
+	if !m.IsEnabled() {
+		return
+	}
+	log := m.logger.WithField("method", "StartGradePassbackWorker")
+
+	cons, err := m.natsService.CreateLtiGradePassbackStream(m.app.LtiSettings.GradePassback.MaxAttempts)
+	if err != nil {
+		log.WithError(err).Errorln("failed to create lti grade passback stream")
+		return
+	}
+
+	// pulled jobs should not wait longer than the ack wait of the consumer
+	sem := make(chan struct{}, ltiGradeWorkers)
+	m.gradeConsumer, err = cons.Consume(func(msg jetstream.Msg) {
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			m.handleGradePassbackJob(msg)
+		}()
+	}, jetstream.PullMaxMessages(ltiGradeWorkers), jetstream.ConsumeErrHandler(func(consumeCtx jetstream.ConsumeContext, err error) {
+		if m.ctx.Err() == nil {
+			log.WithError(err).Warn("jetstream consume error")
+		}
+	}))
+	if err != nil {
+		log.WithError(err).Errorln("failed to consume lti grade passback jobs")
+	}
+}
+
+// StopGradePassbackWorker will stop receiving new jobs
+func (m *LtiGradeModel) StopGradePassbackWorker() {
+	if m.gradeConsumer != nil {
+		m.gradeConsumer.Stop()
+	}
+}
+
+func (m *LtiGradeModel) handleGradePassbackJob(msg jetstream.Msg) {
+	job := new(LtiGradePassbackJob)
+	if err := json.Unmarshal(msg.Data(), job); err != nil || job.Target == nil {
+		m.logger.WithError(err).Errorln("invalid lti grade passback job, dropping")
+		_ = msg.Term()
+		return
+	}
+	log := m.logger.WithFields(logrus.Fields{
+		"jobId":   job.JobId,
+		"roomId":  job.RoomId,
+		"userId":  job.UserId,
+		"version": job.Target.Version,
+		"method":  "handleGradePassbackJob",
+	})
+
+	attempt := 1
+	if meta, err := msg.Metadata(); err == nil {
+		attempt = int(meta.NumDelivered)
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, ltiGradeRequestTimeout)
+	defer cancel()
+
+	err := m.sendGrade(ctx, job)
+	if err == nil {
+		_ = msg.Ack()
+		metrics.LtiGradePassbacks.WithLabelValues(job.Target.Version, metrics.LtiGradeResultSuccess).Inc()
+		log.WithField("score", job.Score).Infoln("successfully sent lti grade")
+		return
+	}
+
+	cnf := m.app.LtiSettings.GradePassback
+	if attempt >= cnf.MaxAttempts {
+		_ = msg.Term()
+		metrics.LtiGradePassbacks.WithLabelValues(job.Target.Version, metrics.LtiGradeResultFailed).Inc()
+		log.WithError(err).WithField("attempts", attempt).Errorln("failed to send lti grade, giving up")
+		return
+	}
+
+	delay := helpers.RetryBackoff(cnf.InitialBackoff, cnf.MaxBackoff, attempt)
+	_ = msg.NakWithDelay(delay)
+	metrics.LtiGradePassbacks.WithLabelValues(job.Target.Version, metrics.LtiGradeResultRetry).Inc()
+	log.WithError(err).WithFields(logrus.Fields{
+		"attempts": attempt,
+		"retryIn":  delay.String(),
+	}).Warnln("failed to send lti grade, will retry")
+}
+
+func (m *LtiGradeModel) sendGrade(ctx context.Context, job *LtiGradePassbackJob) error {
+	t := job.Target
+	switch t.Version {
+	case LtiGradeVersion1p1:
+		secret, err := m.ltiConsumerSecret(t.ConsumerKey)
+		if err != nil {
+			return err
+		}
+		return m.ls.ReplaceResult(ctx, &ltiservice.OutcomeRequest{
+			ServiceUrl:  t.ServiceUrl,
+			SourcedId:   t.SourcedId,
+			ConsumerKey: t.ConsumerKey,
+			Secret:      secret,
+			Score:       job.Score,
+		})
+
+	case LtiGradeVersion1p3:
+		platform, err := m.ds.GetLtiPlatform(t.PlatformId)
+		if err != nil {
+			return err
+		}
+		if platform == nil || !platform.Enabled {
+			return errors.New("platform not found or disabled")
+		}
+		return m.ls.SubmitScore(ctx, &ltiservice.Platform{
+			Issuer:       platform.Issuer,
+			ClientId:     platform.ClientID,
+			AuthTokenUrl: platform.AuthTokenUrl,
+		}, &ltiservice.ScoreRequest{
+			LineItem:     t.LineItem,
+			UserId:       job.UserId,
+			ScoreGiven:   math.Round(job.Score*10000) / 100,
+			ScoreMaximum: 100,
+			Comment:      fmt.Sprintf("Attended %d of %d minutes", job.AttendedSeconds/60, job.DurationSeconds/60),
+		})
+	}
+
+	return fmt.Errorf("unknown lti version %q", t.Version)
+}
+
+// ltiConsumerSecret returns the current secret of the LTI 1.1 consumer key
+func (m *LtiGradeModel) ltiConsumerSecret(consumerKey string) (string, error) {
+	consumer, err := findLtiConsumer(m.app, m.ds.GetLtiConsumer, consumerKey)
+	if err != nil {
+		return "", err
+	}
+	return consumer.Secret, nil
+}
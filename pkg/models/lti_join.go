@@ -29,7 +29,7 @@ func (m *LtiV1Model) LTIV1JoinRoom(ctx context.Context, c *plugnmeet.LtiClaims)
 
 func (m *LtiV1Model) createRoomSession(c *plugnmeet.LtiClaims) (*plugnmeet.ActiveRoomInfo, error) {
 	req := utils.PrepareLTIV1RoomCreateReq(c)
-	return m.rm.CreateRoom(req)
+	return m.rm.CreateRoom("", req)
 }
 
 func (m *LtiV1Model) joinRoom(ctx context.Context, c *plugnmeet.LtiClaims) (string, error) {
@@ -3,22 +3,24 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-protocol/utils"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
 )
 
 func (m *LtiV1Model) LTIV1Landing(c *fiber.Ctx, requests, signingURL string) error {
-	params, err := m.VerifyAuth(requests, signingURL)
+	params, consumer, err := m.VerifyAuth(requests, signingURL)
 	if err != nil {
 		return err
 	}
 
-	roomId := fmt.Sprintf("%s_%s_%s", params.Get("tool_consumer_instance_guid"), params.Get("context_id"), params.Get("resource_link_id"))
-
+	roomId := ltiV1RoomId(consumer, params)
 	userId := params.Get("user_id")
 	if userId == "" {
 		userId = m.genHashId(params.Get("lis_person_contact_email_primary"))
@@ -45,7 +47,46 @@ func (m *LtiV1Model) LTIV1Landing(c *fiber.Ctx, requests, signingURL string) err
 		claims.IsAdmin = true
 	}
 	utils.AssignLTIV1CustomParams(params, claims)
+	applyLtiConsumerDefaults(claims, decodeLtiDefaultParams(consumer.DefaultParams))
+
+	if !claims.IsAdmin && params.Get("lis_outcome_service_url") != "" && params.Get("lis_result_sourcedid") != "" {
+		m.saveLtiGradeTarget(c, claims, &LtiGradeTarget{
+			Version:     LtiGradeVersion1p1,
+			ServiceUrl:  params.Get("lis_outcome_service_url"),
+			SourcedId:   params.Get("lis_result_sourcedid"),
+			ConsumerKey: consumer.ConsumerKey,
+		})
+	}
+
+	return m.renderLTILanding(c, claims)
+}
+
+// ltiV1RoomId returns the room id of the launch before hashing.
+// The same ids may be used by the other LMSes, so the rooms of the consumers must not be shared.
+// The immutable ID of the consumer is used because the consumer key may contain any character.
+// The rooms of the api key of the server stay the same as before.
+func ltiV1RoomId(consumer *dbmodels.LtiConsumer, params *url.Values) string {
+	roomId := fmt.Sprintf("%s_%s_%s", params.Get("tool_consumer_instance_guid"), params.Get("context_id"), params.Get("resource_link_id"))
+	if consumer.ID > 0 {
+		roomId = fmt.Sprintf("consumer_%d:%s", consumer.ID, roomId)
+	}
+	return roomId
+}
+
+// saveLtiGradeTarget keeps where the attendance should be sent after the room has ended.
+// The user can still join if it fails.
+func (m *LtiV1Model) saveLtiGradeTarget(c *fiber.Ctx, claims *plugnmeet.LtiClaims, t *LtiGradeTarget) {
+	if err := m.gm.SaveGradeTarget(c.UserContext(), claims.RoomId, claims.UserId, t); err != nil {
+		m.logger.WithError(err).WithFields(logrus.Fields{
+			"roomId": claims.RoomId,
+			"userId": claims.UserId,
+		}).Warnln("failed to save lti grade target")
+	}
+}
 
+// renderLTILanding renders the landing page with the token for /lti/v1/api,
+// which is the same for all the LTI versions
+func (m *LtiV1Model) renderLTILanding(c *fiber.Ctx, claims *plugnmeet.LtiClaims) error {
 	j, err := m.ToJWT(claims)
 	if err != nil {
 		return err
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+type CreateLtiPlatformReq struct {
+	Name     string `json:"name"`
+	Issuer   string `json:"issuer"`
+	ClientId string `json:"client_id"`
+	// DeploymentIds empty means all the deployments of the client
+	DeploymentIds []string `json:"deployment_ids"`
+	// AuthLoginUrl is the OIDC authentication endpoint of the platform
+	AuthLoginUrl string `json:"auth_login_url"`
+	// AuthTokenUrl is required for LTI Advantage services
+	AuthTokenUrl string `json:"auth_token_url"`
+	JwksUrl      string `json:"jwks_url"`
+	Enabled      *bool  `json:"enabled"`
+}
+
+type UpdateLtiPlatformReq struct {
+	PlatformId    string    `json:"platform_id"`
+	Name          *string   `json:"name"`
+	DeploymentIds *[]string `json:"deployment_ids"`
+	AuthLoginUrl  *string   `json:"auth_login_url"`
+	AuthTokenUrl  *string   `json:"auth_token_url"`
+	JwksUrl       *string   `json:"jwks_url"`
+	Enabled       *bool     `json:"enabled"`
+}
+
+type FetchLtiPlatformsReq struct {
+	From    uint32 `json:"from"`
+	Limit   uint32 `json:"limit"`
+	OrderBy string `json:"order_by"`
+}
+
+type LtiPlatformReq struct {
+	PlatformId string `json:"platform_id"`
+}
+
+type LtiPlatformInfo struct {
+	PlatformId    string   `json:"platform_id"`
+	Name          string   `json:"name"`
+	Issuer        string   `json:"issuer"`
+	ClientId      string   `json:"client_id"`
+	DeploymentIds []string `json:"deployment_ids"`
+	AuthLoginUrl  string   `json:"auth_login_url"`
+	AuthTokenUrl  string   `json:"auth_token_url"`
+	JwksUrl       string   `json:"jwks_url"`
+	Enabled       bool     `json:"enabled"`
+	Created       string   `json:"created"`
+	Modified      string   `json:"modified"`
+}
+
+type FetchLtiPlatformsResult struct {
+	TotalPlatforms int64              `json:"total_platforms"`
+	From           uint32             `json:"from"`
+	Limit          uint32             `json:"limit"`
+	OrderBy        string             `json:"order_by"`
+	PlatformsList  []*LtiPlatformInfo `json:"platforms_list"`
+}
+
+func (m *LtiV1Model) CreateLtiPlatform(r *CreateLtiPlatformReq) (*LtiPlatformInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"issuer":   r.Issuer,
+		"clientId": r.ClientId,
+		"method":   "CreateLtiPlatform",
+	})
+	log.Infoln("request to register lti platform")
+
+	if r.Issuer == "" || r.ClientId == "" {
+		return nil, errors.New("issuer and client_id are required")
+	}
+	for _, u := range []string{r.AuthLoginUrl, r.JwksUrl} {
+		if err := validateLtiPlatformUrl(u); err != nil {
+			return nil, err
+		}
+	}
+	if r.AuthTokenUrl != "" {
+		if err := validateLtiPlatformUrl(r.AuthTokenUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := m.ds.GetLtiPlatformsByIssuer(r.Issuer, r.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("platform with the same issuer and client_id already exists")
+	}
+
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	info := &dbmodels.LtiPlatform{
+		PlatformID:    uuid.NewString(),
+		Name:          r.Name,
+		Issuer:        r.Issuer,
+		ClientID:      r.ClientId,
+		DeploymentIDs: formatLtiDeploymentIds(r.DeploymentIds),
+		AuthLoginUrl:  r.AuthLoginUrl,
+		AuthTokenUrl:  r.AuthTokenUrl,
+		JwksUrl:       r.JwksUrl,
+		Enabled:       enabled,
+	}
+
+	if _, err = m.ds.InsertOrUpdateLtiPlatform(info); err != nil {
+		log.WithError(err).Errorln("failed to save lti platform")
+		return nil, err
+	}
+
+	log.WithField("platformId", info.PlatformID).Infoln("successfully registered lti platform")
+	return m.toLtiPlatformInfo(info), nil
+}
+
+func (m *LtiV1Model) UpdateLtiPlatform(r *UpdateLtiPlatformReq) (*LtiPlatformInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"platformId": r.PlatformId,
+		"method":     "UpdateLtiPlatform",
+	})
+
+	info, err := m.ds.GetLtiPlatform(r.PlatformId)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("lti platform not found")
+	}
+
+	if r.Name != nil {
+		info.Name = *r.Name
+	}
+	if r.DeploymentIds != nil {
+		info.DeploymentIDs = formatLtiDeploymentIds(*r.DeploymentIds)
+	}
+	if r.AuthLoginUrl != nil {
+		if err = validateLtiPlatformUrl(*r.AuthLoginUrl); err != nil {
+			return nil, err
+		}
+		info.AuthLoginUrl = *r.AuthLoginUrl
+	}
+	if r.AuthTokenUrl != nil {
+		if *r.AuthTokenUrl != "" {
+			if err = validateLtiPlatformUrl(*r.AuthTokenUrl); err != nil {
+				return nil, err
+			}
+		}
+		info.AuthTokenUrl = *r.AuthTokenUrl
+	}
+	if r.JwksUrl != nil {
+		if err = validateLtiPlatformUrl(*r.JwksUrl); err != nil {
+			return nil, err
+		}
+		info.JwksUrl = *r.JwksUrl
+	}
+	if r.Enabled != nil {
+		info.Enabled = *r.Enabled
+	}
+
+	if _, err = m.ds.InsertOrUpdateLtiPlatform(info); err != nil {
+		log.WithError(err).Errorln("failed to update lti platform")
+		return nil, err
+	}
+
+	log.Infoln("successfully updated lti platform")
+	return m.toLtiPlatformInfo(info), nil
+}
+
+func (m *LtiV1Model) FetchLtiPlatforms(r *FetchLtiPlatformsReq) (*FetchLtiPlatformsResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "DESC"
+	}
+
+	platforms, total, err := m.ds.GetLtiPlatforms(uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*LtiPlatformInfo, 0, len(platforms))
+	for i := range platforms {
+		list = append(list, m.toLtiPlatformInfo(&platforms[i]))
+	}
+
+	return &FetchLtiPlatformsResult{
+		TotalPlatforms: total,
+		From:           r.From,
+		Limit:          r.Limit,
+		OrderBy:        r.OrderBy,
+		PlatformsList:  list,
+	}, nil
+}
+
+func (m *LtiV1Model) GetLtiPlatformInfo(r *LtiPlatformReq) (*LtiPlatformInfo, error) {
+	info, err := m.ds.GetLtiPlatform(r.PlatformId)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("lti platform not found")
+	}
+
+	return m.toLtiPlatformInfo(info), nil
+}
+
+func (m *LtiV1Model) DeleteLtiPlatform(r *LtiPlatformReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"platformId": r.PlatformId,
+		"method":     "DeleteLtiPlatform",
+	})
+
+	affected, err := m.ds.DeleteLtiPlatform(r.PlatformId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to delete lti platform")
+		return err
+	}
+	if affected == 0 {
+		return errors.New("lti platform not found")
+	}
+
+	log.Infoln("successfully deleted lti platform")
+	return nil
+}
+
+func (m *LtiV1Model) toLtiPlatformInfo(p *dbmodels.LtiPlatform) *LtiPlatformInfo {
+	deployments := p.DeploymentList()
+	if deployments == nil {
+		deployments = []string{}
+	}
+	return &LtiPlatformInfo{
+		PlatformId:    p.PlatformID,
+		Name:          p.Name,
+		Issuer:        p.Issuer,
+		ClientId:      p.ClientID,
+		DeploymentIds: deployments,
+		AuthLoginUrl:  p.AuthLoginUrl,
+		AuthTokenUrl:  p.AuthTokenUrl,
+		JwksUrl:       p.JwksUrl,
+		Enabled:       p.Enabled,
+		Created:       p.Created.Format("2006-01-02 15:04:05"),
+		Modified:      p.Modified.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func validateLtiPlatformUrl(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return errors.New("valid http or https urls required for auth_login_url, auth_token_url and jwks_url")
+	}
+	return nil
+}
+
+// formatLtiDeploymentIds will make the list unique
+func formatLtiDeploymentIds(ids []string) string {
+	seen := make(map[string]bool)
+	var list []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] || strings.Contains(id, ",") {
+			continue
+		}
+		seen[id] = true
+		list = append(list, id)
+	}
+	return strings.Join(list, ",")
+}
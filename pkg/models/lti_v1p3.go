@@ -0,0 +1,226 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-protocol/utils"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/lti"
+	"github.com/sirupsen/logrus"
+)
+
+// ltiLaunchStateTTL is the time the user has to finish the login on the platform
+const ltiLaunchStateTTL = time.Minute * 10
+
+// LTIV1p3LoginReq is the third party initiated login request of the platform,
+// which can be sent as GET or POST
+type LTIV1p3LoginReq struct {
+	Issuer         string `form:"iss" query:"iss"`
+	LoginHint      string `form:"login_hint" query:"login_hint"`
+	TargetLinkUri  string `form:"target_link_uri" query:"target_link_uri"`
+	LtiMessageHint string `form:"lti_message_hint" query:"lti_message_hint"`
+	ClientId       string `form:"client_id" query:"client_id"`
+	DeploymentId   string `form:"lti_deployment_id" query:"lti_deployment_id"`
+}
+
+// LTIV1p3LaunchReq will be posted by the platform after the authentication
+type LTIV1p3LaunchReq struct {
+	IdToken string `form:"id_token"`
+	State   string `form:"state"`
+	// Error will be sent instead of id_token if the platform couldn't authenticate the user
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+type ltiLaunchState struct {
+	PlatformId string `json:"platform_id"`
+	Nonce      string `json:"nonce"`
+}
+
+// LTIV1p3Login starts the OIDC login & returns the url of the platform to redirect the user
+func (m *LtiV1Model) LTIV1p3Login(ctx context.Context, r *LTIV1p3LoginReq, launchUrl string) (string, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"issuer":       r.Issuer,
+		"clientId":     r.ClientId,
+		"deploymentId": r.DeploymentId,
+		"method":       "LTIV1p3Login",
+	})
+	if r.Issuer == "" || r.LoginHint == "" {
+		return "", errors.New("iss and login_hint are required")
+	}
+
+	platform, err := m.findLtiPlatform(r.Issuer, r.ClientId)
+	if err != nil {
+		log.WithError(err).Warnln("failed to find platform")
+		return "", err
+	}
+	if r.DeploymentId != "" && !platform.HasDeployment(r.DeploymentId) {
+		log.Warnln("deployment is not allowed")
+		return "", errors.New(config.VerificationFailed)
+	}
+
+	state, err := generateRandomHex(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := generateRandomHex(32)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(&ltiLaunchState{
+		PlatformId: platform.PlatformID,
+		Nonce:      nonce,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = m.rs.SaveLtiLaunchState(ctx, state, data, ltiLaunchStateTTL); err != nil {
+		log.WithError(err).Errorln("failed to save launch state")
+		return "", err
+	}
+
+	return ltiservice.BuildAuthRequestUrl(platform.AuthLoginUrl, &ltiservice.AuthRequest{
+		ClientId:       platform.ClientID,
+		RedirectUri:    launchUrl,
+		LoginHint:      r.LoginHint,
+		LtiMessageHint: r.LtiMessageHint,
+		State:          state,
+		Nonce:          nonce,
+	})
+}
+
+// LTIV1p3Launch verifies the id_token of the platform & renders the same landing page as LTI 1.1
+func (m *LtiV1Model) LTIV1p3Launch(c *fiber.Ctx, r *LTIV1p3LaunchReq) error {
+	log := m.logger.WithField("method", "LTIV1p3Launch")
+	if r.Error != "" {
+		log.WithField("error", r.Error).Warnln(r.ErrorDescription)
+		return fmt.Errorf("platform authentication failed: %s", r.Error)
+	}
+	if r.IdToken == "" || r.State == "" {
+		return errors.New("id_token and state are required")
+	}
+
+	// the state can be used only once, so the same launch can't be replayed
+	data, err := m.rs.ConsumeLtiLaunchState(c.UserContext(), r.State)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return errors.New("invalid or expired state")
+	}
+	st := new(ltiLaunchState)
+	if err = json.Unmarshal(data, st); err != nil {
+		return err
+	}
+
+	platform, err := m.ds.GetLtiPlatform(st.PlatformId)
+	if err != nil {
+		return err
+	}
+	if platform == nil || !platform.Enabled {
+		return errors.New("platform not found or disabled")
+	}
+	log = log.WithFields(logrus.Fields{
+		"issuer":   platform.Issuer,
+		"clientId": platform.ClientID,
+	})
+
+	lc, err := m.ls.VerifyLaunchToken(c.UserContext(), r.IdToken, &ltiservice.Platform{
+		Issuer:       platform.Issuer,
+		ClientId:     platform.ClientID,
+		AuthLoginUrl: platform.AuthLoginUrl,
+		JwksUrl:      platform.JwksUrl,
+		AuthTokenUrl: platform.AuthTokenUrl,
+	}, st.Nonce)
+	if err != nil {
+		log.WithError(err).Warnln("id_token verification failed")
+		return errors.New(config.VerificationFailed)
+	}
+	if !platform.HasDeployment(lc.DeploymentId) {
+		log.WithField("deploymentId", lc.DeploymentId).Warnln("deployment is not allowed")
+		return errors.New(config.VerificationFailed)
+	}
+
+	claims := m.toLtiClaims(lc)
+	params := lc.CustomParams()
+	utils.AssignLTIV1CustomParams(&params, claims)
+
+	if !claims.IsAdmin && lc.Ags.CanPostScore() {
+		m.saveLtiGradeTarget(c, claims, &LtiGradeTarget{
+			Version:    LtiGradeVersion1p3,
+			PlatformId: platform.PlatformID,
+			LineItem:   lc.Ags.LineItem,
+		})
+	}
+
+	return m.renderLTILanding(c, claims)
+}
+
+// ToolJWKS returns the public key set of this tool
+func (m *LtiV1Model) ToolJWKS() *jose.JSONWebKeySet {
+	return m.ls.ToolJWKS()
+}
+
+// toLtiClaims maps the launch onto the claims of LTI 1.1,
+// so the rooms & users can be handled in the same way
+func (m *LtiV1Model) toLtiClaims(lc *ltiservice.LaunchClaims) *plugnmeet.LtiClaims {
+	contextId, title := "", ""
+	if lc.Context != nil {
+		contextId = lc.Context.Id
+		title = lc.Context.Label
+		if title == "" {
+			title = lc.Context.Title
+		}
+	}
+	if title == "" {
+		title = lc.ResourceLink.Title
+	}
+
+	// tool_platform guid is sent by the platform itself, so it can't be trusted to separate the rooms
+	roomId := fmt.Sprintf("%s_%s_%s_%s", lc.Issuer, lc.DeploymentId, contextId, lc.ResourceLink.Id)
+
+	name := lc.FullName()
+	if name == "" {
+		name = fmt.Sprintf("%s_%s", "User", lc.Subject)
+	}
+
+	return &plugnmeet.LtiClaims{
+		UserId:    lc.Subject,
+		Name:      name,
+		IsAdmin:   lc.IsInstructor(),
+		RoomId:    m.genHashId(roomId),
+		RoomTitle: title,
+	}
+}
+
+// findLtiPlatform returns the enabled registration of the issuer.
+// client_id is optional in the login request, but required if the issuer has more registrations.
+func (m *LtiV1Model) findLtiPlatform(issuer, clientId string) (*dbmodels.LtiPlatform, error) {
+	platforms, err := m.ds.GetLtiPlatformsByIssuer(issuer, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*dbmodels.LtiPlatform
+	for i := range platforms {
+		if platforms[i].Enabled {
+			found = append(found, &platforms[i])
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, errors.New("platform not registered")
+	case 1:
+		return found[0], nil
+	default:
+		return nil, errors.New("client_id is required as the platform has multiple registrations")
+	}
+}
@@ -19,11 +19,12 @@ type NatsModel struct {
 	authModel      *AuthModel
 	natsService    *natsservice.NatsService
 	userModel      *UserModel
+	recorderModel  *RecorderModel
 	analyticsModel *AnalyticsModel
 	logger         *logrus.Entry
 }
 
-func NewNatsModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, lk *livekitservice.LivekitService, analyticsModel *AnalyticsModel, authModel *AuthModel, userModel *UserModel, logger *logrus.Logger) *NatsModel {
+func NewNatsModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, lk *livekitservice.LivekitService, analyticsModel *AnalyticsModel, authModel *AuthModel, userModel *UserModel, recorderModel *RecorderModel, logger *logrus.Logger) *NatsModel {
 	return &NatsModel{
 		app:            app,
 		ds:             ds,
@@ -32,6 +33,7 @@ func NewNatsModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redi
 		natsService:    natsService,
 		authModel:      authModel,
 		userModel:      userModel,
+		recorderModel:  recorderModel,
 		analyticsModel: analyticsModel,
 		logger:         logger.WithField("model", "nats"),
 	}
@@ -49,6 +49,7 @@ func (m *NatsModel) OnAfterUserJoined(roomId, userId string) {
 			ExtraData: &userInfo.Metadata,
 			HsetValue: &now,
 		})
+		go m.recorderModel.AutoStartRecordingOnUserJoin(roomId, userId)
 		log.Info("successfully processed user joined event")
 	} else if err != nil {
 		log.WithError(err).Warn("could not get user info after join")
@@ -111,6 +112,9 @@ func (m *NatsModel) handleDelayedOfflineTasks(roomId, userId string, userInfo *p
 	// Send analytics for the user leaving.
 	m.updateUserLeftAnalytics(roomId, userId)
 
+	// Stop the auto recording if this was the last participant.
+	m.recorderModel.AutoStopRecordingOnUserLeft(roomId, userId)
+
 	// Broadcast the final offline status.
 	if userInfo != nil {
 		if err = m.natsService.BroadcastSystemEventToEveryoneExceptUserId(plugnmeet.NatsMsgServerToClientEvents_USER_OFFLINE, roomId, userInfo, userId); err != nil {
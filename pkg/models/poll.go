@@ -43,3 +43,60 @@ func (m *PollModel) ManageActivation(req *plugnmeet.ActivatePollsReq) error {
 	roomMeta.RoomFeatures.PollsFeatures.IsActive = req.GetIsActive()
 	return m.natsService.UpdateAndBroadcastRoomMetadata(req.GetRoomId(), roomMeta)
 }
+
+// PollSettings are the additional settings of a poll
+type PollSettings struct {
+	// IsQuiz polls will have correct options & scores of the respondents
+	IsQuiz         bool     `json:"is_quiz"`
+	CorrectOptions []uint64 `json:"correct_options,omitempty"`
+	// MultipleChoice allows user to select more than one option
+	MultipleChoice bool `json:"multiple_choice"`
+	// IsAnonymous polls won't store names of the respondents
+	IsAnonymous bool `json:"is_anonymous"`
+	// TimeLimit in seconds, after that the poll will be closed automatically
+	TimeLimit uint64 `json:"time_limit,omitempty"`
+	// Deadline is the unix time when the poll will be closed
+	Deadline int64 `json:"deadline,omitempty"`
+}
+
+type PollOption struct {
+	Id   uint32 `json:"id"`
+	Text string `json:"text"`
+}
+
+type CreatePollWithSettingsReq struct {
+	Question string        `json:"question"`
+	Options  []*PollOption `json:"options"`
+	Settings *PollSettings `json:"settings"`
+}
+
+type SubmitPollResponsesReq struct {
+	PollId          string   `json:"poll_id"`
+	SelectedOptions []uint64 `json:"selected_options"`
+}
+
+// PollUserResult is the result of a respondent in a poll
+type PollUserResult struct {
+	UserId          string   `json:"user_id"`
+	Name            string   `json:"name"`
+	SelectedOptions []uint64 `json:"selected_options"`
+	// Score will be 1 if the selected options match with the correct options
+	Score uint64 `json:"score"`
+}
+
+type QuizLeaderboardEntry struct {
+	UserId string `json:"user_id"`
+	Name   string `json:"name"`
+	// Score is the total score of all the closed quizzes of the session
+	Score         uint64 `json:"score"`
+	TotalAnswered uint64 `json:"total_answered"`
+}
+
+type QuizResult struct {
+	PollId         string                  `json:"poll_id"`
+	Question       string                  `json:"question"`
+	CorrectOptions []uint64                `json:"correct_options"`
+	TotalResponses uint64                  `json:"total_responses"`
+	TotalCorrect   uint64                  `json:"total_correct"`
+	Leaderboard    []*QuizLeaderboardEntry `json:"leaderboard"`
+}
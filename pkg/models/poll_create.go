@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/goccy/go-json"
@@ -11,6 +12,34 @@ import (
 )
 
 func (m *PollModel) CreatePoll(r *plugnmeet.CreatePollReq) (string, error) {
+	return m.createPoll(r, nil)
+}
+
+// CreatePollWithSettings will create poll with additional settings, e.g. quiz, multiple choice etc.
+func (m *PollModel) CreatePollWithSettings(roomId, userId string, r *CreatePollWithSettingsReq) (string, error) {
+	if r.Settings == nil {
+		r.Settings = new(PollSettings)
+	}
+	if err := validatePollSettings(r.Options, r.Settings); err != nil {
+		return "", err
+	}
+
+	req := &plugnmeet.CreatePollReq{
+		RoomId:   roomId,
+		UserId:   userId,
+		Question: r.Question,
+	}
+	for _, opt := range r.Options {
+		req.Options = append(req.Options, &plugnmeet.CreatePollOptions{
+			Id:   opt.Id,
+			Text: opt.Text,
+		})
+	}
+
+	return m.createPoll(req, r.Settings)
+}
+
+func (m *PollModel) createPoll(r *plugnmeet.CreatePollReq, settings *PollSettings) (string, error) {
 	log := m.logger.WithFields(logrus.Fields{
 		"roomId": r.RoomId,
 		"userId": r.UserId,
@@ -21,6 +50,14 @@ func (m *PollModel) CreatePoll(r *plugnmeet.CreatePollReq) (string, error) {
 	r.PollId = uuid.NewString()
 	log = log.WithField("pollId", r.PollId)
 
+	// settings should be ready before users will get the poll
+	if settings != nil {
+		if err := m.setPollSettings(r.RoomId, r.PollId, settings); err != nil {
+			log.WithError(err).Errorln("failed to store poll settings")
+			return "", err
+		}
+	}
+
 	// create poll hash and add to room
 	err := m.createRoomPollHash(r)
 	if err != nil {
@@ -83,27 +120,76 @@ func (m *PollModel) createRoomPollHash(r *plugnmeet.CreatePollReq) error {
 }
 
 func (m *PollModel) UserSubmitResponse(r *plugnmeet.SubmitPollResponseReq) error {
+	return m.submitResponse(r.RoomId, r.PollId, r.UserId, r.Name, []uint64{r.SelectedOption})
+}
+
+// UserSubmitResponses will submit response with one or more options,
+// more than one option only allowed for multiple choice polls
+func (m *PollModel) UserSubmitResponses(roomId, userId string, r *SubmitPollResponsesReq) error {
+	name := ""
+	userInfo, err := m.natsService.GetUserInfo(roomId, userId)
+	if err != nil {
+		return err
+	}
+	if userInfo != nil {
+		name = userInfo.Name
+	}
+
+	return m.submitResponse(roomId, r.PollId, userId, name, r.SelectedOptions)
+}
+
+func (m *PollModel) submitResponse(roomId, pollId, userId, name string, selectedOptions []uint64) error {
 	log := m.logger.WithFields(logrus.Fields{
-		"roomId": r.RoomId,
-		"userId": r.UserId,
-		"pollId": r.PollId,
+		"roomId": roomId,
+		"userId": userId,
+		"pollId": pollId,
 		"method": "UserSubmitResponse",
 	})
 	log.Infoln("request to submit poll response")
 
-	err := m.rs.AddPollResponse(r)
+	info, settings, err := m.getPollWithSettings(roomId, pollId)
+	if err != nil {
+		return err
+	}
+	if !info.IsRunning {
+		return errors.New("poll already closed")
+	}
+	if isPollExpired(settings, time.Now()) {
+		return errors.New("poll deadline has passed")
+	}
+
+	selectedOptions, err = validateSelectedOptions(info, settings, selectedOptions)
+	if err != nil {
+		return err
+	}
+	respondentId := pollRespondentId(userId, settings.IsAnonymous)
+	if settings.IsAnonymous {
+		name = ""
+	}
+
+	err = m.rs.AddPollResponse(roomId, pollId, userId, respondentId, name, selectedOptions)
 	if err != nil {
 		log.WithError(err).Errorln("failed to add poll response to redis")
 		return err
 	}
 
+	if settings.IsAnonymous {
+		// analytics are stored per user, which would link the response to the user
+		log.Info("successfully submitted poll response")
+		return nil
+	}
+
 	// send analytics
 	toRecord := struct {
-		PollId         string `json:"poll_id"`
-		SelectedOption uint64 `json:"selected_option"`
+		PollId          string   `json:"poll_id"`
+		SelectedOption  uint64   `json:"selected_option"`
+		SelectedOptions []uint64 `json:"selected_options,omitempty"`
 	}{
-		PollId:         r.PollId,
-		SelectedOption: r.SelectedOption,
+		PollId:         pollId,
+		SelectedOption: selectedOptions[0],
+	}
+	if len(selectedOptions) > 1 {
+		toRecord.SelectedOptions = selectedOptions
 	}
 	marshal, err := json.Marshal(toRecord)
 	if err != nil {
@@ -113,8 +199,8 @@ func (m *PollModel) UserSubmitResponse(r *plugnmeet.SubmitPollResponseReq) error
 	m.analyticsModel.HandleEvent(&plugnmeet.AnalyticsDataMsg{
 		EventType: plugnmeet.AnalyticsEventType_ANALYTICS_EVENT_TYPE_USER,
 		EventName: plugnmeet.AnalyticsEvents_ANALYTICS_EVENT_USER_VOTED_POLL,
-		RoomId:    r.RoomId,
-		UserId:    &r.UserId,
+		RoomId:    roomId,
+		UserId:    &userId,
 		HsetValue: &val,
 	})
 
@@ -20,11 +20,25 @@ func (m *PollModel) ClosePoll(r *plugnmeet.ClosePollReq) error {
 		return err
 	}
 
+	// in case it was closed before the deadline
+	if _, err = m.rs.RemovePollDeadline(r.RoomId, r.PollId); err != nil {
+		log.WithError(err).Errorln("failed to remove poll deadline")
+	}
+
 	err = m.natsService.BroadcastSystemEventToRoom(plugnmeet.NatsMsgServerToClientEvents_POLL_CLOSED, r.RoomId, r.PollId, nil)
 	if err != nil {
 		log.WithError(err).Errorln("error sending POLL_CLOSED event")
 	}
 
+	settings, err := m.getPollSettings(r.RoomId, r.PollId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get poll settings")
+	} else if settings.IsQuiz && !settings.IsAnonymous {
+		if err = m.broadcastQuizLeaderboard(r.RoomId, r.PollId); err != nil {
+			log.WithError(err).Errorln("error sending quiz leaderboard")
+		}
+	}
+
 	// send analytics
 	m.analyticsModel.HandleEvent(&plugnmeet.AnalyticsDataMsg{
 		EventType: plugnmeet.AnalyticsEventType_ANALYTICS_EVENT_TYPE_ROOM,
@@ -37,10 +51,11 @@ func (m *PollModel) ClosePoll(r *plugnmeet.ClosePollReq) error {
 	return nil
 }
 
-func (m *PollModel) CleanUpPolls(roomId string) error {
+func (m *PollModel) CleanUpPolls(roomId, roomSid string) error {
 	log := m.logger.WithFields(logrus.Fields{
-		"roomId": roomId,
-		"method": "CleanUpPolls",
+		"roomId":  roomId,
+		"roomSid": roomSid,
+		"method":  "CleanUpPolls",
 	})
 	log.Infoln("cleaning up polls for room")
 
@@ -56,6 +71,9 @@ func (m *PollModel) CleanUpPolls(roomId string) error {
 		return nil // No polls to clean up.
 	}
 
+	// keep the results, otherwise those will be lost with redis keys
+	m.storeRoomPolls(roomId, roomSid)
+
 	err = m.rs.CleanUpPolls(roomId, pIds)
 	if err != nil {
 		log.WithError(err).Errorln("failed to clean up polls from redis")
@@ -0,0 +1,239 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+)
+
+const (
+	PollExportFormatCsv  = "csv"
+	PollExportFormatJson = "json"
+	pollExportBatchSize  = 100
+)
+
+type FetchPastPollsReq struct {
+	RoomId  string `json:"room_id"`
+	RoomSid string `json:"room_sid"`
+	From    uint32 `json:"from"`
+	Limit   uint32 `json:"limit"`
+	OrderBy string `json:"order_by"`
+	// Format can be csv or json to download all the matched polls as file
+	Format string `json:"format"`
+}
+
+type PastPollOption struct {
+	Id        uint64 `json:"id"`
+	Text      string `json:"text"`
+	VoteCount uint64 `json:"vote_count"`
+}
+
+type PastPollResponse struct {
+	UserId         string `json:"user_id"`
+	Name           string `json:"name"`
+	SelectedOption uint64 `json:"selected_option"`
+}
+
+type PastPollInfo struct {
+	PollId         string              `json:"poll_id"`
+	RoomId         string              `json:"room_id"`
+	RoomSid        string              `json:"room_sid"`
+	Question       string              `json:"question"`
+	Options        []*PastPollOption   `json:"options"`
+	TotalResponses uint64              `json:"total_responses"`
+	CreatedBy      string              `json:"created_by"`
+	ClosedBy       string              `json:"closed_by"`
+	Created        int64               `json:"created"`
+	IsQuiz         bool                `json:"is_quiz"`
+	IsAnonymous    bool                `json:"is_anonymous"`
+	MultipleChoice bool                `json:"multiple_choice"`
+	CorrectOptions []uint64            `json:"correct_options"`
+	Responses      []*PastPollResponse `json:"responses"`
+	// Results are grouped by respondents with score for quiz
+	Results []*PollUserResult `json:"results"`
+}
+
+type FetchPastPollsResult struct {
+	TotalPolls int64           `json:"total_polls"`
+	From       uint32          `json:"from"`
+	Limit      uint32          `json:"limit"`
+	OrderBy    string          `json:"order_by"`
+	PollsList  []*PastPollInfo `json:"polls_list"`
+}
+
+// FetchPastPolls returns stored polls of the ended sessions
+func (m *PollModel) FetchPastPolls(tenantId string, r *FetchPastPollsReq) (*FetchPastPollsResult, error) {
+	if r.RoomId == "" && r.RoomSid == "" {
+		return nil, errors.New("room_id or room_sid required")
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "ASC"
+	}
+
+	polls, total, err := m.ds.GetPastPolls(tenantId, r.RoomId, r.RoomSid, uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*PastPollInfo, 0, len(polls))
+	for i := range polls {
+		list = append(list, toPastPollInfo(&polls[i]))
+	}
+
+	return &FetchPastPollsResult{
+		TotalPolls: total,
+		From:       r.From,
+		Limit:      r.Limit,
+		OrderBy:    r.OrderBy,
+		PollsList:  list,
+	}, nil
+}
+
+// ExportPastPolls returns the file name & content of all the matched polls
+func (m *PollModel) ExportPastPolls(tenantId string, r *FetchPastPollsReq) (string, []byte, error) {
+	if r.RoomId == "" && r.RoomSid == "" {
+		return "", nil, errors.New("room_id or room_sid required")
+	}
+	if r.Format != PollExportFormatCsv && r.Format != PollExportFormatJson {
+		return "", nil, errors.New("invalid format, supported formats: csv, json")
+	}
+
+	var list []*PastPollInfo
+	orderBy := "ASC"
+	for {
+		polls, total, err := m.ds.GetPastPolls(tenantId, r.RoomId, r.RoomSid, uint64(len(list)), pollExportBatchSize, &orderBy)
+		if err != nil {
+			return "", nil, err
+		}
+		for i := range polls {
+			list = append(list, toPastPollInfo(&polls[i]))
+		}
+		if len(polls) == 0 || int64(len(list)) >= total {
+			break
+		}
+	}
+	if len(list) == 0 {
+		return "", nil, errors.New("no polls found")
+	}
+
+	name := r.RoomSid
+	if name == "" {
+		name = r.RoomId
+	}
+	fileName := fmt.Sprintf("%s-polls.%s", name, r.Format)
+
+	if r.Format == PollExportFormatJson {
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return "", nil, err
+		}
+		return fileName, data, nil
+	}
+
+	data, err := formatPollsCsv(list)
+	if err != nil {
+		return "", nil, err
+	}
+	return fileName, data, nil
+}
+
+// formatPollsCsv prepares csv with a row for each option with vote count
+// and after that a row for each selected option of the respondents.
+// Score of the respondent will be added only for quiz.
+func formatPollsCsv(list []*PastPollInfo) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	_ = w.Write([]string{"room_id", "room_sid", "poll_id", "question", "created", "option_id", "option_text", "is_correct", "vote_count", "user_id", "name", "score"})
+
+	for _, p := range list {
+		created := time.Unix(p.Created, 0).UTC().Format("2006-01-02 15:04:05")
+		isCorrect := func(id uint64) string {
+			if !p.IsQuiz {
+				return ""
+			}
+			return strconv.FormatBool(slices.Contains(p.CorrectOptions, id))
+		}
+
+		options := make(map[uint64]string)
+		for _, opt := range p.Options {
+			options[opt.Id] = opt.Text
+			_ = w.Write([]string{p.RoomId, p.RoomSid, p.PollId, p.Question, created, strconv.FormatUint(opt.Id, 10), opt.Text, isCorrect(opt.Id), strconv.FormatUint(opt.VoteCount, 10), "", "", ""})
+		}
+		for _, r := range p.Results {
+			score := ""
+			if p.IsQuiz {
+				score = strconv.FormatUint(r.Score, 10)
+			}
+			for _, id := range r.SelectedOptions {
+				_ = w.Write([]string{p.RoomId, p.RoomSid, p.PollId, p.Question, created, strconv.FormatUint(id, 10), options[id], isCorrect(id), "", r.UserId, r.Name, score})
+			}
+		}
+	}
+
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+func toPastPollInfo(v *dbmodels.Poll) *PastPollInfo {
+	p := &PastPollInfo{
+		PollId:         v.PollID,
+		RoomId:         v.RoomID,
+		RoomSid:        v.RoomSid,
+		Question:       v.Question,
+		TotalResponses: v.TotalResponses,
+		CreatedBy:      v.CreatedBy,
+		ClosedBy:       v.ClosedBy,
+		Created:        v.PollCreatedAt,
+		IsQuiz:         v.IsQuiz,
+		IsAnonymous:    v.IsAnonymous,
+		MultipleChoice: v.MultipleChoice,
+		Options:        []*PastPollOption{},
+		CorrectOptions: []uint64{},
+		Responses:      make([]*PastPollResponse, 0, len(v.Responses)),
+		Results:        []*PollUserResult{},
+	}
+	_ = json.Unmarshal([]byte(v.Options), &p.Options)
+	if v.CorrectOptions != "" {
+		_ = json.Unmarshal([]byte(v.CorrectOptions), &p.CorrectOptions)
+	}
+
+	results := make(map[string]*PollUserResult)
+	for _, r := range v.Responses {
+		p.Responses = append(p.Responses, &PastPollResponse{
+			UserId:         r.UserID,
+			Name:           r.Name,
+			SelectedOption: r.SelectedOption,
+		})
+
+		ur, ok := results[r.UserID]
+		if !ok {
+			ur = &PollUserResult{
+				UserId: r.UserID,
+				Name:   r.Name,
+			}
+			results[r.UserID] = ur
+			p.Results = append(p.Results, ur)
+		}
+		ur.SelectedOptions = append(ur.SelectedOptions, r.SelectedOption)
+	}
+	if p.IsQuiz {
+		for _, ur := range p.Results {
+			ur.Score = calculateQuizScore(ur.SelectedOptions, p.CorrectOptions)
+		}
+	}
+
+	return p
+}
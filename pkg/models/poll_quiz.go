@@ -0,0 +1,211 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+)
+
+const anonymousRespondentPrefix = "anonymous-"
+
+// GetQuizResult returns result of the closed quiz with the leaderboard of the session.
+// Anonymous quizzes won't have any leaderboard.
+func (m *PollModel) GetQuizResult(roomId, pollId string) (*QuizResult, error) {
+	info, settings, err := m.getPollWithSettings(roomId, pollId)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.IsQuiz {
+		return nil, errors.New("poll is not a quiz")
+	}
+	if info.IsRunning {
+		return nil, errors.New("need to wait until poll close")
+	}
+
+	results, err := m.getPollUserResults(roomId, pollId, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &QuizResult{
+		PollId:         pollId,
+		Question:       info.Question,
+		CorrectOptions: settings.CorrectOptions,
+		TotalResponses: uint64(len(results)),
+		Leaderboard:    []*QuizLeaderboardEntry{},
+	}
+	for _, r := range results {
+		res.TotalCorrect += r.Score
+	}
+
+	if settings.IsAnonymous {
+		return res, nil
+	}
+
+	res.Leaderboard, err = m.getQuizLeaderboard(roomId)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// getQuizLeaderboard calculates total scores of the users
+// from all the closed & non-anonymous quizzes of the session
+func (m *PollModel) getQuizLeaderboard(roomId string) ([]*QuizLeaderboardEntry, error) {
+	polls, err := m.ListPolls(roomId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*QuizLeaderboardEntry)
+	for _, p := range polls {
+		if p.IsRunning {
+			continue
+		}
+		settings, err := m.getPollSettings(roomId, p.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !settings.IsQuiz || settings.IsAnonymous {
+			continue
+		}
+
+		results, err := m.getPollUserResults(roomId, p.Id, settings)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			e, ok := entries[r.UserId]
+			if !ok {
+				e = &QuizLeaderboardEntry{
+					UserId: r.UserId,
+					Name:   r.Name,
+				}
+				entries[r.UserId] = e
+			}
+			e.Score += r.Score
+			e.TotalAnswered++
+		}
+	}
+
+	leaderboard := make([]*QuizLeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		leaderboard = append(leaderboard, e)
+	}
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].Score != leaderboard[j].Score {
+			return leaderboard[i].Score > leaderboard[j].Score
+		}
+		return leaderboard[i].Name < leaderboard[j].Name
+	})
+
+	return leaderboard, nil
+}
+
+// broadcastQuizLeaderboard will send the result of the quiz with the leaderboard to everyone in the room
+func (m *PollModel) broadcastQuizLeaderboard(roomId, pollId string) error {
+	res, err := m.GetQuizResult(roomId, pollId)
+	if err != nil {
+		return err
+	}
+
+	marshal, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return m.natsService.BroadcastSystemEventToRoom(natsservice.PollLeaderboardEvent, roomId, marshal, nil)
+}
+
+// getPollUserResults groups responses by user in the order of the submission
+func (m *PollModel) getPollUserResults(roomId, pollId string, settings *PollSettings) ([]*PollUserResult, error) {
+	allRespondents, err := m.rs.GetPollAllRespondents(roomId, pollId)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*PollUserResult
+	for _, resp := range parsePollRespondents(allRespondents) {
+		var r *PollUserResult
+		if len(results) > 0 && results[len(results)-1].UserId == resp.UserId {
+			r = results[len(results)-1]
+		} else {
+			r = &PollUserResult{
+				UserId: resp.UserId,
+				Name:   resp.Name,
+			}
+			results = append(results, r)
+		}
+		r.SelectedOptions = append(r.SelectedOptions, resp.SelectedOption)
+	}
+
+	if settings.IsQuiz {
+		for _, r := range results {
+			r.Score = calculateQuizScore(r.SelectedOptions, settings.CorrectOptions)
+		}
+	}
+
+	return results, nil
+}
+
+// calculateQuizScore returns 1 only if selected options exactly match with the correct options
+func calculateQuizScore(selected, correct []uint64) uint64 {
+	if len(selected) == 0 || len(selected) != len(correct) {
+		return 0
+	}
+	for _, id := range selected {
+		if !slices.Contains(correct, id) {
+			return 0
+		}
+	}
+	return 1
+}
+
+// pollRespondentId returns the id to store in the all_respondents list.
+// For anonymous polls a random id will be used, so that responses can't be linked to the user
+// but options of the same submission still can be grouped together.
+func pollRespondentId(userId string, isAnonymous bool) string {
+	if !isAnonymous {
+		return userId
+	}
+	return anonymousRespondentPrefix + uuid.NewString()
+}
+
+type pollRespondent struct {
+	UserId         string
+	Name           string
+	SelectedOption uint64
+}
+
+// parsePollRespondents parses entries of all_respondents list,
+// responses of multiple choice poll will be in consecutive entries
+func parsePollRespondents(allRespondents []string) []*pollRespondent {
+	list := make([]*pollRespondent, 0, len(allRespondents))
+	for _, r := range allRespondents {
+		// format userId:option_id:name
+		// name may contain colon too
+		parts := strings.SplitN(r, ":", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		selected, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		resp := &pollRespondent{
+			UserId:         parts[0],
+			SelectedOption: selected,
+		}
+		if len(parts) == 3 {
+			resp.Name = parts[2]
+		}
+		list = append(list, resp)
+	}
+
+	return list
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPollRespondentId(t *testing.T) {
+	if id := pollRespondentId("user-1", false); id != "user-1" {
+		t.Errorf("expected user id for non-anonymous poll, got %s", id)
+	}
+
+	first := pollRespondentId("user-1", true)
+	second := pollRespondentId("user-1", true)
+	if strings.Contains(first, "user-1") || strings.Contains(second, "user-1") {
+		t.Errorf("user id must not be stored for anonymous poll, got %s & %s", first, second)
+	}
+	if first == second {
+		t.Errorf("anonymous responses must not be linkable, got same id %s", first)
+	}
+	if strings.Contains(first, ":") {
+		t.Errorf("respondent id must not contain separator, got %s", first)
+	}
+}
+
+func TestParsePollRespondentsAnonymous(t *testing.T) {
+	// same format as AddPollResponse
+	respondentId := pollRespondentId("user-1", true)
+	list := parsePollRespondents([]string{
+		fmt.Sprintf("%s:%d:%s", respondentId, 1, ""),
+		fmt.Sprintf("%s:%d:%s", respondentId, 3, ""),
+		fmt.Sprintf("%s:%d:%s", "user-2", 2, "Name: with colon"),
+	})
+	if len(list) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(list))
+	}
+	for _, r := range list[:2] {
+		if r.UserId != respondentId || r.Name != "" {
+			t.Errorf("unexpected anonymous entry %+v", r)
+		}
+	}
+	if list[1].SelectedOption != 3 {
+		t.Errorf("expected option 3, got %d", list[1].SelectedOption)
+	}
+	if list[2].UserId != "user-2" || list[2].Name != "Name: with colon" {
+		t.Errorf("unexpected entry %+v", list[2])
+	}
+}
+
+func TestCalculateQuizScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		selected []uint64
+		correct  []uint64
+		want     uint64
+	}{
+		{"correct", []uint64{1}, []uint64{1}, 1},
+		{"wrong", []uint64{2}, []uint64{1}, 0},
+		{"all correct multiple", []uint64{3, 1}, []uint64{1, 3}, 1},
+		{"partially correct", []uint64{1}, []uint64{1, 3}, 0},
+		{"none selected", nil, []uint64{1}, 0},
+	}
+	for _, tt := range tests {
+		if got := calculateQuizScore(tt.selected, tt.correct); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsPollExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tests := []struct {
+		name     string
+		deadline int64
+		want     bool
+	}{
+		{"no deadline", 0, false},
+		{"before deadline", 1001, false},
+		{"at deadline", 1000, true},
+		{"after deadline", 999, true},
+	}
+	for _, tt := range tests {
+		if got := isPollExpired(&PollSettings{Deadline: tt.deadline}, now); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
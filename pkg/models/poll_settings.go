@@ -0,0 +1,159 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// GetPollSettings returns settings of the poll,
+// correct options will be hidden from non-admin users until the poll will be closed
+func (m *PollModel) GetPollSettings(roomId, pollId string, isAdmin bool) (*PollSettings, error) {
+	info, settings, err := m.getPollWithSettings(roomId, pollId)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && info.IsRunning {
+		settings.CorrectOptions = nil
+	}
+	return settings, nil
+}
+
+func (m *PollModel) setPollSettings(roomId, pollId string, settings *PollSettings) error {
+	if settings.TimeLimit > 0 {
+		settings.Deadline = time.Now().Add(time.Duration(settings.TimeLimit) * time.Second).Unix()
+	}
+
+	marshal, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	if err = m.rs.SetPollSettings(roomId, pollId, string(marshal)); err != nil {
+		return err
+	}
+
+	if settings.Deadline > 0 {
+		// janitor will close the poll after deadline
+		return m.rs.AddPollDeadline(roomId, pollId, settings.Deadline)
+	}
+	return nil
+}
+
+// isPollExpired returns true if the deadline of the poll has passed,
+// the poll may be still running until the janitor closes it
+func isPollExpired(settings *PollSettings, now time.Time) bool {
+	return settings.Deadline > 0 && now.Unix() >= settings.Deadline
+}
+
+// getPollSettings returns default settings if the poll was created without any settings
+func (m *PollModel) getPollSettings(roomId, pollId string) (*PollSettings, error) {
+	settings := new(PollSettings)
+	result, err := m.rs.GetPollSettings(roomId, pollId)
+	if err != nil {
+		return nil, err
+	}
+	if result == "" {
+		return settings, nil
+	}
+
+	if err = json.Unmarshal([]byte(result), settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (m *PollModel) getPollWithSettings(roomId, pollId string) (*plugnmeet.PollInfo, *PollSettings, error) {
+	if pollId == "" {
+		return nil, nil, errors.New("pollId required")
+	}
+
+	pi, err := m.rs.GetPollInfoByPollId(roomId, pollId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pi == "" {
+		return nil, nil, errors.New("poll not found")
+	}
+
+	info := new(plugnmeet.PollInfo)
+	if err = protojson.Unmarshal([]byte(pi), info); err != nil {
+		return nil, nil, err
+	}
+
+	settings, err := m.getPollSettings(roomId, pollId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return info, settings, nil
+}
+
+func validatePollSettings(options []*PollOption, settings *PollSettings) error {
+	if len(options) < 2 {
+		return errors.New("minimum 2 options required")
+	}
+
+	ids := make([]uint64, 0, len(options))
+	for _, opt := range options {
+		if slices.Contains(ids, uint64(opt.Id)) {
+			return fmt.Errorf("duplicate option id %d", opt.Id)
+		}
+		ids = append(ids, uint64(opt.Id))
+	}
+
+	if !settings.IsQuiz {
+		settings.CorrectOptions = nil
+		return nil
+	}
+
+	if len(settings.CorrectOptions) == 0 {
+		return errors.New("correct_options required for quiz")
+	}
+	if !settings.MultipleChoice && len(settings.CorrectOptions) > 1 {
+		return errors.New("only one correct option allowed for single choice quiz")
+	}
+	for _, id := range settings.CorrectOptions {
+		if !slices.Contains(ids, id) {
+			return fmt.Errorf("invalid correct option id %d", id)
+		}
+	}
+
+	return nil
+}
+
+// validateSelectedOptions returns selected options without duplicates
+func validateSelectedOptions(info *plugnmeet.PollInfo, settings *PollSettings, selectedOptions []uint64) ([]uint64, error) {
+	var selected []uint64
+	for _, id := range selectedOptions {
+		if !slices.Contains(selected, id) {
+			selected = append(selected, id)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, errors.New("no option selected")
+	}
+	if !settings.MultipleChoice && len(selected) > 1 {
+		return nil, errors.New("only one option can be selected")
+	}
+
+	for _, id := range selected {
+		found := false
+		for _, opt := range info.Options {
+			if uint64(opt.Id) == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid option id %d", id)
+		}
+	}
+
+	return selected, nil
+}
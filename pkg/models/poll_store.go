@@ -0,0 +1,140 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// storeRoomPolls will store all the polls of the session to DB
+// before they will be removed from redis.
+// Polls which are still running will be stored as closed.
+func (m *PollModel) storeRoomPolls(roomId, roomSid string) {
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId":  roomId,
+		"roomSid": roomSid,
+		"method":  "storeRoomPolls",
+	})
+
+	result, err := m.rs.GetPollsListByRoomId(roomId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get polls from redis")
+		return
+	}
+	if len(result) == 0 {
+		return
+	}
+
+	roomInfo, err := m.ds.GetRoomInfoBySid(roomSid, nil)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get room info")
+		return
+	}
+	if roomInfo == nil {
+		log.Warnln("room not found in DB, polls won't be stored")
+		return
+	}
+
+	var polls []*plugnmeet.PollInfo
+	for _, pi := range result {
+		info := new(plugnmeet.PollInfo)
+		if err = protojson.Unmarshal([]byte(pi), info); err != nil {
+			log.WithError(err).Warnln("failed to unmarshal poll info")
+			continue
+		}
+		polls = append(polls, info)
+	}
+	sort.Slice(polls, func(i, j int) bool {
+		return polls[i].Created < polls[j].Created
+	})
+
+	stored := 0
+	for _, info := range polls {
+		p, err := m.preparePollDbInfo(roomInfo, info)
+		if err != nil {
+			log.WithError(err).WithField("pollId", info.Id).Errorln("failed to prepare poll")
+			continue
+		}
+		if _, err = m.ds.InsertPoll(p); err != nil {
+			log.WithError(err).WithField("pollId", info.Id).Errorln("failed to store poll")
+			continue
+		}
+		stored++
+	}
+
+	log.Infof("stored %d polls of the session", stored)
+}
+
+func (m *PollModel) preparePollDbInfo(roomInfo *dbmodels.RoomInfo, info *plugnmeet.PollInfo) (*dbmodels.Poll, error) {
+	counters, err := m.rs.GetPollCountersByPollId(info.RoomId, info.Id)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := m.getPollSettings(info.RoomId, info.Id)
+	if err != nil {
+		return nil, err
+	}
+	correctOptions, err := json.Marshal(settings.CorrectOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]*PastPollOption, 0, len(info.Options))
+	for _, opt := range info.Options {
+		c, _ := strconv.ParseUint(counters[fmt.Sprintf("%d%s", opt.Id, redisservice.PollCountSuffix)], 10, 64)
+		options = append(options, &PastPollOption{
+			Id:        uint64(opt.Id),
+			Text:      opt.Text,
+			VoteCount: c,
+		})
+	}
+	marshal, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	total, _ := strconv.ParseUint(counters[redisservice.PollTotalRespField], 10, 64)
+
+	p := &dbmodels.Poll{
+		RoomTableID:    roomInfo.ID,
+		RoomID:         roomInfo.RoomId,
+		RoomSid:        roomInfo.Sid,
+		TenantID:       roomInfo.TenantID,
+		PollID:         info.Id,
+		Question:       info.Question,
+		Options:        string(marshal),
+		TotalResponses: total,
+		CreatedBy:      info.CreatedBy,
+		ClosedBy:       info.ClosedBy,
+		PollCreatedAt:  info.Created,
+		IsQuiz:         settings.IsQuiz,
+		IsAnonymous:    settings.IsAnonymous,
+		MultipleChoice: settings.MultipleChoice,
+		CorrectOptions: string(correctOptions),
+	}
+
+	// names of the respondents won't be stored for anonymous polls
+	if settings.IsAnonymous {
+		return p, nil
+	}
+
+	allRespondents, err := m.rs.GetPollAllRespondents(info.RoomId, info.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range parsePollRespondents(allRespondents) {
+		p.Responses = append(p.Responses, dbmodels.PollResponse{
+			UserID:         r.UserId,
+			Name:           r.Name,
+			SelectedOption: r.SelectedOption,
+		})
+	}
+
+	return p, nil
+}
@@ -11,27 +11,33 @@ import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 	"github.com/nats-io/nats.go"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/proto"
 )
 
 type RecorderModel struct {
+	ctx         context.Context
 	app         *config.AppConfig
 	ds          *dbservice.DatabaseService
 	rs          *redisservice.RedisService
 	natsService *natsservice.NatsService
 	um          *UserModel
+	tenantModel *TenantModel
 	logger      *logrus.Entry
 }
 
-func NewRecorderModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, um *UserModel, logger *logrus.Logger) *RecorderModel {
+func NewRecorderModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, um *UserModel, tenantModel *TenantModel, logger *logrus.Logger) *RecorderModel {
 	return &RecorderModel{
+		ctx:         ctx,
 		app:         app,
 		ds:          ds,
 		rs:          rs,
 		natsService: natsService,
 		um:          um,
+		tenantModel: tenantModel,
 		logger:      logger.WithField("model", "recorder"),
 	}
 }
@@ -47,7 +53,13 @@ type RecorderReq struct {
 	RtmpUrl     string `json:"rtmp_url"`
 }
 
-func (m *RecorderModel) SendMsgToRecorder(req *plugnmeet.RecordingReq) error {
+func (m *RecorderModel) SendMsgToRecorder(ctx context.Context, req *plugnmeet.RecordingReq) (err error) {
+	ctx, span := tracing.Start(ctx, "RecorderModel.SendMsgToRecorder",
+		tracing.RoomIdKey.String(req.GetRoomId()),
+		attribute.String("pnm.room_sid", req.GetSid()),
+		attribute.String("pnm.recording_task", req.GetTask().String()))
+	defer func() { tracing.End(span, err) }()
+
 	log := m.logger.WithFields(logrus.Fields{
 		"roomId": req.RoomId,
 		"sid":    req.Sid,
@@ -88,20 +100,115 @@ func (m *RecorderModel) SendMsgToRecorder(req *plugnmeet.RecordingReq) error {
 
 	switch req.Task {
 	case plugnmeet.RecordingTasks_START_RECORDING:
-		err := m.addTokenAndRecorder(context.Background(), req, toSend, config.RecorderBot, log)
+		err := m.addRecorderToken(ctx, req, toSend, config.RecorderBot, log)
 		if err != nil {
 			log.WithError(err).Error("failed to add token for recording bot")
 			return err
 		}
+		return m.sendToAvailableRecorder(ctx, toSend, log)
 	case plugnmeet.RecordingTasks_START_RTMP:
 		toSend.RtmpUrl = req.RtmpUrl
-		err := m.addTokenAndRecorder(context.Background(), req, toSend, config.RtmpBot, log)
+		err := m.addRecorderToken(ctx, req, toSend, config.RtmpBot, log)
 		if err != nil {
 			log.WithError(err).Error("failed to add token for rtmp bot")
 			return err
 		}
+		return m.sendToAvailableRecorder(ctx, toSend, log)
+	}
+
+	return m.sendToRecorder(ctx, toSend, log)
+}
+
+// recorderRejectedError is returned if the recorder has replied with a non-successful response
+type recorderRejectedError struct {
+	msg string
+}
+
+func (e *recorderRejectedError) Error() string {
+	return e.msg
+}
+
+// canTryNextRecorder returns true if the task can be sent to another recorder after the error
+func canTryNextRecorder(err error) bool {
+	var rejected *recorderRejectedError
+	return errors.Is(err, nats.ErrTimeout) || errors.Is(err, nats.ErrNoResponders) || errors.As(err, &rejected)
+}
+
+// sendToAvailableRecorder will reserve a slot in the best suited recorder & send the task to it.
+// If the recorder doesn't acknowledge in time or rejects the task, the slot will be released
+// & the next one will be tried with a new recording id.
+func (m *RecorderModel) sendToAvailableRecorder(ctx context.Context, toSend *plugnmeet.PlugNmeetToRecorder, log *logrus.Entry) error {
+	var excluded []string
+	var lastErr error
+	for {
+		recorder := m.reserveRecorder(toSend.RoomId, toSend.RecordingId, excluded, log)
+		if recorder == nil {
+			if lastErr != nil {
+				// the reason of the last recorder will be more helpful
+				return lastErr
+			}
+			err := errors.New("notifications.no-recorder-available")
+			log.WithError(err).Error("no recorder available")
+			return err
+		}
+		toSend.RecorderId = recorder.RecorderId
+		rLog := log.WithFields(logrus.Fields{
+			"recorderId":  recorder.RecorderId,
+			"recordingId": toSend.RecordingId,
+		})
+
+		err := m.sendToRecorder(ctx, toSend, rLog)
+		if err == nil {
+			// reservation will be released when the recorder will report the start of the task
+			return nil
+		}
+
+		if errors.Is(err, nats.ErrTimeout) {
+			// the recorder may still have received the task,
+			// so it must be stopped there before starting in another one
+			m.cancelRecorderTask(toSend, rLog)
+		}
+		if err := m.natsService.ReleaseRecorderSlot(recorder.RecorderId, toSend.RecordingId); err != nil {
+			rLog.WithError(err).Warnln("failed to release recorder slot")
+		}
+		if !canTryNextRecorder(err) {
+			return err
+		}
+
+		rLog.WithError(err).Warnln("recorder couldn't start the task, trying with another one")
+		lastErr = err
+		excluded = append(excluded, recorder.RecorderId)
+		toSend.RecordingId = fmt.Sprintf("%s-%d", toSend.RoomSid, time.Now().UnixMilli())
+	}
+}
+
+// cancelRecorderTask asks the recorder to stop the task which it didn't acknowledge in time.
+// We won't wait for the response as the recorder may be unresponsive.
+func (m *RecorderModel) cancelRecorderTask(toSend *plugnmeet.PlugNmeetToRecorder, log *logrus.Entry) {
+	stop := &plugnmeet.PlugNmeetToRecorder{
+		From:        toSend.From,
+		RoomTableId: toSend.RoomTableId,
+		RoomId:      toSend.RoomId,
+		RoomSid:     toSend.RoomSid,
+		RecordingId: toSend.RecordingId,
+		RecorderId:  toSend.RecorderId,
+		Task:        plugnmeet.RecordingTasks_STOP_RECORDING,
+	}
+	if toSend.Task == plugnmeet.RecordingTasks_START_RTMP {
+		stop.Task = plugnmeet.RecordingTasks_STOP_RTMP
+	}
+
+	payload, err := proto.Marshal(stop)
+	if err != nil {
+		log.WithError(err).Errorln("failed to marshal stop message for recorder")
+		return
+	}
+	if err = m.app.NatsConn.Publish(m.app.NatsInfo.Recorder.RecorderChannel, payload); err != nil {
+		log.WithError(err).Errorln("failed to send stop message to recorder")
 	}
+}
 
+func (m *RecorderModel) sendToRecorder(ctx context.Context, toSend *plugnmeet.PlugNmeetToRecorder, log *logrus.Entry) error {
 	payload, err := proto.Marshal(toSend)
 	if err != nil {
 		log.WithError(err).Error("failed to marshal message for recorder")
@@ -109,10 +216,13 @@ func (m *RecorderModel) SendMsgToRecorder(req *plugnmeet.RecordingReq) error {
 	}
 
 	log.Info("sending request to NATS recorder channel")
-	msg, err := m.app.NatsConn.RequestMsg(&nats.Msg{
+	reqMsg := &nats.Msg{
 		Subject: m.app.NatsInfo.Recorder.RecorderChannel,
 		Data:    payload,
-	}, time.Second*3)
+	}
+	// so that the recorder can continue the same trace
+	tracing.InjectNatsHeader(ctx, reqMsg)
+	msg, err := m.app.NatsConn.RequestMsg(reqMsg, m.app.RecorderInfo.RequestTimeout)
 
 	if err != nil {
 		log.WithError(err).Error("failed to get response from NATS recorder channel")
@@ -125,7 +235,7 @@ func (m *RecorderModel) SendMsgToRecorder(req *plugnmeet.RecordingReq) error {
 		return err
 	}
 	if !res.Status {
-		err = errors.New(res.GetMsg())
+		err = &recorderRejectedError{msg: res.GetMsg()}
 		log.WithError(err).Error("recorder returned a non-successful response")
 		return err
 	}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AutoRecordingOnFirstJoin will start recording when the first participant joins
+	AutoRecordingOnFirstJoin = "first_join"
+	// AutoRecordingOnRoomStart will start recording as soon as the room has started
+	AutoRecordingOnRoomStart = "room_started"
+
+	// how long the manual stop of a host will be remembered for the session
+	recordingManuallyStoppedTtl = time.Hour * 24
+)
+
+// AutoStartRecordingOnRoomStart should be called after the room has started
+func (m *RecorderModel) AutoStartRecordingOnRoomStart(roomId string) {
+	m.autoStartRecording(roomId, AutoRecordingOnRoomStart)
+}
+
+// AutoStartRecordingOnUserJoin should be called after a user has joined.
+// The recording will be started in any mode if the room isn't being recorded yet,
+// so if all the participants have left & someone joins again then the recording will continue.
+// It won't be started again if a host has stopped the recording.
+func (m *RecorderModel) AutoStartRecordingOnUserJoin(roomId, userId string) {
+	if isRecorderBotUser(userId) {
+		return
+	}
+	m.autoStartRecording(roomId, AutoRecordingOnFirstJoin)
+}
+
+// AutoStopRecordingOnUserLeft should be called after a user has gone offline.
+// The recording will be stopped if no other participant is online.
+func (m *RecorderModel) AutoStopRecordingOnUserLeft(roomId, userId string) {
+	if isRecorderBotUser(userId) {
+		return
+	}
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId": roomId,
+		"userId": userId,
+		"method": "AutoStopRecordingOnUserLeft",
+	})
+
+	meta, err := m.natsService.GetRoomMetadataStruct(roomId)
+	if err != nil || meta == nil {
+		return
+	}
+	if !meta.IsRecording || getAutoRecordingTrigger(meta) == "" {
+		return
+	}
+
+	userIds, err := m.natsService.GetOnlineUsersId(roomId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get online users")
+		return
+	}
+	for _, id := range userIds {
+		if !isRecorderBotUser(id) {
+			// still someone is in the room
+			return
+		}
+	}
+
+	log.Infoln("last participant has left, stopping auto recording")
+	m.sendAutoRecordingTask(roomId, plugnmeet.RecordingTasks_STOP_RECORDING, log)
+}
+
+func (m *RecorderModel) autoStartRecording(roomId, trigger string) {
+	meta, err := m.natsService.GetRoomMetadataStruct(roomId)
+	if err != nil || meta == nil {
+		return
+	}
+	if !shouldAutoStartRecording(meta, trigger) {
+		return
+	}
+
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId":  roomId,
+		"trigger": trigger,
+		"method":  "autoStartRecording",
+	})
+	log.Infoln("starting auto recording")
+	m.sendAutoRecordingTask(roomId, plugnmeet.RecordingTasks_START_RECORDING, log)
+}
+
+func (m *RecorderModel) sendAutoRecordingTask(roomId string, task plugnmeet.RecordingTasks, log *logrus.Entry) {
+	// multiple users may join or leave at the same time
+	ok, err := m.rs.LockAutoRecording(m.ctx, roomId, task.String(), m.app.RecorderInfo.ReservationTtl)
+	if err != nil {
+		log.WithError(err).Errorln("failed to lock auto recording")
+		return
+	}
+	if !ok {
+		log.Infoln("auto recording task is already in progress, skipping")
+		return
+	}
+
+	room, err := m.ds.GetRoomInfoByRoomId(roomId, 1)
+	if err != nil || room == nil {
+		log.WithError(err).Errorln("room is not running")
+		return
+	}
+	if task == plugnmeet.RecordingTasks_START_RECORDING {
+		if room.IsRecording == 1 {
+			return
+		}
+		stopped, err := m.rs.IsRecordingManuallyStopped(m.ctx, room.Sid)
+		if err != nil {
+			log.WithError(err).Errorln("failed to check if recording was stopped manually")
+			return
+		}
+		if stopped {
+			log.Infoln("recording was stopped by a host, skipping auto recording")
+			return
+		}
+		if err = m.tenantModel.CheckRecordingQuota(room.TenantID); err != nil {
+			log.WithError(err).Warnln("can't start auto recording")
+			return
+		}
+	}
+
+	err = m.SendMsgToRecorder(m.ctx, &plugnmeet.RecordingReq{
+		Task:        task,
+		Sid:         room.Sid,
+		RoomId:      room.RoomId,
+		RoomTableId: int64(room.ID),
+	})
+	if err != nil {
+		log.WithError(err).Errorln("failed to send auto recording task to recorder")
+	}
+}
+
+// OnRecordingTaskByHost should be called when a host has started or stopped the recording,
+// so that the recording won't be started again automatically after a manual stop.
+func (m *RecorderModel) OnRecordingTaskByHost(roomSid string, task plugnmeet.RecordingTasks) {
+	log := m.logger.WithFields(logrus.Fields{
+		"roomSid": roomSid,
+		"task":    task.String(),
+		"method":  "OnRecordingTaskByHost",
+	})
+
+	var err error
+	switch task {
+	case plugnmeet.RecordingTasks_STOP_RECORDING:
+		err = m.rs.SetRecordingManuallyStopped(m.ctx, roomSid, recordingManuallyStoppedTtl)
+	case plugnmeet.RecordingTasks_START_RECORDING:
+		err = m.rs.DeleteRecordingManuallyStopped(m.ctx, roomSid)
+	}
+	if err != nil {
+		log.WithError(err).Errorln("failed to update manual recording status")
+	}
+}
+
+// shouldAutoStartRecording checks if the recording should be started by the trigger
+func shouldAutoStartRecording(meta *plugnmeet.RoomMetadata, trigger string) bool {
+	if meta.IsRecording {
+		return false
+	}
+	switch getAutoRecordingTrigger(meta) {
+	case AutoRecordingOnFirstJoin:
+		return trigger == AutoRecordingOnFirstJoin
+	case AutoRecordingOnRoomStart:
+		// if everyone has left, recording will continue when someone joins again
+		return true
+	}
+	return false
+}
+
+// getAutoRecordingTrigger returns when the recording should be started by the server,
+// empty if the server shouldn't start the recording.
+// This doesn't use enable_auto_cloud_recording of the room features,
+// because clients start the recording by themselves with that.
+// It can be set as server_auto_recording in the extra_data of room metadata, for example:
+// {"server_auto_recording":"room_started"}
+func getAutoRecordingTrigger(meta *plugnmeet.RoomMetadata) string {
+	rf := meta.GetRoomFeatures().GetRecordingFeatures()
+	if !rf.GetIsAllow() || !rf.GetIsAllowCloud() || meta.GetExtraData() == "" {
+		return ""
+	}
+	extra := struct {
+		ServerAutoRecording string `json:"server_auto_recording"`
+	}{}
+	if err := json.Unmarshal([]byte(meta.GetExtraData()), &extra); err != nil {
+		return ""
+	}
+	switch extra.ServerAutoRecording {
+	case AutoRecordingOnFirstJoin, AutoRecordingOnRoomStart:
+		return extra.ServerAutoRecording
+	}
+	return ""
+}
+
+func isRecorderBotUser(userId string) bool {
+	return userId == config.RecorderBot || userId == config.RtmpBot
+}
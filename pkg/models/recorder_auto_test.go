@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+func newAutoRecordingMeta(extraData string, isRecording, allowCloud, clientAutoRecording bool) *plugnmeet.RoomMetadata {
+	return &plugnmeet.RoomMetadata{
+		IsRecording: isRecording,
+		ExtraData:   &extraData,
+		RoomFeatures: &plugnmeet.RoomCreateFeatures{
+			RecordingFeatures: &plugnmeet.RecordingFeatures{
+				IsAllow:                  true,
+				IsAllowCloud:             allowCloud,
+				EnableAutoCloudRecording: clientAutoRecording,
+			},
+		},
+	}
+}
+
+func TestGetAutoRecordingTrigger(t *testing.T) {
+	tests := []struct {
+		name string
+		meta *plugnmeet.RoomMetadata
+		want string
+	}{
+		{"first join", newAutoRecordingMeta(`{"server_auto_recording":"first_join"}`, false, true, false), AutoRecordingOnFirstJoin},
+		{"room started", newAutoRecordingMeta(`{"server_auto_recording":"room_started"}`, false, true, false), AutoRecordingOnRoomStart},
+		{"client auto recording only", newAutoRecordingMeta("", false, true, true), ""},
+		{"cloud recording not allowed", newAutoRecordingMeta(`{"server_auto_recording":"first_join"}`, false, false, false), ""},
+		{"unknown trigger", newAutoRecordingMeta(`{"server_auto_recording":"always"}`, false, true, false), ""},
+		{"invalid extra data", newAutoRecordingMeta(`server_auto_recording`, false, true, false), ""},
+		{"no features", &plugnmeet.RoomMetadata{}, ""},
+	}
+	for _, tt := range tests {
+		if got := getAutoRecordingTrigger(tt.meta); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestShouldAutoStartRecording(t *testing.T) {
+	firstJoin := `{"server_auto_recording":"first_join"}`
+	roomStarted := `{"server_auto_recording":"room_started"}`
+
+	tests := []struct {
+		name    string
+		meta    *plugnmeet.RoomMetadata
+		trigger string
+		want    bool
+	}{
+		{"first join on join", newAutoRecordingMeta(firstJoin, false, true, false), AutoRecordingOnFirstJoin, true},
+		{"first join on room start", newAutoRecordingMeta(firstJoin, false, true, false), AutoRecordingOnRoomStart, false},
+		{"room started on room start", newAutoRecordingMeta(roomStarted, false, true, false), AutoRecordingOnRoomStart, true},
+		{"room started on rejoin", newAutoRecordingMeta(roomStarted, false, true, false), AutoRecordingOnFirstJoin, true},
+		{"already recording", newAutoRecordingMeta(firstJoin, true, true, false), AutoRecordingOnFirstJoin, false},
+		{"not enabled", newAutoRecordingMeta("", false, true, true), AutoRecordingOnFirstJoin, false},
+	}
+	for _, tt := range tests {
+		if got := shouldAutoStartRecording(tt.meta, tt.trigger); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsRecorderBotUser(t *testing.T) {
+	for _, id := range []string{config.RecorderBot, config.RtmpBot} {
+		if !isRecorderBotUser(id) {
+			t.Errorf("%s should be a recorder bot", id)
+		}
+	}
+	if isRecorderBotUser("user-1") {
+		t.Error("user-1 should not be a recorder bot")
+	}
+}
@@ -2,27 +2,22 @@ package models
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 	"net/url"
+	"slices"
 	"sort"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/sirupsen/logrus"
 )
 
-func (m *RecorderModel) addTokenAndRecorder(ctx context.Context, req *plugnmeet.RecordingReq, rq *plugnmeet.PlugNmeetToRecorder, userId string, log *logrus.Entry) error {
+func (m *RecorderModel) addRecorderToken(ctx context.Context, req *plugnmeet.RecordingReq, rq *plugnmeet.PlugNmeetToRecorder, userId string, log *logrus.Entry) error {
 	log = log.WithFields(logrus.Fields{
 		"userId": userId,
-		"method": "addTokenAndRecorder",
+		"method": "addRecorderToken",
 	})
-	log.Info("adding token and selecting recorder")
-
-	recorderId := m.selectRecorder(log)
-	if recorderId == "" {
-		err := fmt.Errorf("notifications.no-recorder-available")
-		log.WithError(err).Error("no recorder available")
-		return err
-	}
+	log.Info("adding token for recorder")
 
 	gt := &plugnmeet.GenerateTokenReq{
 		RoomId: req.RoomId,
@@ -37,8 +32,6 @@ func (m *RecorderModel) addTokenAndRecorder(ctx context.Context, req *plugnmeet.
 		log.WithError(err).Errorln("error getting pnm token")
 		return err
 	}
-
-	rq.RecorderId = recorderId
 	rq.AccessToken = token
 
 	// if we have custom design, then we'll set custom design with token
@@ -48,38 +41,118 @@ func (m *RecorderModel) addTokenAndRecorder(ctx context.Context, req *plugnmeet.
 		rq.AccessToken += "&custom_design=" + url.QueryEscape(*req.CustomDesign)
 	}
 
-	log.WithField("recorderId", recorderId).Info("successfully added token and selected recorder")
+	log.Info("successfully added token for recorder")
 	return nil
 }
 
-func (m *RecorderModel) selectRecorder(log *logrus.Entry) string {
-	log = log.WithField("method", "selectRecorder")
-	log.Info("selecting a recorder")
+// reserveRecorder will select the recorders suitable for the room & reserve a slot in the first one possible.
+// Recorders in excluded won't be considered. It returns nil if no recorder is available.
+func (m *RecorderModel) reserveRecorder(roomId, reservationId string, excluded []string, log *logrus.Entry) *natsservice.RecorderInfo {
+	log = log.WithField("method", "reserveRecorder")
 
-	recorders := m.natsService.GetAllActiveRecorders()
+	for _, recorder := range m.selectRecorders(roomId, excluded, log) {
+		ok, err := m.natsService.ReserveRecorderSlot(recorder, reservationId, m.app.RecorderInfo.ReservationTtl)
+		if err != nil {
+			log.WithError(err).WithField("recorderId", recorder.RecorderId).Errorln("failed to reserve recorder slot")
+			continue
+		}
+		if ok {
+			log.WithFields(logrus.Fields{
+				"selectedRecorderId": recorder.RecorderId,
+				"currentProgress":    recorder.CurrentProgress,
+				"maxLimit":           recorder.MaxLimit,
+			}).Info("reserved slot in recorder")
+			return recorder
+		}
+		// other request has taken the last slot in the meantime
+		log.WithField("recorderId", recorder.RecorderId).Info("recorder is full, trying with next one")
+	}
+
+	return nil
+}
+
+// selectRecorders returns the active recorders with free slots which match the labels required by the room,
+// sorted by their load including the reserved slots.
+func (m *RecorderModel) selectRecorders(roomId string, excluded []string, log *logrus.Entry) []*natsservice.RecorderInfo {
+	log = log.WithField("method", "selectRecorders")
+	log.Info("selecting recorders")
 
+	recorders := m.natsService.GetAllActiveRecorders()
 	if len(recorders) < 1 {
 		log.Warn("no active recorders found")
-		return ""
+		return nil
 	}
-	// let's sort it based on active processes & max limit.
-	sort.Slice(recorders, func(i int, j int) bool {
-		var iA, jA float64
-		if recorders[i].MaxLimit > 0 {
-			iA = float64(recorders[i].CurrentProgress) / float64(recorders[i].MaxLimit)
+
+	reserved := make(map[string]int64, len(recorders))
+	for _, r := range recorders {
+		n, err := m.natsService.GetRecorderReservations(r.RecorderId)
+		if err != nil {
+			log.WithError(err).WithField("recorderId", r.RecorderId).Warnln("failed to get recorder reservations")
+		}
+		reserved[r.RecorderId] = n
+	}
+
+	labels := m.getRoomRecorderLabels(roomId, log)
+	candidates := rankRecorders(recorders, reserved, labels, excluded)
+	if len(candidates) < 1 {
+		log.WithField("labels", labels).Warn("no recorder with free slot matched")
+		return nil
+	}
+
+	return candidates
+}
+
+// rankRecorders returns the recorders with free slots which match the required labels,
+// sorted by their load including the reserved slots.
+func rankRecorders(recorders []*natsservice.RecorderInfo, reserved map[string]int64, labels map[string]string, excluded []string) []*natsservice.RecorderInfo {
+	loads := make(map[string]float64)
+	var candidates []*natsservice.RecorderInfo
+
+	for _, r := range recorders {
+		if slices.Contains(excluded, r.RecorderId) || !matchRecorderLabels(r.Labels, labels) {
+			continue
 		}
-		if recorders[j].MaxLimit > 0 {
-			jA = float64(recorders[j].CurrentProgress) / float64(recorders[j].MaxLimit)
+		if !r.HasFreeSlot(reserved[r.RecorderId]) {
+			continue
 		}
-		return iA < jA
+		loads[r.RecorderId] = float64(r.CurrentProgress+reserved[r.RecorderId]) / float64(r.MaxLimit)
+		candidates = append(candidates, r)
+	}
+
+	// let's sort it based on active processes & max limit.
+	sort.SliceStable(candidates, func(i int, j int) bool {
+		return loads[candidates[i].RecorderId] < loads[candidates[j].RecorderId]
 	})
 
-	// we'll return the first one
-	selected := recorders[0]
-	log.WithFields(logrus.Fields{
-		"selectedRecorderId": selected.RecorderId,
-		"currentProgress":    selected.CurrentProgress,
-		"maxLimit":           selected.MaxLimit,
-	}).Info("selected recorder")
-	return selected.RecorderId
+	return candidates
+}
+
+// getRoomRecorderLabels returns the labels a recorder must have to serve the room.
+// Those can be set as recorder_labels in the extra_data of room metadata, for example:
+// {"recorder_labels":{"region":"eu"}}
+func (m *RecorderModel) getRoomRecorderLabels(roomId string, log *logrus.Entry) map[string]string {
+	meta, err := m.natsService.GetRoomMetadataStruct(roomId)
+	if err != nil || meta == nil || meta.GetExtraData() == "" {
+		return nil
+	}
+
+	extra := struct {
+		RecorderLabels map[string]string `json:"recorder_labels"`
+	}{}
+	if err = json.Unmarshal([]byte(meta.GetExtraData()), &extra); err != nil {
+		log.WithError(err).Debugln("failed to parse room extra_data for recorder labels")
+		return nil
+	}
+
+	return extra.RecorderLabels
+}
+
+// matchRecorderLabels checks if the recorder has all the required labels
+func matchRecorderLabels(have, required map[string]string) bool {
+	for k, v := range required {
+		if have[k] != v {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/nats-io/nats.go"
+)
+
+func TestMatchRecorderLabels(t *testing.T) {
+	have := map[string]string{"region": "eu", "transcoding": "gpu"}
+	tests := []struct {
+		name     string
+		required map[string]string
+		want     bool
+	}{
+		{"no labels required", nil, true},
+		{"matched", map[string]string{"region": "eu"}, true},
+		{"all matched", map[string]string{"region": "eu", "transcoding": "gpu"}, true},
+		{"different value", map[string]string{"region": "us"}, false},
+		{"missing label", map[string]string{"zone": "a"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchRecorderLabels(have, tt.required); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if matchRecorderLabels(nil, map[string]string{"region": "eu"}) {
+		t.Error("recorder without labels should not match required labels")
+	}
+}
+
+func TestRankRecorders(t *testing.T) {
+	recorders := []*natsservice.RecorderInfo{
+		{RecorderId: "eu-busy", MaxLimit: 4, CurrentProgress: 2, Labels: map[string]string{"region": "eu"}},
+		{RecorderId: "eu-free", MaxLimit: 4, CurrentProgress: 1, Labels: map[string]string{"region": "eu"}},
+		{RecorderId: "us", MaxLimit: 10, Labels: map[string]string{"region": "us"}},
+		{RecorderId: "full", MaxLimit: 2, CurrentProgress: 2},
+		{RecorderId: "no-limit"},
+	}
+
+	tests := []struct {
+		name     string
+		reserved map[string]int64
+		labels   map[string]string
+		excluded []string
+		want     []string
+	}{
+		{"sorted by load", nil, nil, nil, []string{"us", "eu-free", "eu-busy"}},
+		{"affinity", nil, map[string]string{"region": "eu"}, nil, []string{"eu-free", "eu-busy"}},
+		{"reserved slots counted", map[string]int64{"eu-free": 2}, map[string]string{"region": "eu"}, nil, []string{"eu-busy", "eu-free"}},
+		{"reserved slots fill recorder", map[string]int64{"eu-busy": 2}, map[string]string{"region": "eu"}, nil, []string{"eu-free"}},
+		{"reserved slots change order", map[string]int64{"us": 9}, nil, nil, []string{"eu-free", "eu-busy", "us"}},
+		{"excluded", nil, map[string]string{"region": "eu"}, []string{"eu-free"}, []string{"eu-busy"}},
+		{"no match", nil, map[string]string{"region": "ap"}, nil, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range rankRecorders(recorders, tt.reserved, tt.labels, tt.excluded) {
+			got = append(got, r.RecorderId)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanTryNextRecorder(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", nats.ErrTimeout, true},
+		{"no responders", nats.ErrNoResponders, true},
+		{"rejected", &recorderRejectedError{msg: "busy"}, true},
+		{"wrapped rejected", fmt.Errorf("send: %w", &recorderRejectedError{msg: "busy"}), true},
+		{"other", errors.New("failed to marshal"), false},
+	}
+	for _, tt := range tests {
+		if got := canTryNextRecorder(tt.err); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/livekit"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
 	"github.com/mynaparrot/plugnmeet-server/pkg/services/redis"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,10 +21,12 @@ type RecordingModel struct {
 	analyticsModel  *AnalyticsModel
 	webhookNotifier *helpers.WebhookNotifier
 	natsService     *natsservice.NatsService
+	storage         *storageservice.StorageService
+	tenantModel     *TenantModel
 	logger          *logrus.Entry
 }
 
-func NewRecordingModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, analyticsModel *AnalyticsModel, webhookNotifier *helpers.WebhookNotifier, logger *logrus.Logger) *RecordingModel {
+func NewRecordingModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, analyticsModel *AnalyticsModel, webhookNotifier *helpers.WebhookNotifier, storage *storageservice.StorageService, tenantModel *TenantModel, logger *logrus.Logger) *RecordingModel {
 	return &RecordingModel{
 		app:             app,
 		ds:              ds,
@@ -31,11 +34,23 @@ func NewRecordingModel(app *config.AppConfig, ds *dbservice.DatabaseService, rs
 		analyticsModel:  analyticsModel,
 		webhookNotifier: webhookNotifier,
 		natsService:     natsService,
+		storage:         storage,
+		tenantModel:     tenantModel,
 		logger:          logger.WithField("model", "recording"),
 	}
 }
 
 func (m *RecordingModel) HandleRecorderResp(r *plugnmeet.RecorderToPlugNmeet, roomInfo *dbmodels.RoomInfo) {
+	switch r.Task {
+	case plugnmeet.RecordingTasks_START_RECORDING, plugnmeet.RecordingTasks_END_RECORDING,
+		plugnmeet.RecordingTasks_START_RTMP, plugnmeet.RecordingTasks_END_RTMP:
+		// the task is now counted in the progress of the recorder or has failed,
+		// so the reserved slot isn't needed anymore
+		if err := m.natsService.ReleaseRecorderSlot(r.RecorderId, r.RecordingId); err != nil {
+			m.logger.WithError(err).WithField("recorderId", r.RecorderId).Warnln("failed to release recorder slot")
+		}
+	}
+
 	switch r.Task {
 	case plugnmeet.RecordingTasks_START_RECORDING:
 		m.recordingStarted(r)
@@ -43,6 +58,9 @@ func (m *RecordingModel) HandleRecorderResp(r *plugnmeet.RecorderToPlugNmeet, ro
 
 	case plugnmeet.RecordingTasks_END_RECORDING:
 		m.recordingEnded(r)
+		if roomInfo.IsRecording == 1 {
+			m.tenantModel.AddRecordingUsage(roomInfo)
+		}
 		go m.sendToWebhookNotifier(r)
 
 	case plugnmeet.RecordingTasks_START_RTMP:
@@ -54,9 +72,12 @@ func (m *RecordingModel) HandleRecorderResp(r *plugnmeet.RecorderToPlugNmeet, ro
 		go m.sendToWebhookNotifier(r)
 
 	case plugnmeet.RecordingTasks_RECORDING_PROCEEDED:
-		creation, err := m.addRecordingInfoToDB(r, roomInfo.CreationTime)
+		creation, err := m.addRecordingInfoToDB(r, roomInfo)
 		if err != nil {
 			m.logger.WithError(err).Errorln("error adding recording info to db")
+		} else {
+			// duration, resolution & thumbnails will be updated later
+			go m.processRecordingMedia(r.RecordingId, r.FilePath)
 		}
 		// keep record of this file
 		m.addRecordingInfoFile(r, creation, roomInfo)
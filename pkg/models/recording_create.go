@@ -1,9 +1,9 @@
 package models
 
 import (
+	"bytes"
 	"database/sql"
-	"fmt"
-	"os"
+	"time"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
@@ -52,7 +52,7 @@ func (m *RecordingModel) recordingStarted(r *plugnmeet.RecorderToPlugNmeet) {
 	log.Infoln("finished processing recording_started event")
 }
 
-func (m *RecordingModel) addRecordingInfoToDB(r *plugnmeet.RecorderToPlugNmeet, roomCreationTime int64) (int64, error) {
+func (m *RecordingModel) addRecordingInfoToDB(r *plugnmeet.RecorderToPlugNmeet, roomInfo *dbmodels.RoomInfo) (int64, error) {
 	log := m.logger.WithFields(logrus.Fields{
 		"roomId":      r.RoomId,
 		"recordingId": r.RecordingId,
@@ -72,7 +72,13 @@ func (m *RecordingModel) addRecordingInfoToDB(r *plugnmeet.RecorderToPlugNmeet,
 		RecorderID:       r.RecorderId,
 		Size:             helpers.ToFixed(float64(r.FileSize), 2),
 		FilePath:         r.FilePath,
-		RoomCreationTime: roomCreationTime,
+		RoomCreationTime: roomInfo.CreationTime,
+		TenantID:         roomInfo.TenantID,
+		ParentRoomID:     roomInfo.ParentRoomID,
+	}
+	// the retention was decided during the creation of the room
+	if roomInfo.RecordingRetention > 0 {
+		data.ExpireAt = time.Now().Unix() + roomInfo.RecordingRetention
 	}
 
 	_, err := m.ds.InsertRecordingData(data)
@@ -119,9 +125,7 @@ func (m *RecordingModel) addRecordingInfoFile(r *plugnmeet.RecorderToPlugNmeet,
 		log.WithError(err).Errorln("failed to marshal recording info file data")
 		return
 	}
-	path := fmt.Sprintf("%s/%s.json", m.app.RecorderInfo.RecordingFilesPath, r.FilePath)
-
-	err = os.WriteFile(path, marshal, 0644)
+	err = m.storage.Recordings().Put(r.FilePath+".json", bytes.NewReader(marshal), int64(len(marshal)), "application/json")
 	if err != nil {
 		log.WithError(err).Errorln("failed to write recording info file")
 		return
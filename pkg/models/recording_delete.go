@@ -2,15 +2,10 @@ package models
 
 import (
 	"errors"
-	"fmt"
-	"io"
-	"io/fs"
-	"os"
 	"path"
-	"strings"
-	"time"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,21 +22,18 @@ func (m *RecordingModel) DeleteRecording(r *plugnmeet.DeleteRecordingReq) error
 		return err
 	}
 
-	filePath := fmt.Sprintf("%s/%s", m.app.RecorderInfo.RecordingFilesPath, recording.FilePath)
-	log = log.WithField("filePath", filePath)
+	store := m.storage.Recordings()
+	log = log.WithField("filePath", recording.FilePath)
 	fileExist := true
 
-	f, err := os.Stat(filePath)
+	_, err = store.Stat(recording.FilePath)
 	if err != nil {
-		var pathError *fs.PathError
-		if errors.As(err, &pathError) {
+		if errors.Is(err, storageservice.ErrNotFound) {
 			log.WithError(err).Warnln("recording file does not exist, will proceed to delete DB record")
 			fileExist = false
 		} else {
-			ms := strings.SplitN(err.Error(), "/", -1)
-			err = fmt.Errorf(ms[len(ms)-1])
 			log.WithError(err).Errorln("failed to stat recording file")
-			return err
+			return errors.New("failed to stat recording file")
 		}
 	}
 
@@ -52,21 +44,15 @@ func (m *RecordingModel) DeleteRecording(r *plugnmeet.DeleteRecordingReq) error
 		if m.app.RecorderInfo.EnableDelRecordingBackup {
 			log.Info("backing up recording before deletion")
 			// first with the video file
-			toFile := path.Join(m.app.RecorderInfo.DelRecordingBackupPath, f.Name())
-			err := os.Rename(filePath, toFile)
+			toFile := path.Base(recording.FilePath)
+			err = m.storage.Move(store, recording.FilePath, m.storage.RecordingBackups(), toFile)
 			if err != nil {
 				log.WithError(err).Errorln("error moving file to backup")
 				return err
 			}
 
-			// otherwise during cleanup will be hard to detect
-			newTime := time.Now()
-			if err := os.Chtimes(toFile, newTime, newTime); err != nil {
-				log.WithError(err).Warnln("failed to update file modification time for backup")
-			}
-
 			// now the JSON file
-			err = os.Rename(filePath+".json", toFile+".json")
+			err = m.storage.Move(store, recording.FilePath+".json", m.storage.RecordingBackups(), toFile+".json")
 			if err != nil {
 				// just log
 				log.WithError(err).Warnln("error moving json info file to backup")
@@ -74,37 +60,24 @@ func (m *RecordingModel) DeleteRecording(r *plugnmeet.DeleteRecordingReq) error
 
 		} else {
 			log.Info("deleting recording file permanently")
-			err = os.Remove(filePath)
+			err = store.Delete(recording.FilePath)
 			if err != nil {
-				ms := strings.SplitN(err.Error(), "/", -1)
-				err = fmt.Errorf(ms[len(ms)-1])
 				log.WithError(err).Errorln("failed to remove recording file")
-				return err
+				return errors.New("failed to remove recording file")
 			}
 		}
 	}
 
 	// delete compressed, if any
-	_ = os.Remove(filePath + ".fiber.gz")
+	_ = store.Delete(recording.FilePath + ".fiber.gz")
 	// delete record info file too
-	_ = os.Remove(filePath + ".json")
-
-	// we will check if the directory is empty or not
-	// if empty then better to delete that directory
-	if fileExist {
-		dir := strings.Replace(filePath, f.Name(), "", 1)
-		if dir != m.app.RecorderInfo.RecordingFilesPath {
-			log.WithField("dir", dir).Info("checking if recording directory is empty")
-			empty, err := m.isDirEmpty(dir)
-			if err == nil && empty {
-				log.Info("recording directory is empty, removing it")
-				err = os.Remove(dir)
-				if err != nil {
-					log.WithError(err).Error("error deleting directory")
-				}
-			}
-		}
+	// empty directory will be removed by the storage driver
+	_ = store.Delete(recording.FilePath + ".json")
+	// thumbnails can be generated again, so those won't be kept in the backup
+	if v, err := m.ds.GetRecording(r.RecordId); err == nil && v != nil {
+		m.deleteRecordingThumbnails(v)
 	}
+	m.deleteRecordingTextTracks(recording.FilePath, fileExist && m.app.RecorderInfo.EnableDelRecordingBackup, log)
 
 	// no error, so we'll delete record from DB
 	log.Info("deleting recording record from database")
@@ -117,17 +90,3 @@ func (m *RecordingModel) DeleteRecording(r *plugnmeet.DeleteRecordingReq) error
 	log.Info("successfully deleted recording")
 	return nil
 }
-
-func (m *RecordingModel) isDirEmpty(name string) (bool, error) {
-	f, err := os.Open(name)
-	if err != nil {
-		return false, err
-	}
-	defer f.Close()
-
-	_, err = f.Readdirnames(1) // Or f.Readdir(1)
-	if err == io.EOF {
-		return true, nil
-	}
-	return false, err // Either not empty or error, suits both cases
-}
@@ -6,7 +6,8 @@ import (
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 )
 
-func (m *RecordingModel) FetchRecordings(r *plugnmeet.FetchRecordingsReq) (*plugnmeet.FetchRecordingsResult, error) {
+// FetchRecordings returns the recordings with their metadata by record id
+func (m *RecordingModel) FetchRecordings(tenantId string, r *plugnmeet.FetchRecordingsReq, filter *FetchRecordingsFilter) (*plugnmeet.FetchRecordingsResult, map[string]*RecordingMetadata, error) {
 	if r.Limit <= 0 {
 		r.Limit = 20
 	}
@@ -18,13 +19,16 @@ func (m *RecordingModel) FetchRecordings(r *plugnmeet.FetchRecordingsReq) (*plug
 		r.OrderBy = "DESC"
 	}
 
-	data, total, err := m.ds.GetRecordings(r.RoomIds, uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	data, total, err := m.ds.GetRecordings(tenantId, r.RoomIds, uint64(r.From), uint64(r.Limit), &r.OrderBy, filter.toDbFilter())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	recordings := make([]*plugnmeet.RecordingInfo, 0, len(data))
+	metadata := make(map[string]*RecordingMetadata, len(data))
 
 	for _, v := range data {
+		metadata[v.RecordID] = m.ToRecordingMetadata(&v)
+		metadata[v.RecordID].Media = m.toRecordingMediaInfo(&v)
 		recording := &plugnmeet.RecordingInfo{
 			RecordId:         v.RecordID,
 			RoomId:           v.RoomID,
@@ -45,7 +49,7 @@ func (m *RecordingModel) FetchRecordings(r *plugnmeet.FetchRecordingsReq) (*plug
 		RecordingsList:  recordings,
 	}
 
-	return result, nil
+	return result, metadata, nil
 }
 
 // FetchRecording to get single recording information from DB
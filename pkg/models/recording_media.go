@@ -0,0 +1,194 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// RecordingMediaInfo is the extracted information of the recording file
+type RecordingMediaInfo struct {
+	// Duration in seconds
+	Duration   float64 `json:"duration"`
+	VideoCodec string  `json:"video_codec,omitempty"`
+	AudioCodec string  `json:"audio_codec,omitempty"`
+	Width      int     `json:"width,omitempty"`
+	Height     int     `json:"height,omitempty"`
+	// Thumbnails is the number of available thumbnails
+	Thumbnails int `json:"thumbnails"`
+	// ThumbnailToken can be used to download the thumbnails
+	// format: /download/recording/thumbnail/{token}/{index}, index starts from 0
+	ThumbnailToken string `json:"thumbnail_token,omitempty"`
+}
+
+// recordingThumbnailPrefix returns the prefix of the thumbnails of the recording file,
+// which will be used as the subject of the thumbnail token.
+// So, the same token can't be used to download the recording itself.
+func recordingThumbnailPrefix(filePath string) string {
+	return filePath + ".thumb_"
+}
+
+// recordingThumbnailKey returns the key of the thumbnail in the recording storage
+// format: path/recording_file_name.{mp4|webm}.thumb_{index}.jpg
+func recordingThumbnailKey(prefix string, index int) string {
+	return prefix + strconv.Itoa(index) + ".jpg"
+}
+
+// processRecordingMedia will probe the recording file & generate thumbnails,
+// then the information will be updated in the DB. It can take a while, so should run in goroutine.
+func (m *RecordingModel) processRecordingMedia(recordId, filePath string) {
+	cnf := m.app.RecorderInfo.MediaInfo
+	if cnf == nil || !cnf.Enabled {
+		return
+	}
+	log := m.logger.WithFields(logrus.Fields{
+		"recordId": recordId,
+		"filePath": filePath,
+		"method":   "processRecordingMedia",
+	})
+	log.Infoln("extracting media info of the recording")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cnf.Timeout)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp(m.app.UploadFileSettings.Path, "recording-media-")
+	if err != nil {
+		log.WithError(err).Errorln("failed to create working directory")
+		return
+	}
+	defer os.RemoveAll(workDir)
+
+	input, err := m.recordingMediaInput(filePath, workDir, cnf.Timeout)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get recording file")
+		return
+	}
+
+	output, err := exec.CommandContext(ctx, cnf.FFprobePath, "-v", "error", "-print_format", "json", "-show_format", "-show_streams", input).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			log.Errorf("ffprobe stderr: %s", string(exitErr.Stderr))
+		}
+		log.WithError(err).Errorln("ffprobe command failed")
+		return
+	}
+	info, err := helpers.ParseFFprobeOutput(output)
+	if err != nil {
+		log.WithError(err).Errorln("failed to parse ffprobe output")
+		return
+	}
+
+	thumbnails := 0
+	if info.VideoCodec != "" && cnf.Thumbnails > 0 {
+		thumbnails = m.generateRecordingThumbnails(ctx, log, input, filePath, workDir, info.Duration)
+	}
+
+	_, err = m.ds.UpdateRecording(recordId, map[string]interface{}{
+		"duration":    info.Duration,
+		"video_codec": info.VideoCodec,
+		"audio_codec": info.AudioCodec,
+		"width":       info.Width,
+		"height":      info.Height,
+		"thumbnails":  thumbnails,
+	})
+	if err != nil {
+		log.WithError(err).Errorln("failed to update media info of the recording")
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		"duration":   info.Duration,
+		"thumbnails": thumbnails,
+	}).Infoln("successfully extracted media info of the recording")
+}
+
+// recordingMediaInput returns the input for ffmpeg. The local path will be used if possible,
+// otherwise a presigned url, so that the whole file doesn't need to be downloaded
+// or as the last option the file will be downloaded to the working directory.
+func (m *RecordingModel) recordingMediaInput(filePath, workDir string, expiry time.Duration) (string, error) {
+	store := m.storage.Recordings()
+	if p := store.LocalPath(filePath); p != "" {
+		return p, nil
+	}
+
+	u, err := store.PresignedURL(filePath, path.Base(filePath), expiry)
+	if err == nil {
+		return u, nil
+	}
+	if !errors.Is(err, storageservice.ErrNotSupported) {
+		return "", err
+	}
+
+	p := filepath.Join(workDir, path.Base(filePath))
+	if err = store.GetFile(filePath, p); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// generateRecordingThumbnails takes the thumbnails evenly from the video & returns the number of saved thumbnails.
+// The thumbnails must be continuous, so it will stop on the first failure.
+func (m *RecordingModel) generateRecordingThumbnails(ctx context.Context, log *logrus.Entry, input, filePath, workDir string, duration float64) int {
+	cnf := m.app.RecorderInfo.MediaInfo
+	prefix := recordingThumbnailPrefix(filePath)
+	scale := fmt.Sprintf("scale=%d:-2", cnf.ThumbnailWidth)
+
+	for i, ts := range helpers.ThumbnailTimestamps(duration, cnf.Thumbnails) {
+		out := filepath.Join(workDir, fmt.Sprintf("thumb_%d.jpg", i))
+		// -ss before -i will seek by the keyframes, which is much faster for the large files
+		err := executeCommand(ctx, log, cnf.FFmpegPath, "-v", "error", "-ss", strconv.FormatFloat(ts, 'f', 3, 64), "-i", input, "-frames:v", "1", "-vf", scale, "-q:v", "4", "-y", out)
+		if err != nil {
+			return i
+		}
+		if err = m.storage.Recordings().PutFile(recordingThumbnailKey(prefix, i), out); err != nil {
+			log.WithError(err).Errorln("failed to save thumbnail")
+			return i
+		}
+	}
+
+	return cnf.Thumbnails
+}
+
+// deleteRecordingThumbnails will remove the thumbnails of the recording from the storage
+func (m *RecordingModel) deleteRecordingThumbnails(recording *dbmodels.Recording) {
+	prefix := recordingThumbnailPrefix(recording.FilePath)
+	for i := 0; i < recording.Thumbnails; i++ {
+		_ = m.storage.Recordings().Delete(recordingThumbnailKey(prefix, i))
+	}
+}
+
+// toRecordingMediaInfo returns nil if the media info hasn't been extracted
+func (m *RecordingModel) toRecordingMediaInfo(v *dbmodels.Recording) *RecordingMediaInfo {
+	if v.Duration <= 0 {
+		return nil
+	}
+	info := &RecordingMediaInfo{
+		Duration:   v.Duration,
+		VideoCodec: v.VideoCodec,
+		AudioCodec: v.AudioCodec,
+		Width:      v.Width,
+		Height:     v.Height,
+		Thumbnails: v.Thumbnails,
+	}
+	if v.Thumbnails > 0 {
+		token, err := m.GetThumbnailToken(v)
+		if err != nil {
+			m.logger.WithError(err).WithField("recordId", v.RecordID).Warnln("failed to generate thumbnail token")
+		} else {
+			info.ThumbnailToken = token
+		}
+	}
+	return info
+}
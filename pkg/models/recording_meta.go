@@ -0,0 +1,183 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
+	"github.com/sirupsen/logrus"
+)
+
+// RecordingMetadata holds the published state & the custom information of a recording
+type RecordingMetadata struct {
+	Published   bool     `json:"published"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	// Metadata is for custom key/value pairs, e.g. the meta_ parameters of BBB
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Media will be nil until the recording file has been probed
+	Media *RecordingMediaInfo `json:"media,omitempty"`
+}
+
+// FetchRecordingsFilter can be sent with the body of FetchRecordingsReq
+type FetchRecordingsFilter struct {
+	Published *bool    `json:"published"`
+	Tags      []string `json:"tags"`
+}
+
+type PublishRecordingReq struct {
+	RecordId string `json:"record_id"`
+	Publish  bool   `json:"publish"`
+}
+
+// UpdateRecordingReq will change only the fields which were sent.
+// An empty tags array will remove all the tags & an empty value in metadata will remove that key.
+type UpdateRecordingReq struct {
+	RecordId    string            `json:"record_id"`
+	Title       *string           `json:"title"`
+	Description *string           `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// PublishRecordings will set the published state of the recordings
+func (m *RecordingModel) PublishRecordings(recordIds []string, publish bool) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"recordIds": recordIds,
+		"publish":   publish,
+		"method":    "PublishRecordings",
+	})
+
+	published := 0
+	if publish {
+		published = 1
+	}
+	for _, recordId := range recordIds {
+		affected, err := m.ds.UpdateRecording(recordId, map[string]interface{}{
+			"published": published,
+		})
+		if err != nil {
+			log.WithError(err).Errorln("failed to update published state")
+			return err
+		}
+		if affected == 0 {
+			// either not found or already in the same state
+			if r, err := m.ds.GetRecording(recordId); err != nil || r == nil {
+				return errors.New("recording not found")
+			}
+		}
+	}
+
+	log.Infoln("successfully updated published state")
+	return nil
+}
+
+// UpdateRecording will update the custom information of the recording
+func (m *RecordingModel) UpdateRecording(r *UpdateRecordingReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"recordId": r.RecordId,
+		"method":   "UpdateRecording",
+	})
+
+	recording, err := m.ds.GetRecording(r.RecordId)
+	if err != nil {
+		return err
+	}
+	if recording == nil {
+		return errors.New("recording not found")
+	}
+
+	update := make(map[string]interface{})
+	if r.Title != nil {
+		title := strings.TrimSpace(*r.Title)
+		if len(title) > 255 {
+			return errors.New("title is too long")
+		}
+		update["title"] = title
+	}
+	if r.Description != nil {
+		update["description"] = strings.TrimSpace(*r.Description)
+	}
+	if r.Tags != nil {
+		tags := strings.Join(normalizeRecordingTags(r.Tags), ",")
+		if len(tags) > 1024 {
+			return errors.New("tags are too long")
+		}
+		update["tags"] = tags
+	}
+	if len(r.Metadata) > 0 {
+		meta := m.ToRecordingMetadata(recording).Metadata
+		if meta == nil {
+			meta = make(map[string]string)
+		}
+		for k, v := range r.Metadata {
+			if v == "" {
+				delete(meta, k)
+			} else {
+				meta[k] = v
+			}
+		}
+		marshal, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		update["metadata"] = string(marshal)
+	}
+
+	if len(update) == 0 {
+		return nil
+	}
+	if _, err = m.ds.UpdateRecording(r.RecordId, update); err != nil {
+		log.WithError(err).Errorln("failed to update recording")
+		return err
+	}
+
+	log.Infoln("successfully updated recording")
+	return nil
+}
+
+// ToRecordingMetadata returns the published state & the custom information of the recording
+func (m *RecordingModel) ToRecordingMetadata(v *dbmodels.Recording) *RecordingMetadata {
+	meta := &RecordingMetadata{
+		Published:   v.Published == 1,
+		Title:       v.Title,
+		Description: v.Description,
+		Tags:        make([]string, 0),
+	}
+	if v.Tags != "" {
+		meta.Tags = strings.Split(v.Tags, ",")
+	}
+	if v.Metadata != "" {
+		if err := json.Unmarshal([]byte(v.Metadata), &meta.Metadata); err != nil {
+			m.logger.WithError(err).WithField("recordId", v.RecordID).Warnln("invalid recording metadata")
+		}
+	}
+	return meta
+}
+
+func (f *FetchRecordingsFilter) toDbFilter() *dbservice.RecordingsFilter {
+	if f == nil {
+		return nil
+	}
+	return &dbservice.RecordingsFilter{
+		Published: f.Published,
+		Tags:      normalizeRecordingTags(f.Tags),
+	}
+}
+
+// normalizeRecordingTags will trim & remove empty or duplicate tags.
+// Comma is used as separator in the DB, so it can't be part of a tag.
+func normalizeRecordingTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(strings.ReplaceAll(t, ",", " "))
+		if t != "" && !slices.Contains(result, t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
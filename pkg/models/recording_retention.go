@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RecordingExpiredEvent = "recording_expired"
+
+	// recordingExpiryRetryDelay is how long to wait before trying again
+	// to delete an expired recording which has failed to delete
+	recordingExpiryRetryDelay = time.Hour
+)
+
+type ExpiredRecordingsReq struct {
+	// DryRun will only report the expired recordings without deleting them
+	DryRun bool   `json:"dry_run"`
+	Limit  uint32 `json:"limit"`
+}
+
+type ExpiredRecordingInfo struct {
+	RecordId     string  `json:"record_id"`
+	RoomId       string  `json:"room_id"`
+	RoomSid      string  `json:"room_sid"`
+	FilePath     string  `json:"file_path"`
+	FileSize     float64 `json:"file_size"`
+	CreationTime int64   `json:"creation_time"`
+	ExpireAt     int64   `json:"expire_at"`
+	Deleted      bool    `json:"deleted"`
+}
+
+type ExpiredRecordingsResult struct {
+	DryRun       bool  `json:"dry_run"`
+	TotalExpired int64 `json:"total_expired"`
+	Deleted      int   `json:"deleted"`
+	// Postponed are the failed ones, which will be tried again after a delay
+	Postponed  int                     `json:"postponed"`
+	Recordings []*ExpiredRecordingInfo `json:"recordings"`
+}
+
+// ExpireRecordings will delete the recordings older than their retention
+// using the same way as DeleteRecording, so backup will be used if enabled.
+// Empty tenantId means recordings of all the tenants.
+func (m *RecordingModel) ExpireRecordings(tenantId string, r *ExpiredRecordingsReq) (*ExpiredRecordingsResult, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"tenantId": tenantId,
+		"dryRun":   r.DryRun,
+		"method":   "ExpireRecordings",
+	})
+
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+
+	recordings, total, err := m.ds.GetExpiredRecordings(tenantId, time.Now().Unix(), uint64(r.Limit))
+	if err != nil {
+		log.WithError(err).Errorln("failed to get expired recordings")
+		return nil, err
+	}
+
+	result := &ExpiredRecordingsResult{
+		DryRun:       r.DryRun,
+		TotalExpired: total,
+		Recordings:   make([]*ExpiredRecordingInfo, 0, len(recordings)),
+	}
+
+	for i := range recordings {
+		rec := &recordings[i]
+		info := &ExpiredRecordingInfo{
+			RecordId:     rec.RecordID,
+			RoomId:       rec.RoomID,
+			RoomSid:      rec.RoomSid.String,
+			FilePath:     rec.FilePath,
+			FileSize:     rec.Size,
+			CreationTime: rec.CreationTime,
+			ExpireAt:     rec.ExpireAt,
+		}
+		result.Recordings = append(result.Recordings, info)
+		if r.DryRun {
+			continue
+		}
+
+		err = m.DeleteRecording(&plugnmeet.DeleteRecordingReq{
+			RecordId: rec.RecordID,
+		})
+		if err != nil {
+			log.WithError(err).WithField("recordId", rec.RecordID).Errorln("failed to delete expired recording")
+			// otherwise it will stay at the beginning of the next batches
+			if m.postponeRecordingExpiry(rec, time.Now()) {
+				result.Postponed++
+			}
+			continue
+		}
+		info.Deleted = true
+		result.Deleted++
+		m.sendRecordingExpiredWebhook(rec)
+	}
+
+	if result.Deleted > 0 {
+		log.WithField("deleted", result.Deleted).Infoln("deleted expired recordings")
+	}
+	return result, nil
+}
+
+// postponeRecordingExpiry moves the expiry of the recording,
+// so that it will be tried again after recordingExpiryRetryDelay.
+func (m *RecordingModel) postponeRecordingExpiry(rec *dbmodels.Recording, now time.Time) bool {
+	_, err := m.ds.UpdateRecording(rec.RecordID, map[string]interface{}{
+		"expire_at": nextRecordingExpiryRetry(now),
+	})
+	if err != nil {
+		m.logger.WithError(err).WithField("recordId", rec.RecordID).Errorln("failed to postpone recording expiry")
+		return false
+	}
+	return true
+}
+
+func nextRecordingExpiryRetry(now time.Time) int64 {
+	return now.Add(recordingExpiryRetryDelay).Unix()
+}
+
+func (m *RecordingModel) sendRecordingExpiredWebhook(rec *dbmodels.Recording) {
+	if m.webhookNotifier == nil || !rec.RoomSid.Valid {
+		return
+	}
+
+	event := RecordingExpiredEvent
+	fileSize := float32(rec.Size)
+	msg := &plugnmeet.CommonNotifyEvent{
+		Event: &event,
+		Room: &plugnmeet.NotifyEventRoom{
+			Sid:    &rec.RoomSid.String,
+			RoomId: &rec.RoomID,
+		},
+		RecordingInfo: &plugnmeet.RecordingInfoEvent{
+			RecordId:   rec.RecordID,
+			RecorderId: rec.RecorderID,
+			FilePath:   &rec.FilePath,
+			FileSize:   &fileSize,
+		},
+	}
+	// the room has ended long ago, so the url will be retrieved from the DB
+	m.webhookNotifier.ForceToPutInQueue(msg)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHasMoreExpiredRecordings(t *testing.T) {
+	newResult := func(total, deleted, postponed int) *ExpiredRecordingsResult {
+		return &ExpiredRecordingsResult{
+			Deleted:    deleted,
+			Postponed:  postponed,
+			Recordings: make([]*ExpiredRecordingInfo, total),
+		}
+	}
+
+	tests := []struct {
+		name string
+		res  *ExpiredRecordingsResult
+		want bool
+	}{
+		{"full batch deleted", newResult(10, 10, 0), true},
+		{"full batch with failed ones postponed", newResult(10, 7, 3), true},
+		{"full batch all failed & postponed", newResult(10, 0, 10), true},
+		{"full batch nothing processed", newResult(10, 0, 0), false},
+		{"last batch", newResult(4, 4, 0), false},
+		{"last batch with failed", newResult(4, 2, 2), false},
+		{"empty", newResult(0, 0, 0), false},
+	}
+	for _, tt := range tests {
+		if got := hasMoreExpiredRecordings(tt.res, 10); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNextRecordingExpiryRetry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	got := nextRecordingExpiryRetry(now)
+	if got <= now.Unix() {
+		t.Fatalf("retry must be after now, got %d", got)
+	}
+	if got != now.Add(recordingExpiryRetryDelay).Unix() {
+		t.Errorf("unexpected retry time %d", got)
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
@@ -13,7 +14,7 @@ var recordingModel *RecordingModel
 func TestAuthRecording_FetchRecordings(t *testing.T) {
 	recordingModel = NewRecordingModel(nil, nil, nil)
 
-	result, err := recordingModel.FetchRecordings(&plugnmeet.FetchRecordingsReq{
+	result, err := recordingModel.FetchRecordings("", &plugnmeet.FetchRecordingsReq{
 		RoomIds: []string{roomId},
 	})
 	if err != nil {
@@ -78,7 +79,7 @@ func TestAnalyticsAuthModel_CreateAndVerifyToken(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	_, res, err := recordingModel.VerifyRecordingToken(token)
+	_, res, err := recordingModel.VerifyRecordingToken(context.Background(), token, "")
 	if err == nil {
 		t.Error("should not found the file")
 		return
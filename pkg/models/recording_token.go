@@ -1,58 +1,81 @@
 package models
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"os"
+	"strconv"
 	"strings"
-	"time"
 
-	"github.com/go-jose/go-jose/v4"
-	"github.com/go-jose/go-jose/v4/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/mynaparrot/plugnmeet-protocol/auth"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
 )
 
-// GetDownloadToken will use the same JWT token generator as plugNmeet is using
-func (m *RecordingModel) GetDownloadToken(r *plugnmeet.GetDownloadTokenReq) (string, error) {
-	recording, err := m.FetchRecording(r.RecordId)
+// GetDownloadToken will generate token to download the recording,
+// opts can be nil to use the defaults
+func (m *RecordingModel) GetDownloadToken(r *plugnmeet.GetDownloadTokenReq, opts *DownloadTokenOptions) (string, error) {
+	recording, err := m.ds.GetRecording(r.RecordId)
 	if err != nil {
 		return "", err
 	}
+	if recording == nil {
+		return "", errors.New("no info found")
+	}
 
-	return m.CreateTokenForDownload(recording.FilePath)
+	return generateDownloadToken(m.app, recording.FilePath, recording.TenantID, recording.RecordID, m.app.RecorderInfo.TokenValidity, opts)
 }
 
-// CreateTokenForDownload will generate token
+// CreateTokenForDownload will use the same JWT token generator as plugNmeet is using
 // path format: sub_path/roomSid/filename
 func (m *RecordingModel) CreateTokenForDownload(path string) (string, error) {
 	return auth.GenerateTokenForDownloadRecording(path, m.app.Client.ApiKey, m.app.Client.Secret, m.app.RecorderInfo.TokenValidity)
 }
 
-// VerifyRecordingToken verify token & provide file path
-func (m *RecordingModel) VerifyRecordingToken(token string) (string, int, error) {
-	tok, err := jwt.ParseSigned(token, []jose.SignatureAlgorithm{jose.HS256})
+// VerifyRecordingToken verify token for the client & provide the info with the key of the file in recording storage
+func (m *RecordingModel) VerifyRecordingToken(ctx context.Context, token, clientIp string) (*DownloadTokenInfo, int, error) {
+	info, status, err := verifyDownloadToken(ctx, m.app, m.rs, token, clientIp)
 	if err != nil {
-		return "", fiber.StatusUnauthorized, err
+		return nil, status, err
 	}
 
-	out := jwt.Claims{}
-	if err = tok.Claims([]byte(m.app.Client.Secret), &out); err != nil {
-		return "", fiber.StatusUnauthorized, err
+	_, err = m.storage.Recordings().Stat(info.Key)
+	if err != nil {
+		if errors.Is(err, storageservice.ErrNotFound) {
+			return nil, fiber.StatusNotFound, err
+		}
+		m.logger.WithError(err).Errorln("failed to get recording file info")
+		return nil, fiber.StatusInternalServerError, errors.New("failed to get recording file info")
 	}
 
-	if err = out.Validate(jwt.Expected{Issuer: m.app.Client.ApiKey, Time: time.Now().UTC()}); err != nil {
-		return "", fiber.StatusUnauthorized, err
-	}
+	return info, fiber.StatusOK, nil
+}
 
-	file := fmt.Sprintf("%s/%s", m.app.RecorderInfo.RecordingFilesPath, out.Subject)
-	_, err = os.Lstat(file)
+// GetThumbnailToken will generate token to download the thumbnails of the recording.
+// The thumbnails are used as preview, so the token is never single use.
+func (m *RecordingModel) GetThumbnailToken(recording *dbmodels.Recording) (string, error) {
+	singleUse := false
+	return generateDownloadToken(m.app, recordingThumbnailPrefix(recording.FilePath), recording.TenantID, recording.RecordID, m.app.RecorderInfo.TokenValidity, &DownloadTokenOptions{
+		SingleUse: &singleUse,
+	})
+}
+
+// VerifyThumbnailToken verify token for the client & provide the info with the key of the thumbnail by index
+func (m *RecordingModel) VerifyThumbnailToken(ctx context.Context, token, index, clientIp string) (*DownloadTokenInfo, int, error) {
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 {
+		return nil, fiber.StatusBadRequest, errors.New("invalid thumbnail index")
+	}
 
+	info, status, err := verifyDownloadToken(ctx, m.app, m.rs, token, clientIp)
 	if err != nil {
-		ms := strings.SplitN(err.Error(), "/", -1)
-		return "", fiber.StatusNotFound, errors.New(ms[len(ms)-1])
+		return nil, status, err
+	}
+	if !strings.HasSuffix(info.Key, ".thumb_") {
+		return nil, fiber.StatusUnauthorized, errors.New("invalid thumbnail token")
 	}
+	info.Key = recordingThumbnailKey(info.Key, i)
 
-	return file, fiber.StatusOK, nil
+	return info, fiber.StatusOK, nil
 }
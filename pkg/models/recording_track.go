@@ -0,0 +1,251 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of the text tracks of the recordings
+const (
+	RecordingTextTrackKindSubtitles = "subtitles"
+	RecordingTextTrackKindCaptions  = "captions"
+)
+
+// RecordingTextTrackSourceUpload is the source of the tracks uploaded using the API
+const RecordingTextTrackSourceUpload = "upload"
+
+// RecordingTextTrackMaxSize is the maximum size of a single uploaded text track
+const RecordingTextTrackMaxSize = 5 * 1000000
+
+const (
+	// recordingTextTracksLockTTL & recordingTextTracksMaxWaitTime are for the lock of
+	// the list of the text tracks, so the concurrent uploads won't overwrite each other
+	recordingTextTracksLockTTL     = 30 * time.Second
+	recordingTextTracksMaxWaitTime = 10 * time.Second
+)
+
+// ErrRecordingNotFound will be returned if the recording of the text tracks doesn't exist
+var ErrRecordingNotFound = errors.New("recording not found")
+
+// RecordingTextTrack is a caption or subtitle of the recording.
+// The tracks are kept next to the recording file as WebVTT,
+// format: path/recording_file_name.{mp4|webm}.track_{lang}_{kind}.vtt
+type RecordingTextTrack struct {
+	Kind    string `json:"kind"`
+	Lang    string `json:"lang"`
+	Label   string `json:"label"`
+	Source  string `json:"source"`
+	Created int64  `json:"created"`
+}
+
+// Name is unique for every kind & language of the recording
+func (t *RecordingTextTrack) Name() string {
+	return t.Lang + "_" + t.Kind
+}
+
+// recordingTextTrackIndexKey returns the key of the list of the text tracks of the recording file
+func recordingTextTrackIndexKey(filePath string) string {
+	return filePath + ".tracks.json"
+}
+
+// recordingTextTrackPrefix returns the prefix of the text tracks of the recording file,
+// which will be used as the subject of the text track token
+func recordingTextTrackPrefix(filePath string) string {
+	return filePath + ".track_"
+}
+
+// recordingTextTrackKey returns the key of the text track in the recording storage
+func recordingTextTrackKey(prefix, name string) string {
+	return prefix + name + ".vtt"
+}
+
+// GetRecordingTextTracks returns the recording & its text tracks
+func (m *RecordingModel) GetRecordingTextTracks(recordId string) (*dbmodels.Recording, []*RecordingTextTrack, error) {
+	recording, err := m.ds.GetRecording(recordId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if recording == nil {
+		return nil, nil, ErrRecordingNotFound
+	}
+
+	tracks, err := m.readRecordingTextTracks(recording.FilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return recording, tracks, nil
+}
+
+// PutRecordingTextTrack saves the text track of the recording in WebVTT,
+// the existing track of the same kind & language will be replaced
+func (m *RecordingModel) PutRecordingTextTrack(recordId string, t *RecordingTextTrack, data []byte) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"recordId": recordId,
+		"kind":     t.Kind,
+		"lang":     t.Lang,
+		"method":   "PutRecordingTextTrack",
+	})
+
+	vtt, err := prepareRecordingTextTrack(t, data)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	lockName := "recordingTextTracks-" + recordId
+	lockValue, err := acquireLockWithRetry(ctx, m.rs, lockName, recordingTextTracksLockTTL, recordingTextTracksMaxWaitTime)
+	if err != nil {
+		log.WithError(err).Errorln("failed to acquire text tracks lock")
+		return err
+	}
+	defer func() {
+		if err := m.rs.Unlock(ctx, lockName, lockValue); err != nil {
+			log.WithError(err).Errorln("failed to release text tracks lock")
+		}
+	}()
+
+	recording, tracks, err := m.GetRecordingTextTracks(recordId)
+	if err != nil {
+		return err
+	}
+
+	store := m.storage.Recordings()
+	key := recordingTextTrackKey(recordingTextTrackPrefix(recording.FilePath), t.Name())
+	if err = store.Put(key, bytes.NewReader(vtt), int64(len(vtt)), "text/vtt"); err != nil {
+		log.WithError(err).Errorln("failed to save text track")
+		return err
+	}
+
+	if err = m.writeRecordingTextTracks(recording.FilePath, replaceRecordingTextTrack(tracks, t)); err != nil {
+		log.WithError(err).Errorln("failed to save text track list")
+		return err
+	}
+
+	log.Infoln("successfully saved text track")
+	return nil
+}
+
+// prepareRecordingTextTrack validates the track, sets the defaults & returns the data converted to WebVTT
+func prepareRecordingTextTrack(t *RecordingTextTrack, data []byte) ([]byte, error) {
+	if t.Kind != RecordingTextTrackKindSubtitles && t.Kind != RecordingTextTrackKindCaptions {
+		return nil, errors.New("kind must be subtitles or captions")
+	}
+	if !helpers.IsValidLanguageTag(t.Lang) {
+		return nil, errors.New("invalid lang")
+	}
+	if len(data) > RecordingTextTrackMaxSize {
+		return nil, errors.New("text track is too large")
+	}
+	vtt, err := helpers.ToWebVTT(data)
+	if err != nil {
+		return nil, err
+	}
+	if t.Label == "" {
+		t.Label = t.Lang
+	}
+	if t.Source == "" {
+		t.Source = RecordingTextTrackSourceUpload
+	}
+	t.Created = time.Now().UnixMilli()
+	return vtt, nil
+}
+
+// replaceRecordingTextTrack adds the track at first, the existing one of the same name will be removed
+func replaceRecordingTextTrack(tracks []*RecordingTextTrack, t *RecordingTextTrack) []*RecordingTextTrack {
+	list := []*RecordingTextTrack{t}
+	for _, v := range tracks {
+		if v.Name() != t.Name() {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// GetTextTrackToken will generate token to download the text tracks of the recording.
+// Same as the thumbnails, the token is never single use.
+func (m *RecordingModel) GetTextTrackToken(recording *dbmodels.Recording) (string, error) {
+	singleUse := false
+	return generateDownloadToken(m.app, recordingTextTrackPrefix(recording.FilePath), recording.TenantID, recording.RecordID, m.app.RecorderInfo.TokenValidity, &DownloadTokenOptions{
+		SingleUse: &singleUse,
+	})
+}
+
+// VerifyTextTrackToken verify token for the client & provide the info with the key of the text track,
+// name format: {lang}_{kind}.vtt
+func (m *RecordingModel) VerifyTextTrackToken(ctx context.Context, token, name, clientIp string) (*DownloadTokenInfo, int, error) {
+	lang, kind, ok := strings.Cut(strings.TrimSuffix(name, ".vtt"), "_")
+	if !ok || !helpers.IsValidLanguageTag(lang) || (kind != RecordingTextTrackKindSubtitles && kind != RecordingTextTrackKindCaptions) {
+		return nil, fiber.StatusBadRequest, errors.New("invalid text track name")
+	}
+
+	info, status, err := verifyDownloadToken(ctx, m.app, m.rs, token, clientIp)
+	if err != nil {
+		return nil, status, err
+	}
+	if !strings.HasSuffix(info.Key, ".track_") {
+		return nil, fiber.StatusUnauthorized, errors.New("invalid text track token")
+	}
+	info.Key = recordingTextTrackKey(info.Key, lang+"_"+kind)
+
+	return info, fiber.StatusOK, nil
+}
+
+func (m *RecordingModel) readRecordingTextTracks(filePath string) ([]*RecordingTextTrack, error) {
+	rc, _, err := m.storage.Recordings().Get(recordingTextTrackIndexKey(filePath))
+	if err != nil {
+		if errors.Is(err, storageservice.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer rc.Close()
+
+	var tracks []*RecordingTextTrack
+	if err = json.NewDecoder(rc).Decode(&tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (m *RecordingModel) writeRecordingTextTracks(filePath string, tracks []*RecordingTextTrack) error {
+	data, err := json.Marshal(tracks)
+	if err != nil {
+		return err
+	}
+	return m.storage.Recordings().Put(recordingTextTrackIndexKey(filePath), bytes.NewReader(data), int64(len(data)), "application/json")
+}
+
+// deleteRecordingTextTracks will remove the text tracks of the recording from the storage.
+// The uploaded tracks can't be generated again, so will be moved to the backup if enabled.
+func (m *RecordingModel) deleteRecordingTextTracks(filePath string, backup bool, log *logrus.Entry) {
+	tracks, err := m.readRecordingTextTracks(filePath)
+	if err != nil || len(tracks) == 0 {
+		return
+	}
+
+	store := m.storage.Recordings()
+	keys := []string{recordingTextTrackIndexKey(filePath)}
+	for _, t := range tracks {
+		keys = append(keys, recordingTextTrackKey(recordingTextTrackPrefix(filePath), t.Name()))
+	}
+	for _, key := range keys {
+		if backup {
+			if err = m.storage.Move(store, key, m.storage.RecordingBackups(), path.Base(key)); err == nil {
+				continue
+			}
+			log.WithError(err).WithField("key", key).Warnln("error moving text track to backup")
+		}
+		_ = store.Delete(key)
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrepareRecordingTextTrack(t *testing.T) {
+	srt := []byte("1\n00:00:01,600 --> 00:00:04,200\nHello\n")
+
+	track := &RecordingTextTrack{Kind: RecordingTextTrackKindCaptions, Lang: "en-US"}
+	vtt, err := prepareRecordingTextTrack(track, srt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(vtt), "WEBVTT") {
+		t.Errorf("expected WebVTT, got %q", vtt)
+	}
+	if track.Label != "en-US" || track.Source != RecordingTextTrackSourceUpload || track.Created == 0 {
+		t.Errorf("defaults were not set %+v", track)
+	}
+
+	tests := []struct {
+		name  string
+		track *RecordingTextTrack
+		data  []byte
+	}{
+		{"invalid kind", &RecordingTextTrack{Kind: "chapters", Lang: "en"}, srt},
+		{"invalid lang", &RecordingTextTrack{Kind: RecordingTextTrackKindSubtitles, Lang: "../en"}, srt},
+		{"too large", &RecordingTextTrack{Kind: RecordingTextTrackKindSubtitles, Lang: "en"}, make([]byte, RecordingTextTrackMaxSize+1)},
+		{"invalid format", &RecordingTextTrack{Kind: RecordingTextTrackKindSubtitles, Lang: "en"}, []byte("hello")},
+	}
+	for _, tt := range tests {
+		if _, err = prepareRecordingTextTrack(tt.track, tt.data); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestReplaceRecordingTextTrack(t *testing.T) {
+	tracks := []*RecordingTextTrack{
+		{Kind: RecordingTextTrackKindSubtitles, Lang: "en", Label: "old"},
+		{Kind: RecordingTextTrackKindCaptions, Lang: "en"},
+		{Kind: RecordingTextTrackKindSubtitles, Lang: "de"},
+	}
+	list := replaceRecordingTextTrack(tracks, &RecordingTextTrack{Kind: RecordingTextTrackKindSubtitles, Lang: "en", Label: "new"})
+	if len(list) != 3 || list[0].Label != "new" {
+		t.Fatalf("unexpected list %v", list)
+	}
+	for _, v := range list[1:] {
+		if v.Name() == "en_subtitles" {
+			t.Errorf("old track wasn't replaced")
+		}
+	}
+
+	list = replaceRecordingTextTrack(tracks, &RecordingTextTrack{Kind: RecordingTextTrackKindCaptions, Lang: "fr"})
+	if len(list) != 4 {
+		t.Errorf("expected new track to be added, got %d", len(list))
+	}
+}
+
+func TestRecordingTextTrackKeys(t *testing.T) {
+	filePath := "node_01/room01/rec01.mp4"
+	if got := recordingTextTrackIndexKey(filePath); got != "node_01/room01/rec01.mp4.tracks.json" {
+		t.Errorf("unexpected index key %q", got)
+	}
+	track := &RecordingTextTrack{Kind: RecordingTextTrackKindCaptions, Lang: "pt-BR"}
+	if got := recordingTextTrackKey(recordingTextTrackPrefix(filePath), track.Name()); got != "node_01/room01/rec01.mp4.track_pt-BR_captions.vtt" {
+		t.Errorf("unexpected track key %q", got)
+	}
+}
@@ -30,9 +30,11 @@ type RoomModel struct {
 	speechToText    *SpeechToTextModel
 	analyticsModel  *AnalyticsModel
 	breakoutModel   *BreakoutRoomModel
+	tenantModel     *TenantModel
+	chatArchive     *ChatArchiveModel
 }
 
-func NewRoomModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, lk *livekitservice.LivekitService, natsService *natsservice.NatsService, webhookNotifier *helpers.WebhookNotifier, userModel *UserModel, recorderModel *RecorderModel, fileModel *FileModel, roomDuration *RoomDurationModel, etherpadModel *EtherpadModel, pollModel *PollModel, speechToText *SpeechToTextModel, analyticsModel *AnalyticsModel, logger *logrus.Logger) *RoomModel {
+func NewRoomModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, lk *livekitservice.LivekitService, natsService *natsservice.NatsService, webhookNotifier *helpers.WebhookNotifier, userModel *UserModel, recorderModel *RecorderModel, fileModel *FileModel, roomDuration *RoomDurationModel, etherpadModel *EtherpadModel, pollModel *PollModel, speechToText *SpeechToTextModel, analyticsModel *AnalyticsModel, tenantModel *TenantModel, chatArchive *ChatArchiveModel, logger *logrus.Logger) *RoomModel {
 	return &RoomModel{
 		ctx:             ctx,
 		app:             app,
@@ -49,6 +51,8 @@ func NewRoomModel(ctx context.Context, app *config.AppConfig, ds *dbservice.Data
 		pollModel:       pollModel,
 		speechToText:    speechToText,
 		analyticsModel:  analyticsModel,
+		tenantModel:     tenantModel,
+		chatArchive:     chatArchive,
 		logger:          logger.WithField("model", "room"),
 	}
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -13,9 +14,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func (m *RoomModel) CreateRoom(r *plugnmeet.CreateRoomReq) (*plugnmeet.ActiveRoomInfo, error) {
+// CreateRoom will create a new room or return the existing active room.
+// tenantId should be empty for rooms which were created using the default API key.
+func (m *RoomModel) CreateRoom(tenantId string, r *plugnmeet.CreateRoomReq) (*plugnmeet.ActiveRoomInfo, error) {
 	log := m.logger.WithFields(logrus.Fields{
 		"room_id":       r.GetRoomId(),
+		"tenant_id":     tenantId,
 		"breakout_room": r.GetMetadata().GetIsBreakoutRoom(),
 		"method":        "CreateRoom",
 	})
@@ -45,6 +49,12 @@ func (m *RoomModel) CreateRoom(r *plugnmeet.CreateRoomReq) (*plugnmeet.ActiveRoo
 		return nil, err
 	}
 
+	// the row can't be moved to another tenant
+	if err = checkRoomOwnership(roomDbInfo, tenantId); err != nil {
+		log.WithField("owner", roomDbInfo.TenantID).Warnln("room id is already in use by another tenant")
+		return nil, err
+	}
+
 	// handle existing room logic
 	if roomDbInfo != nil && roomDbInfo.Sid != "" {
 		log.Info("found existing active room in db, attempting to handle it")
@@ -64,11 +74,36 @@ func (m *RoomModel) CreateRoom(r *plugnmeet.CreateRoomReq) (*plugnmeet.ActiveRoo
 	// initialize room defaults
 	m.setRoomDefaults(r)
 
+	// breakout rooms are part of the parent room, so quota is checked only once
+	unlockTenant := func() {}
+	if tenantId != "" && !r.Metadata.IsBreakoutRoom {
+		// checking the quota & saving the room should be atomic for the tenant
+		lockName := "tenantRoomCreation-" + tenantId
+		tenantLockValue, err := acquireLockWithRetry(m.ctx, m.rs, lockName, defaultRoomCreationLockTTL, defaultRoomCreationMaxWaitTime)
+		if err != nil {
+			log.WithError(err).Errorln("failed to acquire tenant room creation lock")
+			return nil, err
+		}
+		unlockTenant = func() {
+			if err := m.rs.Unlock(m.ctx, lockName, tenantLockValue); err != nil {
+				log.WithError(err).Errorln("failed to release tenant room creation lock")
+			}
+		}
+
+		if err = m.tenantModel.CheckCreateRoomQuota(tenantId, r, roomDbInfo != nil && roomDbInfo.IsRunning == 1); err != nil {
+			unlockTenant()
+			log.WithError(err).Warnln("tenant quota check failed")
+			return nil, err
+		}
+	}
+
 	// prepare DB model
-	roomDbInfo, sid := m.prepareRoomDbInfo(r, roomDbInfo)
+	roomDbInfo, sid := m.prepareRoomDbInfo(tenantId, r, roomDbInfo)
 
 	// save info to db
 	_, err = m.ds.InsertOrUpdateRoomInfo(roomDbInfo)
+	// the room is counted as active now
+	unlockTenant()
 	if err != nil {
 		log.WithError(err).Error("failed to insert or update room in db")
 		return nil, err
@@ -211,8 +246,17 @@ func (m *RoomModel) setRoomDefaults(r *plugnmeet.CreateRoomReq) {
 	}
 }
 
+// checkRoomOwnership returns error if the existing row of the room belongs to another tenant,
+// including the rooms of the tenants for the default API key & vice versa
+func checkRoomOwnership(existing *dbmodels.RoomInfo, tenantId string) error {
+	if existing != nil && existing.TenantID != tenantId {
+		return ErrRoomIdInUse
+	}
+	return nil
+}
+
 // prepareRoomDbInfo Prepares DB model for room
-func (m *RoomModel) prepareRoomDbInfo(r *plugnmeet.CreateRoomReq, existing *dbmodels.RoomInfo) (*dbmodels.RoomInfo, string) {
+func (m *RoomModel) prepareRoomDbInfo(tenantId string, r *plugnmeet.CreateRoomReq, existing *dbmodels.RoomInfo) (*dbmodels.RoomInfo, string) {
 	sId := uuid.New().String()
 	isBreakoutRoom := 0
 	if r.Metadata.IsBreakoutRoom {
@@ -229,16 +273,45 @@ func (m *RoomModel) prepareRoomDbInfo(r *plugnmeet.CreateRoomReq, existing *dbmo
 			WebhookUrl:         "",
 			IsBreakoutRoom:     isBreakoutRoom,
 			ParentRoomID:       r.Metadata.ParentRoomId,
+			TenantID:           tenantId,
 		}
 	} else {
+		// the owner was already checked by checkRoomOwnership
 		existing.Sid = sId
 	}
+	existing.RecordingRetention = int64(m.getRecordingRetention(tenantId, r.Metadata).Seconds())
 	if r.Metadata.WebhookUrl != nil {
 		existing.WebhookUrl = *r.Metadata.WebhookUrl
 	}
 	return existing, sId
 }
 
+// getRecordingRetention returns the duration to keep the recordings of this room.
+// The value from the extra_data of room metadata will get priority, for example:
+// {"recording_retention_days": 30}
+// then the value of the tenant & at last the global one from config.
+func (m *RoomModel) getRecordingRetention(tenantId string, meta *plugnmeet.RoomMetadata) time.Duration {
+	if meta.GetExtraData() != "" {
+		extra := struct {
+			RecordingRetentionDays uint32 `json:"recording_retention_days"`
+		}{}
+		if err := json.Unmarshal([]byte(meta.GetExtraData()), &extra); err == nil && extra.RecordingRetentionDays > 0 {
+			return time.Duration(extra.RecordingRetentionDays) * 24 * time.Hour
+		}
+	}
+
+	if tenantId != "" {
+		tenant, err := m.ds.GetTenant(tenantId)
+		if err != nil {
+			m.logger.WithError(err).WithField("tenantId", tenantId).Warnln("failed to get tenant for recording retention")
+		} else if tenant != nil && tenant.RecordingRetentionDays > 0 {
+			return time.Duration(tenant.RecordingRetentionDays) * 24 * time.Hour
+		}
+	}
+
+	return m.app.RecorderInfo.RecordingRetention
+}
+
 // prepareWhiteboardPreloadFile preload whiteboard file
 func (m *RoomModel) prepareWhiteboardPreloadFile(meta *plugnmeet.RoomMetadata, roomId, roomSid string, log *logrus.Entry) {
 	wbf := meta.RoomFeatures.WhiteboardFeatures
@@ -253,7 +326,7 @@ func (m *RoomModel) prepareWhiteboardPreloadFile(meta *plugnmeet.RoomMetadata, r
 
 	log.Info("preparing preloaded whiteboard file")
 
-	res, err := m.fileModel.DownloadAndProcessPreUploadWBfile(roomId, roomSid, preloadFile, log)
+	jobId, err := m.fileModel.DownloadAndProcessPreUploadWBfile(roomId, roomSid, preloadFile, log)
 	if err != nil {
 		log.WithError(err).Error("failed to download and process preloaded whiteboard file")
 
@@ -263,19 +336,8 @@ func (m *RoomModel) prepareWhiteboardPreloadFile(meta *plugnmeet.RoomMetadata, r
 		return
 	}
 
-	meta.RoomFeatures.WhiteboardFeatures.PreloadFile = nil
-	// TODO: change current metadata update way and think to broadcast differently
-	// TODO: may be use ADD_WHITEBOARD_OFFICE_FILE which will be clean approach
-	meta.RoomFeatures.WhiteboardFeatures.WhiteboardFileId = res.FileId
-	meta.RoomFeatures.WhiteboardFeatures.FileName = res.FileName
-	meta.RoomFeatures.WhiteboardFeatures.FilePath = res.FilePath
-	meta.RoomFeatures.WhiteboardFeatures.TotalPages = uint32(res.TotalPages)
-
-	if updateErr := m.natsService.UpdateAndBroadcastRoomMetadata(roomId, meta); updateErr != nil {
-		log.WithError(updateErr).Error("failed to update room metadata after whiteboard processing error")
-	}
-
-	log.Info("preloaded whiteboard file processed successfully")
+	// the conversion worker will update the metadata of the room
+	log.WithField("jobId", jobId).Info("preloaded whiteboard file queued for conversion")
 }
 
 // sendRoomCreatedWebhook to send webhook
@@ -121,7 +121,7 @@ func (m *RoomModel) OnAfterRoomEnded(roomID, roomSID, metadata, roomStatus strin
 	m.natsService.DeleteRoomUsersBlockList(roomID)
 
 	// Step 6: Send a stop signal to any active recorders for this room.
-	if err = m.recorderModel.SendMsgToRecorder(&plugnmeet.RecordingReq{Task: plugnmeet.RecordingTasks_STOP, Sid: roomSID, RoomId: roomID}); err != nil {
+	if err = m.recorderModel.SendMsgToRecorder(m.ctx, &plugnmeet.RecordingReq{Task: plugnmeet.RecordingTasks_STOP, Sid: roomSID, RoomId: roomID}); err != nil {
 		log.WithError(err).Error("Error sending stop to recorder")
 	}
 
@@ -140,8 +140,8 @@ func (m *RoomModel) OnAfterRoomEnded(roomID, roomSID, metadata, roomStatus strin
 	// Step 9: Clean up any associated Etherpad (shared notepad) pads.
 	_ = m.etherpadModel.CleanAfterRoomEnd(roomID, metadata)
 
-	// Step 10: Clean up any polls created during the session.
-	if err = m.pollModel.CleanUpPolls(roomID); err != nil {
+	// Step 10: Store & clean up any polls created during the session.
+	if err = m.pollModel.CleanUpPolls(roomID, roomSID); err != nil {
 		log.WithError(err).Error("Error cleaning polls")
 	}
 
@@ -155,11 +155,14 @@ func (m *RoomModel) OnAfterRoomEnded(roomID, roomSID, metadata, roomStatus strin
 		log.WithError(err).Error("Error in speech service cleanup")
 	}
 
-	// Step 13: Perform the final NATS cleanup, deleting room-specific streams and KV stores.
+	// Step 13: Archive chat messages if enabled, as the stream will be deleted in the next step.
+	m.chatArchive.ArchiveRoomChat(roomID, roomSID, metadata)
+
+	// Step 14: Perform the final NATS cleanup, deleting room-specific streams and KV stores.
 	m.natsService.OnAfterSessionEndCleanup(roomID)
 	log.Info("Room has been cleaned properly")
 
-	// Step 14: Schedule the analytics export to run after a delay.
+	// Step 15: Schedule the analytics export to run after a delay.
 	// This is done asynchronously to allow the current cleanup lock to be released.
 	time.AfterFunc(config.WaitBeforeAnalyticsStartProcessing, func() {
 		// PrepareToExportAnalytics has it's own room creation locking logic
@@ -110,8 +110,8 @@ func (m *RoomModel) GetActiveRoomInfo(ctx context.Context, r *plugnmeet.GetActiv
 	return true, "success", res
 }
 
-func (m *RoomModel) GetActiveRoomsInfo() (bool, string, []*plugnmeet.ActiveRoomWithParticipant) {
-	roomsInfo, err := m.ds.GetActiveRoomsInfo()
+func (m *RoomModel) GetActiveRoomsInfo(tenantId string) (bool, string, []*plugnmeet.ActiveRoomWithParticipant) {
+	roomsInfo, err := m.ds.GetActiveRoomsInfo(tenantId)
 	if err != nil {
 		return false, err.Error(), nil
 	}
@@ -160,7 +160,7 @@ func (m *RoomModel) GetActiveRoomsInfo() (bool, string, []*plugnmeet.ActiveRoomW
 	return true, "success", res
 }
 
-func (m *RoomModel) FetchPastRooms(r *plugnmeet.FetchPastRoomsReq) (*plugnmeet.FetchPastRoomsResult, error) {
+func (m *RoomModel) FetchPastRooms(tenantId string, r *plugnmeet.FetchPastRoomsReq) (*plugnmeet.FetchPastRoomsResult, error) {
 	if r.Limit <= 0 {
 		r.Limit = 20
 	}
@@ -171,7 +171,7 @@ func (m *RoomModel) FetchPastRooms(r *plugnmeet.FetchPastRoomsReq) (*plugnmeet.F
 	if r.OrderBy == "" {
 		r.OrderBy = "DESC"
 	}
-	rooms, total, err := m.ds.GetPastRooms(r.RoomIds, uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	rooms, total, err := m.ds.GetPastRooms(tenantId, r.RoomIds, uint64(r.From), uint64(r.Limit), &r.OrderBy)
 	if err != nil {
 		return nil, err
 	}
@@ -132,3 +132,33 @@ func waitUntilRoomCreationCompletes(ctx context.Context, rs *redisservice.RedisS
 		}
 	}
 }
+
+// acquireLockWithRetry acquires the named lock using the same backoff as the room creation lock
+func acquireLockWithRetry(ctx context.Context, rs *redisservice.RedisService, name string, ttl, maxWaitTime time.Duration) (string, error) {
+	currentInterval := backoffInitialInterval
+	loopStartTime := time.Now()
+
+	for {
+		acquired, lockValue, err := rs.Lock(ctx, name, ttl)
+		if err != nil {
+			return "", err
+		}
+		if acquired {
+			return lockValue, nil
+		}
+		if time.Since(loopStartTime) >= maxWaitTime {
+			return "", fmt.Errorf("timeout waiting to acquire lock %s", name)
+		}
+
+		jitter := time.Duration(rand.Float64() * backoffJitter * float64(currentInterval))
+		select {
+		case <-time.After(currentInterval + jitter):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		currentInterval = time.Duration(float64(currentInterval) * backoffMultiplier)
+		if currentInterval > backoffMaxInterval {
+			currentInterval = backoffMaxInterval
+		}
+	}
+}
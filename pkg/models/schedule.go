@@ -0,0 +1,138 @@
+package models
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// scheduleDefaultLeadTime in minutes
+	scheduleDefaultLeadTime = 5
+	// OccurrenceStatusScheduled is used for upcoming occurrences which are not stored in DB yet
+	OccurrenceStatusScheduled = "scheduled"
+)
+
+type ScheduleModel struct {
+	app    *config.AppConfig
+	ds     *dbservice.DatabaseService
+	rm     *RoomModel
+	logger *logrus.Entry
+}
+
+func NewScheduleModel(app *config.AppConfig, ds *dbservice.DatabaseService, rm *RoomModel, logger *logrus.Logger) *ScheduleModel {
+	return &ScheduleModel{
+		app:    app,
+		ds:     ds,
+		rm:     rm,
+		logger: logger.WithField("model", "schedule"),
+	}
+}
+
+type ScheduleMeetingReq struct {
+	Title string `json:"title"`
+	// RoomTemplate is CreateRoomReq in protojson format
+	RoomTemplate json.RawMessage `json:"room_template"`
+	// StartTime in unix timestamp (seconds)
+	StartTime int64 `json:"start_time"`
+	// RecurrenceRule in RRULE format, e.g. FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10
+	RecurrenceRule string `json:"recurrence_rule"`
+	// Timezone is the TZID of the schedule, e.g. Europe/Berlin, default UTC.
+	// The occurrences will keep the local time of the start time across DST.
+	Timezone string `json:"timezone"`
+	// LeadTime in minutes, room will be created before start time
+	LeadTime uint32 `json:"lead_time"`
+}
+
+type UpdateScheduleReq struct {
+	ScheduleId     string          `json:"schedule_id"`
+	Title          *string         `json:"title"`
+	RoomTemplate   json.RawMessage `json:"room_template"`
+	StartTime      *int64          `json:"start_time"`
+	RecurrenceRule *string         `json:"recurrence_rule"`
+	Timezone       *string         `json:"timezone"`
+	LeadTime       *uint32         `json:"lead_time"`
+}
+
+type FetchSchedulesReq struct {
+	RoomIds []string `json:"room_ids"`
+	Status  string   `json:"status"`
+	From    uint32   `json:"from"`
+	Limit   uint32   `json:"limit"`
+	OrderBy string   `json:"order_by"`
+}
+
+type ScheduleOccurrencesReq struct {
+	ScheduleId string `json:"schedule_id"`
+	Limit      int    `json:"limit"`
+}
+
+type CancelScheduleReq struct {
+	ScheduleId string `json:"schedule_id"`
+	// OccurrenceTime if set then only this occurrence will be cancelled
+	// otherwise the whole schedule
+	OccurrenceTime int64 `json:"occurrence_time"`
+}
+
+type ScheduleInfo struct {
+	ScheduleId     string          `json:"schedule_id"`
+	RoomId         string          `json:"room_id"`
+	Title          string          `json:"title"`
+	RoomTemplate   json.RawMessage `json:"room_template"`
+	StartTime      int64           `json:"start_time"`
+	RecurrenceRule string          `json:"recurrence_rule"`
+	Timezone       string          `json:"timezone,omitempty"`
+	LeadTime       uint32          `json:"lead_time"`
+	NextOccurrence int64           `json:"next_occurrence"`
+	Status         string          `json:"status"`
+	Created        string          `json:"created"`
+}
+
+type FetchSchedulesResult struct {
+	TotalSchedules int64           `json:"total_schedules"`
+	From           uint32          `json:"from"`
+	Limit          uint32          `json:"limit"`
+	OrderBy        string          `json:"order_by"`
+	SchedulesList  []*ScheduleInfo `json:"schedules_list"`
+}
+
+type ScheduleOccurrence struct {
+	OccurrenceTime int64  `json:"occurrence_time"`
+	Status         string `json:"status"`
+	RoomSid        string `json:"room_sid,omitempty"`
+}
+
+func (m *ScheduleModel) toScheduleInfo(s *dbmodels.ScheduledMeeting) *ScheduleInfo {
+	return &ScheduleInfo{
+		ScheduleId:     s.ScheduleID,
+		RoomId:         s.RoomID,
+		Title:          s.Title,
+		RoomTemplate:   json.RawMessage(s.RoomTemplate),
+		StartTime:      s.StartTime,
+		RecurrenceRule: s.RecurrenceRule,
+		Timezone:       s.Timezone,
+		LeadTime:       s.LeadTime,
+		NextOccurrence: s.NextOccurrence,
+		Status:         s.Status,
+		Created:        s.Created.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// scheduleRecurrence returns the recurrence rule & the start time in the time zone of the schedule
+func scheduleRecurrence(info *dbmodels.ScheduledMeeting) (*helpers.RecurrenceRule, time.Time, error) {
+	// empty means UTC
+	loc, err := time.LoadLocation(info.Timezone)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	rule, err := helpers.ParseRecurrenceRuleInLocation(info.RecurrenceRule, loc)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return rule, time.Unix(info.StartTime, 0).In(loc), nil
+}
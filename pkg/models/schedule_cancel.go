@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+// CancelSchedule will cancel either the whole schedule or a single occurrence
+func (m *ScheduleModel) CancelSchedule(tenantId string, r *CancelScheduleReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"scheduleId":     r.ScheduleId,
+		"occurrenceTime": r.OccurrenceTime,
+		"method":         "CancelSchedule",
+	})
+	log.Infoln("request to cancel scheduled meeting")
+
+	info, err := m.getTenantSchedule(tenantId, r.ScheduleId)
+	if err != nil {
+		return err
+	}
+	if info.Status != dbmodels.ScheduleStatusActive {
+		return errors.New("schedule is not active")
+	}
+
+	if r.OccurrenceTime == 0 {
+		_, err = m.ds.UpdateScheduledMeetingNextOccurrence(info.ScheduleID, 0, dbmodels.ScheduleStatusCancelled)
+		if err != nil {
+			log.WithError(err).Errorln("failed to cancel schedule")
+			return err
+		}
+		log.Infoln("successfully cancelled schedule")
+		return nil
+	}
+
+	if r.OccurrenceTime < info.NextOccurrence {
+		return errors.New("can't cancel past occurrence")
+	}
+	valid, err := m.isValidOccurrence(info, r.OccurrenceTime)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid occurrence_time for this schedule")
+	}
+
+	existing, err := m.ds.GetScheduledMeetingOccurrence(info.ScheduleID, r.OccurrenceTime)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Status == dbmodels.OccurrenceStatusCreated {
+		return errors.New("room has already been created for this occurrence, please end the room instead")
+	}
+
+	// janitor will skip this occurrence when it becomes due
+	_, err = m.ds.UpsertScheduledMeetingOccurrence(&dbmodels.ScheduledMeetingOccurrence{
+		ScheduleID:     info.ScheduleID,
+		OccurrenceTime: r.OccurrenceTime,
+		Status:         dbmodels.OccurrenceStatusCancelled,
+	})
+	if err != nil {
+		log.WithError(err).Errorln("failed to cancel occurrence")
+		return err
+	}
+
+	log.Infoln("successfully cancelled occurrence")
+	return nil
+}
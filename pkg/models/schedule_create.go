@@ -0,0 +1,136 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// CreateSchedule will store a new scheduled meeting
+// tmpl should be already validated
+func (m *ScheduleModel) CreateSchedule(tenantId string, r *ScheduleMeetingReq, tmpl *plugnmeet.CreateRoomReq) (*ScheduleInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId": tmpl.GetRoomId(),
+		"method": "CreateSchedule",
+	})
+	log.Infoln("request to create scheduled meeting")
+
+	if r.StartTime <= 0 {
+		return nil, errors.New("valid start_time required")
+	}
+	info := &dbmodels.ScheduledMeeting{
+		StartTime:      r.StartTime,
+		RecurrenceRule: r.RecurrenceRule,
+		Timezone:       r.Timezone,
+	}
+	rule, start, err := scheduleRecurrence(info)
+	if err != nil {
+		return nil, err
+	}
+
+	next, ok := rule.NextOccurrence(start, time.Now())
+	if !ok {
+		return nil, errors.New("schedule does not have any upcoming occurrence")
+	}
+
+	template, err := protojson.Marshal(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.LeadTime == 0 {
+		r.LeadTime = scheduleDefaultLeadTime
+	}
+	if r.Title == "" {
+		r.Title = tmpl.GetMetadata().GetRoomTitle()
+	}
+
+	info.ScheduleID = uuid.NewString()
+	info.TenantID = tenantId
+	info.RoomID = tmpl.GetRoomId()
+	info.Title = r.Title
+	info.RoomTemplate = string(template)
+	info.LeadTime = r.LeadTime
+	info.NextOccurrence = next.Unix()
+	info.Status = dbmodels.ScheduleStatusActive
+
+	_, err = m.ds.InsertOrUpdateScheduledMeeting(info)
+	if err != nil {
+		log.WithError(err).Errorln("failed to save scheduled meeting")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"scheduleId":     info.ScheduleID,
+		"nextOccurrence": info.NextOccurrence,
+	}).Infoln("successfully created scheduled meeting")
+	return m.toScheduleInfo(info), nil
+}
+
+// UpdateSchedule will update the schedule & recalculate the next occurrence
+// tmpl can be nil if room template was not changed
+func (m *ScheduleModel) UpdateSchedule(tenantId string, r *UpdateScheduleReq, tmpl *plugnmeet.CreateRoomReq) (*ScheduleInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"scheduleId": r.ScheduleId,
+		"method":     "UpdateSchedule",
+	})
+	log.Infoln("request to update scheduled meeting")
+
+	info, err := m.getTenantSchedule(tenantId, r.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+	if info.Status == dbmodels.ScheduleStatusCancelled {
+		return nil, errors.New("can't update cancelled schedule")
+	}
+
+	if r.Title != nil {
+		info.Title = *r.Title
+	}
+	if r.StartTime != nil {
+		info.StartTime = *r.StartTime
+	}
+	if r.RecurrenceRule != nil {
+		info.RecurrenceRule = *r.RecurrenceRule
+	}
+	if r.Timezone != nil {
+		info.Timezone = *r.Timezone
+	}
+	if r.LeadTime != nil && *r.LeadTime > 0 {
+		info.LeadTime = *r.LeadTime
+	}
+	if tmpl != nil {
+		template, err := protojson.Marshal(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		info.RoomTemplate = string(template)
+		info.RoomID = tmpl.GetRoomId()
+	}
+
+	rule, start, err := scheduleRecurrence(info)
+	if err != nil {
+		return nil, err
+	}
+
+	info.NextOccurrence = 0
+	info.Status = dbmodels.ScheduleStatusCompleted
+	if next, ok := rule.NextOccurrence(start, time.Now()); ok {
+		info.NextOccurrence = next.Unix()
+		info.Status = dbmodels.ScheduleStatusActive
+	}
+
+	_, err = m.ds.InsertOrUpdateScheduledMeeting(info)
+	if err != nil {
+		log.WithError(err).Errorln("failed to update scheduled meeting")
+		return nil, err
+	}
+
+	log.WithField("nextOccurrence", info.NextOccurrence).Infoln("successfully updated scheduled meeting")
+	return m.toScheduleInfo(info), nil
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+)
+
+func (m *ScheduleModel) FetchSchedules(tenantId string, r *FetchSchedulesReq) (*FetchSchedulesResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "DESC"
+	}
+
+	schedules, total, err := m.ds.GetScheduledMeetings(tenantId, r.RoomIds, r.Status, uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*ScheduleInfo, 0, len(schedules))
+	for i := range schedules {
+		list = append(list, m.toScheduleInfo(&schedules[i]))
+	}
+
+	return &FetchSchedulesResult{
+		TotalSchedules: total,
+		From:           r.From,
+		Limit:          r.Limit,
+		OrderBy:        r.OrderBy,
+		SchedulesList:  list,
+	}, nil
+}
+
+// GetScheduleOccurrences returns already processed occurrences
+// followed by the upcoming occurrences of the schedule
+func (m *ScheduleModel) GetScheduleOccurrences(tenantId string, r *ScheduleOccurrencesReq) ([]*ScheduleOccurrence, error) {
+	if r.Limit <= 0 {
+		r.Limit = 10
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+
+	info, err := m.getTenantSchedule(tenantId, r.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := m.ds.GetScheduledMeetingOccurrences(info.ScheduleID, 0)
+	if err != nil {
+		return nil, err
+	}
+	storedMap := make(map[int64]dbmodels.ScheduledMeetingOccurrence, len(stored))
+	for _, o := range stored {
+		storedMap[o.OccurrenceTime] = o
+	}
+
+	var list []*ScheduleOccurrence
+	// past occurrences
+	for _, o := range stored {
+		if info.NextOccurrence > 0 && o.OccurrenceTime >= info.NextOccurrence {
+			continue
+		}
+		list = append(list, &ScheduleOccurrence{
+			OccurrenceTime: o.OccurrenceTime,
+			Status:         o.Status,
+			RoomSid:        o.RoomSid,
+		})
+	}
+
+	// upcoming occurrences
+	if info.Status == dbmodels.ScheduleStatusActive && info.NextOccurrence > 0 {
+		rule, start, err := scheduleRecurrence(info)
+		if err != nil {
+			return nil, err
+		}
+		next := time.Unix(info.NextOccurrence, 0).In(start.Location())
+
+		for i := 0; i < r.Limit; i++ {
+			o := &ScheduleOccurrence{
+				OccurrenceTime: next.Unix(),
+				Status:         OccurrenceStatusScheduled,
+			}
+			if s, ok := storedMap[o.OccurrenceTime]; ok {
+				o.Status = s.Status
+				o.RoomSid = s.RoomSid
+			}
+			list = append(list, o)
+
+			var ok bool
+			next, ok = rule.NextOccurrence(start, next)
+			if !ok {
+				break
+			}
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].OccurrenceTime < list[j].OccurrenceTime
+	})
+
+	return list, nil
+}
+
+// isValidOccurrence checks if the given time is a part of the schedule series
+func (m *ScheduleModel) isValidOccurrence(info *dbmodels.ScheduledMeeting, occurrenceTime int64) (bool, error) {
+	rule, start, err := scheduleRecurrence(info)
+	if err != nil {
+		return false, err
+	}
+
+	ot := time.Unix(occurrenceTime, 0)
+	next, ok := rule.NextOccurrence(start, ot.Add(-time.Second))
+	return ok && next.Equal(ot), nil
+}
+
+// getTenantSchedule returns the schedule only if it belongs to the tenant.
+// Empty tenantId means the request was made with the default api key.
+func (m *ScheduleModel) getTenantSchedule(tenantId, scheduleId string) (*dbmodels.ScheduledMeeting, error) {
+	info, err := m.ds.GetScheduledMeeting(scheduleId)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || (tenantId != "" && info.TenantID != tenantId) {
+		return nil, errors.New("schedule not found")
+	}
+	return info, nil
+}
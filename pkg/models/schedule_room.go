@@ -0,0 +1,124 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// CreateDueScheduledRooms will create rooms for all the occurrences
+// which are within their lead time & move schedules to the next occurrence
+func (m *ScheduleModel) CreateDueScheduledRooms() {
+	log := m.logger.WithField("method", "CreateDueScheduledRooms")
+
+	now := time.Now().UTC()
+	schedules, err := m.ds.GetDueScheduledMeetings(now.Unix())
+	if err != nil {
+		log.WithError(err).Errorln("failed to get due scheduled meetings")
+		return
+	}
+
+	for i := range schedules {
+		m.handleDueSchedule(&schedules[i], now, log)
+	}
+}
+
+func (m *ScheduleModel) handleDueSchedule(info *dbmodels.ScheduledMeeting, now time.Time, log *logrus.Entry) {
+	log = log.WithFields(logrus.Fields{
+		"scheduleId":     info.ScheduleID,
+		"roomId":         info.RoomID,
+		"occurrenceTime": info.NextOccurrence,
+	})
+
+	existing, err := m.ds.GetScheduledMeetingOccurrence(info.ScheduleID, info.NextOccurrence)
+	if err != nil {
+		log.WithError(err).Errorln("failed to get occurrence info")
+		return
+	}
+
+	switch {
+	case existing != nil && existing.Status == dbmodels.OccurrenceStatusCancelled:
+		log.Infoln("occurrence was cancelled, skipping")
+	case existing != nil && existing.Status == dbmodels.OccurrenceStatusCreated:
+		log.Infoln("room was already created for this occurrence")
+	case now.Unix() > info.NextOccurrence+int64(config.MaxDelayToCreateScheduledRoom.Seconds()):
+		// may be the server was down, so better not to create the room too late
+		log.Warnln("occurrence was missed, skipping")
+		_, err = m.ds.UpsertScheduledMeetingOccurrence(&dbmodels.ScheduledMeetingOccurrence{
+			ScheduleID:     info.ScheduleID,
+			OccurrenceTime: info.NextOccurrence,
+			Status:         dbmodels.OccurrenceStatusMissed,
+		})
+		if err != nil {
+			log.WithError(err).Errorln("failed to update occurrence status")
+		}
+	default:
+		if err = m.createScheduledRoom(info, log); err != nil {
+			// we'll try again in the next round
+			return
+		}
+	}
+
+	m.moveToNextOccurrence(info, log)
+}
+
+func (m *ScheduleModel) createScheduledRoom(info *dbmodels.ScheduledMeeting, log *logrus.Entry) error {
+	req := new(plugnmeet.CreateRoomReq)
+	err := protojson.Unmarshal([]byte(info.RoomTemplate), req)
+	if err != nil {
+		log.WithError(err).Errorln("failed to unmarshal room template")
+		return err
+	}
+	if req.Metadata != nil && info.Title != "" {
+		req.Metadata.RoomTitle = info.Title
+	}
+
+	log.Infoln("creating room for scheduled meeting")
+	ari, err := m.rm.CreateRoom(info.TenantID, req)
+	if err != nil {
+		log.WithError(err).Errorln("failed to create room for scheduled meeting")
+		return err
+	}
+
+	_, err = m.ds.UpsertScheduledMeetingOccurrence(&dbmodels.ScheduledMeetingOccurrence{
+		ScheduleID:     info.ScheduleID,
+		OccurrenceTime: info.NextOccurrence,
+		Status:         dbmodels.OccurrenceStatusCreated,
+		RoomSid:        ari.GetSid(),
+	})
+	if err != nil {
+		log.WithError(err).Errorln("failed to update occurrence status")
+	}
+
+	log.WithField("roomSid", ari.GetSid()).Infoln("successfully created room for scheduled meeting")
+	return nil
+}
+
+func (m *ScheduleModel) moveToNextOccurrence(info *dbmodels.ScheduledMeeting, log *logrus.Entry) {
+	rule, start, err := scheduleRecurrence(info)
+	if err != nil {
+		log.WithError(err).Errorln("invalid recurrence rule")
+		return
+	}
+
+	nextOccurrence := int64(0)
+	status := dbmodels.ScheduleStatusCompleted
+	if next, ok := rule.NextOccurrence(start, time.Unix(info.NextOccurrence, 0)); ok {
+		nextOccurrence = next.Unix()
+		status = dbmodels.ScheduleStatusActive
+	}
+
+	_, err = m.ds.UpdateScheduledMeetingNextOccurrence(info.ScheduleID, nextOccurrence, status)
+	if err != nil {
+		log.WithError(err).Errorln("failed to update next occurrence")
+		return
+	}
+	log.WithFields(logrus.Fields{
+		"nextOccurrence": nextOccurrence,
+		"status":         status,
+	}).Infoln("schedule moved to the next occurrence")
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/pkg/services/db"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidApiKey  = errors.New("invalid API key")
+	ErrTenantDisabled = errors.New("tenant is disabled")
+)
+
+// TenantModel handles tenants, their API keys & quotas.
+// Empty tenantId means the default API key from config,
+// which has access to the data of all the tenants.
+type TenantModel struct {
+	app    *config.AppConfig
+	ds     *dbservice.DatabaseService
+	logger *logrus.Entry
+}
+
+func NewTenantModel(app *config.AppConfig, ds *dbservice.DatabaseService, logger *logrus.Logger) *TenantModel {
+	return &TenantModel{
+		app:    app,
+		ds:     ds,
+		logger: logger.WithField("model", "tenant"),
+	}
+}
+
+type CreateTenantReq struct {
+	Name                string `json:"name"`
+	MaxConcurrentRooms  uint32 `json:"max_concurrent_rooms"`
+	MaxParticipants     uint32 `json:"max_participants"`
+	MaxRecordingMinutes uint32 `json:"max_recording_minutes"`
+	// RecordingRetentionDays will be used for the recordings of the rooms of this tenant,
+	// unless the room has its own value. 0 means to use the global retention
+	RecordingRetentionDays uint32 `json:"recording_retention_days"`
+	Enabled                *bool  `json:"enabled"`
+}
+
+type UpdateTenantReq struct {
+	TenantId               string  `json:"tenant_id"`
+	Name                   *string `json:"name"`
+	MaxConcurrentRooms     *uint32 `json:"max_concurrent_rooms"`
+	MaxParticipants        *uint32 `json:"max_participants"`
+	MaxRecordingMinutes    *uint32 `json:"max_recording_minutes"`
+	RecordingRetentionDays *uint32 `json:"recording_retention_days"`
+	Enabled                *bool   `json:"enabled"`
+}
+
+type FetchTenantsReq struct {
+	From    uint32 `json:"from"`
+	Limit   uint32 `json:"limit"`
+	OrderBy string `json:"order_by"`
+}
+
+type TenantReq struct {
+	TenantId string `json:"tenant_id"`
+}
+
+type RevokeTenantApiKeyReq struct {
+	TenantId string `json:"tenant_id"`
+	ApiKey   string `json:"api_key"`
+}
+
+type TenantInfo struct {
+	TenantId               string `json:"tenant_id"`
+	Name                   string `json:"name"`
+	MaxConcurrentRooms     uint32 `json:"max_concurrent_rooms"`
+	MaxParticipants        uint32 `json:"max_participants"`
+	MaxRecordingMinutes    uint32 `json:"max_recording_minutes"`
+	RecordingRetentionDays uint32 `json:"recording_retention_days"`
+	Enabled                bool   `json:"enabled"`
+	Created                string `json:"created"`
+}
+
+type TenantApiKeyInfo struct {
+	ApiKey string `json:"api_key"`
+	// Secret will be only available during creation
+	Secret  string `json:"secret,omitempty"`
+	Revoked bool   `json:"revoked"`
+	Created string `json:"created"`
+}
+
+type TenantUsageInfo struct {
+	ActiveRooms        int64 `json:"active_rooms"`
+	ActiveParticipants int64 `json:"active_participants"`
+	// RecordingMinutes of the current month
+	RecordingMinutes int64 `json:"recording_minutes"`
+}
+
+type TenantDetails struct {
+	Tenant  *TenantInfo         `json:"tenant"`
+	ApiKeys []*TenantApiKeyInfo `json:"api_keys"`
+	Usage   *TenantUsageInfo    `json:"usage"`
+}
+
+type FetchTenantsResult struct {
+	TotalTenants int64         `json:"total_tenants"`
+	From         uint32        `json:"from"`
+	Limit        uint32        `json:"limit"`
+	OrderBy      string        `json:"order_by"`
+	TenantsList  []*TenantInfo `json:"tenants_list"`
+}
@@ -0,0 +1,109 @@
+package models
+
+// VerifyApiKey returns the tenant & secret of the API key.
+// Keys are checked in DB every time, so revoked key won't work anymore without restarting.
+func (m *TenantModel) VerifyApiKey(apiKey string) (string, string, error) {
+	if apiKey == "" {
+		return "", "", ErrInvalidApiKey
+	}
+	if apiKey == m.app.Client.ApiKey {
+		return "", m.app.Client.Secret, nil
+	}
+
+	key, err := m.ds.GetTenantApiKey(apiKey)
+	if err != nil {
+		m.logger.WithError(err).Errorln("failed to get tenant api key")
+		return "", "", err
+	}
+	if key == nil || key.Revoked {
+		return "", "", ErrInvalidApiKey
+	}
+
+	tenant, err := m.ds.GetTenant(key.TenantID)
+	if err != nil {
+		m.logger.WithError(err).Errorln("failed to get tenant")
+		return "", "", err
+	}
+	if tenant == nil {
+		return "", "", ErrInvalidApiKey
+	}
+	if !tenant.Enabled {
+		return "", "", ErrTenantDisabled
+	}
+
+	return tenant.TenantID, key.Secret, nil
+}
+
+// CanAccessRoom checks if the active room belongs to the tenant.
+// It will return true if the room isn't active, so the caller can handle it.
+func (m *TenantModel) CanAccessRoom(tenantId, roomId string) bool {
+	if tenantId == "" {
+		return true
+	}
+
+	info, err := m.ds.GetRoomInfoByRoomId(roomId, 1)
+	if err != nil {
+		m.logger.WithError(err).Errorln("failed to get room info")
+		return false
+	}
+	if info == nil {
+		return true
+	}
+	return info.TenantID == tenantId
+}
+
+// OwnsRoom checks if all the sessions of the room, including the ended ones, belong to the tenant.
+// It will return true if the room was never created.
+func (m *TenantModel) OwnsRoom(tenantId, roomId string) bool {
+	if tenantId == "" {
+		return true
+	}
+
+	tenantIds, err := m.ds.GetRoomTenantIds(roomId)
+	if err != nil {
+		m.logger.WithError(err).Errorln("failed to get tenants of the room")
+		return false
+	}
+	for _, id := range tenantIds {
+		if id != tenantId {
+			return false
+		}
+	}
+	return true
+}
+
+// CanAccessRecording checks if the recording belongs to the tenant.
+// It will return true if the recording doesn't exist, so the caller can handle it.
+func (m *TenantModel) CanAccessRecording(tenantId, recordId string) bool {
+	if tenantId == "" {
+		return true
+	}
+
+	info, err := m.ds.GetRecording(recordId)
+	if err != nil {
+		m.logger.WithError(err).Errorln("failed to get recording info")
+		return false
+	}
+	if info == nil {
+		return true
+	}
+	return info.TenantID == tenantId
+}
+
+// CanAccessAnalytics checks if the analytics file belongs to the tenant.
+// It will return true if the file doesn't exist, so the caller can handle it.
+func (m *TenantModel) CanAccessAnalytics(tenantId, fileId string) bool {
+	if tenantId == "" {
+		return true
+	}
+
+	info, err := m.ds.GetAnalyticByFileId(fileId)
+	if err != nil {
+		m.logger.WithError(err).Errorln("failed to get analytics info")
+		return false
+	}
+	if info == nil {
+		return true
+	}
+	return info.TenantID == tenantId
+}
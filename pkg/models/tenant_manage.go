@@ -0,0 +1,278 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+func (m *TenantModel) CreateTenant(r *CreateTenantReq) (*TenantDetails, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"name":   r.Name,
+		"method": "CreateTenant",
+	})
+	log.Infoln("request to create tenant")
+
+	if strings.TrimSpace(r.Name) == "" {
+		return nil, errors.New("name required")
+	}
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	tenant := &dbmodels.Tenant{
+		TenantID:               uuid.NewString(),
+		Name:                   r.Name,
+		MaxConcurrentRooms:     r.MaxConcurrentRooms,
+		MaxParticipants:        r.MaxParticipants,
+		MaxRecordingMinutes:    r.MaxRecordingMinutes,
+		RecordingRetentionDays: r.RecordingRetentionDays,
+		Enabled:                enabled,
+	}
+	_, err := m.ds.InsertOrUpdateTenant(tenant)
+	if err != nil {
+		log.WithError(err).Errorln("failed to save tenant")
+		return nil, err
+	}
+
+	// every tenant will need at least one key
+	key, err := m.CreateTenantApiKey(&TenantReq{TenantId: tenant.TenantID})
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("tenantId", tenant.TenantID).Infoln("successfully created tenant")
+	return &TenantDetails{
+		Tenant:  m.toTenantInfo(tenant),
+		ApiKeys: []*TenantApiKeyInfo{key},
+		Usage:   new(TenantUsageInfo),
+	}, nil
+}
+
+func (m *TenantModel) UpdateTenant(r *UpdateTenantReq) (*TenantInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"tenantId": r.TenantId,
+		"method":   "UpdateTenant",
+	})
+
+	tenant, err := m.ds.GetTenant(r.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, errors.New("tenant not found")
+	}
+
+	if r.Name != nil && strings.TrimSpace(*r.Name) != "" {
+		tenant.Name = *r.Name
+	}
+	if r.MaxConcurrentRooms != nil {
+		tenant.MaxConcurrentRooms = *r.MaxConcurrentRooms
+	}
+	if r.MaxParticipants != nil {
+		tenant.MaxParticipants = *r.MaxParticipants
+	}
+	if r.MaxRecordingMinutes != nil {
+		tenant.MaxRecordingMinutes = *r.MaxRecordingMinutes
+	}
+	if r.RecordingRetentionDays != nil {
+		tenant.RecordingRetentionDays = *r.RecordingRetentionDays
+	}
+	if r.Enabled != nil {
+		tenant.Enabled = *r.Enabled
+	}
+
+	_, err = m.ds.InsertOrUpdateTenant(tenant)
+	if err != nil {
+		log.WithError(err).Errorln("failed to update tenant")
+		return nil, err
+	}
+
+	log.Infoln("successfully updated tenant")
+	return m.toTenantInfo(tenant), nil
+}
+
+func (m *TenantModel) FetchTenants(r *FetchTenantsReq) (*FetchTenantsResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "DESC"
+	}
+
+	tenants, total, err := m.ds.GetTenants(uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*TenantInfo, 0, len(tenants))
+	for i := range tenants {
+		list = append(list, m.toTenantInfo(&tenants[i]))
+	}
+
+	return &FetchTenantsResult{
+		TotalTenants: total,
+		From:         r.From,
+		Limit:        r.Limit,
+		OrderBy:      r.OrderBy,
+		TenantsList:  list,
+	}, nil
+}
+
+// GetTenantDetails returns tenant info with api keys & current usage
+func (m *TenantModel) GetTenantDetails(r *TenantReq) (*TenantDetails, error) {
+	tenant, err := m.ds.GetTenant(r.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, errors.New("tenant not found")
+	}
+
+	keys, err := m.ds.GetTenantApiKeys(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	apiKeys := make([]*TenantApiKeyInfo, 0, len(keys))
+	for _, k := range keys {
+		apiKeys = append(apiKeys, &TenantApiKeyInfo{
+			ApiKey:  k.ApiKey,
+			Revoked: k.Revoked,
+			Created: k.Created.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	rooms, participants, err := m.ds.GetTenantActiveUsage(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	minutes, err := m.getRecordingMinutes(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TenantDetails{
+		Tenant:  m.toTenantInfo(tenant),
+		ApiKeys: apiKeys,
+		Usage: &TenantUsageInfo{
+			ActiveRooms:        rooms,
+			ActiveParticipants: participants,
+			RecordingMinutes:   minutes,
+		},
+	}, nil
+}
+
+func (m *TenantModel) DeleteTenant(r *TenantReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"tenantId": r.TenantId,
+		"method":   "DeleteTenant",
+	})
+
+	affected, err := m.ds.DeleteTenant(r.TenantId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to delete tenant")
+		return err
+	}
+	if affected == 0 {
+		return errors.New("tenant not found")
+	}
+
+	log.Infoln("successfully deleted tenant")
+	return nil
+}
+
+// CreateTenantApiKey will generate new api key & secret for the tenant
+func (m *TenantModel) CreateTenantApiKey(r *TenantReq) (*TenantApiKeyInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"tenantId": r.TenantId,
+		"method":   "CreateTenantApiKey",
+	})
+
+	tenant, err := m.ds.GetTenant(r.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, errors.New("tenant not found")
+	}
+
+	apiKey, err := generateRandomHex(12)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateRandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &dbmodels.TenantApiKey{
+		TenantID: tenant.TenantID,
+		ApiKey:   "pnm_" + apiKey,
+		Secret:   secret,
+	}
+	_, err = m.ds.InsertTenantApiKey(key)
+	if err != nil {
+		log.WithError(err).Errorln("failed to save api key")
+		return nil, err
+	}
+
+	log.WithField("apiKey", key.ApiKey).Infoln("successfully created api key")
+	return &TenantApiKeyInfo{
+		ApiKey:  key.ApiKey,
+		Secret:  key.Secret,
+		Revoked: false,
+		Created: key.Created.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// RevokeTenantApiKey will revoke the key, it won't be accepted from the next request
+func (m *TenantModel) RevokeTenantApiKey(r *RevokeTenantApiKeyReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"tenantId": r.TenantId,
+		"apiKey":   r.ApiKey,
+		"method":   "RevokeTenantApiKey",
+	})
+
+	affected, err := m.ds.RevokeTenantApiKey(r.TenantId, r.ApiKey)
+	if err != nil {
+		log.WithError(err).Errorln("failed to revoke api key")
+		return err
+	}
+	if affected == 0 {
+		return errors.New("api key not found")
+	}
+
+	log.Infoln("successfully revoked api key")
+	return nil
+}
+
+func (m *TenantModel) toTenantInfo(t *dbmodels.Tenant) *TenantInfo {
+	return &TenantInfo{
+		TenantId:               t.TenantID,
+		Name:                   t.Name,
+		MaxConcurrentRooms:     t.MaxConcurrentRooms,
+		MaxParticipants:        t.MaxParticipants,
+		MaxRecordingMinutes:    t.MaxRecordingMinutes,
+		RecordingRetentionDays: t.RecordingRetentionDays,
+		Enabled:                t.Enabled,
+		Created:                t.Created.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func generateRandomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrMaxConcurrentRoomsReached = errors.New("maximum number of concurrent rooms reached")
+	ErrMaxParticipantsReached    = errors.New("notifications.max-num-participates-exceeded")
+	ErrRecordingQuotaExceeded    = errors.New("recording quota exceeded")
+	ErrMaxParticipantsExceeded   = errors.New("max_participants exceeds the limit of the tenant")
+	ErrRoomIdInUse               = errors.New("room id is already in use")
+)
+
+// CheckCreateRoomQuota will return error if the tenant can't create more rooms
+// or the room is over the limits of the tenant. activeRow should be true if the room
+// will reuse its running row of DB, which is already counted as an active room.
+// The caller should hold the room creation lock of the tenant till the room was saved,
+// otherwise the concurrent requests can go over the limit.
+func (m *TenantModel) CheckCreateRoomQuota(tenantId string, r *plugnmeet.CreateRoomReq, activeRow bool) error {
+	if tenantId == "" {
+		return nil
+	}
+	log := m.logger.WithFields(logrus.Fields{
+		"tenantId": tenantId,
+		"roomId":   r.GetRoomId(),
+		"method":   "CheckCreateRoomQuota",
+	})
+
+	tenant, err := m.getEnabledTenant(tenantId)
+	if err != nil {
+		return err
+	}
+
+	var activeRooms, recordingMinutes int64
+	if tenant.MaxConcurrentRooms > 0 {
+		if activeRooms, _, err = m.ds.GetTenantActiveUsage(tenantId); err != nil {
+			log.WithError(err).Errorln("failed to get active usage")
+			return err
+		}
+		if activeRow && activeRooms > 0 {
+			activeRooms--
+		}
+	}
+	if tenant.MaxRecordingMinutes > 0 {
+		if recordingMinutes, err = m.getRecordingMinutes(tenantId); err != nil {
+			return err
+		}
+	}
+
+	if err = checkCreateRoomQuota(tenant, r, activeRooms, recordingMinutes); err != nil {
+		log.WithFields(logrus.Fields{
+			"activeRooms":      activeRooms,
+			"recordingMinutes": recordingMinutes,
+		}).WithError(err).Warnln("tenant quota exceeded")
+		return err
+	}
+
+	// unlimited isn't allowed for the tenant with limit
+	if tenant.MaxParticipants > 0 && r.GetMaxParticipants() == 0 {
+		maxParticipants := tenant.MaxParticipants
+		r.MaxParticipants = &maxParticipants
+	}
+
+	return nil
+}
+
+// checkCreateRoomQuota validates the room against the limits of the tenant with its current usage
+func checkCreateRoomQuota(tenant *dbmodels.Tenant, r *plugnmeet.CreateRoomReq, activeRooms, recordingMinutes int64) error {
+	if tenant.MaxConcurrentRooms > 0 && activeRooms >= int64(tenant.MaxConcurrentRooms) {
+		return ErrMaxConcurrentRoomsReached
+	}
+	if tenant.MaxParticipants > 0 && r.GetMaxParticipants() > tenant.MaxParticipants {
+		return ErrMaxParticipantsExceeded
+	}
+	if tenant.MaxRecordingMinutes > 0 && recordingMinutes >= int64(tenant.MaxRecordingMinutes) &&
+		r.GetMetadata().GetRoomFeatures().GetRecordingFeatures().GetIsAllow() {
+		return ErrRecordingQuotaExceeded
+	}
+	return nil
+}
+
+// CheckJoinQuota will return error if the tenant has reached
+// the maximum number of participants in all the active rooms
+func (m *TenantModel) CheckJoinQuota(tenantId string) error {
+	if tenantId == "" {
+		return nil
+	}
+
+	tenant, err := m.getEnabledTenant(tenantId)
+	if err != nil {
+		return err
+	}
+	if tenant.MaxParticipants == 0 {
+		return nil
+	}
+
+	_, participants, err := m.ds.GetTenantActiveUsage(tenantId)
+	if err != nil {
+		return err
+	}
+	if participants >= int64(tenant.MaxParticipants) {
+		m.logger.WithFields(logrus.Fields{
+			"tenantId":           tenantId,
+			"activeParticipants": participants,
+			"method":             "CheckJoinQuota",
+		}).Warnln("maximum number of participants reached")
+		return ErrMaxParticipantsReached
+	}
+
+	return nil
+}
+
+// CheckRecordingQuota will return error if the tenant has used all the recording minutes of this month
+func (m *TenantModel) CheckRecordingQuota(tenantId string) error {
+	if tenantId == "" {
+		return nil
+	}
+
+	tenant, err := m.getEnabledTenant(tenantId)
+	if err != nil {
+		return err
+	}
+	return m.checkRecordingQuota(tenant)
+}
+
+func (m *TenantModel) checkRecordingQuota(tenant *dbmodels.Tenant) error {
+	if tenant.MaxRecordingMinutes == 0 {
+		return nil
+	}
+
+	minutes, err := m.getRecordingMinutes(tenant.TenantID)
+	if err != nil {
+		return err
+	}
+	if minutes >= int64(tenant.MaxRecordingMinutes) {
+		return ErrRecordingQuotaExceeded
+	}
+	return nil
+}
+
+// AddRecordingUsage will add the duration of the recording, which is just ended, to the usage of the tenant
+func (m *TenantModel) AddRecordingUsage(roomInfo *dbmodels.RoomInfo) {
+	if roomInfo == nil || roomInfo.TenantID == "" || roomInfo.RecordingStartedAt == 0 {
+		return
+	}
+
+	seconds := time.Now().Unix() - roomInfo.RecordingStartedAt
+	if seconds <= 0 {
+		return
+	}
+
+	_, err := m.ds.AddTenantRecordingUsage(roomInfo.TenantID, currentUsagePeriod(), seconds)
+	if err != nil {
+		m.logger.WithFields(logrus.Fields{
+			"tenantId": roomInfo.TenantID,
+			"roomId":   roomInfo.RoomId,
+			"seconds":  seconds,
+			"method":   "AddRecordingUsage",
+		}).WithError(err).Errorln("failed to add recording usage")
+	}
+}
+
+func (m *TenantModel) getRecordingMinutes(tenantId string) (int64, error) {
+	usage, err := m.ds.GetTenantUsage(tenantId, currentUsagePeriod())
+	if err != nil {
+		return 0, err
+	}
+	if usage == nil {
+		return 0, nil
+	}
+	return usage.RecordingSeconds / 60, nil
+}
+
+func (m *TenantModel) getEnabledTenant(tenantId string) (*dbmodels.Tenant, error) {
+	tenant, err := m.ds.GetTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, errors.New("tenant not found")
+	}
+	if !tenant.Enabled {
+		return nil, ErrTenantDisabled
+	}
+	return tenant, nil
+}
+
+// currentUsagePeriod returns the month of usage in YYYY-MM format
+func currentUsagePeriod() string {
+	return time.Now().UTC().Format("2006-01")
+}
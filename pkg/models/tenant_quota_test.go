@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+)
+
+func TestCheckRoomOwnership(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *dbmodels.RoomInfo
+		tenantId string
+		wantErr  bool
+	}{
+		{"new room", nil, "t1", false},
+		{"same tenant", &dbmodels.RoomInfo{TenantID: "t1"}, "t1", false},
+		{"default key", &dbmodels.RoomInfo{}, "", false},
+		{"another tenant", &dbmodels.RoomInfo{TenantID: "t2"}, "t1", true},
+		{"tenant room with default key", &dbmodels.RoomInfo{TenantID: "t2"}, "", true},
+		{"default key room with tenant", &dbmodels.RoomInfo{}, "t1", true},
+	}
+	for _, tt := range tests {
+		err := checkRoomOwnership(tt.existing, tt.tenantId)
+		if tt.wantErr != errors.Is(err, ErrRoomIdInUse) {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
+
+func TestCheckCreateRoomQuota(t *testing.T) {
+	tenant := &dbmodels.Tenant{
+		MaxConcurrentRooms:  2,
+		MaxParticipants:     10,
+		MaxRecordingMinutes: 60,
+	}
+	newReq := func(maxParticipants uint32, recording bool) *plugnmeet.CreateRoomReq {
+		return &plugnmeet.CreateRoomReq{
+			MaxParticipants: &maxParticipants,
+			Metadata: &plugnmeet.RoomMetadata{
+				RoomFeatures: &plugnmeet.RoomCreateFeatures{
+					RecordingFeatures: &plugnmeet.RecordingFeatures{IsAllow: recording},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		req              *plugnmeet.CreateRoomReq
+		activeRooms      int64
+		recordingMinutes int64
+		want             error
+	}{
+		{"within quota", newReq(10, true), 1, 59, nil},
+		{"unlimited participants", newReq(0, false), 0, 0, nil},
+		{"concurrent rooms", newReq(5, false), 2, 0, ErrMaxConcurrentRoomsReached},
+		{"participants", newReq(11, false), 0, 0, ErrMaxParticipantsExceeded},
+		{"recording", newReq(5, true), 0, 60, ErrRecordingQuotaExceeded},
+		{"recording not allowed", newReq(5, false), 0, 60, nil},
+	}
+	for _, tt := range tests {
+		if err := checkCreateRoomQuota(tenant, tt.req, tt.activeRooms, tt.recordingMinutes); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// without limits
+	if err := checkCreateRoomQuota(&dbmodels.Tenant{}, newReq(1000, true), 100, 1000); err != nil {
+		t.Errorf("unexpected error for tenant without limits: %v", err)
+	}
+}
@@ -11,13 +11,23 @@ import (
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
 	natsservice "github.com/mynaparrot/plugnmeet-server/pkg/services/nats"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/proto"
 )
 
 var validUserIDRegex = regexp.MustCompile("^[a-zA-Z0-9-_]+$")
 
-func (m *UserModel) GetPNMJoinToken(ctx context.Context, g *plugnmeet.GenerateTokenReq) (string, error) {
+func (m *UserModel) GetPNMJoinToken(ctx context.Context, g *plugnmeet.GenerateTokenReq) (token string, err error) {
+	ctx, span := tracing.Start(ctx, "UserModel.GetPNMJoinToken",
+		tracing.RoomIdKey.String(g.GetRoomId()),
+		tracing.UserIdKey.String(g.GetUserInfo().GetUserId()),
+		attribute.Bool("pnm.is_admin", g.GetUserInfo().GetIsAdmin()))
+	defer func() { tracing.End(span, err) }()
+	// KV operations will be children of this span
+	natsService := m.natsService.WithContext(ctx)
+
 	log := m.logger.WithFields(logrus.Fields{
 		"room_id":  g.GetRoomId(),
 		"user_id":  g.GetUserInfo().GetUserId(),
@@ -38,7 +48,7 @@ func (m *UserModel) GetPNMJoinToken(ctx context.Context, g *plugnmeet.GenerateTo
 	}
 
 	// Step 3: Fetch the current room information and metadata from NATS.
-	rInfo, meta, err := m.natsService.GetRoomInfoWithMetadata(g.GetRoomId())
+	rInfo, meta, err := natsService.GetRoomInfoWithMetadata(g.GetRoomId())
 	if err != nil {
 		log.WithError(err).Errorln("failed to get room info with metadata")
 		return "", err
@@ -57,6 +67,21 @@ func (m *UserModel) GetPNMJoinToken(ctx context.Context, g *plugnmeet.GenerateTo
 		return "", err
 	}
 
+	// Step 4.1: Check the participant quota if the room belongs to a tenant. Internal bots are not counted.
+	if g.GetUserInfo().GetUserId() != config.RecorderBot && g.GetUserInfo().GetUserId() != config.RtmpBot {
+		roomDbInfo, err := m.ds.GetRoomInfoByRoomId(g.GetRoomId(), 1)
+		if err != nil {
+			log.WithError(err).Errorln("failed to get room info from db")
+			return "", err
+		}
+		if roomDbInfo != nil {
+			if err = m.tenantModel.CheckJoinQuota(roomDbInfo.TenantID); err != nil {
+				log.WithError(err).Warnln("tenant quota check failed")
+				return "", err
+			}
+		}
+	}
+
 	if g.UserInfo.UserMetadata == nil {
 		g.UserInfo.UserMetadata = new(plugnmeet.UserMetadata)
 	}
@@ -81,7 +106,7 @@ func (m *UserModel) GetPNMJoinToken(ctx context.Context, g *plugnmeet.GenerateTo
 	} else {
 		// If auto-generation is off, check if a user with the same ID is already online.
 		// If so, remove the existing participant to prevent a duplicate join issue.
-		status, err := m.natsService.GetRoomUserStatus(g.GetRoomId(), g.GetUserInfo().GetUserId())
+		status, err := natsService.GetRoomUserStatus(g.GetRoomId(), g.GetUserInfo().GetUserId())
 		if err != nil {
 			log.WithError(err).Errorln("failed to get room user status")
 			return "", err
@@ -141,7 +166,7 @@ func (m *UserModel) GetPNMJoinToken(ctx context.Context, g *plugnmeet.GenerateTo
 	}
 
 	// Step 9: Add the user's information to the NATS key-value store for the room.
-	err = m.natsService.AddUser(g.RoomId, g.UserInfo.UserId, g.UserInfo.Name, g.UserInfo.IsAdmin, g.UserInfo.UserMetadata.IsPresenter, g.UserInfo.UserMetadata)
+	err = natsService.AddUser(g.RoomId, g.UserInfo.UserId, g.UserInfo.Name, g.UserInfo.IsAdmin, g.UserInfo.UserMetadata.IsPresenter, g.UserInfo.UserMetadata)
 	if err != nil {
 		log.WithError(err).Errorln("failed to add user to nats")
 		return "", err
@@ -156,9 +181,17 @@ func (m *UserModel) GetPNMJoinToken(ctx context.Context, g *plugnmeet.GenerateTo
 		IsHidden: g.UserInfo.IsHidden,
 	}
 
-	log.Infoln("successfully generated pnm join token")
 	am := NewAuthModel(m.app, m.natsService, m.logger.Logger)
-	return am.GeneratePNMJoinToken(c)
+	_, tokenSpan := tracing.Start(ctx, "AuthModel.GeneratePNMJoinToken")
+	token, err = am.GeneratePNMJoinToken(c)
+	tracing.End(tokenSpan, err)
+	if err != nil {
+		log.WithError(err).Errorln("failed to generate pnm join token")
+		return "", err
+	}
+
+	log.Infoln("successfully generated pnm join token")
+	return token, nil
 }
 
 // waitForUserToBeOffline polls until the user's status is no longer "online".
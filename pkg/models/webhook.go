@@ -26,12 +26,13 @@ type WebhookModel struct {
 	bm              *BreakoutRoomModel
 	nm              *NatsModel
 	sm              *SpeechToTextModel
+	recorderModel   *RecorderModel
 	webhookNotifier *helpers.WebhookNotifier
 	natsService     *natsservice.NatsService
 	logger          *logrus.Entry
 }
 
-func NewWebhookModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, lk *livekitservice.LivekitService, rm *RoomModel, analyticsModel *AnalyticsModel, rmDuration *RoomDurationModel, bm *BreakoutRoomModel, nm *NatsModel, sm *SpeechToTextModel, webhookNotifier *helpers.WebhookNotifier, logger *logrus.Logger) *WebhookModel {
+func NewWebhookModel(ctx context.Context, app *config.AppConfig, ds *dbservice.DatabaseService, rs *redisservice.RedisService, natsService *natsservice.NatsService, lk *livekitservice.LivekitService, rm *RoomModel, analyticsModel *AnalyticsModel, rmDuration *RoomDurationModel, bm *BreakoutRoomModel, nm *NatsModel, sm *SpeechToTextModel, recorderModel *RecorderModel, webhookNotifier *helpers.WebhookNotifier, logger *logrus.Logger) *WebhookModel {
 	return &WebhookModel{
 		ctx:             ctx,
 		app:             app,
@@ -44,6 +45,7 @@ func NewWebhookModel(ctx context.Context, app *config.AppConfig, ds *dbservice.D
 		bm:              bm,
 		nm:              nm,
 		sm:              sm,
+		recorderModel:   recorderModel,
 		webhookNotifier: webhookNotifier,
 		natsService:     natsService,
 		logger:          logger.WithField("model", "webhook"),
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/helpers"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultWebhookDeliveriesLimit = 20
+
+type FetchWebhookDeliveriesReq struct {
+	From  uint64 `json:"from"`
+	Limit int    `json:"limit"`
+}
+
+type FetchWebhookDeliveriesResult struct {
+	TotalDeliveries uint64                           `json:"total_deliveries"`
+	From            uint64                           `json:"from"`
+	Limit           int                              `json:"limit"`
+	DeliveriesList  []*helpers.FailedWebhookDelivery `json:"deliveries_list"`
+}
+
+type WebhookDeliveriesReq struct {
+	Seqs []uint64 `json:"seqs"`
+}
+
+// FetchFailedWebhookDeliveries returns the deliveries from the dead-letter queue
+func (m *WebhookModel) FetchFailedWebhookDeliveries(r *FetchWebhookDeliveriesReq) (*FetchWebhookDeliveriesResult, error) {
+	if r.Limit <= 0 || r.Limit > 100 {
+		r.Limit = defaultWebhookDeliveriesLimit
+	}
+
+	list, total, err := m.webhookNotifier.GetFailedDeliveries(r.From, r.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FetchWebhookDeliveriesResult{
+		TotalDeliveries: total,
+		From:            r.From,
+		Limit:           r.Limit,
+		DeliveriesList:  list,
+	}, nil
+}
+
+// ReplayFailedWebhookDeliveries will queue the deliveries again for sending
+func (m *WebhookModel) ReplayFailedWebhookDeliveries(r *WebhookDeliveriesReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"seqs":   r.Seqs,
+		"method": "ReplayFailedWebhookDeliveries",
+	})
+	if len(r.Seqs) == 0 {
+		return errors.New("seqs required")
+	}
+
+	var errs []error
+	for _, seq := range r.Seqs {
+		if err := m.webhookNotifier.ReplayFailedDelivery(seq); err != nil {
+			log.WithError(err).WithField("seq", seq).Errorln("failed to replay webhook delivery")
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		log.Infoln("successfully queued webhook deliveries for replay")
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeleteFailedWebhookDeliveries will remove the deliveries from the dead-letter queue
+func (m *WebhookModel) DeleteFailedWebhookDeliveries(r *WebhookDeliveriesReq) error {
+	if len(r.Seqs) == 0 {
+		return errors.New("seqs required")
+	}
+
+	var errs []error
+	for _, seq := range r.Seqs {
+		if err := m.webhookNotifier.DeleteFailedDelivery(seq); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
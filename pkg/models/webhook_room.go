@@ -83,6 +83,8 @@ func (m *WebhookModel) roomStarted(event *livekit.WebhookEvent) {
 
 	// webhook notification
 	m.sendToWebhookNotifier(event)
+
+	go m.recorderModel.AutoStartRecordingOnRoomStart(rInfo.RoomId)
 	log.Info("successfully processed room_started webhook")
 }
 
@@ -0,0 +1,256 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"github.com/sirupsen/logrus"
+)
+
+type CreateWebhookSubscriptionReq struct {
+	// RoomId empty means subscription for all the rooms
+	RoomId string `json:"room_id"`
+	Url    string `json:"url"`
+	// Secret will be used to sign the payload, if empty then will be generated
+	Secret string `json:"secret"`
+	// Events empty means all the events
+	Events  []string `json:"events"`
+	Enabled *bool    `json:"enabled"`
+}
+
+type UpdateWebhookSubscriptionReq struct {
+	SubscriptionId string    `json:"subscription_id"`
+	RoomId         *string   `json:"room_id"`
+	Url            *string   `json:"url"`
+	Secret         *string   `json:"secret"`
+	Events         *[]string `json:"events"`
+	Enabled        *bool     `json:"enabled"`
+}
+
+type FetchWebhookSubscriptionsReq struct {
+	RoomIds []string `json:"room_ids"`
+	From    uint32   `json:"from"`
+	Limit   uint32   `json:"limit"`
+	OrderBy string   `json:"order_by"`
+}
+
+type WebhookSubscriptionReq struct {
+	SubscriptionId string `json:"subscription_id"`
+}
+
+type WebhookSubscriptionInfo struct {
+	SubscriptionId string   `json:"subscription_id"`
+	RoomId         string   `json:"room_id"`
+	Url            string   `json:"url"`
+	Events         []string `json:"events"`
+	Enabled        bool     `json:"enabled"`
+	// TenantId is set for the subscriptions created using the BBB hooks API
+	TenantId string `json:"tenant_id,omitempty"`
+	// Format is bbb for the subscriptions created using the BBB hooks API
+	Format string `json:"format,omitempty"`
+	// Secret will be only available during creation
+	Secret   string `json:"secret,omitempty"`
+	Created  string `json:"created"`
+	Modified string `json:"modified"`
+}
+
+type FetchWebhookSubscriptionsResult struct {
+	TotalSubscriptions int64                      `json:"total_subscriptions"`
+	From               uint32                     `json:"from"`
+	Limit              uint32                     `json:"limit"`
+	OrderBy            string                     `json:"order_by"`
+	SubscriptionsList  []*WebhookSubscriptionInfo `json:"subscriptions_list"`
+}
+
+func (m *WebhookModel) CreateWebhookSubscription(r *CreateWebhookSubscriptionReq) (*WebhookSubscriptionInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"roomId": r.RoomId,
+		"url":    r.Url,
+		"method": "CreateWebhookSubscription",
+	})
+	log.Infoln("request to create webhook subscription")
+
+	if err := validateWebhookSubscriptionUrl(r.Url); err != nil {
+		return nil, err
+	}
+	if r.Secret == "" {
+		secret, err := generateRandomHex(32)
+		if err != nil {
+			return nil, err
+		}
+		r.Secret = secret
+	}
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	info := &dbmodels.WebhookSubscription{
+		SubscriptionID: uuid.NewString(),
+		RoomID:         r.RoomId,
+		Url:            r.Url,
+		Secret:         r.Secret,
+		Events:         formatWebhookSubscriptionEvents(r.Events),
+		Enabled:        enabled,
+	}
+
+	_, err := m.ds.InsertOrUpdateWebhookSubscription(info)
+	if err != nil {
+		log.WithError(err).Errorln("failed to save webhook subscription")
+		return nil, err
+	}
+
+	log.WithField("subscriptionId", info.SubscriptionID).Infoln("successfully created webhook subscription")
+	si := m.toWebhookSubscriptionInfo(info)
+	si.Secret = info.Secret
+	return si, nil
+}
+
+func (m *WebhookModel) UpdateWebhookSubscription(r *UpdateWebhookSubscriptionReq) (*WebhookSubscriptionInfo, error) {
+	log := m.logger.WithFields(logrus.Fields{
+		"subscriptionId": r.SubscriptionId,
+		"method":         "UpdateWebhookSubscription",
+	})
+
+	info, err := m.ds.GetWebhookSubscription(r.SubscriptionId)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("webhook subscription not found")
+	}
+
+	if r.RoomId != nil {
+		info.RoomID = *r.RoomId
+	}
+	if r.Url != nil {
+		if err := validateWebhookSubscriptionUrl(*r.Url); err != nil {
+			return nil, err
+		}
+		info.Url = *r.Url
+	}
+	if r.Secret != nil && *r.Secret != "" {
+		info.Secret = *r.Secret
+	}
+	if r.Events != nil {
+		info.Events = formatWebhookSubscriptionEvents(*r.Events)
+	}
+	if r.Enabled != nil {
+		info.Enabled = *r.Enabled
+	}
+
+	_, err = m.ds.InsertOrUpdateWebhookSubscription(info)
+	if err != nil {
+		log.WithError(err).Errorln("failed to update webhook subscription")
+		return nil, err
+	}
+
+	log.Infoln("successfully updated webhook subscription")
+	return m.toWebhookSubscriptionInfo(info), nil
+}
+
+func (m *WebhookModel) FetchWebhookSubscriptions(r *FetchWebhookSubscriptionsReq) (*FetchWebhookSubscriptionsResult, error) {
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	// If the limit exceeds the maximum, cap it at the maximum.
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+	if r.OrderBy == "" {
+		r.OrderBy = "DESC"
+	}
+
+	subscriptions, total, err := m.ds.GetWebhookSubscriptions(r.RoomIds, uint64(r.From), uint64(r.Limit), &r.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*WebhookSubscriptionInfo, 0, len(subscriptions))
+	for i := range subscriptions {
+		list = append(list, m.toWebhookSubscriptionInfo(&subscriptions[i]))
+	}
+
+	return &FetchWebhookSubscriptionsResult{
+		TotalSubscriptions: total,
+		From:               r.From,
+		Limit:              r.Limit,
+		OrderBy:            r.OrderBy,
+		SubscriptionsList:  list,
+	}, nil
+}
+
+func (m *WebhookModel) GetWebhookSubscriptionInfo(r *WebhookSubscriptionReq) (*WebhookSubscriptionInfo, error) {
+	info, err := m.ds.GetWebhookSubscription(r.SubscriptionId)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("webhook subscription not found")
+	}
+
+	return m.toWebhookSubscriptionInfo(info), nil
+}
+
+func (m *WebhookModel) DeleteWebhookSubscription(r *WebhookSubscriptionReq) error {
+	log := m.logger.WithFields(logrus.Fields{
+		"subscriptionId": r.SubscriptionId,
+		"method":         "DeleteWebhookSubscription",
+	})
+
+	affected, err := m.ds.DeleteWebhookSubscription(r.SubscriptionId)
+	if err != nil {
+		log.WithError(err).Errorln("failed to delete webhook subscription")
+		return err
+	}
+	if affected == 0 {
+		return errors.New("webhook subscription not found")
+	}
+
+	log.Infoln("successfully deleted webhook subscription")
+	return nil
+}
+
+func (m *WebhookModel) toWebhookSubscriptionInfo(s *dbmodels.WebhookSubscription) *WebhookSubscriptionInfo {
+	events := s.EventList()
+	if events == nil {
+		events = []string{}
+	}
+	return &WebhookSubscriptionInfo{
+		SubscriptionId: s.SubscriptionID,
+		TenantId:       s.TenantID,
+		Format:         s.Format,
+		RoomId:         s.RoomID,
+		Url:            s.Url,
+		Events:         events,
+		Enabled:        s.Enabled,
+		Created:        s.Created.Format("2006-01-02 15:04:05"),
+		Modified:       s.Modified.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func validateWebhookSubscriptionUrl(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return errors.New("valid http or https url required")
+	}
+	return nil
+}
+
+// formatWebhookSubscriptionEvents will make the list lowercase & unique
+func formatWebhookSubscriptionEvents(events []string) string {
+	seen := make(map[string]bool)
+	var list []string
+	for _, e := range events {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		list = append(list, e)
+	}
+	return strings.Join(list, ",")
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/gofiber/template/html/v2"
 	"github.com/mynaparrot/plugnmeet-server/pkg/config"
 	"github.com/mynaparrot/plugnmeet-server/pkg/factory"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 	"github.com/mynaparrot/plugnmeet-server/version"
 )
 
@@ -61,6 +62,10 @@ func New(appConfig *config.AppConfig, ctrl *factory.ApplicationControllers) *fib
 		app.Use(prometheus.Middleware)
 	}
 
+	if appConfig.TracingSettings != nil && appConfig.TracingSettings.Enabled {
+		app.Use(tracing.FiberMiddleware())
+	}
+
 	app.Use(rr.New())
 	app.Use(cors.New(cors.Config{
 		AllowMethods: "POST,GET,OPTIONS",
@@ -99,7 +104,10 @@ func (r *router) registerBaseRoutes() {
 	r.app.Post("/webhook", r.ctrl.WebhookController.HandleWebhook)
 	r.app.Get("/download/uploadedFile/:sid/*", r.ctrl.FileController.HandleDownloadUploadedFile)
 	r.app.Get("/download/recording/:token", r.ctrl.RecordingController.HandleDownloadRecording)
+	r.app.Get("/download/recording/thumbnail/:token/:index", r.ctrl.RecordingController.HandleDownloadRecordingThumbnail)
+	r.app.Get("/download/recording/track/:token/:name", r.ctrl.RecordingController.HandleDownloadRecordingTextTrack)
 	r.app.Get("/download/analytics/:token", r.ctrl.AnalyticsController.HandleDownloadAnalytics)
+	r.app.Get("/download/chat/:token", r.ctrl.ChatController.HandleDownloadChat)
 	r.app.Get("/healthCheck", r.ctrl.HealthCheckController.HandleHealthCheck)
 }
 
@@ -114,6 +122,11 @@ func (r *router) registerLtiRoutes() {
 	ltiV1API.Post("/recording/fetch", r.ctrl.LtiV1Controller.HandleLTIV1FetchRecordings)
 	ltiV1API.Post("/recording/download", r.ctrl.LtiV1Controller.HandleLTIV1GetRecordingDownloadToken)
 	ltiV1API.Post("/recording/delete", r.ctrl.LtiV1Controller.HandleLTIV1DeleteRecordings)
+
+	// LTI 1.3, after the launch the same /v1/api will be used
+	lti.All("/v1p3/login", r.ctrl.LtiV1Controller.HandleLTIV1p3Login)
+	lti.Post("/v1p3/launch", r.ctrl.LtiV1Controller.HandleLTIV1p3Launch)
+	lti.Get("/v1p3/jwks", r.ctrl.LtiV1Controller.HandleLTIV1p3Jwks)
 }
 
 func (r *router) registerAuthRoutes() {
@@ -134,14 +147,77 @@ func (r *router) registerAuthRoutes() {
 	recording.Post("/recordingInfo", r.ctrl.RecordingController.HandleRecordingInfo)
 	recording.Post("/delete", r.ctrl.RecordingController.HandleDeleteRecording)
 	recording.Post("/getDownloadToken", r.ctrl.RecordingController.HandleGetDownloadToken)
+	recording.Post("/publish", r.ctrl.RecordingController.HandlePublishRecording)
+	recording.Post("/update", r.ctrl.RecordingController.HandleUpdateRecording)
+	recording.Post("/expired", r.ctrl.RecordingController.HandleExpiredRecordings)
+
+	downloadAudit := auth.Group("/downloadAudit")
+	downloadAudit.Post("/fetch", r.ctrl.DownloadAuditController.HandleFetchDownloadAudits)
 
 	analytics := auth.Group("/analytics")
 	analytics.Post("/fetch", r.ctrl.AnalyticsController.HandleFetchAnalytics)
 	analytics.Post("/delete", r.ctrl.AnalyticsController.HandleDeleteAnalytics)
 	analytics.Post("/getDownloadToken", r.ctrl.AnalyticsController.HandleGetAnalyticsDownloadToken)
 
-	recorder := auth.Group("/recorder")
+	chat := auth.Group("/chat")
+	chat.Post("/fetch", r.ctrl.ChatController.HandleFetchChatMessages)
+	chat.Post("/getDownloadToken", r.ctrl.ChatController.HandleGetChatDownloadToken)
+
+	polls := auth.Group("/polls")
+	polls.Post("/fetchPast", r.ctrl.PollsController.HandleFetchPastPolls)
+
+	// only the default API key from config can be used for these routes
+	recorder := auth.Group("/recorder", r.ctrl.AuthController.HandleDefaultApiKeyOnly)
 	recorder.Post("/notify", r.ctrl.RecorderController.HandleRecorderEvents)
+
+	schedule := auth.Group("/schedule")
+	schedule.Post("/create", r.ctrl.ScheduleController.HandleCreateSchedule)
+	schedule.Post("/update", r.ctrl.ScheduleController.HandleUpdateSchedule)
+	schedule.Post("/list", r.ctrl.ScheduleController.HandleFetchSchedules)
+	schedule.Post("/occurrences", r.ctrl.ScheduleController.HandleGetScheduleOccurrences)
+	schedule.Post("/cancel", r.ctrl.ScheduleController.HandleCancelSchedule)
+
+	webhook := auth.Group("/webhook", r.ctrl.AuthController.HandleDefaultApiKeyOnly)
+	webhook.Post("/deliveries", r.ctrl.WebhookController.HandleFetchWebhookDeliveries)
+	webhook.Post("/deliveries/replay", r.ctrl.WebhookController.HandleReplayWebhookDeliveries)
+	webhook.Post("/deliveries/delete", r.ctrl.WebhookController.HandleDeleteWebhookDeliveries)
+	webhook.Post("/subscriptions/create", r.ctrl.WebhookController.HandleCreateWebhookSubscription)
+	webhook.Post("/subscriptions/update", r.ctrl.WebhookController.HandleUpdateWebhookSubscription)
+	webhook.Post("/subscriptions/list", r.ctrl.WebhookController.HandleFetchWebhookSubscriptions)
+	webhook.Post("/subscriptions/info", r.ctrl.WebhookController.HandleGetWebhookSubscription)
+	webhook.Post("/subscriptions/delete", r.ctrl.WebhookController.HandleDeleteWebhookSubscription)
+
+	ltiPlatform := auth.Group("/lti/platform", r.ctrl.AuthController.HandleDefaultApiKeyOnly)
+	ltiPlatform.Post("/create", r.ctrl.LtiV1Controller.HandleCreateLtiPlatform)
+	ltiPlatform.Post("/update", r.ctrl.LtiV1Controller.HandleUpdateLtiPlatform)
+	ltiPlatform.Post("/list", r.ctrl.LtiV1Controller.HandleFetchLtiPlatforms)
+	ltiPlatform.Post("/info", r.ctrl.LtiV1Controller.HandleGetLtiPlatform)
+	ltiPlatform.Post("/delete", r.ctrl.LtiV1Controller.HandleDeleteLtiPlatform)
+
+	ltiConsumer := auth.Group("/lti/consumer", r.ctrl.AuthController.HandleDefaultApiKeyOnly)
+	ltiConsumer.Post("/create", r.ctrl.LtiV1Controller.HandleCreateLtiConsumer)
+	ltiConsumer.Post("/update", r.ctrl.LtiV1Controller.HandleUpdateLtiConsumer)
+	ltiConsumer.Post("/list", r.ctrl.LtiV1Controller.HandleFetchLtiConsumers)
+	ltiConsumer.Post("/info", r.ctrl.LtiV1Controller.HandleGetLtiConsumer)
+	ltiConsumer.Post("/regenerateSecret", r.ctrl.LtiV1Controller.HandleRegenerateLtiConsumerSecret)
+	ltiConsumer.Post("/delete", r.ctrl.LtiV1Controller.HandleDeleteLtiConsumer)
+
+	bbbIntegration := auth.Group("/bbb/integration", r.ctrl.AuthController.HandleDefaultApiKeyOnly)
+	bbbIntegration.Post("/create", r.ctrl.BBBController.HandleCreateBBBIntegration)
+	bbbIntegration.Post("/update", r.ctrl.BBBController.HandleUpdateBBBIntegration)
+	bbbIntegration.Post("/list", r.ctrl.BBBController.HandleFetchBBBIntegrations)
+	bbbIntegration.Post("/info", r.ctrl.BBBController.HandleGetBBBIntegration)
+	bbbIntegration.Post("/regenerateSecret", r.ctrl.BBBController.HandleRegenerateBBBIntegrationSecret)
+	bbbIntegration.Post("/delete", r.ctrl.BBBController.HandleDeleteBBBIntegration)
+
+	tenant := auth.Group("/tenant", r.ctrl.AuthController.HandleDefaultApiKeyOnly)
+	tenant.Post("/create", r.ctrl.TenantController.HandleCreateTenant)
+	tenant.Post("/update", r.ctrl.TenantController.HandleUpdateTenant)
+	tenant.Post("/list", r.ctrl.TenantController.HandleFetchTenants)
+	tenant.Post("/info", r.ctrl.TenantController.HandleGetTenantInfo)
+	tenant.Post("/delete", r.ctrl.TenantController.HandleDeleteTenant)
+	tenant.Post("/createApiKey", r.ctrl.TenantController.HandleCreateTenantApiKey)
+	tenant.Post("/revokeApiKey", r.ctrl.TenantController.HandleRevokeTenantApiKey)
 }
 
 func (r *router) registerBBBRoutes() {
@@ -156,6 +232,12 @@ func (r *router) registerBBBRoutes() {
 	bbb.All("/deleteRecordings", r.ctrl.BBBController.HandleBBBDeleteRecordings)
 	bbb.All("/updateRecordings", r.ctrl.BBBController.HandleBBBUpdateRecordings)
 	bbb.All("/publishRecordings", r.ctrl.BBBController.HandleBBBPublishRecordings)
+	bbb.All("/getRecordingTextTracks", r.ctrl.BBBController.HandleBBBGetRecordingTextTracks)
+	bbb.All("/putRecordingTextTrack", r.ctrl.BBBController.HandleBBBPutRecordingTextTrack)
+	bbb.All("/insertDocument", r.ctrl.BBBController.HandleBBBInsertDocument)
+	bbb.All("/hooks/create", r.ctrl.BBBController.HandleBBBCreateHook)
+	bbb.All("/hooks/list", r.ctrl.BBBController.HandleBBBListHooks)
+	bbb.All("/hooks/destroy", r.ctrl.BBBController.HandleBBBDestroyHook)
 }
 
 func (r *router) registerAPIRoutes() {
@@ -167,6 +249,8 @@ func (r *router) registerAPIRoutes() {
 	api.Post("/endRoom", r.ctrl.RoomController.HandleEndRoomForAPI)
 	api.Post("/changeVisibility", r.ctrl.RoomController.HandleChangeVisibilityForAPI)
 	api.Post("/convertWhiteboardFile", r.ctrl.FileController.HandleConvertWhiteboardFile)
+	api.Post("/getWhiteboardFileInfo", r.ctrl.FileController.HandleGetWhiteboardFileInfo)
+	api.Post("/searchWhiteboardFiles", r.ctrl.FileController.HandleSearchWhiteboardFiles)
 	api.Post("/externalMediaPlayer", r.ctrl.ExMediaController.HandleExternalMediaPlayer)
 	api.Post("/externalDisplayLink", r.ctrl.ExDisplayController.HandleExternalDisplayLink)
 	api.Post("/updateLockSettings", r.ctrl.UserController.HandleUpdateUserLockSetting)
@@ -195,6 +279,15 @@ func (r *router) registerAPIRoutes() {
 	polls.Post("/submitResponse", r.ctrl.PollsController.HandleUserSubmitResponse)
 	polls.Post("/closePoll", r.ctrl.PollsController.HandleClosePoll)
 
+	// routes of this group will use JSON for both request & response
+	// because the protocol doesn't have messages for them.
+	apiJson := api.Group("/json")
+	pollsJson := apiJson.Group("/polls")
+	pollsJson.Post("/createWithSettings", r.ctrl.PollsController.HandleCreatePollWithSettings)
+	pollsJson.Post("/submitResponses", r.ctrl.PollsController.HandleUserSubmitResponses)
+	pollsJson.Get("/pollSettings/:pollId", r.ctrl.PollsController.HandleGetPollSettings)
+	pollsJson.Get("/quizResult/:pollId", r.ctrl.PollsController.HandleGetQuizResult)
+
 	breakoutRoom := api.Group("/breakoutRoom")
 	breakoutRoom.Post("/create", r.ctrl.BreakoutRoomController.HandleCreateBreakoutRooms)
 	breakoutRoom.Post("/join", r.ctrl.BreakoutRoomController.HandleJoinBreakoutRoom)
@@ -204,6 +297,9 @@ func (r *router) registerAPIRoutes() {
 	breakoutRoom.Post("/sendMsg", r.ctrl.BreakoutRoomController.HandleSendBreakoutRoomMsg)
 	breakoutRoom.Post("/endRoom", r.ctrl.BreakoutRoomController.HandleEndBreakoutRoom)
 	breakoutRoom.Post("/endAllRooms", r.ctrl.BreakoutRoomController.HandleEndBreakoutRooms)
+	breakoutRoom.Post("/autoAssign", r.ctrl.BreakoutRoomController.HandleAutoAssignBreakoutRooms)
+	breakoutRoom.Post("/choose", r.ctrl.BreakoutRoomController.HandleChooseBreakoutRoom)
+	breakoutRoom.Post("/moveUser", r.ctrl.BreakoutRoomController.HandleMoveBreakoutRoomUser)
 
 	ingress := api.Group("/ingress")
 	ingress.Post("/create", r.ctrl.IngressController.HandleCreateIngress)
@@ -0,0 +1,93 @@
+package converterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrUnsupportedType = errors.New("unsupported file type for conversion")
+
+// backend converts office documents to PDF
+type backend interface {
+	// convertToPDF writes the PDF inside outputDir & returns its path
+	convertToPDF(ctx context.Context, filePath, mimeType, outputDir string) (string, error)
+	// requiredBinaries returns the external tools which must be installed
+	requiredBinaries() []string
+}
+
+// ConverterService converts the whiteboard files using the configured backend.
+// Rendering PDF to images is always done locally by mutool.
+type ConverterService struct {
+	app     *config.AppConfig
+	backend backend
+	logger  *logrus.Entry
+}
+
+func New(app *config.AppConfig, logger *logrus.Logger) (*ConverterService, error) {
+	s := &ConverterService{
+		app:    app,
+		logger: logger.WithField("service", "converter"),
+	}
+
+	cnf := app.FileConversionSettings
+	switch cnf.Backend {
+	case config.ConverterBackendLocal:
+		s.backend = newLocalBackend(s.logger)
+	case config.ConverterBackendHttp:
+		s.backend = newHttpBackend(cnf.HttpConverter)
+	default:
+		return nil, fmt.Errorf("unknown file conversion backend: %s", cnf.Backend)
+	}
+
+	s.logger.Infof("using %s file conversion backend", cnf.Backend)
+	return s, nil
+}
+
+// ConvertToPDF converts the office document to PDF inside outputDir & returns the path of the PDF
+func (s *ConverterService) ConvertToPDF(ctx context.Context, filePath, mimeType, outputDir string) (string, error) {
+	if _, ok := sofficeExportFilters[mimeType]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, mimeType)
+	}
+	return s.backend.convertToPDF(ctx, filePath, mimeType, outputDir)
+}
+
+// RenderPDFToImages uses mutool to render every page of the PDF in the resolution (DPI)
+// as page_{n}.png inside outputDir
+func (s *ConverterService) RenderPDFToImages(ctx context.Context, pdfPath, outputDir string, resolution int) error {
+	return executeCommand(ctx, "mutool", "convert", "-O", "resolution="+strconv.Itoa(resolution), "-o", filepath.Join(outputDir, "page_%d.png"), pdfPath)
+}
+
+// ExtractPDFText uses mutool to write the text of every page of the PDF as page_{n}.txt inside outputDir.
+// Pages without text, e.g. scanned documents, will have empty files.
+func (s *ConverterService) ExtractPDFText(ctx context.Context, pdfPath, outputDir string) error {
+	return executeCommand(ctx, "mutool", "draw", "-q", "-F", "txt", "-o", filepath.Join(outputDir, "page_%d.txt"), pdfPath)
+}
+
+// CheckDependencies verifies that required external tools are installed.
+func (s *ConverterService) CheckDependencies() error {
+	for _, bin := range append([]string{"mutool"}, s.backend.requiredBinaries()...) {
+		if _, err := exec.LookPath(bin); err != nil {
+			return fmt.Errorf("required binary not found in PATH: %s", bin)
+		}
+	}
+	return nil
+}
+
+// executeCommand runs a command with the context & handles common error cases.
+func executeCommand(ctx context.Context, name string, arg ...string) error {
+	cmd := exec.CommandContext(ctx, name, arg...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s command timed out", name)
+		}
+		return fmt.Errorf("%s command failed: %w; output: %s", name, err, string(output))
+	}
+	return nil
+}
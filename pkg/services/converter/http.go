@@ -0,0 +1,89 @@
+package converterservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// httpBackend uses a Gotenberg compatible service to convert office documents,
+// which will detect the type of the document by the extension of the file name
+type httpBackend struct {
+	cnf    *config.HttpConverterSettings
+	client *http.Client
+}
+
+func newHttpBackend(cnf *config.HttpConverterSettings) *httpBackend {
+	return &httpBackend{
+		cnf: cnf,
+		// the timeout will be handled by the context of the job
+		client: &http.Client{},
+	}
+}
+
+func (b *httpBackend) convertToPDF(ctx context.Context, filePath, _, outputDir string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// stream the file without keeping it in memory
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("files", filepath.Base(filePath))
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(b.cnf.Url, "/")+"/forms/libreoffice/convert", pr)
+	if err != nil {
+		_ = pr.Close()
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if b.cnf.Username != "" {
+		req.SetBasicAuth(b.cnf.Username, b.cnf.Password)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("http converter request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("http converter returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	fileName := filepath.Base(filePath)
+	pdfPath := filepath.Join(outputDir, strings.TrimSuffix(fileName, filepath.Ext(fileName))+".pdf")
+	out, err := os.Create(pdfPath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err = io.Copy(out, resp.Body); err != nil {
+		return "", fmt.Errorf("failed to save converted PDF: %w", err)
+	}
+	return pdfPath, nil
+}
+
+func (b *httpBackend) requiredBinaries() []string {
+	return nil
+}
@@ -0,0 +1,66 @@
+package converterservice
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+func TestHttpBackend_ConvertToPDF(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/forms/libreoffice/convert" {
+			http.NotFound(w, r)
+			return
+		}
+		if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		file, header, err := r.FormFile("files")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		if header.Filename != "slides.pptx" || string(content) != "pptx content" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("%PDF-1.7"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "slides.pptx")
+	if err := os.WriteFile(input, []byte("pptx content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outputDir := filepath.Join(dir, "out")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	b := newHttpBackend(&config.HttpConverterSettings{Url: srv.URL + "/", Username: "user", Password: "pass"})
+	pdf, err := b.convertToPDF(context.Background(), input, "", outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf != filepath.Join(outputDir, "slides.pdf") {
+		t.Errorf("unexpected pdf path %s", pdf)
+	}
+	if data, _ := os.ReadFile(pdf); string(data) != "%PDF-1.7" {
+		t.Errorf("unexpected pdf content %q", string(data))
+	}
+
+	b.cnf.Password = "wrong"
+	if _, err = b.convertToPDF(context.Background(), input, "", outputDir); err == nil {
+		t.Error("expected error for unauthorized request")
+	}
+}
@@ -0,0 +1,65 @@
+package converterservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sofficeExportFilters has the supported mime types with the export filter of soffice
+var sofficeExportFilters = map[string]string{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "pdf:writer_pdf_Export",
+	"application/msword":                      "pdf:writer_pdf_Export",
+	"application/vnd.oasis.opendocument.text": "pdf:writer_pdf_Export",
+	"text/plain":                              "pdf:writer_pdf_Export",
+	"application/rtf":                         "pdf:writer_pdf_Export",
+	"application/xml":                         "pdf:writer_pdf_Export",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": "pdf:calc_pdf_Export",
+	"application/vnd.ms-excel":                       "pdf:calc_pdf_Export",
+	"application/vnd.oasis.opendocument.spreadsheet": "pdf:calc_pdf_Export",
+	"text/csv": "pdf:calc_pdf_Export",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": "pdf:impress_pdf_Export",
+	"application/vnd.ms-powerpoint":                                             "pdf:impress_pdf_Export",
+	"application/vnd.oasis.opendocument.presentation":                           "pdf:impress_pdf_Export",
+	"application/vnd.visio":                                                     "pdf:draw_pdf_Export",
+	"application/vnd.oasis.opendocument.graphics":                               "pdf:draw_pdf_Export",
+	"text/html": "pdf:writer_web_pdf_Export",
+}
+
+// localBackend uses soffice of this server
+type localBackend struct {
+	logger *logrus.Entry
+}
+
+func newLocalBackend(logger *logrus.Entry) *localBackend {
+	return &localBackend{
+		logger: logger.WithField("backend", "local"),
+	}
+}
+
+func (b *localBackend) convertToPDF(ctx context.Context, filePath, mimeType, outputDir string) (string, error) {
+	// soffice can't run multiple conversions with the same user profile,
+	// so every job will use its own profile
+	profileDir, err := os.MkdirTemp("", "soffice-profile-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(profileDir)
+
+	err = executeCommand(ctx, "soffice", "-env:UserInstallation=file://"+filepath.ToSlash(profileDir), "--headless", "--invisible", "--nologo", "--nolockcheck", "--convert-to", sofficeExportFilters[mimeType], "--outdir", outputDir, filePath)
+	if err != nil {
+		b.logger.WithError(err).WithField("file", filePath).Errorln("soffice conversion failed")
+		return "", fmt.Errorf("soffice: converting to PDF failed")
+	}
+
+	fileName := filepath.Base(filePath)
+	return filepath.Join(outputDir, strings.TrimSuffix(fileName, filepath.Ext(fileName))+".pdf"), nil
+}
+
+func (b *localBackend) requiredBinaries() []string {
+	return []string{"soffice"}
+}
@@ -0,0 +1,35 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// InsertOrUpdateBBBIntegration will insert new integration
+// or update if table ID was sent
+func (s *DatabaseService) InsertOrUpdateBBBIntegration(info *dbmodels.BBBIntegration) (int64, error) {
+	result := s.db.Save(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) DeleteBBBIntegration(apiKey string) (int64, error) {
+	cond := &dbmodels.BBBIntegration{
+		ApiKey: apiKey,
+	}
+
+	result := s.db.Where(cond).Delete(&dbmodels.BBBIntegration{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,48 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// GetChatMessages returns archived chat messages of the session.
+// If isAdmin is false then private messages will be limited to the messages
+// sent by or to the userId, empty userId will return public messages only.
+func (s *DatabaseService) GetChatMessages(tenantId, roomSid, userId string, isAdmin bool, offset, limit uint64, direction *string) ([]dbmodels.ChatMessage, int64, error) {
+	var messages []dbmodels.ChatMessage
+	var total int64
+
+	cond := &dbmodels.ChatMessage{
+		RoomSid: roomSid,
+	}
+	d := s.db.Model(&dbmodels.ChatMessage{}).Scopes(tenantScope(tenantId)).Where(cond)
+	if !isAdmin {
+		if userId != "" {
+			d.Where("is_private = 0 OR from_user_id = ? OR to_user_id = ?", userId, userId)
+		} else {
+			d.Where("is_private = 0")
+		}
+	}
+
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Offset(int(offset)).Limit(int(limit)).Order("sent_at " + orderBy).Order("id " + orderBy).Find(&messages)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return messages, total, nil
+}
@@ -0,0 +1,38 @@
+package dbservice
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertChatMessages will insert messages in batches,
+// already archived messages of the session will be ignored
+func (s *DatabaseService) InsertChatMessages(messages []*dbmodels.ChatMessage) (int64, error) {
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(messages, 200)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteChatMessagesBefore will delete messages which were archived before the time
+func (s *DatabaseService) DeleteChatMessagesBefore(before time.Time) (int64, error) {
+	result := s.db.Where("created < ?", before).Delete(&dbmodels.ChatMessage{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,40 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// GetDownloadAudits returns the download logs, empty fileType or fileId won't be used as filter
+func (s *DatabaseService) GetDownloadAudits(tenantId, fileType, fileId string, offset, limit uint64, direction *string) ([]dbmodels.DownloadAudit, int64, error) {
+	var audits []dbmodels.DownloadAudit
+	var total int64
+
+	cond := &dbmodels.DownloadAudit{
+		FileType: fileType,
+		FileID:   fileId,
+	}
+	d := s.db.Model(&dbmodels.DownloadAudit{}).Scopes(tenantScope(tenantId)).Where(cond)
+
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Offset(int(offset)).Limit(int(limit)).Order("id " + orderBy).Find(&audits)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return audits, total, nil
+}
@@ -0,0 +1,31 @@
+package dbservice
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+func (s *DatabaseService) InsertDownloadAudit(info *dbmodels.DownloadAudit) (int64, error) {
+	result := s.db.Create(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteDownloadAuditsBefore will delete the logs which were created before the time
+func (s *DatabaseService) DeleteDownloadAuditsBefore(before time.Time) (int64, error) {
+	result := s.db.Where("created < ?", before).Delete(&dbmodels.DownloadAudit{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
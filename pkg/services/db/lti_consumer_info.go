@@ -0,0 +1,50 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+func (s *DatabaseService) GetLtiConsumer(consumerKey string) (*dbmodels.LtiConsumer, error) {
+	info := new(dbmodels.LtiConsumer)
+	cond := &dbmodels.LtiConsumer{
+		ConsumerKey: consumerKey,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
+
+func (s *DatabaseService) GetLtiConsumers(offset, limit uint64, direction *string) ([]dbmodels.LtiConsumer, int64, error) {
+	var consumers []dbmodels.LtiConsumer
+	var total int64
+
+	d := s.db.Model(&dbmodels.LtiConsumer{})
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Offset(int(offset)).Limit(int(limit)).Order("id " + orderBy).Find(&consumers)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return consumers, total, nil
+}
@@ -0,0 +1,35 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// InsertOrUpdateLtiConsumer will insert new consumer
+// or update if table ID was sent
+func (s *DatabaseService) InsertOrUpdateLtiConsumer(info *dbmodels.LtiConsumer) (int64, error) {
+	result := s.db.Save(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) DeleteLtiConsumer(consumerKey string) (int64, error) {
+	cond := &dbmodels.LtiConsumer{
+		ConsumerKey: consumerKey,
+	}
+
+	result := s.db.Where(cond).Delete(&dbmodels.LtiConsumer{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,71 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+func (s *DatabaseService) GetLtiPlatform(platformId string) (*dbmodels.LtiPlatform, error) {
+	info := new(dbmodels.LtiPlatform)
+	cond := &dbmodels.LtiPlatform{
+		PlatformID: platformId,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
+
+// GetLtiPlatformsByIssuer returns the registrations of the issuer,
+// clientId can be empty as the platforms may not send it during login initiation
+func (s *DatabaseService) GetLtiPlatformsByIssuer(issuer, clientId string) ([]dbmodels.LtiPlatform, error) {
+	var platforms []dbmodels.LtiPlatform
+
+	d := s.db.Where("issuer = ?", issuer)
+	if clientId != "" {
+		d.Where("client_id = ?", clientId)
+	}
+
+	result := d.Find(&platforms)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return platforms, nil
+}
+
+func (s *DatabaseService) GetLtiPlatforms(offset, limit uint64, direction *string) ([]dbmodels.LtiPlatform, int64, error) {
+	var platforms []dbmodels.LtiPlatform
+	var total int64
+
+	d := s.db.Model(&dbmodels.LtiPlatform{})
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Offset(int(offset)).Limit(int(limit)).Order("id " + orderBy).Find(&platforms)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return platforms, total, nil
+}
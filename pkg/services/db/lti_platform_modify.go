@@ -0,0 +1,35 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// InsertOrUpdateLtiPlatform will insert new platform
+// or update if table ID was sent
+func (s *DatabaseService) InsertOrUpdateLtiPlatform(info *dbmodels.LtiPlatform) (int64, error) {
+	result := s.db.Save(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) DeleteLtiPlatform(platformId string) (int64, error) {
+	cond := &dbmodels.LtiPlatform{
+		PlatformID: platformId,
+	}
+
+	result := s.db.Where(cond).Delete(&dbmodels.LtiPlatform{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
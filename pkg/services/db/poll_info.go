@@ -0,0 +1,43 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// GetPastPolls returns stored polls with their responses,
+// empty roomId or roomSid won't be used as filter
+func (s *DatabaseService) GetPastPolls(tenantId, roomId, roomSid string, offset, limit uint64, direction *string) ([]dbmodels.Poll, int64, error) {
+	var polls []dbmodels.Poll
+	var total int64
+
+	cond := &dbmodels.Poll{
+		RoomID:  roomId,
+		RoomSid: roomSid,
+	}
+	d := s.db.Model(&dbmodels.Poll{}).Scopes(tenantScope(tenantId)).Where(cond)
+
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Preload("Responses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Offset(int(offset)).Limit(int(limit)).Order("poll_created_at " + orderBy).Order("id " + orderBy).Find(&polls)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return polls, total, nil
+}
@@ -0,0 +1,33 @@
+package dbservice
+
+import (
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertPoll will insert the poll with its responses,
+// already stored poll of the session will be ignored
+func (s *DatabaseService) InsertPoll(info *dbmodels.Poll) (int64, error) {
+	var affected int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(info)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+		if affected == 0 || len(info.Responses) == 0 {
+			return nil
+		}
+
+		for i := range info.Responses {
+			info.Responses[i].PollTableID = info.ID
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(info.Responses, 200).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
@@ -7,13 +7,41 @@ import (
 	"gorm.io/gorm"
 )
 
-func (s *DatabaseService) GetRecordings(roomIds []string, offset, limit uint64, direction *string) ([]dbmodels.Recording, int64, error) {
+// RecordingsFilter narrows down the recordings by their published state & tags.
+// Nil or empty values won't be used.
+type RecordingsFilter struct {
+	Published *bool
+	// Tags will match recordings which have all the tags
+	Tags []string
+}
+
+func recordingsFilterScope(f *RecordingsFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if f == nil {
+			return db
+		}
+		if f.Published != nil {
+			published := 0
+			if *f.Published {
+				published = 1
+			}
+			db = db.Where("published = ?", published)
+		}
+		for _, tag := range f.Tags {
+			db = db.Where("FIND_IN_SET(?, tags) > 0", tag)
+		}
+		return db
+	}
+}
+
+// GetRecordings returns recordings of the rooms including the recordings of their breakout rooms
+func (s *DatabaseService) GetRecordings(tenantId string, roomIds []string, offset, limit uint64, direction *string, filter *RecordingsFilter) ([]dbmodels.Recording, int64, error) {
 	var recordings []dbmodels.Recording
 	var total int64
 
-	d := s.db.Model(&dbmodels.Recording{})
+	d := s.db.Model(&dbmodels.Recording{}).Scopes(tenantScope(tenantId), recordingsFilterScope(filter))
 	if len(roomIds) > 0 {
-		d.Where("room_id IN ?", roomIds)
+		d.Where("room_id IN ? OR parent_room_id IN ?", roomIds, roomIds)
 	}
 
 	if err := d.Count(&total).Error; err != nil {
@@ -54,11 +82,11 @@ func (s *DatabaseService) GetRecording(recordId string) (*dbmodels.Recording, er
 	return info, nil
 }
 
-func (s *DatabaseService) GetRecordingsForBBB(recordIds, meetingIds []string, offset, limit uint64) ([]dbmodels.Recording, int64, error) {
+func (s *DatabaseService) GetRecordingsForBBB(tenantId string, recordIds, meetingIds []string, offset, limit uint64, filter *RecordingsFilter) ([]dbmodels.Recording, int64, error) {
 	var recordings []dbmodels.Recording
 	var total int64
 
-	d := s.db.Model(&dbmodels.Recording{})
+	d := s.db.Model(&dbmodels.Recording{}).Scopes(tenantScope(tenantId), recordingsFilterScope(filter))
 
 	if len(recordIds) > 0 {
 		d.Where("record_id IN ?", recordIds)
@@ -77,3 +105,24 @@ func (s *DatabaseService) GetRecordingsForBBB(recordIds, meetingIds []string, of
 
 	return recordings, total, nil
 }
+
+// GetExpiredRecordings returns the recordings which have expire_at before the given unix time.
+// Recordings with 0 expire_at will be kept forever.
+func (s *DatabaseService) GetExpiredRecordings(tenantId string, before int64, limit uint64) ([]dbmodels.Recording, int64, error) {
+	var recordings []dbmodels.Recording
+	var total int64
+
+	d := s.db.Model(&dbmodels.Recording{}).Scopes(tenantScope(tenantId)).
+		Where("expire_at > 0 AND expire_at <= ?", before)
+
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := d.Limit(int(limit)).Order("expire_at ASC").Find(&recordings)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return recordings, total, nil
+}
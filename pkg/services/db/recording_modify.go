@@ -31,3 +31,13 @@ func (s *DatabaseService) DeleteRecording(recordId string) (int64, error) {
 
 	return result.RowsAffected, nil
 }
+
+// UpdateRecording updates only the given columns of the recording
+func (s *DatabaseService) UpdateRecording(recordId string, values map[string]interface{}) (int64, error) {
+	result := s.db.Model(&dbmodels.Recording{}).Where("record_id = ?", recordId).Updates(values)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -9,6 +9,7 @@ import (
 )
 
 var recordId = fmt.Sprintf("%d", time.Now().UnixNano())
+var bkRecordId = fmt.Sprintf("bk-%d", time.Now().UnixNano())
 
 func TestDatabaseService_InsertRecordingData(t *testing.T) {
 	v := sql.NullString{
@@ -34,7 +35,7 @@ func TestDatabaseService_InsertRecordingData(t *testing.T) {
 
 func TestDatabaseService_GetRecordings(t *testing.T) {
 	roomIds := []string{roomId}
-	recordings, total, err := s.GetRecordings(roomIds, 0, 5, nil)
+	recordings, total, err := s.GetRecordings("", roomIds, 0, 5, nil, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -42,6 +43,37 @@ func TestDatabaseService_GetRecordings(t *testing.T) {
 	t.Logf("%+v with total: %d", recordings, total)
 }
 
+func TestDatabaseService_GetRecordingsWithBreakoutRooms(t *testing.T) {
+	data := &dbmodels.Recording{
+		RecordID:         bkRecordId,
+		RoomID:           roomId + "-bk",
+		ParentRoomID:     roomId,
+		Size:             10.10,
+		RoomCreationTime: roomCreationTime,
+	}
+	_, err := s.InsertRecordingData(data)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer s.DeleteRecording(bkRecordId)
+
+	recordings, _, err := s.GetRecordings("", []string{roomId}, 0, 100, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	found := false
+	for _, r := range recordings {
+		if r.RecordID == bkRecordId {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("recording of the breakout room should be fetched with the parent room")
+	}
+}
+
 func TestDatabaseService_GetRecording(t *testing.T) {
 	recording, err := s.GetRecording(recordId)
 	if err != nil {
@@ -7,11 +7,11 @@ import (
 	"gorm.io/gorm"
 )
 
-func (s *DatabaseService) GetAnalytics(roomIds []string, offset, limit uint64, direction *string) ([]dbmodels.Analytics, int64, error) {
+func (s *DatabaseService) GetAnalytics(tenantId string, roomIds []string, offset, limit uint64, direction *string) ([]dbmodels.Analytics, int64, error) {
 	var analytics []dbmodels.Analytics
 	var total int64
 
-	d := s.db.Model(&dbmodels.Analytics{})
+	d := s.db.Model(&dbmodels.Analytics{}).Scopes(tenantScope(tenantId))
 	if len(roomIds) > 0 {
 		d.Where("room_id IN ?", roomIds)
 	}
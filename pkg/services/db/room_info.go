@@ -25,6 +25,18 @@ func (s *DatabaseService) GetRoomInfoByRoomId(roomId string, isRunning int) (*db
 	return info, nil
 }
 
+// GetRoomTenantIds returns the tenants of all the sessions of the room, including the ended ones
+func (s *DatabaseService) GetRoomTenantIds(roomId string) ([]string, error) {
+	var tenantIds []string
+
+	result := s.db.Model(&dbmodels.RoomInfo{}).Where("room_id = ?", roomId).Distinct().Pluck("tenant_id", &tenantIds)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return tenantIds, nil
+}
+
 func (s *DatabaseService) GetRoomInfoBySid(sId string, isRunning *int) (*dbmodels.RoomInfo, error) {
 	info := new(dbmodels.RoomInfo)
 	cond := &dbmodels.RoomInfo{}
@@ -60,13 +72,15 @@ func (s *DatabaseService) GetRoomInfoByTableId(tableId uint64) (*dbmodels.RoomIn
 	return info, nil
 }
 
-func (s *DatabaseService) GetActiveRoomsInfo() ([]dbmodels.RoomInfo, error) {
+// GetActiveRoomsInfo returns running rooms of the tenant,
+// empty tenantId will return rooms of all the tenants
+func (s *DatabaseService) GetActiveRoomsInfo(tenantId string) ([]dbmodels.RoomInfo, error) {
 	var rooms []dbmodels.RoomInfo
 	cond := &dbmodels.RoomInfo{
 		IsRunning: 1,
 	}
 
-	result := s.db.Where(cond).Find(&rooms)
+	result := s.db.Scopes(tenantScope(tenantId)).Where(cond).Find(&rooms)
 	switch {
 	case errors.Is(result.Error, gorm.ErrRecordNotFound):
 		return nil, nil
@@ -77,14 +91,14 @@ func (s *DatabaseService) GetActiveRoomsInfo() ([]dbmodels.RoomInfo, error) {
 	return rooms, nil
 }
 
-func (s *DatabaseService) GetPastRooms(roomIds []string, offset, limit uint64, direction *string) ([]dbmodels.RoomInfo, int64, error) {
+func (s *DatabaseService) GetPastRooms(tenantId string, roomIds []string, offset, limit uint64, direction *string) ([]dbmodels.RoomInfo, int64, error) {
 	var roomsInfo []dbmodels.RoomInfo
 	var total int64
 	cond := &dbmodels.RoomInfo{
 		IsRunning: 0,
 	}
 
-	d := s.db.Model(&dbmodels.RoomInfo{}).Where(cond)
+	d := s.db.Model(&dbmodels.RoomInfo{}).Scopes(tenantScope(tenantId)).Where(cond)
 	if len(roomIds) > 0 {
 		d.Where("roomId IN ?", roomIds)
 	}
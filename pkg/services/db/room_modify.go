@@ -57,6 +57,10 @@ func (s *DatabaseService) UpdateRoomRecordingStatus(roomTableId uint64, isRecord
 	if recorderId != nil && *recorderId != "" {
 		update["recorder_id"] = *recorderId
 	}
+	if isRecording == 1 {
+		// will be used to calculate recording usage
+		update["recording_started_at"] = time.Now().Unix()
+	}
 
 	result := s.db.Model(&dbmodels.RoomInfo{}).Where(cond).Updates(update)
 	if result.Error != nil {
@@ -0,0 +1,111 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+func (s *DatabaseService) GetScheduledMeeting(scheduleId string) (*dbmodels.ScheduledMeeting, error) {
+	info := new(dbmodels.ScheduledMeeting)
+	cond := &dbmodels.ScheduledMeeting{
+		ScheduleID: scheduleId,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
+
+func (s *DatabaseService) GetScheduledMeetings(tenantId string, roomIds []string, status string, offset, limit uint64, direction *string) ([]dbmodels.ScheduledMeeting, int64, error) {
+	var schedules []dbmodels.ScheduledMeeting
+	var total int64
+
+	d := s.db.Model(&dbmodels.ScheduledMeeting{}).Scopes(tenantScope(tenantId))
+	if len(roomIds) > 0 {
+		d.Where("room_id IN ?", roomIds)
+	}
+	if status != "" {
+		d.Where("status = ?", status)
+	}
+
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Offset(int(offset)).Limit(int(limit)).Order("id " + orderBy).Find(&schedules)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return schedules, total, nil
+}
+
+// GetDueScheduledMeetings will return active schedules
+// which next occurrence minus lead time (in minutes) is on or before the given unix time
+func (s *DatabaseService) GetDueScheduledMeetings(now int64) ([]dbmodels.ScheduledMeeting, error) {
+	var schedules []dbmodels.ScheduledMeeting
+	cond := &dbmodels.ScheduledMeeting{
+		Status: dbmodels.ScheduleStatusActive,
+	}
+
+	result := s.db.Where(cond).Where("next_occurrence > 0 AND next_occurrence - (lead_time * 60) <= ?", now).Find(&schedules)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return schedules, nil
+}
+
+func (s *DatabaseService) GetScheduledMeetingOccurrences(scheduleId string, from int64) ([]dbmodels.ScheduledMeetingOccurrence, error) {
+	var occurrences []dbmodels.ScheduledMeetingOccurrence
+	cond := &dbmodels.ScheduledMeetingOccurrence{
+		ScheduleID: scheduleId,
+	}
+
+	result := s.db.Where(cond).Where("occurrence_time >= ?", from).Order("occurrence_time ASC").Find(&occurrences)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return occurrences, nil
+}
+
+func (s *DatabaseService) GetScheduledMeetingOccurrence(scheduleId string, occurrenceTime int64) (*dbmodels.ScheduledMeetingOccurrence, error) {
+	info := new(dbmodels.ScheduledMeetingOccurrence)
+	cond := &dbmodels.ScheduledMeetingOccurrence{
+		ScheduleID:     scheduleId,
+		OccurrenceTime: occurrenceTime,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
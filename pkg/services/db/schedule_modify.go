@@ -0,0 +1,64 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertOrUpdateScheduledMeeting will insert new schedule
+// or update if table ID was sent
+func (s *DatabaseService) InsertOrUpdateScheduledMeeting(info *dbmodels.ScheduledMeeting) (int64, error) {
+	result := s.db.Save(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) UpdateScheduledMeetingNextOccurrence(scheduleId string, nextOccurrence int64, status string) (int64, error) {
+	update := map[string]interface{}{
+		"next_occurrence": nextOccurrence,
+		"status":          status,
+	}
+
+	result := s.db.Model(&dbmodels.ScheduledMeeting{}).Where("schedule_id = ?", scheduleId).Updates(update)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// UpsertScheduledMeetingOccurrence will insert occurrence status
+// or update if the same occurrence already exists
+func (s *DatabaseService) UpsertScheduledMeetingOccurrence(info *dbmodels.ScheduledMeetingOccurrence) (int64, error) {
+	result := s.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"status", "room_sid"}),
+	}).Create(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) DeleteScheduledMeetingOccurrence(scheduleId string, occurrenceTime int64) (int64, error) {
+	cond := &dbmodels.ScheduledMeetingOccurrence{
+		ScheduleID:     scheduleId,
+		OccurrenceTime: occurrenceTime,
+	}
+
+	result := s.db.Where(cond).Delete(&dbmodels.ScheduledMeetingOccurrence{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
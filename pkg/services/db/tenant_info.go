@@ -0,0 +1,136 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// tenantScope limits the query to the tenant,
+// empty tenantId won't apply any limit
+func tenantScope(tenantId string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenantId == "" {
+			return db
+		}
+		return db.Where("tenant_id = ?", tenantId)
+	}
+}
+
+func (s *DatabaseService) GetTenant(tenantId string) (*dbmodels.Tenant, error) {
+	info := new(dbmodels.Tenant)
+	cond := &dbmodels.Tenant{
+		TenantID: tenantId,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
+
+func (s *DatabaseService) GetTenants(offset, limit uint64, direction *string) ([]dbmodels.Tenant, int64, error) {
+	var tenants []dbmodels.Tenant
+	var total int64
+
+	d := s.db.Model(&dbmodels.Tenant{})
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Offset(int(offset)).Limit(int(limit)).Order("id " + orderBy).Find(&tenants)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return tenants, total, nil
+}
+
+func (s *DatabaseService) GetTenantApiKey(apiKey string) (*dbmodels.TenantApiKey, error) {
+	info := new(dbmodels.TenantApiKey)
+	cond := &dbmodels.TenantApiKey{
+		ApiKey: apiKey,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
+
+func (s *DatabaseService) GetTenantApiKeys(tenantId string) ([]dbmodels.TenantApiKey, error) {
+	var keys []dbmodels.TenantApiKey
+	cond := &dbmodels.TenantApiKey{
+		TenantID: tenantId,
+	}
+
+	result := s.db.Where(cond).Order("id ASC").Find(&keys)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return keys, nil
+}
+
+// GetTenantActiveUsage returns the number of running rooms
+// & the number of joined participants in those rooms.
+// Breakout rooms aren't counted as their participants are already part of the parent room.
+func (s *DatabaseService) GetTenantActiveUsage(tenantId string) (int64, int64, error) {
+	var usage struct {
+		Rooms        int64
+		Participants int64
+	}
+	cond := &dbmodels.RoomInfo{
+		TenantID:  tenantId,
+		IsRunning: 1,
+	}
+
+	result := s.db.Model(&dbmodels.RoomInfo{}).
+		Select("COUNT(id) AS rooms, COALESCE(SUM(joined_participants), 0) AS participants").
+		Where(cond).Where("is_breakout_room = ?", 0).Scan(&usage)
+	if result.Error != nil {
+		return 0, 0, result.Error
+	}
+
+	return usage.Rooms, usage.Participants, nil
+}
+
+func (s *DatabaseService) GetTenantUsage(tenantId, period string) (*dbmodels.TenantUsage, error) {
+	info := new(dbmodels.TenantUsage)
+	cond := &dbmodels.TenantUsage{
+		TenantID: tenantId,
+		Period:   period,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
@@ -0,0 +1,80 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertOrUpdateTenant will insert new tenant
+// or update if table ID was sent
+func (s *DatabaseService) InsertOrUpdateTenant(info *dbmodels.Tenant) (int64, error) {
+	result := s.db.Save(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteTenant will delete the tenant, api keys will be deleted by foreign key
+func (s *DatabaseService) DeleteTenant(tenantId string) (int64, error) {
+	cond := &dbmodels.Tenant{
+		TenantID: tenantId,
+	}
+
+	result := s.db.Where(cond).Delete(&dbmodels.Tenant{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) InsertTenantApiKey(info *dbmodels.TenantApiKey) (int64, error) {
+	result := s.db.Create(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) RevokeTenantApiKey(tenantId, apiKey string) (int64, error) {
+	cond := &dbmodels.TenantApiKey{
+		TenantID: tenantId,
+		ApiKey:   apiKey,
+	}
+
+	result := s.db.Model(&dbmodels.TenantApiKey{}).Where(cond).Update("revoked", true)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// AddTenantRecordingUsage will add seconds to the recording usage of the period
+func (s *DatabaseService) AddTenantRecordingUsage(tenantId, period string, seconds int64) (int64, error) {
+	info := &dbmodels.TenantUsage{
+		TenantID:         tenantId,
+		Period:           period,
+		RecordingSeconds: seconds,
+	}
+
+	result := s.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"recording_seconds": gorm.Expr("recording_seconds + ?", seconds),
+		}),
+	}).Create(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
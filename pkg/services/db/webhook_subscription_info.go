@@ -0,0 +1,108 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+func (s *DatabaseService) GetWebhookSubscription(subscriptionId string) (*dbmodels.WebhookSubscription, error) {
+	info := new(dbmodels.WebhookSubscription)
+	cond := &dbmodels.WebhookSubscription{
+		SubscriptionID: subscriptionId,
+	}
+
+	result := s.db.Where(cond).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
+
+// GetWebhookSubscriptionById returns the subscription by the table ID,
+// which is used as the hookID of the BBB hooks API
+func (s *DatabaseService) GetWebhookSubscriptionById(id uint64) (*dbmodels.WebhookSubscription, error) {
+	info := new(dbmodels.WebhookSubscription)
+
+	result := s.db.Where("id = ?", id).Take(info)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return info, nil
+}
+
+func (s *DatabaseService) GetWebhookSubscriptions(roomIds []string, offset, limit uint64, direction *string) ([]dbmodels.WebhookSubscription, int64, error) {
+	var subscriptions []dbmodels.WebhookSubscription
+	var total int64
+
+	d := s.db.Model(&dbmodels.WebhookSubscription{})
+	if len(roomIds) > 0 {
+		d.Where("room_id IN ?", roomIds)
+	}
+
+	if err := d.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		limit = 20
+	}
+	orderBy := "DESC"
+	if direction != nil && *direction == "ASC" {
+		orderBy = "ASC"
+	}
+
+	result := d.Offset(int(offset)).Limit(int(limit)).Order("id " + orderBy).Find(&subscriptions)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, 0, result.Error
+	}
+
+	return subscriptions, total, nil
+}
+
+// GetEnabledWebhookSubscriptionsForRoom returns enabled subscriptions of the room
+// including the subscriptions for all the rooms. Subscriptions of the tenants will be used
+// only for their own rooms, the ones of the server itself don't have any tenant.
+func (s *DatabaseService) GetEnabledWebhookSubscriptionsForRoom(tenantId, roomId string) ([]dbmodels.WebhookSubscription, error) {
+	var subscriptions []dbmodels.WebhookSubscription
+
+	result := s.db.Where("enabled = ? AND (tenant_id = '' OR tenant_id = ?) AND (room_id = '' OR room_id = ?)", true, tenantId, roomId).Find(&subscriptions)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return subscriptions, nil
+}
+
+// GetWebhookSubscriptionsForBBB returns the subscriptions of the tenant,
+// roomIds empty means the subscriptions of all the rooms
+func (s *DatabaseService) GetWebhookSubscriptionsForBBB(tenantId string, roomIds []string) ([]dbmodels.WebhookSubscription, error) {
+	var subscriptions []dbmodels.WebhookSubscription
+
+	d := s.db.Where("tenant_id = ?", tenantId)
+	if len(roomIds) > 0 {
+		d.Where("room_id IN ?", roomIds)
+	}
+
+	result := d.Order("id ASC").Find(&subscriptions)
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return nil, nil
+	case result.Error != nil:
+		return nil, result.Error
+	}
+
+	return subscriptions, nil
+}
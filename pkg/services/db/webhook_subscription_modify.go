@@ -0,0 +1,35 @@
+package dbservice
+
+import (
+	"errors"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/dbmodels"
+	"gorm.io/gorm"
+)
+
+// InsertOrUpdateWebhookSubscription will insert new subscription
+// or update if table ID was sent
+func (s *DatabaseService) InsertOrUpdateWebhookSubscription(info *dbmodels.WebhookSubscription) (int64, error) {
+	result := s.db.Save(info)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (s *DatabaseService) DeleteWebhookSubscription(subscriptionId string) (int64, error) {
+	cond := &dbmodels.WebhookSubscription{
+		SubscriptionID: subscriptionId,
+	}
+
+	result := s.db.Where(cond).Delete(&dbmodels.WebhookSubscription{})
+	switch {
+	case errors.Is(result.Error, gorm.ErrRecordNotFound):
+		return 0, nil
+	case result.Error != nil:
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,309 @@
+package ltiservice
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+)
+
+const (
+	ClaimAgsEndpoint = "https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"
+	ScopeAgsScore    = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+
+	scoreContentType    = "application/vnd.ims.lis.v1.score+json"
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+var ErrToolKeyNotConfigured = errors.New("lti_settings.tool_private_key is not configured")
+
+// AgsEndpointClaim is the Assignment and Grade Services claim of the launch
+type AgsEndpointClaim struct {
+	Scope     []string `json:"scope,omitempty"`
+	LineItems string   `json:"lineitems,omitempty"`
+	LineItem  string   `json:"lineitem,omitempty"`
+}
+
+// CanPostScore returns true if the platform allows the tool to post the score to the line item of the launch
+func (c *AgsEndpointClaim) CanPostScore() bool {
+	if c == nil || c.LineItem == "" {
+		return false
+	}
+	for _, s := range c.Scope {
+		if s == ScopeAgsScore {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoreRequest is the score of a user for the line item
+type ScoreRequest struct {
+	LineItem     string
+	UserId       string
+	ScoreGiven   float64
+	ScoreMaximum float64
+	Comment      string
+}
+
+type agsScore struct {
+	UserId           string  `json:"userId"`
+	ScoreGiven       float64 `json:"scoreGiven"`
+	ScoreMaximum     float64 `json:"scoreMaximum"`
+	Comment          string  `json:"comment,omitempty"`
+	Timestamp        string  `json:"timestamp"`
+	ActivityProgress string  `json:"activityProgress"`
+	GradingProgress  string  `json:"gradingProgress"`
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type cachedAccessToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// toolKey is the signing key of this tool, which will be used for the client assertion
+type toolKey struct {
+	key *rsa.PrivateKey
+	kid string
+}
+
+// loadToolKey reads the RSA private key in PKCS#1 or PKCS#8 PEM format
+func loadToolKey(file string) (*toolKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	var key *rsa.PrivateKey
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		var k any
+		k, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err == nil {
+			var ok bool
+			if key, ok = k.(*rsa.PrivateKey); !ok {
+				err = errors.New("tool key must be an RSA key")
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newToolKey(key)
+}
+
+func newToolKey(key *rsa.PrivateKey) (*toolKey, error) {
+	// kid will change only if the key changes
+	tp, err := (&jose.JSONWebKey{Key: key.Public()}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return &toolKey{
+		key: key,
+		kid: base64.RawURLEncoding.EncodeToString(tp),
+	}, nil
+}
+
+// ToolJWKS returns the public key of this tool, which the platforms will use to verify the client assertion
+func (s *LtiService) ToolJWKS() *jose.JSONWebKeySet {
+	set := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+	if s.toolKey != nil {
+		set.Keys = append(set.Keys, jose.JSONWebKey{
+			Key:       s.toolKey.key.Public(),
+			KeyID:     s.toolKey.kid,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		})
+	}
+	return set
+}
+
+// SubmitScore posts the score of the user to the line item using Assignment and Grade Services
+func (s *LtiService) SubmitScore(ctx context.Context, p *Platform, r *ScoreRequest) error {
+	if r.LineItem == "" || r.UserId == "" {
+		return errors.New("line item and user id are required")
+	}
+	scoresUrl, err := lineItemScoresUrl(r.LineItem)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.accessToken(ctx, p, ScopeAgsScore)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(&agsScore{
+		UserId:           r.UserId,
+		ScoreGiven:       r.ScoreGiven,
+		ScoreMaximum:     r.ScoreMaximum,
+		Comment:          r.Comment,
+		Timestamp:        time.Now().UTC().Format(time.RFC3339Nano),
+		ActivityProgress: "Completed",
+		GradingProgress:  "FullyGraded",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scoresUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", scoreContentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to submit score: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// the platform may have revoked the token, so the next attempt should request a new one
+		s.forgetAccessToken(p, ScopeAgsScore)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to submit score: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// lineItemScoresUrl appends /scores to the path of the line item, the query string will be kept
+func lineItemScoresUrl(lineItem string) (string, error) {
+	u, err := url.Parse(lineItem)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", errors.New("invalid line item url")
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/scores"
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// accessToken returns the cached token or requests a new one
+// using the client credentials grant with the signed client assertion
+func (s *LtiService) accessToken(ctx context.Context, p *Platform, scope string) (string, error) {
+	if s.toolKey == nil {
+		return "", ErrToolKeyNotConfigured
+	}
+	if p.AuthTokenUrl == "" {
+		return "", errors.New("auth_token_url of the platform is required")
+	}
+
+	cacheKey := accessTokenCacheKey(p, scope)
+	s.mu.Lock()
+	cached := s.tokens[cacheKey]
+	s.mu.Unlock()
+	if cached != nil && time.Now().Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	assertion, err := s.clientAssertion(p)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_assertion_type", clientAssertionType)
+	form.Set("client_assertion", assertion)
+	form.Set("scope", scope)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.AuthTokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request access token: unexpected status %d", resp.StatusCode)
+	}
+
+	res := new(accessTokenResponse)
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(res); err != nil {
+		return "", fmt.Errorf("invalid access token response: %w", err)
+	}
+	if res.AccessToken == "" {
+		return "", errors.New("invalid access token response: missing access_token")
+	}
+
+	expiresIn := time.Duration(res.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = time.Hour
+	}
+	s.mu.Lock()
+	s.tokens[cacheKey] = &cachedAccessToken{
+		token: res.AccessToken,
+		// so that the token won't expire during the request
+		expiresAt: time.Now().Add(expiresIn - min(time.Minute, expiresIn/2)),
+	}
+	s.mu.Unlock()
+
+	return res.AccessToken, nil
+}
+
+func (s *LtiService) forgetAccessToken(p *Platform, scope string) {
+	s.mu.Lock()
+	delete(s.tokens, accessTokenCacheKey(p, scope))
+	s.mu.Unlock()
+}
+
+func accessTokenCacheKey(p *Platform, scope string) string {
+	return p.AuthTokenUrl + "|" + p.ClientId + "|" + scope
+}
+
+// clientAssertion is the JWT of the tool for the token endpoint of the platform
+func (s *LtiService) clientAssertion(p *Platform) (string, error) {
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: s.toolKey.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader(jose.HeaderKey("kid"), s.toolKey.kid))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	cl := jwt.Claims{
+		Issuer:   p.ClientId,
+		Subject:  p.ClientId,
+		Audience: jwt.Audience{p.AuthTokenUrl},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Minute * 5)),
+		ID:       uuid.NewString(),
+	}
+
+	return jwt.Signed(sig).Claims(cl).Serialize()
+}
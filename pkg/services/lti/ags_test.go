@@ -0,0 +1,124 @@
+package ltiservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+func TestSubmitScore(t *testing.T) {
+	s := newTestService()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.toolKey, err = newToolKey(key); err != nil {
+		t.Fatal(err)
+	}
+
+	var tokenRequests atomic.Int32
+	var received agsScore
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests.Add(1)
+			if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != ScopeAgsScore {
+				t.Errorf("unexpected token request %v", r.Form)
+			}
+			tok, err := jwt.ParseSigned(r.FormValue("client_assertion"), []jose.SignatureAlgorithm{jose.RS256})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the platform verifies the assertion using the JWKS of the tool
+			jwks := s.ToolJWKS()
+			found := jwks.Key(tok.Headers[0].KeyID)
+			if len(found) != 1 {
+				t.Fatalf("kid %q not found in the tool JWKS", tok.Headers[0].KeyID)
+			}
+			cl := jwt.Claims{}
+			if err = tok.Claims(found[0].Key, &cl); err != nil {
+				t.Fatal(err)
+			}
+			if err = cl.Validate(jwt.Expected{Issuer: "client-1", Subject: "client-1", AnyAudience: jwt.Audience{server.URL + "/token"}}); err != nil {
+				t.Error(err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "access-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+		case "/lineitems/1/scores":
+			if r.URL.Query().Get("type") != "attendance" {
+				t.Errorf("query of the line item was not kept: %s", r.URL.RawQuery)
+			}
+			if r.Header.Get("Authorization") != "Bearer access-token" || r.Header.Get("Content-Type") != scoreContentType {
+				t.Errorf("unexpected headers %v", r.Header)
+			}
+			_ = json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := &Platform{
+		Issuer:       "https://lms.example.com",
+		ClientId:     "client-1",
+		AuthTokenUrl: server.URL + "/token",
+	}
+	for i := 0; i < 2; i++ {
+		err = s.SubmitScore(context.Background(), p, &ScoreRequest{
+			LineItem:     server.URL + "/lineitems/1?type=attendance",
+			UserId:       "user-1",
+			ScoreGiven:   45,
+			ScoreMaximum: 60,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if tokenRequests.Load() != 1 {
+		t.Errorf("expected the access token to be cached, requested %d times", tokenRequests.Load())
+	}
+	if received.UserId != "user-1" || received.ScoreGiven != 45 || received.ScoreMaximum != 60 || received.GradingProgress != "FullyGraded" {
+		t.Errorf("unexpected score %+v", received)
+	}
+}
+
+func TestSubmitScoreWithoutToolKey(t *testing.T) {
+	s := newTestService()
+	err := s.SubmitScore(context.Background(), &Platform{AuthTokenUrl: "https://lms.example.com/token"}, &ScoreRequest{
+		LineItem: "https://lms.example.com/lineitems/1",
+		UserId:   "user-1",
+	})
+	if !errors.Is(err, ErrToolKeyNotConfigured) {
+		t.Errorf("expected ErrToolKeyNotConfigured, got %v", err)
+	}
+}
+
+func TestAgsEndpointClaimCanPostScore(t *testing.T) {
+	var c *AgsEndpointClaim
+	if c.CanPostScore() {
+		t.Error("nil claim can't post score")
+	}
+	c = &AgsEndpointClaim{LineItem: "https://lms.example.com/lineitems/1"}
+	if c.CanPostScore() {
+		t.Error("score scope is required")
+	}
+	c.Scope = []string{ScopeAgsScore}
+	if !c.CanPostScore() {
+		t.Error("expected to be able to post score")
+	}
+}
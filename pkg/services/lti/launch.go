@@ -0,0 +1,231 @@
+package ltiservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// Claim names of LTI 1.3 Core
+const (
+	ClaimMessageType  = "https://purl.imsglobal.org/spec/lti/claim/message_type"
+	ClaimVersion      = "https://purl.imsglobal.org/spec/lti/claim/version"
+	ClaimDeploymentId = "https://purl.imsglobal.org/spec/lti/claim/deployment_id"
+	ClaimTargetLink   = "https://purl.imsglobal.org/spec/lti/claim/target_link_uri"
+	ClaimRoles        = "https://purl.imsglobal.org/spec/lti/claim/roles"
+	ClaimContext      = "https://purl.imsglobal.org/spec/lti/claim/context"
+	ClaimResourceLink = "https://purl.imsglobal.org/spec/lti/claim/resource_link"
+	ClaimToolPlatform = "https://purl.imsglobal.org/spec/lti/claim/tool_platform"
+	ClaimCustom       = "https://purl.imsglobal.org/spec/lti/claim/custom"
+
+	MessageTypeResourceLink = "LtiResourceLinkRequest"
+	Version                 = "1.3.0"
+)
+
+// roles which will be treated as the admin of the room
+var instructorRoles = []string{
+	"http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor",
+	"http://purl.imsglobal.org/vocab/lis/v2/membership#Administrator",
+	"http://purl.imsglobal.org/vocab/lis/v2/membership#ContentDeveloper",
+	"http://purl.imsglobal.org/vocab/lis/v2/institution/person#Administrator",
+	"http://purl.imsglobal.org/vocab/lis/v2/system/person#Administrator",
+}
+
+// Platform is the registration of the LMS, which will be used to verify the launch
+type Platform struct {
+	Issuer       string
+	ClientId     string
+	AuthLoginUrl string
+	JwksUrl      string
+	// AuthTokenUrl is required for LTI Advantage services
+	AuthTokenUrl string
+}
+
+// AuthRequest is the OIDC authentication request, which will be sent to the platform
+// after the third party initiated login
+type AuthRequest struct {
+	ClientId       string
+	RedirectUri    string
+	LoginHint      string
+	LtiMessageHint string
+	State          string
+	Nonce          string
+}
+
+type ContextClaim struct {
+	Id    string   `json:"id"`
+	Label string   `json:"label,omitempty"`
+	Title string   `json:"title,omitempty"`
+	Type  []string `json:"type,omitempty"`
+}
+
+type ResourceLinkClaim struct {
+	Id          string `json:"id"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type ToolPlatformClaim struct {
+	Guid              string `json:"guid,omitempty"`
+	Name              string `json:"name,omitempty"`
+	ProductFamilyCode string `json:"product_family_code,omitempty"`
+}
+
+// LaunchClaims are the claims of the id_token of a resource link launch
+type LaunchClaims struct {
+	jwt.Claims
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp,omitempty"`
+	Name            string `json:"name,omitempty"`
+	GivenName       string `json:"given_name,omitempty"`
+	FamilyName      string `json:"family_name,omitempty"`
+	Email           string `json:"email,omitempty"`
+
+	MessageType   string                 `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version       string                 `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentId  string                 `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	TargetLinkUri string                 `json:"https://purl.imsglobal.org/spec/lti/claim/target_link_uri"`
+	Roles         []string               `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	Context       *ContextClaim          `json:"https://purl.imsglobal.org/spec/lti/claim/context,omitempty"`
+	ResourceLink  *ResourceLinkClaim     `json:"https://purl.imsglobal.org/spec/lti/claim/resource_link,omitempty"`
+	ToolPlatform  *ToolPlatformClaim     `json:"https://purl.imsglobal.org/spec/lti/claim/tool_platform,omitempty"`
+	Custom        map[string]interface{} `json:"https://purl.imsglobal.org/spec/lti/claim/custom,omitempty"`
+	Ags           *AgsEndpointClaim      `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint,omitempty"`
+}
+
+// IsInstructor returns true if any of the roles can manage the room
+func (c *LaunchClaims) IsInstructor() bool {
+	for _, r := range c.Roles {
+		for _, ir := range instructorRoles {
+			if r == ir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FullName returns the name of the user or empty string if the platform didn't send it
+func (c *LaunchClaims) FullName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return strings.TrimSpace(c.GivenName + " " + c.FamilyName)
+}
+
+// CustomParams returns the custom claim in the same format as LTI 1.1 launch parameters,
+// e.g. room_duration will be custom_room_duration
+func (c *LaunchClaims) CustomParams() url.Values {
+	params := url.Values{}
+	for k, v := range c.Custom {
+		switch val := v.(type) {
+		case string:
+			params.Set("custom_"+k, val)
+		case nil:
+			continue
+		default:
+			params.Set("custom_"+k, fmt.Sprint(val))
+		}
+	}
+	return params
+}
+
+// BuildAuthRequestUrl returns the url of the platform to redirect the user with the OIDC authentication request
+func BuildAuthRequestUrl(authLoginUrl string, r *AuthRequest) (string, error) {
+	u, err := url.Parse(authLoginUrl)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", errors.New("invalid platform auth login url")
+	}
+
+	q := u.Query()
+	q.Set("scope", "openid")
+	q.Set("response_type", "id_token")
+	q.Set("response_mode", "form_post")
+	q.Set("prompt", "none")
+	q.Set("client_id", r.ClientId)
+	q.Set("redirect_uri", r.RedirectUri)
+	q.Set("login_hint", r.LoginHint)
+	q.Set("state", r.State)
+	q.Set("nonce", r.Nonce)
+	if r.LtiMessageHint != "" {
+		q.Set("lti_message_hint", r.LtiMessageHint)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// VerifyLaunchToken verifies the id_token of the launch against the JWKS of the platform
+// & returns the claims if the token is valid for a resource link launch
+func (s *LtiService) VerifyLaunchToken(ctx context.Context, idToken string, p *Platform, nonce string) (*LaunchClaims, error) {
+	tok, err := jwt.ParseSigned(idToken, []jose.SignatureAlgorithm{jose.RS256})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if len(tok.Headers) == 0 {
+		return nil, errors.New("invalid id_token: missing header")
+	}
+
+	key, err := s.platformKey(ctx, p.JwksUrl, tok.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := new(LaunchClaims)
+	if err = tok.Claims(key.Key, claims); err != nil {
+		return nil, fmt.Errorf("id_token signature verification failed: %w", err)
+	}
+	if err = validateLaunchClaims(claims, p, nonce, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func validateLaunchClaims(c *LaunchClaims, p *Platform, nonce string, now time.Time) error {
+	err := c.Claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:      p.Issuer,
+		AnyAudience: jwt.Audience{p.ClientId},
+		Time:        now,
+	}, time.Minute)
+	if err != nil {
+		return fmt.Errorf("invalid id_token: %w", err)
+	}
+	if c.Expiry == nil || c.IssuedAt == nil {
+		return errors.New("invalid id_token: exp & iat are required")
+	}
+	// azp must be the tool if the token was issued for more audiences
+	if (len(c.Audience) > 1 || c.AuthorizedParty != "") && c.AuthorizedParty != p.ClientId {
+		return errors.New("invalid id_token: azp doesn't match client_id")
+	}
+	if nonce == "" || c.Nonce != nonce {
+		return errors.New("invalid id_token: nonce mismatch")
+	}
+
+	if c.Version != Version {
+		return fmt.Errorf("unsupported LTI version: %s", c.Version)
+	}
+	if c.MessageType != MessageTypeResourceLink {
+		return fmt.Errorf("unsupported LTI message type: %s", c.MessageType)
+	}
+	if c.DeploymentId == "" {
+		return errors.New("deployment_id is required")
+	}
+	if c.Subject == "" {
+		return errors.New("sub is required, anonymous launches are not supported")
+	}
+	if c.ResourceLink == nil || c.ResourceLink.Id == "" {
+		return errors.New("resource_link id is required")
+	}
+
+	return nil
+}
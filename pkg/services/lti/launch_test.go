@@ -0,0 +1,239 @@
+package ltiservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// mockPlatform signs the launches like an LMS & serves its JWKS
+type mockPlatform struct {
+	t        *testing.T
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	requests atomic.Int32
+	platform *Platform
+}
+
+func newMockPlatform(t *testing.T) *mockPlatform {
+	mp := &mockPlatform{t: t}
+	mp.rotateKey("key-1")
+	mp.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mp.requests.Add(1)
+		set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+			Key:       &mp.key.PublicKey,
+			KeyID:     mp.kid,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}}}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(mp.server.Close)
+
+	mp.platform = &Platform{
+		Issuer:       "https://lms.example.com",
+		ClientId:     "plugnmeet-client",
+		AuthLoginUrl: mp.server.URL + "/auth",
+		JwksUrl:      mp.server.URL + "/jwks",
+	}
+	return mp
+}
+
+func (mp *mockPlatform) rotateKey(kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		mp.t.Fatal(err)
+	}
+	mp.key = key
+	mp.kid = kid
+}
+
+func (mp *mockPlatform) launchClaims(nonce string) map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":             mp.platform.Issuer,
+		"aud":             mp.platform.ClientId,
+		"sub":             "user-1",
+		"exp":             now.Add(time.Minute * 5).Unix(),
+		"iat":             now.Unix(),
+		"nonce":           nonce,
+		"given_name":      "Jane",
+		"family_name":     "Doe",
+		ClaimMessageType:  MessageTypeResourceLink,
+		ClaimVersion:      Version,
+		ClaimDeploymentId: "deployment-1",
+		ClaimRoles:        []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"},
+		ClaimContext:      map[string]interface{}{"id": "course-1", "label": "CS101", "title": "Computer Science"},
+		ClaimResourceLink: map[string]interface{}{"id": "link-1"},
+		ClaimToolPlatform: map[string]interface{}{"guid": "lms-guid"},
+		ClaimCustom:       map[string]interface{}{"room_duration": "30", "mute_on_start": true},
+	}
+}
+
+func (mp *mockPlatform) sign(claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: mp.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader(jose.HeaderKey("kid"), mp.kid))
+	if err != nil {
+		mp.t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		mp.t.Fatal(err)
+	}
+	return token
+}
+
+func newTestService() *LtiService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	s, err := New(&config.AppConfig{}, logger)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func TestVerifyLaunchToken(t *testing.T) {
+	mp := newMockPlatform(t)
+	s := newTestService()
+	ctx := context.Background()
+
+	claims, err := s.VerifyLaunchToken(ctx, mp.sign(mp.launchClaims("nonce-1")), mp.platform, "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "user-1" || claims.FullName() != "Jane Doe" || !claims.IsInstructor() {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if claims.Context.Label != "CS101" || claims.ResourceLink.Id != "link-1" || claims.ToolPlatform.Guid != "lms-guid" {
+		t.Errorf("unexpected context claims: %+v", claims)
+	}
+	params := claims.CustomParams()
+	if params.Get("custom_room_duration") != "30" || params.Get("custom_mute_on_start") != "true" {
+		t.Errorf("unexpected custom params: %v", params)
+	}
+
+	// the keys should be cached
+	if _, err = s.VerifyLaunchToken(ctx, mp.sign(mp.launchClaims("nonce-2")), mp.platform, "nonce-2"); err != nil {
+		t.Fatal(err)
+	}
+	if n := mp.requests.Load(); n != 1 {
+		t.Errorf("expected JWKS to be fetched once, got %d", n)
+	}
+}
+
+func TestVerifyLaunchTokenInvalid(t *testing.T) {
+	mp := newMockPlatform(t)
+	s := newTestService()
+	ctx := context.Background()
+
+	tests := map[string]func(c map[string]interface{}){
+		"wrong issuer":       func(c map[string]interface{}) { c["iss"] = "https://other.example.com" },
+		"wrong audience":     func(c map[string]interface{}) { c["aud"] = "other-client" },
+		"expired":            func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"missing iat":        func(c map[string]interface{}) { delete(c, "iat") },
+		"wrong nonce":        func(c map[string]interface{}) { c["nonce"] = "other" },
+		"wrong version":      func(c map[string]interface{}) { c[ClaimVersion] = "1.1" },
+		"deep linking":       func(c map[string]interface{}) { c[ClaimMessageType] = "LtiDeepLinkingRequest" },
+		"missing deployment": func(c map[string]interface{}) { delete(c, ClaimDeploymentId) },
+		"anonymous":          func(c map[string]interface{}) { delete(c, "sub") },
+		"missing link":       func(c map[string]interface{}) { delete(c, ClaimResourceLink) },
+		"wrong azp": func(c map[string]interface{}) {
+			c["aud"] = []string{mp.platform.ClientId, "other-client"}
+			c["azp"] = "other-client"
+		},
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := mp.launchClaims("nonce")
+			modify(c)
+			if _, err := s.VerifyLaunchToken(ctx, mp.sign(c), mp.platform, "nonce"); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	// signed by another key with the same kid
+	forged := &mockPlatform{t: t, platform: mp.platform}
+	forged.rotateKey(mp.kid)
+	if _, err := s.VerifyLaunchToken(ctx, forged.sign(mp.launchClaims("nonce")), mp.platform, "nonce"); err == nil {
+		t.Error("expected signature verification error")
+	}
+}
+
+func TestVerifyLaunchTokenKeyRotation(t *testing.T) {
+	mp := newMockPlatform(t)
+	s := newTestService()
+	ctx := context.Background()
+
+	if _, err := s.VerifyLaunchToken(ctx, mp.sign(mp.launchClaims("n")), mp.platform, "n"); err != nil {
+		t.Fatal(err)
+	}
+
+	mp.rotateKey("key-2")
+	// too early to fetch the keys again
+	_, err := s.VerifyLaunchToken(ctx, mp.sign(mp.launchClaims("n")), mp.platform, "n")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	s.jwks[mp.platform.JwksUrl].fetchedAt = time.Now().Add(-jwksMinRefreshInterval)
+	if _, err = s.VerifyLaunchToken(ctx, mp.sign(mp.launchClaims("n")), mp.platform, "n"); err != nil {
+		t.Fatal(err)
+	}
+	if n := mp.requests.Load(); n != 2 {
+		t.Errorf("expected JWKS to be fetched twice, got %d", n)
+	}
+}
+
+func TestBuildAuthRequestUrl(t *testing.T) {
+	u, err := BuildAuthRequestUrl("https://lms.example.com/auth?platform=1", &AuthRequest{
+		ClientId:       "client",
+		RedirectUri:    "https://pnm.example.com/lti/v1p3/launch",
+		LoginHint:      "hint",
+		LtiMessageHint: "msg",
+		State:          "state",
+		Nonce:          "nonce",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := url.Parse(u)
+	q := parsed.Query()
+	want := map[string]string{
+		"platform":         "1",
+		"scope":            "openid",
+		"response_type":    "id_token",
+		"response_mode":    "form_post",
+		"prompt":           "none",
+		"client_id":        "client",
+		"redirect_uri":     "https://pnm.example.com/lti/v1p3/launch",
+		"login_hint":       "hint",
+		"lti_message_hint": "msg",
+		"state":            "state",
+		"nonce":            "nonce",
+	}
+	for k, v := range want {
+		if q.Get(k) != v {
+			t.Errorf("%s: got %q, want %q", k, q.Get(k), v)
+		}
+	}
+
+	if _, err = BuildAuthRequestUrl("/auth", &AuthRequest{}); err == nil {
+		t.Error("expected error for relative url")
+	}
+}
@@ -0,0 +1,133 @@
+package ltiservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// jwksCacheTTL is how long the keys of a platform will be used before fetching again
+	jwksCacheTTL = time.Hour
+	// jwksMinRefreshInterval prevents fetching the keys for every token with an unknown kid
+	jwksMinRefreshInterval = time.Minute
+)
+
+var ErrKeyNotFound = errors.New("signing key not found in platform JWKS")
+
+type cachedKeySet struct {
+	keys      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// LtiService implements the parts of LTI 1.3 which don't depend on the storage,
+// so the same logic can be used for all the registered platforms
+type LtiService struct {
+	app        *config.AppConfig
+	httpClient *http.Client
+	logger     *logrus.Entry
+
+	toolKey *toolKey
+
+	mu     sync.Mutex
+	jwks   map[string]*cachedKeySet
+	tokens map[string]*cachedAccessToken
+}
+
+func New(app *config.AppConfig, logger *logrus.Logger) (*LtiService, error) {
+	s := &LtiService{
+		app: app,
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+		logger: logger.WithField("service", "lti"),
+		jwks:   make(map[string]*cachedKeySet),
+		tokens: make(map[string]*cachedAccessToken),
+	}
+
+	if app.LtiSettings != nil && app.LtiSettings.ToolPrivateKey != "" {
+		key, err := loadToolKey(app.LtiSettings.ToolPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load lti tool private key: %w", err)
+		}
+		s.toolKey = key
+	}
+
+	return s, nil
+}
+
+// platformKey returns the key of kid from the JWKS of the platform.
+// The keys will be fetched again if kid is unknown, because the platform may have rotated the keys.
+func (s *LtiService) platformKey(ctx context.Context, jwksUrl, kid string) (*jose.JSONWebKey, error) {
+	s.mu.Lock()
+	cached := s.jwks[jwksUrl]
+	s.mu.Unlock()
+
+	if cached != nil {
+		if key := findKey(cached.keys, kid); key != nil && time.Since(cached.fetchedAt) < jwksCacheTTL {
+			return key, nil
+		}
+		if time.Since(cached.fetchedAt) < jwksMinRefreshInterval {
+			return nil, ErrKeyNotFound
+		}
+	}
+
+	keys, err := s.fetchKeySet(ctx, jwksUrl)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.jwks[jwksUrl] = &cachedKeySet{keys: keys, fetchedAt: time.Now()}
+	s.mu.Unlock()
+
+	if key := findKey(keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (s *LtiService) fetchKeySet(ctx context.Context, jwksUrl string) (*jose.JSONWebKeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch platform JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch platform JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	keys := new(jose.JSONWebKeySet)
+	if err = json.NewDecoder(resp.Body).Decode(keys); err != nil {
+		return nil, fmt.Errorf("invalid platform JWKS: %w", err)
+	}
+	s.logger.WithField("jwksUrl", jwksUrl).Debugf("fetched %d keys of the platform", len(keys.Keys))
+	return keys, nil
+}
+
+// findKey returns the public key of kid, or the only key of the set if kid is empty
+func findKey(keys *jose.JSONWebKeySet, kid string) *jose.JSONWebKey {
+	if kid == "" {
+		if len(keys.Keys) == 1 {
+			return &keys.Keys[0]
+		}
+		return nil
+	}
+	if found := keys.Key(kid); len(found) > 0 {
+		return &found[0]
+	}
+	return nil
+}
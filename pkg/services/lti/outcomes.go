@@ -0,0 +1,169 @@
+package ltiservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const outcomesNamespace = "http://www.imsglobal.org/services/ltiv1p1/xsd/imsoms_v1p0"
+
+// OutcomeRequest is the grade of a user for the LTI 1.1 Basic Outcomes service.
+// ServiceUrl & SourcedId are the lis_outcome_service_url & lis_result_sourcedid of the launch.
+type OutcomeRequest struct {
+	ServiceUrl  string
+	SourcedId   string
+	ConsumerKey string
+	Secret      string
+	// Score must be between 0 & 1
+	Score float64
+}
+
+type poxEnvelopeRequest struct {
+	XMLName xml.Name `xml:"imsx_POXEnvelopeRequest"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Header  struct {
+		Version           string `xml:"imsx_POXRequestHeaderInfo>imsx_version"`
+		MessageIdentifier string `xml:"imsx_POXRequestHeaderInfo>imsx_messageIdentifier"`
+	} `xml:"imsx_POXHeader"`
+	SourcedId string `xml:"imsx_POXBody>replaceResultRequest>resultRecord>sourcedGUID>sourcedId"`
+	Language  string `xml:"imsx_POXBody>replaceResultRequest>resultRecord>result>resultScore>language"`
+	Score     string `xml:"imsx_POXBody>replaceResultRequest>resultRecord>result>resultScore>textString"`
+}
+
+type poxEnvelopeResponse struct {
+	XMLName     xml.Name `xml:"imsx_POXEnvelopeResponse"`
+	CodeMajor   string   `xml:"imsx_POXHeader>imsx_POXResponseHeaderInfo>imsx_statusInfo>imsx_codeMajor"`
+	Description string   `xml:"imsx_POXHeader>imsx_POXResponseHeaderInfo>imsx_statusInfo>imsx_description"`
+}
+
+// ReplaceResult sends the score to the LMS using replaceResultRequest of LTI 1.1 Basic Outcomes.
+// The request is signed by OAuth 1.0 with the body hash.
+func (s *LtiService) ReplaceResult(ctx context.Context, r *OutcomeRequest) error {
+	if r.ServiceUrl == "" || r.SourcedId == "" {
+		return errors.New("lis_outcome_service_url and lis_result_sourcedid are required")
+	}
+	if r.Score < 0 || r.Score > 1 {
+		return fmt.Errorf("invalid score %v, must be between 0 and 1", r.Score)
+	}
+
+	env := &poxEnvelopeRequest{
+		Xmlns:     outcomesNamespace,
+		SourcedId: r.SourcedId,
+		Language:  "en",
+		Score:     strconv.FormatFloat(r.Score, 'f', -1, 64),
+	}
+	env.Header.Version = "V1.0"
+	env.Header.MessageIdentifier = uuid.NewString()
+	body, err := xml.Marshal(env)
+	if err != nil {
+		return err
+	}
+	body = append([]byte(xml.Header), body...)
+
+	authHeader, err := oauthBodyHashHeader(http.MethodPost, r.ServiceUrl, body, r.ConsumerKey, r.Secret)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.ServiceUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Authorization", authHeader)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send outcome: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send outcome: unexpected status %d", resp.StatusCode)
+	}
+
+	res := new(poxEnvelopeResponse)
+	if err = xml.Unmarshal(data, res); err != nil {
+		return fmt.Errorf("invalid outcome response: %w", err)
+	}
+	if res.CodeMajor != "success" {
+		return fmt.Errorf("outcome was not accepted: %s %s", res.CodeMajor, res.Description)
+	}
+	return nil
+}
+
+// oauthBodyHashHeader returns the OAuth 1.0 Authorization header with HMAC-SHA1 signature,
+// the query parameters of the url will be included in the signature
+func oauthBodyHashHeader(method, rawUrl string, body []byte, consumerKey, secret string) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	bodyHash := sha1.Sum(body)
+
+	oauthParams := map[string]string{
+		"oauth_body_hash":        base64.StdEncoding.EncodeToString(bodyHash[:]),
+		"oauth_consumer_key":     consumerKey,
+		"oauth_nonce":            strings.ReplaceAll(uuid.NewString(), "-", ""),
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+
+	var pairs []string
+	for k, vals := range u.Query() {
+		for _, v := range vals {
+			pairs = append(pairs, oauthEscape(k)+"="+oauthEscape(v))
+		}
+	}
+	for k, v := range oauthParams {
+		pairs = append(pairs, oauthEscape(k)+"="+oauthEscape(v))
+	}
+	sort.Strings(pairs)
+
+	baseUrl := url.URL{Scheme: strings.ToLower(u.Scheme), Host: strings.ToLower(u.Host), Path: u.EscapedPath()}
+	if baseUrl.Path == "" {
+		baseUrl.Path = "/"
+	}
+	base := strings.Join([]string{
+		oauthEscape(strings.ToUpper(method)),
+		oauthEscape(baseUrl.String()),
+		oauthEscape(strings.Join(pairs, "&")),
+	}, "&")
+
+	mac := hmac.New(sha1.New, []byte(oauthEscape(secret)+"&"))
+	mac.Write([]byte(base))
+	oauthParams["oauth_signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	keys := make([]string, 0, len(oauthParams))
+	for k := range oauthParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, k, oauthEscape(oauthParams[k])))
+	}
+
+	return "OAuth " + strings.Join(parts, ","), nil
+}
+
+// oauthEscape is the percent encoding of RFC 3986, which is required by OAuth 1.0
+func oauthEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
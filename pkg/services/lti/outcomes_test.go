@@ -0,0 +1,113 @@
+package ltiservice
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// verifyOAuthRequest checks the body hash & the signature of the request like an LMS
+func verifyOAuthRequest(r *http.Request, body []byte, secret string) error {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "OAuth ") {
+		return fmt.Errorf("invalid authorization header %q", header)
+	}
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(header, "OAuth "), ",") {
+		kv := strings.SplitN(part, "=", 2)
+		v, err := url.PathUnescape(strings.Trim(kv[1], `"`))
+		if err != nil {
+			return err
+		}
+		params[kv[0]] = v
+	}
+
+	hash := sha1.Sum(body)
+	if params["oauth_body_hash"] != base64.StdEncoding.EncodeToString(hash[:]) {
+		return fmt.Errorf("body hash mismatch")
+	}
+
+	var pairs []string
+	for k, v := range params {
+		if k != "oauth_signature" {
+			pairs = append(pairs, oauthEscape(k)+"="+oauthEscape(v))
+		}
+	}
+	for k, vals := range r.URL.Query() {
+		for _, v := range vals {
+			pairs = append(pairs, oauthEscape(k)+"="+oauthEscape(v))
+		}
+	}
+	sort.Strings(pairs)
+	base := "POST&" + oauthEscape("http://"+r.Host+r.URL.Path) + "&" + oauthEscape(strings.Join(pairs, "&"))
+
+	mac := hmac.New(sha1.New, []byte(oauthEscape(secret)+"&"))
+	mac.Write([]byte(base))
+	if params["oauth_signature"] != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func TestReplaceResult(t *testing.T) {
+	var received poxEnvelopeRequest
+	codeMajor := "success"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := verifyOAuthRequest(r, body, "secret"); err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/xml" {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		if err := xml.Unmarshal(body, &received); err != nil {
+			t.Error(err)
+		}
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<imsx_POXEnvelopeResponse xmlns="%s">
+  <imsx_POXHeader><imsx_POXResponseHeaderInfo>
+    <imsx_version>V1.0</imsx_version>
+    <imsx_statusInfo><imsx_codeMajor>%s</imsx_codeMajor><imsx_description>done</imsx_description></imsx_statusInfo>
+  </imsx_POXResponseHeaderInfo></imsx_POXHeader>
+  <imsx_POXBody><replaceResultResponse/></imsx_POXBody>
+</imsx_POXEnvelopeResponse>`, outcomesNamespace, codeMajor)
+	}))
+	defer server.Close()
+
+	s := newTestService()
+	req := &OutcomeRequest{
+		ServiceUrl:  server.URL + "/outcomes?course=cs%20101&term=2026",
+		SourcedId:   "course-1:user-1:<link>",
+		ConsumerKey: "plugnmeet",
+		Secret:      "secret",
+		Score:       0.75,
+	}
+	if err := s.ReplaceResult(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if received.SourcedId != req.SourcedId || received.Score != "0.75" {
+		t.Errorf("unexpected request %+v", received)
+	}
+
+	codeMajor = "failure"
+	if err := s.ReplaceResult(context.Background(), req); err == nil {
+		t.Error("expected an error when the outcome was not accepted")
+	}
+
+	req.Score = 1.5
+	if err := s.ReplaceResult(context.Background(), req); err == nil {
+		t.Error("expected an error for invalid score")
+	}
+}
@@ -7,7 +7,11 @@ import (
 	"github.com/nats-io/nats.go/jetstream"
 )
 
-const breakoutRoomBucket = Prefix + "breakoutRoom-%s"
+const (
+	breakoutRoomBucket       = Prefix + "breakoutRoom-%s"
+	breakoutRoomAssignBucket = Prefix + "breakoutRoomAssign-%s"
+	breakoutRoomAssignKey    = "assignment"
+)
 
 func (s *NatsService) InsertOrUpdateBreakoutRoom(parentRoomId, bkRoomId string, val []byte) error {
 	kv, err := s.js.CreateOrUpdateKeyValue(s.ctx, jetstream.KeyValueConfig{
@@ -61,6 +65,71 @@ func (s *NatsService) GetBreakoutRoom(parentRoomId, bkRoomId string) ([]byte, er
 	return entry.Value(), nil
 }
 
+// GetBreakoutRoomWithRevision returns the room with the revision of the entry,
+// which can be used with UpdateBreakoutRoom for optimistic concurrency
+func (s *NatsService) GetBreakoutRoomWithRevision(parentRoomId, bkRoomId string) ([]byte, uint64, error) {
+	kv, err := s.js.KeyValue(s.ctx, fmt.Sprintf(breakoutRoomBucket, parentRoomId))
+	switch {
+	case errors.Is(err, jetstream.ErrBucketNotFound):
+		return nil, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+
+	entry, err := kv.Get(s.ctx, bkRoomId)
+	switch {
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		return nil, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+
+	return entry.Value(), entry.Revision(), nil
+}
+
+// UpdateBreakoutRoom will update the room only if the entry is still at the given revision.
+// Returns jetstream.ErrKeyExists if it was modified by someone else in the meantime.
+func (s *NatsService) UpdateBreakoutRoom(parentRoomId, bkRoomId string, val []byte, revision uint64) error {
+	kv, err := s.js.KeyValue(s.ctx, fmt.Sprintf(breakoutRoomBucket, parentRoomId))
+	if err != nil {
+		return err
+	}
+
+	_, err = kv.Update(s.ctx, bkRoomId, val, revision)
+	return err
+}
+
+// SetBreakoutRoomAssignment stores how users were assigned to the breakout rooms of the parent room
+func (s *NatsService) SetBreakoutRoomAssignment(parentRoomId string, val []byte) error {
+	kv, err := s.js.CreateOrUpdateKeyValue(s.ctx, jetstream.KeyValueConfig{
+		Replicas: s.app.NatsInfo.NumReplicas,
+		Bucket:   fmt.Sprintf(breakoutRoomAssignBucket, parentRoomId),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = kv.Put(s.ctx, breakoutRoomAssignKey, val)
+	return err
+}
+
+func (s *NatsService) GetBreakoutRoomAssignment(parentRoomId string) ([]byte, error) {
+	kv, err := s.getKV(fmt.Sprintf(breakoutRoomAssignBucket, parentRoomId))
+	if err != nil || kv == nil {
+		return nil, err
+	}
+
+	entry, err := kv.Get(s.ctx, breakoutRoomAssignKey)
+	switch {
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return entry.Value(), nil
+}
+
 func (s *NatsService) CountBreakoutRooms(parentRoomId string) (int64, error) {
 	kv, err := s.js.KeyValue(s.ctx, fmt.Sprintf(breakoutRoomBucket, parentRoomId))
 	switch {
@@ -107,6 +176,7 @@ func (s *NatsService) GetAllBreakoutRoomsByParentRoomId(parentRoomId string) (ma
 
 func (s *NatsService) DeleteAllBreakoutRoomsByParentRoomId(parentRoomId string) {
 	_ = s.js.DeleteKeyValue(s.ctx, fmt.Sprintf(breakoutRoomBucket, parentRoomId))
+	_ = s.js.DeleteKeyValue(s.ctx, fmt.Sprintf(breakoutRoomAssignBucket, parentRoomId))
 }
 
 func (s *NatsService) GetBreakoutRoomIdsByParentRoomId(parentRoomId string) ([]string, error) {
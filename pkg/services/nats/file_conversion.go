@@ -0,0 +1,64 @@
+package natsservice
+
+import (
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	FileConversionStream          = Prefix + "fileConversionJobs"
+	fileConversionConsumerDurable = "fileConversionWorker"
+	fileConversionMaxDeliver      = 3
+	fileConversionResultSubject   = Prefix + "fileConversionResult."
+)
+
+// CreateFileConversionStream will create the work-queue stream for file conversion jobs.
+// It returns the shared consumer of the stream.
+// ackWait should be longer than the timeout of a job, as the job will be redelivered after it.
+func (s *NatsService) CreateFileConversionStream(ackWait time.Duration) (jetstream.Consumer, error) {
+	stream, err := s.js.CreateOrUpdateStream(s.ctx, jetstream.StreamConfig{
+		Name:      FileConversionStream,
+		Replicas:  s.app.NatsInfo.NumReplicas,
+		Retention: jetstream.WorkQueuePolicy,
+		Subjects:  []string{FileConversionStream},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.CreateOrUpdateConsumer(s.ctx, jetstream.ConsumerConfig{
+		Durable:   fileConversionConsumerDurable,
+		AckPolicy: jetstream.AckExplicitPolicy,
+		AckWait:   ackWait,
+		// only if the server which was converting it has gone
+		MaxDeliver: fileConversionMaxDeliver,
+	})
+}
+
+func (s *NatsService) PublishFileConversionJob(data []byte) error {
+	return s.publish(FileConversionStream, data)
+}
+
+// FileConversionProgressEvent is sent with the progress of a file conversion job.
+// It isn't part of NatsMsgServerToClientEvents of the protocol yet,
+// so the clients which don't know it will ignore it like any other unknown event.
+const FileConversionProgressEvent plugnmeet.NatsMsgServerToClientEvents = 100
+
+// BroadcastFileConversionProgress will send the progress of a conversion job to everyone in the room
+func (s *NatsService) BroadcastFileConversionProgress(roomId string, data []byte) error {
+	return s.BroadcastSystemEventToRoom(FileConversionProgressEvent, roomId, data, nil)
+}
+
+// SubscribeFileConversionResult subscribes to the final result of the job,
+// it must be done before publishing the job to not miss the result.
+func (s *NatsService) SubscribeFileConversionResult(jobId string) (*nats.Subscription, error) {
+	return s.nc.SubscribeSync(fileConversionResultSubject + jobId)
+}
+
+// PublishFileConversionResult will send the final result of the job to the server which is waiting for it, if any
+func (s *NatsService) PublishFileConversionResult(jobId string, data []byte) error {
+	return s.nc.Publish(fileConversionResultSubject+jobId, data)
+}
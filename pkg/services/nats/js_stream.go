@@ -1,6 +1,7 @@
 package natsservice
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/nats-io/nats.go/jetstream"
@@ -28,3 +29,37 @@ func (s *NatsService) CreateRoomNatsStreams(roomId string) error {
 func (s *NatsService) DeleteRoomNatsStream(roomId string) error {
 	return s.js.DeleteStream(s.ctx, roomId)
 }
+
+// GetRoomChatMessages returns all the chat messages from the room stream.
+// It should be called before deleting the stream using DeleteRoomNatsStream
+func (s *NatsService) GetRoomChatMessages(roomId string) ([]*jetstream.RawStreamMsg, error) {
+	stream, err := s.js.Stream(s.ctx, roomId)
+	switch {
+	case errors.Is(err, jetstream.ErrStreamNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	info, err := stream.Info(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*jetstream.RawStreamMsg
+	subject := fmt.Sprintf("%s:%s.*", roomId, s.app.NatsInfo.Subjects.Chat)
+	seq := info.State.FirstSeq
+	for seq <= info.State.LastSeq {
+		// will return the next chat message from this sequence
+		msg, err := stream.GetMsg(s.ctx, seq, jetstream.WithGetMsgSubject(subject))
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		seq = msg.Sequence + 1
+		list = append(list, msg)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,38 @@
+package natsservice
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	LtiGradePassbackStream          = Prefix + "ltiGradePassbackJobs"
+	ltiGradePassbackConsumerDurable = "ltiGradePassbackWorker"
+)
+
+// CreateLtiGradePassbackStream will create the work-queue stream for sending the grades to the LMS.
+// It returns the shared consumer of the stream.
+// Failed jobs will be redelivered with delay by the worker, at most maxDeliver times.
+func (s *NatsService) CreateLtiGradePassbackStream(maxDeliver int) (jetstream.Consumer, error) {
+	stream, err := s.js.CreateOrUpdateStream(s.ctx, jetstream.StreamConfig{
+		Name:      LtiGradePassbackStream,
+		Replicas:  s.app.NatsInfo.NumReplicas,
+		Retention: jetstream.WorkQueuePolicy,
+		Subjects:  []string{LtiGradePassbackStream},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.CreateOrUpdateConsumer(s.ctx, jetstream.ConsumerConfig{
+		Durable:    ltiGradePassbackConsumerDurable,
+		AckPolicy:  jetstream.AckExplicitPolicy,
+		AckWait:    time.Minute,
+		MaxDeliver: maxDeliver,
+	})
+}
+
+func (s *NatsService) PublishLtiGradePassbackJob(data []byte) error {
+	return s.publish(LtiGradePassbackStream, data)
+}
@@ -81,3 +81,22 @@ func (ncs *NatsCacheService) convertTextToUint64(text string) uint64 {
 	value, _ := strconv.ParseUint(text, 10, 64)
 	return value
 }
+
+// GetCacheStats returns number of the cached rooms & online users of those rooms
+func (ncs *NatsCacheService) GetCacheStats() (rooms int, onlineUsers int) {
+	ncs.roomLock.RLock()
+	rooms = len(ncs.roomsInfoStore)
+	ncs.roomLock.RUnlock()
+
+	ncs.userLock.RLock()
+	defer ncs.userLock.RUnlock()
+	for _, users := range ncs.roomUsersStatusStore {
+		for _, entry := range users {
+			if entry.Status == UserStatusOnline {
+				onlineUsers++
+			}
+		}
+	}
+
+	return rooms, onlineUsers
+}
@@ -101,3 +101,9 @@ func (s *NatsService) UnmarshalUserMetadata(metadata string) (*plugnmeet.UserMet
 
 	return m, nil
 }
+
+// GetCacheStats returns number of active rooms & online users
+// known by the cache of this instance
+func (s *NatsService) GetCacheStats() (int, int) {
+	return s.cs.GetCacheStats()
+}
@@ -1,6 +1,7 @@
 package natsservice
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -8,11 +9,23 @@ import (
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 )
 
+// RecorderInfoLabelsKey is the key where a recorder can advertise its labels
+// as a JSON object, for example: {"region":"eu","transcoding":"cpu"}
+const RecorderInfoLabelsKey = "labels"
+
 type RecorderInfo struct {
 	RecorderId      string
 	MaxLimit        int64
 	CurrentProgress int64
 	LastPing        int64
+	Labels          map[string]string
+}
+
+// HasFreeSlot checks if the recorder can accept one more task along with the reserved slots.
+// A recorder which hasn't reported its max limit is considered unavailable,
+// because its load can't be compared with the others.
+func (r *RecorderInfo) HasFreeSlot(reserved int64) bool {
+	return r.MaxLimit > 0 && r.CurrentProgress+reserved < r.MaxLimit
 }
 
 func (s *NatsService) GetAllActiveRecorders() []*RecorderInfo {
@@ -48,5 +61,11 @@ func (s *NatsService) GetRecorderInfo(recorderId string) (*RecorderInfo, error)
 	info.CurrentProgress, _ = s.getInt64Value(kv, fmt.Sprintf("%d", plugnmeet.RecorderInfoKeys_RECORDER_INFO_CURRENT_PROGRESS))
 	info.LastPing, _ = s.getInt64Value(kv, fmt.Sprintf("%d", plugnmeet.RecorderInfoKeys_RECORDER_INFO_LAST_PING))
 
+	if labels, _ := s.getStringValue(kv, RecorderInfoLabelsKey); labels != "" {
+		if err = json.Unmarshal([]byte(labels), &info.Labels); err != nil {
+			s.logger.WithError(err).WithField("recorderId", recorderId).Warnln("invalid recorder labels")
+		}
+	}
+
 	return info, nil
 }
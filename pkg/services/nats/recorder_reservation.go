@@ -0,0 +1,136 @@
+package natsservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	recorderReservationBucket = Prefix + "recorderReservations"
+	maxReservationAttempts    = 5
+)
+
+// recorderReservations holds the reservation id with its expiry time in unix milliseconds
+type recorderReservations map[string]int64
+
+// GetRecorderReservations returns the number of active reservations of the recorder
+func (s *NatsService) GetRecorderReservations(recorderId string) (int64, error) {
+	kv, err := s.getKV(recorderReservationBucket)
+	if err != nil || kv == nil {
+		return 0, err
+	}
+
+	res, _, err := getRecorderReservations(s.ctx, kv, recorderId)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(res)), nil
+}
+
+// ReserveRecorderSlot will reserve a slot in the recorder using the revision of the key,
+// so that two concurrent requests can't reserve the last free slot.
+// It returns false if the recorder doesn't have any free slot.
+func (s *NatsService) ReserveRecorderSlot(recorder *RecorderInfo, reservationId string, ttl time.Duration) (bool, error) {
+	kv, err := s.js.CreateOrUpdateKeyValue(s.ctx, jetstream.KeyValueConfig{
+		Replicas: s.app.NatsInfo.NumReplicas,
+		Bucket:   recorderReservationBucket,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return reserveRecorderSlot(s.ctx, kv, recorder, reservationId, ttl)
+}
+
+func reserveRecorderSlot(ctx context.Context, kv jetstream.KeyValue, recorder *RecorderInfo, reservationId string, ttl time.Duration) (bool, error) {
+	for i := 0; i < maxReservationAttempts; i++ {
+		res, revision, err := getRecorderReservations(ctx, kv, recorder.RecorderId)
+		if err != nil {
+			return false, err
+		}
+		if !recorder.HasFreeSlot(int64(len(res))) {
+			return false, nil
+		}
+		res[reservationId] = time.Now().Add(ttl).UnixMilli()
+
+		marshal, err := json.Marshal(res)
+		if err != nil {
+			return false, err
+		}
+		if revision == 0 {
+			_, err = kv.Create(ctx, recorder.RecorderId, marshal)
+		} else {
+			_, err = kv.Update(ctx, recorder.RecorderId, marshal, revision)
+		}
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			// someone else has changed it, try again with the latest value
+			continue
+		}
+		return err == nil, err
+	}
+
+	return false, errors.New("recorder reservation was modified concurrently")
+}
+
+// ReleaseRecorderSlot will remove the reservation from the recorder
+func (s *NatsService) ReleaseRecorderSlot(recorderId, reservationId string) error {
+	kv, err := s.getKV(recorderReservationBucket)
+	if err != nil || kv == nil {
+		return err
+	}
+
+	for i := 0; i < maxReservationAttempts; i++ {
+		res, revision, err := getRecorderReservations(s.ctx, kv, recorderId)
+		if err != nil {
+			return err
+		}
+		if _, ok := res[reservationId]; !ok || revision == 0 {
+			return nil
+		}
+		delete(res, reservationId)
+
+		marshal, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		_, err = kv.Update(s.ctx, recorderId, marshal, revision)
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			continue
+		}
+		return err
+	}
+
+	return errors.New("recorder reservation was modified concurrently")
+}
+
+// getRecorderReservations returns the unexpired reservations of the recorder with the revision of the key.
+// Revision will be 0 if the key doesn't exist yet.
+func getRecorderReservations(ctx context.Context, kv jetstream.KeyValue, recorderId string) (recorderReservations, uint64, error) {
+	res := make(recorderReservations)
+	entry, err := kv.Get(ctx, recorderId)
+	switch {
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		return res, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+
+	if len(entry.Value()) > 0 {
+		if err = json.Unmarshal(entry.Value(), &res); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	for id, expiry := range res {
+		if expiry < now {
+			delete(res, id)
+		}
+	}
+
+	return res, entry.Revision(), nil
+}
@@ -0,0 +1,178 @@
+package natsservice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type testKvEntry struct {
+	jetstream.KeyValueEntry
+	value    []byte
+	revision uint64
+}
+
+func (e *testKvEntry) Value() []byte    { return e.value }
+func (e *testKvEntry) Revision() uint64 { return e.revision }
+
+// testKv keeps values in memory & checks revisions like the real bucket,
+// only the methods used by the reservation are implemented.
+type testKv struct {
+	jetstream.KeyValue
+	entries map[string]*testKvEntry
+	// beforeWrite is called before every Create or Update to simulate concurrent changes
+	beforeWrite func(kv *testKv)
+	writes      int
+}
+
+func newTestKv() *testKv {
+	return &testKv{entries: make(map[string]*testKvEntry)}
+}
+
+func (kv *testKv) Get(_ context.Context, key string) (jetstream.KeyValueEntry, error) {
+	e, ok := kv.entries[key]
+	if !ok {
+		return nil, jetstream.ErrKeyNotFound
+	}
+	return e, nil
+}
+
+func (kv *testKv) put(key string, value []byte) uint64 {
+	var revision uint64 = 1
+	if e, ok := kv.entries[key]; ok {
+		revision = e.revision + 1
+	}
+	kv.entries[key] = &testKvEntry{value: value, revision: revision}
+	return revision
+}
+
+func (kv *testKv) Create(_ context.Context, key string, value []byte, _ ...jetstream.KVCreateOpt) (uint64, error) {
+	kv.writes++
+	if kv.beforeWrite != nil {
+		kv.beforeWrite(kv)
+	}
+	if _, ok := kv.entries[key]; ok {
+		return 0, jetstream.ErrKeyExists
+	}
+	return kv.put(key, value), nil
+}
+
+func (kv *testKv) Update(_ context.Context, key string, value []byte, revision uint64) (uint64, error) {
+	kv.writes++
+	if kv.beforeWrite != nil {
+		kv.beforeWrite(kv)
+	}
+	if e, ok := kv.entries[key]; !ok || e.revision != revision {
+		return 0, jetstream.ErrKeyExists
+	}
+	return kv.put(key, value), nil
+}
+
+func (kv *testKv) setReservations(t *testing.T, recorderId string, res recorderReservations) {
+	marshal, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv.put(recorderId, marshal)
+}
+
+func TestReserveRecorderSlot(t *testing.T) {
+	ctx := context.Background()
+	recorder := &RecorderInfo{RecorderId: "rec-1", MaxLimit: 2}
+
+	kv := newTestKv()
+	for _, id := range []string{"a", "b"} {
+		ok, err := reserveRecorderSlot(ctx, kv, recorder, id, time.Minute)
+		if err != nil || !ok {
+			t.Fatalf("expected reservation %s, got %v %v", id, ok, err)
+		}
+	}
+	ok, err := reserveRecorderSlot(ctx, kv, recorder, "c", time.Minute)
+	if err != nil || ok {
+		t.Errorf("recorder is full, expected no reservation, got %v %v", ok, err)
+	}
+
+	// expired reservations won't be counted
+	kv = newTestKv()
+	kv.setReservations(t, recorder.RecorderId, recorderReservations{
+		"old": time.Now().Add(-time.Second).UnixMilli(),
+		"a":   time.Now().Add(time.Minute).UnixMilli(),
+	})
+	ok, err = reserveRecorderSlot(ctx, kv, recorder, "b", time.Minute)
+	if err != nil || !ok {
+		t.Errorf("expected reservation with expired one, got %v %v", ok, err)
+	}
+	res, _, _ := getRecorderReservations(ctx, kv, recorder.RecorderId)
+	if _, found := res["old"]; found || len(res) != 2 {
+		t.Errorf("unexpected reservations %v", res)
+	}
+
+	// running tasks are counted as well
+	busy := &RecorderInfo{RecorderId: "rec-2", MaxLimit: 2, CurrentProgress: 2}
+	if ok, _ = reserveRecorderSlot(ctx, newTestKv(), busy, "a", time.Minute); ok {
+		t.Error("expected no reservation in busy recorder")
+	}
+
+	// max limit isn't reported
+	unknown := &RecorderInfo{RecorderId: "rec-3"}
+	if ok, _ = reserveRecorderSlot(ctx, newTestKv(), unknown, "a", time.Minute); ok {
+		t.Error("expected no reservation in recorder without max limit")
+	}
+}
+
+func TestReserveRecorderSlotConcurrent(t *testing.T) {
+	ctx := context.Background()
+	recorder := &RecorderInfo{RecorderId: "rec-1", MaxLimit: 1}
+
+	// another request takes the last slot between our read & write
+	kv := newTestKv()
+	kv.beforeWrite = func(kv *testKv) {
+		kv.beforeWrite = nil
+		kv.setReservations(t, recorder.RecorderId, recorderReservations{
+			"other": time.Now().Add(time.Minute).UnixMilli(),
+		})
+	}
+	ok, err := reserveRecorderSlot(ctx, kv, recorder, "mine", time.Minute)
+	if err != nil || ok {
+		t.Errorf("expected last slot to be taken by other request, got %v %v", ok, err)
+	}
+	res, _, _ := getRecorderReservations(ctx, kv, recorder.RecorderId)
+	if _, found := res["mine"]; found {
+		t.Errorf("reservation overbooked the recorder: %v", res)
+	}
+
+	// with a free slot left it should retry with the latest value & keep both
+	recorder.MaxLimit = 2
+	kv = newTestKv()
+	kv.setReservations(t, recorder.RecorderId, recorderReservations{})
+	kv.beforeWrite = func(kv *testKv) {
+		kv.beforeWrite = nil
+		kv.setReservations(t, recorder.RecorderId, recorderReservations{
+			"other": time.Now().Add(time.Minute).UnixMilli(),
+		})
+	}
+	ok, err = reserveRecorderSlot(ctx, kv, recorder, "mine", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("expected reservation after retry, got %v %v", ok, err)
+	}
+	if kv.writes != 2 {
+		t.Errorf("expected 2 write attempts, got %d", kv.writes)
+	}
+	res, _, _ = getRecorderReservations(ctx, kv, recorder.RecorderId)
+	if len(res) != 2 {
+		t.Errorf("expected both reservations, got %v", res)
+	}
+
+	// gives up if the key keeps changing
+	kv = newTestKv()
+	kv.setReservations(t, recorder.RecorderId, recorderReservations{})
+	kv.beforeWrite = func(kv *testKv) {
+		kv.setReservations(t, recorder.RecorderId, recorderReservations{})
+	}
+	if ok, err = reserveRecorderSlot(ctx, kv, recorder, "mine", time.Minute); err == nil || ok {
+		t.Errorf("expected error after %d attempts, got %v %v", maxReservationAttempts, ok, err)
+	}
+}
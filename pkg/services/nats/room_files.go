@@ -1,6 +1,7 @@
 package natsservice
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -18,6 +19,13 @@ const (
 // AddRoomFile adds or updates a file's metadata in the room's file bucket.
 // The fileId will be used as the key.
 func (s *NatsService) AddRoomFile(roomId string, meta *plugnmeet.RoomUploadedFileMetadata) error {
+	return s.AddRoomFileWithExtra(roomId, meta, nil)
+}
+
+// AddRoomFileWithExtra works like AddRoomFile but stores the fields of extra
+// in the same record, for the information RoomUploadedFileMetadata can't carry.
+// Those can be read back with GetRoomFileWithExtra.
+func (s *NatsService) AddRoomFileWithExtra(roomId string, meta *plugnmeet.RoomUploadedFileMetadata, extra interface{}) error {
 	kv, err := s.js.CreateOrUpdateKeyValue(s.ctx, jetstream.KeyValueConfig{
 		Replicas: s.app.NatsInfo.NumReplicas,
 		Bucket:   fmt.Sprintf(RoomFilesBucket, roomId),
@@ -26,7 +34,7 @@ func (s *NatsService) AddRoomFile(roomId string, meta *plugnmeet.RoomUploadedFil
 		return err
 	}
 
-	metaBytes, err := protojson.Marshal(meta)
+	metaBytes, err := marshalRoomFile(meta, extra)
 	if err != nil {
 		return fmt.Errorf("failed to marshal file metadata: %w", err)
 	}
@@ -53,6 +61,12 @@ func (s *NatsService) DeleteRoomFile(roomId, fileId string) error {
 
 // GetRoomFile retrieves a specific file's metadata.
 func (s *NatsService) GetRoomFile(roomId, fileId string) (*plugnmeet.RoomUploadedFileMetadata, error) {
+	return s.GetRoomFileWithExtra(roomId, fileId, nil)
+}
+
+// GetRoomFileWithExtra retrieves a specific file's metadata & decodes
+// the extra fields stored by AddRoomFileWithExtra into extra.
+func (s *NatsService) GetRoomFileWithExtra(roomId, fileId string, extra interface{}) (*plugnmeet.RoomUploadedFileMetadata, error) {
 	kv, err := s.js.KeyValue(s.ctx, fmt.Sprintf(RoomFilesBucket, roomId))
 	switch {
 	case errors.Is(err, jetstream.ErrBucketNotFound):
@@ -69,8 +83,7 @@ func (s *NatsService) GetRoomFile(roomId, fileId string) (*plugnmeet.RoomUploade
 		return nil, err
 	}
 
-	meta := new(plugnmeet.RoomUploadedFileMetadata)
-	err = protojson.Unmarshal(entry.Value(), meta)
+	meta, err := unmarshalRoomFile(entry.Value(), extra)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal file metadata: %w", err)
 	}
@@ -96,8 +109,7 @@ func (s *NatsService) GetAllRoomFiles(roomId string) (map[string]*plugnmeet.Room
 	files := make(map[string]*plugnmeet.RoomUploadedFileMetadata)
 	for k := range keys.Keys() {
 		if entry, err := kv.Get(s.ctx, k); err == nil && entry != nil {
-			meta := new(plugnmeet.RoomUploadedFileMetadata)
-			err = protojson.Unmarshal(entry.Value(), meta)
+			meta, err := unmarshalRoomFile(entry.Value(), nil)
 			if err == nil {
 				files[k] = meta
 			}
@@ -111,3 +123,39 @@ func (s *NatsService) GetAllRoomFiles(roomId string) (map[string]*plugnmeet.Room
 func (s *NatsService) DeleteAllRoomFiles(roomId string) error {
 	return s.js.DeleteKeyValue(s.ctx, fmt.Sprintf(RoomFilesBucket, roomId))
 }
+
+// marshalRoomFile adds the fields of extra next to the fields of meta
+func marshalRoomFile(meta *plugnmeet.RoomUploadedFileMetadata, extra interface{}) ([]byte, error) {
+	metaBytes, err := protojson.Marshal(meta)
+	if err != nil || extra == nil {
+		return metaBytes, err
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(extraBytes, &fields); err != nil {
+		return nil, err
+	}
+	// fields of meta always win
+	if err = json.Unmarshal(metaBytes, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalRoomFile ignores the extra fields for meta & decodes them into extra if not nil
+func unmarshalRoomFile(data []byte, extra interface{}) (*plugnmeet.RoomUploadedFileMetadata, error) {
+	meta := new(plugnmeet.RoomUploadedFileMetadata)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, meta); err != nil {
+		return nil, err
+	}
+	if extra != nil {
+		if err := json.Unmarshal(data, extra); err != nil {
+			return nil, err
+		}
+	}
+	return meta, nil
+}
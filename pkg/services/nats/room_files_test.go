@@ -0,0 +1,61 @@
+package natsservice
+
+import (
+	"testing"
+
+	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type testRoomFileExtra struct {
+	Variants []string `json:"variants,omitempty"`
+	HasText  bool     `json:"has_text,omitempty"`
+	// FileId must not override the value of the metadata
+	FileId string `json:"fileId,omitempty"`
+}
+
+func TestRoomFileWithExtra(t *testing.T) {
+	pages := int32(3)
+	meta := &plugnmeet.RoomUploadedFileMetadata{
+		FileId:     "file-1",
+		FileName:   "slides.pdf",
+		FilePath:   "sid/file-1",
+		FileType:   plugnmeet.RoomUploadedFileType_WHITEBOARD_CONVERTED_FILE,
+		TotalPages: &pages,
+	}
+
+	data, err := marshalRoomFile(meta, &testRoomFileExtra{
+		Variants: []string{"high_dpi", "screen"},
+		HasText:  true,
+		FileId:   "other",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	extra := new(testRoomFileExtra)
+	got, err := unmarshalRoomFile(data, extra)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.FileId != "file-1" || got.FilePath != "sid/file-1" || got.GetTotalPages() != 3 {
+		t.Errorf("unexpected metadata %v", got)
+	}
+	if len(extra.Variants) != 2 || !extra.HasText || extra.FileId != "file-1" {
+		t.Errorf("unexpected extra %+v", extra)
+	}
+
+	// records without extra fields
+	data, _ = protojson.Marshal(meta)
+	extra = new(testRoomFileExtra)
+	if got, err = unmarshalRoomFile(data, extra); err != nil || got.FileName != "slides.pdf" {
+		t.Fatalf("unexpected result %v %v", got, err)
+	}
+	if len(extra.Variants) != 0 || extra.HasText {
+		t.Errorf("expected empty extra, got %+v", extra)
+	}
+
+	if _, err = unmarshalRoomFile([]byte("invalid"), nil); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
@@ -4,22 +4,26 @@ import (
 	"fmt"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 )
 
 // GetRoomInfo retrieves the room information for the given roomId
-func (s *NatsService) GetRoomInfo(roomId string) (*plugnmeet.NatsKvRoomInfo, error) {
+func (s *NatsService) GetRoomInfo(roomId string) (info *plugnmeet.NatsKvRoomInfo, err error) {
 	// try to get cached room info first
-	if info := s.cs.GetCachedRoomInfo(roomId); info != nil {
+	if info = s.cs.GetCachedRoomInfo(roomId); info != nil {
 		return info, nil
 	}
 
+	s, span := s.startSpan("GetRoomInfo", tracing.RoomIdKey.String(roomId))
+	defer func() { tracing.End(span, err) }()
+
 	bucket := fmt.Sprintf(RoomInfoBucket, roomId)
 	kv, err := s.getKV(bucket)
 	if err != nil || kv == nil {
 		return nil, err
 	}
 
-	info := new(plugnmeet.NatsKvRoomInfo)
+	info = new(plugnmeet.NatsKvRoomInfo)
 	info.DbTableId, _ = s.getUint64Value(kv, RoomDbTableIdKey)
 	info.RoomId, _ = s.getStringValue(kv, RoomIdKey)
 	info.RoomSid, _ = s.getStringValue(kv, RoomSidKey)
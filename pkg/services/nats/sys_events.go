@@ -10,6 +10,11 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// PollLeaderboardEvent is sent to the room with the result of a non-anonymous quiz after closing it.
+// The protocol doesn't have any event for it yet, so a value far from the existing ones is used
+// & the clients which don't know about it will ignore it.
+const PollLeaderboardEvent plugnmeet.NatsMsgServerToClientEvents = 1001
+
 func (s *NatsService) BroadcastSystemEventToRoom(event plugnmeet.NatsMsgServerToClientEvents, roomId string, data interface{}, toUserId *string) error {
 	var msg string
 	var err error
@@ -46,7 +51,7 @@ func (s *NatsService) BroadcastSystemEventToRoom(event plugnmeet.NatsMsgServerTo
 		sub = fmt.Sprintf("%s:%s.%s.system", roomId, s.app.NatsInfo.Subjects.SystemPrivate, *toUserId)
 	}
 
-	_, err = s.js.Publish(s.ctx, sub, message)
+	err = s.publish(sub, message)
 	if err != nil {
 		return err
 	}
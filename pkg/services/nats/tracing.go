@@ -0,0 +1,45 @@
+package natsservice
+
+import (
+	"context"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithContext returns a copy of the service whose spans will be children of the span in ctx.
+// The service context itself will be kept, so cancellation during shutdown will work as before.
+func (s *NatsService) WithContext(ctx context.Context) *NatsService {
+	ns := *s
+	ns.ctx = tracing.ContextWithSpanOf(s.ctx, ctx)
+	return &ns
+}
+
+// startSpan starts a span for a KV or stream operation.
+// The returned service must be used for the operation, so that nested calls will be linked.
+func (s *NatsService) startSpan(name string, attrs ...attribute.KeyValue) (*NatsService, trace.Span) {
+	ctx, span := tracing.Tracer().Start(s.ctx, "nats."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.MessagingSystemKey.String("nats")),
+		trace.WithAttributes(attrs...))
+	ns := *s
+	ns.ctx = ctx
+	return &ns, span
+}
+
+// publish will publish the data to the stream with the trace context in the headers
+func (s *NatsService) publish(subject string, data []byte) error {
+	ns, span := s.startSpan("publish", semconv.MessagingDestinationName(subject))
+	msg := &nats.Msg{
+		Subject: subject,
+		Data:    data,
+	}
+	tracing.InjectNatsHeader(ns.ctx, msg)
+
+	_, err := ns.js.PublishMsg(ns.ctx, msg)
+	tracing.End(span, err)
+	return err
+}
@@ -6,17 +6,20 @@ import (
 	"strconv"
 
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 	"github.com/nats-io/nats.go/jetstream"
 )
 
 // GetRoomUserStatus retrieves the status of a user in a specific room.
 // Returns an empty string if the user or room is not found.
-func (s *NatsService) GetRoomUserStatus(roomId, userId string) (string, error) {
-	var status string
+func (s *NatsService) GetRoomUserStatus(roomId, userId string) (status string, err error) {
 	if status, _ = s.cs.GetCachedRoomUserStatus(roomId, userId); status != "" {
 		return status, nil
 	}
 
+	s, span := s.startSpan("GetRoomUserStatus", tracing.RoomIdKey.String(roomId), tracing.UserIdKey.String(userId))
+	defer func() { tracing.End(span, err) }()
+
 	bucket := fmt.Sprintf(RoomUsersBucket, roomId)
 	kv, err := s.getKV(bucket)
 	if err != nil || kv == nil {
@@ -31,18 +34,21 @@ func (s *NatsService) GetRoomUserStatus(roomId, userId string) (string, error) {
 
 // GetUserInfo retrieves detailed information about a user in a specific room.
 // Returns nil if the user or room is not found.
-func (s *NatsService) GetUserInfo(roomId, userId string) (*plugnmeet.NatsKvUserInfo, error) {
-	if info := s.cs.GetUserInfo(roomId, userId); info != nil {
+func (s *NatsService) GetUserInfo(roomId, userId string) (info *plugnmeet.NatsKvUserInfo, err error) {
+	if info = s.cs.GetUserInfo(roomId, userId); info != nil {
 		return info, nil
 	}
 
+	s, span := s.startSpan("GetUserInfo", tracing.RoomIdKey.String(roomId), tracing.UserIdKey.String(userId))
+	defer func() { tracing.End(span, err) }()
+
 	bucket := fmt.Sprintf(UserInfoBucket, roomId, userId)
 	kv, err := s.getKV(bucket)
 	if err != nil || kv == nil {
 		return nil, err
 	}
 
-	info := &plugnmeet.NatsKvUserInfo{}
+	info = &plugnmeet.NatsKvUserInfo{}
 	info.UserId, _ = s.getStringValue(kv, UserIdKey)
 	info.UserSid, _ = s.getStringValue(kv, UserSidKey)
 	info.Name, _ = s.getStringValue(kv, UserNameKey)
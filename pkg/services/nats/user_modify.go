@@ -7,7 +7,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
+	"github.com/mynaparrot/plugnmeet-server/pkg/tracing"
 	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Constants for bucket naming and user metadata keys
@@ -41,7 +43,10 @@ const (
 )
 
 // AddUser adds a new user to a room and stores their metadata
-func (s *NatsService) AddUser(roomId, userId, name string, isAdmin, isPresenter bool, metadata *plugnmeet.UserMetadata) error {
+func (s *NatsService) AddUser(roomId, userId, name string, isAdmin, isPresenter bool, metadata *plugnmeet.UserMetadata) (err error) {
+	s, span := s.startSpan("AddUser", tracing.RoomIdKey.String(roomId), tracing.UserIdKey.String(userId))
+	defer func() { tracing.End(span, err) }()
+
 	// Create or update the room users bucket
 	bucket := fmt.Sprintf(RoomUsersBucket, roomId)
 	roomKV, err := s.js.CreateOrUpdateKeyValue(s.ctx, jetstream.KeyValueConfig{
@@ -101,7 +106,10 @@ func (s *NatsService) AddUser(roomId, userId, name string, isAdmin, isPresenter
 }
 
 // UpdateUserStatus updates the status of a user in a room
-func (s *NatsService) UpdateUserStatus(roomId, userId string, status string) error {
+func (s *NatsService) UpdateUserStatus(roomId, userId string, status string) (err error) {
+	s, span := s.startSpan("UpdateUserStatus", tracing.RoomIdKey.String(roomId), tracing.UserIdKey.String(userId), attribute.String("pnm.user_status", status))
+	defer func() { tracing.End(span, err) }()
+
 	// Retrieve the room users bucket
 	roomKV, err := s.js.KeyValue(s.ctx, fmt.Sprintf(RoomUsersBucket, roomId))
 	if err != nil {
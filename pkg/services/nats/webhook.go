@@ -2,12 +2,31 @@ package natsservice
 
 import (
 	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
 )
 
-const WebhookKvKey = Prefix + "webhookData"
-const WebhookCleanupSubject = Prefix + "webhookCleanup"
+const (
+	WebhookKvKey            = Prefix + "webhookData"
+	WebhookEndpointKvKey    = Prefix + "webhookEndpoints"
+	WebhookDeliveryStream   = Prefix + "webhookDeliveries"
+	WebhookDeadLetterStream = Prefix + "webhookDeadLetters"
+	webhookDeliveryDurable  = "webhookDeliveryWorker"
+	// WebhookDeliveryPartitions is the number of deliveries can be sent concurrently by the cluster.
+	// Every partition will be consumed in order, one delivery at a time.
+	WebhookDeliveryPartitions = 20
+	// webhookEndpointLaneSubject has the deliveries of a failing endpoint,
+	// so the endpoint can wait for its backoff without blocking the partitions
+	webhookEndpointLaneSubject = WebhookDeliveryStream + ".lane"
+	webhookEndpointLaneDurable = webhookDeliveryDurable + "_lane_"
+	// webhookEndpointLaneInactiveThreshold will remove the consumer of the lane
+	// after no server has consumed from it for a while
+	webhookEndpointLaneInactiveThreshold = time.Minute * 10
+)
 
 func (s *NatsService) AddWebhookData(roomId string, val []byte) error {
 	kv, err := s.js.CreateOrUpdateKeyValue(s.ctx, jetstream.KeyValueConfig{
@@ -61,3 +80,274 @@ func (s *NatsService) DeleteWebhookData(roomId string) error {
 
 	return kv.Purge(s.ctx, roomId)
 }
+
+// CreateWebhookDeliveryStreams will create the work-queue stream for outbound webhook deliveries
+// & the dead-letter stream for deliveries that failed after all the attempts.
+// It returns the shared consumers of the partitions of the delivery stream,
+// each of them allows only one pending delivery, so the order will be kept across the cluster.
+func (s *NatsService) CreateWebhookDeliveryStreams(deadLetterMaxAge time.Duration) ([]jetstream.Consumer, error) {
+	stream, err := s.js.CreateOrUpdateStream(s.ctx, jetstream.StreamConfig{
+		Name:      WebhookDeliveryStream,
+		Replicas:  s.app.NatsInfo.NumReplicas,
+		Retention: jetstream.WorkQueuePolicy,
+		// the subject without partition is from the older versions
+		Subjects: []string{WebhookDeliveryStream, WebhookDeliveryStream + ".*", webhookEndpointLaneSubject + ".*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.js.CreateOrUpdateStream(s.ctx, jetstream.StreamConfig{
+		Name:     WebhookDeadLetterStream,
+		Replicas: s.app.NatsInfo.NumReplicas,
+		Subjects: []string{WebhookDeadLetterStream},
+		MaxAge:   deadLetterMaxAge,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the backoff state of the endpoints will be removed if there was no failure for a while
+	_, err = s.js.CreateOrUpdateKeyValue(s.ctx, jetstream.KeyValueConfig{
+		Replicas: s.app.NatsInfo.NumReplicas,
+		Bucket:   WebhookEndpointKvKey,
+		TTL:      s.app.Client.WebhookConf.MaxBackoff * 2,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the consumer of the older versions receives all the subjects,
+	// which isn't allowed together with the partition consumers in a work-queue stream
+	if err = stream.DeleteConsumer(s.ctx, webhookDeliveryDurable); err != nil && !errors.Is(err, jetstream.ErrConsumerNotFound) {
+		return nil, err
+	}
+
+	consumers := make([]jetstream.Consumer, 0, WebhookDeliveryPartitions)
+	for i := 0; i < WebhookDeliveryPartitions; i++ {
+		filters := []string{webhookDeliverySubject(i)}
+		if i == 0 {
+			filters = append(filters, WebhookDeliveryStream)
+		}
+		cons, err := stream.CreateOrUpdateConsumer(s.ctx, jetstream.ConsumerConfig{
+			Durable:        fmt.Sprintf("%s_%d", webhookDeliveryDurable, i),
+			FilterSubjects: filters,
+			AckPolicy:      jetstream.AckExplicitPolicy,
+			AckWait:        time.Minute,
+			// the next delivery of the partition won't be sent until this one
+			// was acknowledged, including the waiting time of the retries
+			MaxAckPending: 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, cons)
+	}
+
+	return consumers, nil
+}
+
+// PublishWebhookDelivery will publish the delivery to the partition of the key,
+// the deliveries with the same key will be sent in the same order
+func (s *NatsService) PublishWebhookDelivery(key string, data []byte) error {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return s.publish(webhookDeliverySubject(int(h.Sum32()%WebhookDeliveryPartitions)), data)
+}
+
+func webhookDeliverySubject(partition int) string {
+	return fmt.Sprintf("%s.%d", WebhookDeliveryStream, partition)
+}
+
+// PublishWebhookLaneDelivery will publish the delivery to the lane of the endpoint
+func (s *NatsService) PublishWebhookLaneDelivery(endpointKey string, data []byte) error {
+	return s.publish(webhookEndpointLaneSubject+"."+endpointKey, data)
+}
+
+// CreateWebhookLaneConsumer returns the consumer of the lane of the endpoint,
+// which allows only one pending delivery, same as the partitions
+func (s *NatsService) CreateWebhookLaneConsumer(endpointKey string) (jetstream.Consumer, error) {
+	return s.js.CreateOrUpdateConsumer(s.ctx, WebhookDeliveryStream, jetstream.ConsumerConfig{
+		Durable:           webhookEndpointLaneDurable + endpointKey,
+		FilterSubject:     webhookEndpointLaneSubject + "." + endpointKey,
+		AckPolicy:         jetstream.AckExplicitPolicy,
+		AckWait:           time.Minute,
+		MaxAckPending:     1,
+		InactiveThreshold: webhookEndpointLaneInactiveThreshold,
+	})
+}
+
+// GetWebhookLaneSizes returns the number of deliveries in the lanes by the endpoint key,
+// the lanes without any delivery won't be included
+func (s *NatsService) GetWebhookLaneSizes() (map[string]uint64, error) {
+	stream, err := s.js.Stream(s.ctx, WebhookDeliveryStream)
+	switch {
+	case errors.Is(err, jetstream.ErrStreamNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	info, err := stream.Info(s.ctx, jetstream.WithSubjectFilter(webhookEndpointLaneSubject+".*"))
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]uint64, len(info.State.Subjects))
+	for subject, n := range info.State.Subjects {
+		sizes[strings.TrimPrefix(subject, webhookEndpointLaneSubject+".")] = n
+	}
+	return sizes, nil
+}
+
+// GetWebhookLaneSize returns the number of deliveries in the lane of the endpoint,
+// including the one which wasn't acknowledged yet
+func (s *NatsService) GetWebhookLaneSize(endpointKey string) (uint64, error) {
+	stream, err := s.js.Stream(s.ctx, WebhookDeliveryStream)
+	if err != nil {
+		return 0, err
+	}
+
+	subject := webhookEndpointLaneSubject + "." + endpointKey
+	info, err := stream.Info(s.ctx, jetstream.WithSubjectFilter(subject))
+	if err != nil {
+		return 0, err
+	}
+	return info.State.Subjects[subject], nil
+}
+
+// GetWebhookEndpointState returns the backoff state of the endpoint with the revision,
+// which can be used to update it
+func (s *NatsService) GetWebhookEndpointState(key string) ([]byte, uint64, error) {
+	kv, err := s.js.KeyValue(s.ctx, WebhookEndpointKvKey)
+	switch {
+	case errors.Is(err, jetstream.ErrBucketNotFound):
+		return nil, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+
+	entry, err := kv.Get(s.ctx, key)
+	switch {
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		return nil, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+
+	return entry.Value(), entry.Revision(), nil
+}
+
+// UpdateWebhookEndpointState will save the state only if it wasn't changed after the revision,
+// 0 revision means it should not exist yet
+func (s *NatsService) UpdateWebhookEndpointState(key string, val []byte, revision uint64) error {
+	kv, err := s.js.KeyValue(s.ctx, WebhookEndpointKvKey)
+	if err != nil {
+		return err
+	}
+
+	if revision == 0 {
+		_, err = kv.Create(s.ctx, key, val)
+	} else {
+		_, err = kv.Update(s.ctx, key, val, revision)
+	}
+	return err
+}
+
+// DeleteWebhookEndpointStateAt will delete the state only if it wasn't changed after the revision
+func (s *NatsService) DeleteWebhookEndpointStateAt(key string, revision uint64) error {
+	kv, err := s.js.KeyValue(s.ctx, WebhookEndpointKvKey)
+	if err != nil {
+		return err
+	}
+	return kv.Delete(s.ctx, key, jetstream.LastRevision(revision))
+}
+
+func (s *NatsService) PublishWebhookDeadLetter(data []byte) error {
+	return s.publish(WebhookDeadLetterStream, data)
+}
+
+// GetWebhookDeadLetters returns dead letters starting after skipping `from` messages
+// with the total number of messages in the stream
+func (s *NatsService) GetWebhookDeadLetters(from uint64, limit int) ([]*jetstream.RawStreamMsg, uint64, error) {
+	stream, err := s.js.Stream(s.ctx, WebhookDeadLetterStream)
+	switch {
+	case errors.Is(err, jetstream.ErrStreamNotFound):
+		return nil, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+
+	info, err := stream.Info(s.ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := info.State.Msgs
+	if total == 0 || from >= total {
+		return nil, total, nil
+	}
+
+	var list []*jetstream.RawStreamMsg
+	var skipped uint64
+	seq := info.State.FirstSeq
+	for seq <= info.State.LastSeq && len(list) < limit {
+		// will return the next available message from this sequence
+		msg, err := stream.GetMsg(s.ctx, seq, jetstream.WithGetMsgSubject(WebhookDeadLetterStream))
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			break
+		} else if err != nil {
+			return nil, 0, err
+		}
+		seq = msg.Sequence + 1
+
+		if skipped < from {
+			skipped++
+			continue
+		}
+		list = append(list, msg)
+	}
+
+	return list, total, nil
+}
+
+func (s *NatsService) GetWebhookDeadLetter(seq uint64) (*jetstream.RawStreamMsg, error) {
+	stream, err := s.js.Stream(s.ctx, WebhookDeadLetterStream)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := stream.GetMsg(s.ctx, seq)
+	if errors.Is(err, jetstream.ErrMsgNotFound) {
+		return nil, nil
+	}
+	return msg, err
+}
+
+func (s *NatsService) DeleteWebhookDeadLetter(seq uint64) error {
+	stream, err := s.js.Stream(s.ctx, WebhookDeadLetterStream)
+	if err != nil {
+		return err
+	}
+	return stream.DeleteMsg(s.ctx, seq)
+}
+
+// GetWebhookQueueSizes returns number of pending deliveries & dead letters
+func (s *NatsService) GetWebhookQueueSizes() (uint64, uint64, error) {
+	var sizes [2]uint64
+	for i, name := range []string{WebhookDeliveryStream, WebhookDeadLetterStream} {
+		stream, err := s.js.Stream(s.ctx, name)
+		switch {
+		case errors.Is(err, jetstream.ErrStreamNotFound):
+			continue
+		case err != nil:
+			return 0, 0, err
+		}
+
+		info, err := stream.Info(s.ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		sizes[i] = info.State.Msgs
+	}
+
+	return sizes[0], sizes[1], nil
+}
@@ -0,0 +1,20 @@
+package redisservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const bbbRequestChecksumKey = Prefix + "bbbRequestChecksum-%s"
+
+// ClaimBBBRequestChecksum returns true if the checksum wasn't used before,
+// so the same request can't be replayed within the ttl
+func (s *RedisService) ClaimBBBRequestChecksum(ctx context.Context, checksum string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf(bbbRequestChecksumKey, checksum)
+	ok, err := s.rc.SetNX(ctx, key, time.Now().UnixMilli(), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis SetNX error for key %s: %w", key, err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,28 @@
+package redisservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const downloadTokenClaimKey = Prefix + "downloadTokenClaim-%s"
+
+// ClaimDownloadToken will claim the single use token for the ip, if it wasn't claimed yet.
+// It returns the ip which has claimed the token.
+func (s *RedisService) ClaimDownloadToken(ctx context.Context, tokenId, ip string, ttl time.Duration) (string, error) {
+	key := fmt.Sprintf(downloadTokenClaimKey, tokenId)
+	ok, err := s.rc.SetNX(ctx, key, ip, ttl).Result()
+	if err != nil {
+		return "", fmt.Errorf("redis SetNX error for key %s: %w", key, err)
+	}
+	if ok {
+		return ip, nil
+	}
+
+	claimedBy, err := s.rc.Get(ctx, key).Result()
+	if err != nil {
+		return "", fmt.Errorf("redis Get error for key %s: %w", key, err)
+	}
+	return claimedBy, nil
+}
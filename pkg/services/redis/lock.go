@@ -14,6 +14,7 @@ import (
 const (
 	RoomCreationLockKey = Prefix + "roomCreationLock-%s"
 	janitorLockKey      = Prefix + "janitorLeaderLock"
+	namedLockKey        = Prefix + "lock-%s"
 )
 
 // unlockScript is a Lua script for atomic check-and-delete.
@@ -91,6 +92,38 @@ func (s *RedisService) IsRoomCreationLock(ctx context.Context, roomID string) (i
 	return val == 1, nil
 }
 
+// Lock attempts to acquire a distributed lock by name,
+// which can be used to make any operation atomic across the servers.
+// Same as LockRoomCreation the lockValue should be used to unlock.
+func (s *RedisService) Lock(ctx context.Context, name string, ttl time.Duration) (acquired bool, lockValue string, err error) {
+	key := fmt.Sprintf(namedLockKey, name)
+	val := uuid.New().String()
+
+	ok, err := s.rc.SetNX(ctx, key, val, ttl).Result()
+	if err != nil {
+		return false, "", fmt.Errorf("redis SetNX error for key %s: %w", key, err)
+	}
+	if !ok {
+		return false, "", nil
+	}
+
+	return true, val, nil
+}
+
+// Unlock safely releases the lock acquired by Lock.
+func (s *RedisService) Unlock(ctx context.Context, name string, lockValue string) error {
+	if lockValue == "" {
+		return nil
+	}
+	key := fmt.Sprintf(namedLockKey, name)
+
+	_, err := s.unlockScriptExec.Eval(ctx, s.rc, []string{key}, lockValue).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("redis Eval error for unlock script on key %s: %w", key, err)
+	}
+	return nil
+}
+
 // AcquireJanitorLeaderLock attempts to acquire a distributed lock for a janitor leader election.
 func (s *RedisService) AcquireJanitorLeaderLock(ctx context.Context, ttl time.Duration) (acquired bool, lockValue string, err error) {
 	val := uuid.New().String()
@@ -0,0 +1,69 @@
+package redisservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ltiLaunchStateKey  = Prefix + "ltiLaunchState-%s"
+	ltiGradeTargetsKey = Prefix + "ltiGradeTargets-%s"
+)
+
+// SaveLtiLaunchState keeps the state of the OIDC login until the platform posts the launch
+func (s *RedisService) SaveLtiLaunchState(ctx context.Context, state string, data []byte, ttl time.Duration) error {
+	key := fmt.Sprintf(ltiLaunchStateKey, state)
+	if err := s.rc.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis Set error for key %s: %w", key, err)
+	}
+	return nil
+}
+
+// ConsumeLtiLaunchState returns the saved state & removes it, so the same launch can't be replayed.
+// nil will be returned if the state doesn't exist or has expired.
+func (s *RedisService) ConsumeLtiLaunchState(ctx context.Context, state string) ([]byte, error) {
+	key := fmt.Sprintf(ltiLaunchStateKey, state)
+	data, err := s.rc.GetDel(ctx, key).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("redis GetDel error for key %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// AddLtiGradeTarget keeps where the grade of the user should be sent after the room has ended.
+// The latest launch of the user will replace the previous one.
+func (s *RedisService) AddLtiGradeTarget(ctx context.Context, roomId, userId string, data []byte, ttl time.Duration) error {
+	key := fmt.Sprintf(ltiGradeTargetsKey, roomId)
+	_, err := s.rc.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, userId, data)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis HSet error for key %s: %w", key, err)
+	}
+	return nil
+}
+
+// ConsumeLtiGradeTargets returns the grade targets of the room by user id & removes them,
+// so the grades of a session will be sent only once
+func (s *RedisService) ConsumeLtiGradeTargets(ctx context.Context, roomId string) (map[string]string, error) {
+	key := fmt.Sprintf(ltiGradeTargetsKey, roomId)
+	var res *redis.MapStringStringCmd
+	_, err := s.rc.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		res = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redis HGetAll error for key %s: %w", key, err)
+	}
+	return res.Val(), nil
+}
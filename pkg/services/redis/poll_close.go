@@ -29,6 +29,9 @@ func (s *RedisService) ClosePoll(r *plugnmeet.ClosePollReq) error {
 		if err != nil {
 			return err
 		}
+		if !info.IsRunning {
+			return errors.New("poll already closed")
+		}
 
 		info.IsRunning = false
 		info.ClosedBy = r.UserId
@@ -58,11 +61,14 @@ func (s *RedisService) CleanUpPolls(roomId string, pollIds []string) error {
 		pp.Del(s.ctx, respondentsKey)
 		pp.Del(s.ctx, votedUsersKey)
 		pp.Del(s.ctx, allRespondentsKey)
+		pp.ZRem(s.ctx, pollDeadlinesKey, pollDeadlineMember(roomId, id))
 	}
 
 	// e.g. pnm:polls:{roomId}
 	roomKey := pollsKey + roomId
 	pp.Del(s.ctx, roomKey)
+	// e.g. pnm:polls:{roomId}:settings
+	pp.Del(s.ctx, roomKey+pollSettingsSubKey)
 
 	_, err := pp.Exec(s.ctx)
 	if err != nil {
@@ -71,3 +77,13 @@ func (s *RedisService) CleanUpPolls(roomId string, pollIds []string) error {
 
 	return nil
 }
+
+// RemovePollDeadline returns true if the poll was removed by this call,
+// which helps to make sure that only one process will close the poll
+func (s *RedisService) RemovePollDeadline(roomId, pollId string) (bool, error) {
+	removed, err := s.rc.ZRem(s.ctx, pollDeadlinesKey, pollDeadlineMember(roomId, pollId)).Result()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, nil
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/mynaparrot/plugnmeet-protocol/plugnmeet"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -13,6 +12,8 @@ const (
 	pollRespondentsSubKey = ":respondents:"
 	pollVotedUsersSubKey  = ":voted_users"
 	pollAllResSubKey      = ":all_respondents"
+	pollSettingsSubKey    = ":settings"
+	pollDeadlinesKey      = "pnm:poll_deadlines"
 	PollTotalRespField    = "total_resp"
 	PollCountSuffix       = "_count"
 )
@@ -25,11 +26,31 @@ func (s *RedisService) CreateRoomPoll(roomId string, val map[string]string) erro
 	return nil
 }
 
-func (s *RedisService) AddPollResponse(r *plugnmeet.SubmitPollResponseReq) error {
+// SetPollSettings will store additional settings of the poll
+func (s *RedisService) SetPollSettings(roomId, pollId, settings string) error {
+	// e.g. key: pnm:polls:{roomId}:settings
+	_, err := s.rc.HSet(s.ctx, pollsKey+roomId+pollSettingsSubKey, pollId, settings).Result()
+	return err
+}
+
+// AddPollDeadline will add the poll to the sorted set with deadline as score,
+// so that it can be closed automatically.
+func (s *RedisService) AddPollDeadline(roomId, pollId string, deadline int64) error {
+	_, err := s.rc.ZAdd(s.ctx, pollDeadlinesKey, redis.Z{
+		Score:  float64(deadline),
+		Member: pollDeadlineMember(roomId, pollId),
+	}).Result()
+	return err
+}
+
+// AddPollResponse will add the response of the user.
+// For multiple choice polls selectedOptions can have more than one option,
+// each of them will be added in the all_respondents list with respondentId.
+func (s *RedisService) AddPollResponse(roomId, pollId, userId, respondentId, name string, selectedOptions []uint64) error {
 	// respondentsKey is the base key for a specific poll's responses.
 	// It's a HASH that stores counters like total_resp, 1_count, etc.
 	// e.g. pnm:polls:room_id:respondents:poll_id
-	respondentsKey := fmt.Sprintf("%s%s%s%s", pollsKey, r.RoomId, pollRespondentsSubKey, r.PollId)
+	respondentsKey := fmt.Sprintf("%s%s%s%s", pollsKey, roomId, pollRespondentsSubKey, pollId)
 
 	// votedUsersKey is a SET that stores the user IDs of everyone who has voted.
 	// Used for O(1) check to see if a user has already voted.
@@ -42,7 +63,7 @@ func (s *RedisService) AddPollResponse(r *plugnmeet.SubmitPollResponseReq) error
 
 	return s.rc.Watch(s.ctx, func(tx *redis.Tx) error {
 		// Check if the user has already voted using a Set for O(1) lookup.
-		voted, err := tx.SIsMember(s.ctx, votedUsersKey, r.UserId).Result()
+		voted, err := tx.SIsMember(s.ctx, votedUsersKey, userId).Result()
 		if err != nil && !errors.Is(err, redis.Nil) {
 			return err
 		}
@@ -50,20 +71,27 @@ func (s *RedisService) AddPollResponse(r *plugnmeet.SubmitPollResponseReq) error
 			return fmt.Errorf("user already voted")
 		}
 
-		// format userId:option_id:name
-		voteData := fmt.Sprintf("%s:%d:%s", r.UserId, r.SelectedOption, r.Name)
-
 		// Queue commands directly on the transaction object.
 		// Add user to the set of voters.
-		tx.SAdd(s.ctx, votedUsersKey, r.UserId)
-		// Add the vote details to a list.
-		tx.RPush(s.ctx, allRespondentsKey, voteData)
+		tx.SAdd(s.ctx, votedUsersKey, userId)
 		// Increment the total response counter.
 		tx.HIncrBy(s.ctx, respondentsKey, PollTotalRespField, 1)
-		// Increment the specific option counter.
-		tx.HIncrBy(s.ctx, respondentsKey, fmt.Sprintf("%d%s", r.SelectedOption, PollCountSuffix), 1)
+
+		for _, opt := range selectedOptions {
+			// format respondentId:option_id:name
+			voteData := fmt.Sprintf("%s:%d:%s", respondentId, opt, name)
+			// Add the vote details to a list.
+			tx.RPush(s.ctx, allRespondentsKey, voteData)
+			// Increment the specific option counter.
+			tx.HIncrBy(s.ctx, respondentsKey, fmt.Sprintf("%d%s", opt, PollCountSuffix), 1)
+		}
 		// The commands will be executed when the function returns.
 
 		return nil
 	}, votedUsersKey)
 }
+
+// pollDeadlineMember uses pollId first as it's an uuid & won't contain colon
+func pollDeadlineMember(roomId, pollId string) string {
+	return pollId + ":" + roomId
+}
@@ -3,6 +3,8 @@ package redisservice
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -91,3 +93,45 @@ func (s *RedisService) GetPollInfoByPollId(roomId, pollId string) (string, error
 
 	return result, nil
 }
+
+func (s *RedisService) GetPollSettings(roomId, pollId string) (string, error) {
+	// e.g. key: pnm:polls:{roomId}:settings
+	result, err := s.rc.HGet(s.ctx, pollsKey+roomId+pollSettingsSubKey, pollId).Result()
+
+	switch {
+	case errors.Is(err, redis.Nil):
+		return "", nil
+	case err != nil:
+		return "", err
+	}
+
+	return result, nil
+}
+
+// GetExpiredPollDeadlines returns map of pollId => roomId of the polls
+// which deadline has been passed
+func (s *RedisService) GetExpiredPollDeadlines(now int64) (map[string]string, error) {
+	result, err := s.rc.ZRangeByScore(s.ctx, pollDeadlinesKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now, 10),
+	}).Result()
+
+	switch {
+	case errors.Is(err, redis.Nil):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	polls := make(map[string]string, len(result))
+	for _, m := range result {
+		// format pollId:roomId
+		p := strings.SplitN(m, ":", 2)
+		if len(p) != 2 {
+			continue
+		}
+		polls[p[0]] = p[1]
+	}
+
+	return polls, nil
+}
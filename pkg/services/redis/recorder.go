@@ -0,0 +1,45 @@
+package redisservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const autoRecordingLockKey = Prefix + "autoRecordingLock-%s-%s"
+
+// LockAutoRecording will make sure that only one request can send the same auto recording task of the room at a time.
+// The lock will be released automatically after the ttl, so the status of the room can be updated by the recorder meanwhile.
+func (s *RedisService) LockAutoRecording(ctx context.Context, roomID, task string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf(autoRecordingLockKey, roomID, task)
+	ok, err := s.rc.SetNX(ctx, key, time.Now().UnixMilli(), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis SetNX error for key %s: %w", key, err)
+	}
+	return ok, nil
+}
+
+const recordingManuallyStoppedKey = Prefix + "recordingManuallyStopped-%s"
+
+// SetRecordingManuallyStopped will mark that the recording of the session was stopped by a host,
+// so that it won't be started again automatically.
+func (s *RedisService) SetRecordingManuallyStopped(ctx context.Context, roomSid string, ttl time.Duration) error {
+	key := fmt.Sprintf(recordingManuallyStoppedKey, roomSid)
+	return s.rc.Set(ctx, key, time.Now().UnixMilli(), ttl).Err()
+}
+
+// DeleteRecordingManuallyStopped will remove the mark when a host has started the recording again
+func (s *RedisService) DeleteRecordingManuallyStopped(ctx context.Context, roomSid string) error {
+	key := fmt.Sprintf(recordingManuallyStoppedKey, roomSid)
+	return s.rc.Del(ctx, key).Err()
+}
+
+// IsRecordingManuallyStopped checks if the recording of the session was stopped by a host
+func (s *RedisService) IsRecordingManuallyStopped(ctx context.Context, roomSid string) (bool, error) {
+	key := fmt.Sprintf(recordingManuallyStoppedKey, roomSid)
+	n, err := s.rc.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis Exists error for key %s: %w", key, err)
+	}
+	return n > 0, nil
+}
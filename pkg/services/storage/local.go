@@ -0,0 +1,260 @@
+package storageservice
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// localStore keeps files in a directory of the local filesystem,
+// which can also be an NFS or other network-accessible location.
+type localStore struct {
+	root string
+}
+
+func newLocalStore(root string) *localStore {
+	return &localStore{
+		root: root,
+	}
+}
+
+// path returns the full path of the key.
+// Cleaning with a leading slash makes sure that key can't go outside the root.
+func (s *localStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(filepath.Clean("/"+key)))
+}
+
+func (s *localStore) Put(key string, r io.Reader, _ int64, _ string) error {
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (s *localStore) PutFile(key, localPath string) error {
+	if isSamePath(localPath, s.path(key)) {
+		// already in the right place
+		return nil
+	}
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.Put(key, f, 0, "")
+}
+
+func (s *localStore) GetFile(key, localPath string) error {
+	p := s.path(key)
+	if isSamePath(localPath, p) {
+		_, err := s.Stat(key)
+		return err
+	}
+
+	rc, _, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err = os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, rc)
+	return err
+}
+
+func (s *localStore) Get(key string) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+func (s *localStore) GetRange(key string, offset, length int64) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+
+	return &limitedReadCloser{
+		Reader: io.LimitReader(f, length),
+		Closer: f,
+	}, info, nil
+}
+
+func (s *localStore) Stat(key string) (*ObjectInfo, error) {
+	st, err := os.Stat(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if st.IsDir() {
+		return nil, ErrNotFound
+	}
+
+	return &ObjectInfo{
+		Key:         key,
+		Size:        st.Size(),
+		ModTime:     st.ModTime(),
+		ContentType: mime.TypeByExtension(filepath.Ext(key)),
+	}, nil
+}
+
+func (s *localStore) Delete(key string) error {
+	p := s.path(key)
+	err := os.Remove(p)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	s.removeEmptyParents(filepath.Dir(p))
+	return nil
+}
+
+func (s *localStore) DeletePrefix(prefix string) error {
+	p := s.path(prefix)
+	if isSamePath(p, s.root) {
+		return errors.New("can't delete root directory of the store")
+	}
+	return os.RemoveAll(p)
+}
+
+func (s *localStore) List(prefix string) ([]*ObjectInfo, error) {
+	var list []*ObjectInfo
+	err := filepath.WalkDir(s.path(prefix), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return nil
+		}
+
+		list = append(list, &ObjectInfo{
+			Key:     filepath.ToSlash(rel),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+
+	return list, err
+}
+
+func (s *localStore) PresignedURL(_, _ string, _ time.Duration) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *localStore) LocalPath(key string) string {
+	return s.path(key)
+}
+
+// moveTo uses os.Rename, so both the stores should be on the same disk
+func (s *localStore) moveTo(key string, dst *localStore, dstKey string) error {
+	from := s.path(key)
+	to := dst.path(dstKey)
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	// otherwise during cleanup will be hard to detect
+	now := time.Now()
+	_ = os.Chtimes(to, now, now)
+	s.removeEmptyParents(filepath.Dir(from))
+
+	return nil
+}
+
+// removeEmptyParents will remove empty directories up to the root
+func (s *localStore) removeEmptyParents(dir string) {
+	for dir = filepath.Clean(dir); isInsideRoot(s.root, dir); dir = filepath.Dir(dir) {
+		// os.Remove won't delete non-empty directory
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
+
+// isInsideRoot returns true if dir is a subdirectory of root,
+// the root itself & the siblings with the same prefix, e.g. /data/upload2 for /data/upload are not
+func isInsideRoot(root, dir string) bool {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func isSamePath(a, b string) bool {
+	aa, err := filepath.Abs(a)
+	if err != nil {
+		return false
+	}
+	bb, err := filepath.Abs(b)
+	if err != nil {
+		return false
+	}
+	return aa == bb
+}
+
+// limitedReadCloser will read only up to the limit but close the underlying file
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
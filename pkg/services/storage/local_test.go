@@ -0,0 +1,122 @@
+package storageservice
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	root := t.TempDir()
+	s := newLocalStore(root)
+
+	data := []byte("test content")
+	if err := s.Put("room_sid/file.txt", bytes.NewReader(data), int64(len(data)), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := s.Stat("room_sid/file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), info.Size)
+	}
+
+	rc, _, err := s.Get("room_sid/file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if !bytes.Equal(got, data) {
+		t.Errorf("expected %q, got %q", data, got)
+	}
+
+	rc, _, err = s.GetRange("room_sid/file.txt", 5, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = io.ReadAll(rc)
+	_ = rc.Close()
+	if string(got) != "cont" {
+		t.Errorf("expected %q, got %q", "cont", got)
+	}
+
+	// key must not be able to escape from root
+	if p := s.LocalPath("../../etc/passwd"); p != filepath.Join(root, "etc", "passwd") {
+		t.Errorf("unexpected path %s", p)
+	}
+
+	list, err := s.List("room_sid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Key != "room_sid/file.txt" {
+		t.Errorf("unexpected list result %+v", list)
+	}
+
+	if err = s.Delete("room_sid/file.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Stat("room_sid/file.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	// empty directory should be removed too
+	if _, err = os.Stat(filepath.Join(root, "room_sid")); !os.IsNotExist(err) {
+		t.Errorf("expected directory to be removed")
+	}
+	// deleting again shouldn't return error
+	if err = s.Delete("room_sid/file.txt"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLocalStore_Move(t *testing.T) {
+	src := newLocalStore(t.TempDir())
+	dst := newLocalStore(t.TempDir())
+	ss := &StorageService{}
+
+	if err := src.Put("sub/rec.mp4", bytes.NewReader([]byte("video")), 5, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Move(src, "sub/rec.mp4", dst, "rec.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.Stat("sub/rec.mp4"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected source to be removed, got %v", err)
+	}
+	if _, err := dst.Stat("rec.mp4"); err != nil {
+		t.Errorf("expected file in destination, got %v", err)
+	}
+}
+
+func TestLocalStore_RemoveEmptyParents(t *testing.T) {
+	base := t.TempDir()
+	s := newLocalStore(filepath.Join(base, "upload"))
+
+	sibling := filepath.Join(base, "upload2", "room_sid")
+	if err := os.MkdirAll(sibling, 0755); err != nil {
+		t.Fatal(err)
+	}
+	s.removeEmptyParents(sibling)
+	if _, err := os.Stat(sibling); err != nil {
+		t.Errorf("directory outside of the root should not be removed, got %v", err)
+	}
+
+	if err := s.Put("a/b/file.txt", bytes.NewReader([]byte("x")), 1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("a/b/file.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "upload", "a")); !os.IsNotExist(err) {
+		t.Errorf("expected empty parents to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "upload")); err != nil {
+		t.Errorf("root should not be removed, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package storageservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+)
+
+// s3Store keeps files in a S3-compatible object storage, e.g. AWS S3, MinIO etc.
+type s3Store struct {
+	ctx    context.Context
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3Client(ctx context.Context, cnf *config.S3StorageSettings) (*minio.Client, error) {
+	opts := &minio.Options{
+		Creds:  credentials.NewStaticV4(cnf.AccessKey, cnf.SecretKey, ""),
+		Secure: cnf.UseSSL,
+		Region: cnf.Region,
+	}
+	if cnf.ForcePathStyle {
+		opts.BucketLookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(cnf.Endpoint, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cnf.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check s3 bucket %s: %w", cnf.Bucket, err)
+	}
+	if !exists {
+		err = client.MakeBucket(ctx, cnf.Bucket, minio.MakeBucketOptions{Region: cnf.Region})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 bucket %s: %w", cnf.Bucket, err)
+		}
+	}
+
+	return client, nil
+}
+
+func newS3Store(ctx context.Context, client *minio.Client, bucket, prefix string) *s3Store {
+	return &s3Store{
+		ctx:    ctx,
+		client: client,
+		bucket: bucket,
+		prefix: strings.Trim(prefix, "/"),
+	}
+}
+
+func (s *s3Store) objectName(key string) string {
+	return strings.TrimPrefix(path.Join(s.prefix, path.Clean("/"+key)), "/")
+}
+
+// listPrefix makes sure that prefix will match only a "directory"
+func (s *s3Store) listPrefix(prefix string) string {
+	p := s.objectName(prefix)
+	if p == "" {
+		return ""
+	}
+	return p + "/"
+}
+
+func (s *s3Store) Put(key string, r io.Reader, size int64, contentType string) error {
+	if size <= 0 {
+		size = -1
+	}
+	_, err := s.client.PutObject(s.ctx, s.bucket, s.objectName(key), r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (s *s3Store) PutFile(key, localPath string) error {
+	_, err := s.client.FPutObject(s.ctx, s.bucket, s.objectName(key), localPath, minio.PutObjectOptions{})
+	return err
+}
+
+func (s *s3Store) GetFile(key, localPath string) error {
+	err := s.client.FGetObject(s.ctx, s.bucket, s.objectName(key), localPath, minio.GetObjectOptions{})
+	return s.convertErr(err)
+}
+
+func (s *s3Store) Get(key string) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := s.client.GetObject(s.ctx, s.bucket, s.objectName(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, s.convertErr(err)
+	}
+	return obj, info, nil
+}
+
+func (s *s3Store) GetRange(key string, offset, length int64) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := minio.GetObjectOptions{}
+	if err = opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, nil, err
+	}
+	obj, err := s.client.GetObject(s.ctx, s.bucket, s.objectName(key), opts)
+	if err != nil {
+		return nil, nil, s.convertErr(err)
+	}
+	return obj, info, nil
+}
+
+func (s *s3Store) Stat(key string) (*ObjectInfo, error) {
+	st, err := s.client.StatObject(s.ctx, s.bucket, s.objectName(key), minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s.convertErr(err)
+	}
+
+	return &ObjectInfo{
+		Key:         key,
+		Size:        st.Size,
+		ModTime:     st.LastModified,
+		ContentType: st.ContentType,
+	}, nil
+}
+
+func (s *s3Store) Delete(key string) error {
+	return s.client.RemoveObject(s.ctx, s.bucket, s.objectName(key), minio.RemoveObjectOptions{})
+}
+
+func (s *s3Store) DeletePrefix(prefix string) error {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	objectsCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+			Prefix:    s.listPrefix(prefix),
+			Recursive: true,
+		}) {
+			if obj.Err != nil {
+				return
+			}
+			select {
+			case objectsCh <- obj:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for e := range s.client.RemoveObjects(ctx, s.bucket, objectsCh, minio.RemoveObjectsOptions{}) {
+		if e.Err != nil {
+			return e.Err
+		}
+	}
+	return nil
+}
+
+func (s *s3Store) List(prefix string) ([]*ObjectInfo, error) {
+	var list []*ObjectInfo
+	base := s.objectName("")
+	if base != "" {
+		base += "/"
+	}
+	for obj := range s.client.ListObjects(s.ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    s.listPrefix(prefix),
+		Recursive: true,
+	}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		list = append(list, &ObjectInfo{
+			Key:         strings.TrimPrefix(obj.Key, base),
+			Size:        obj.Size,
+			ModTime:     obj.LastModified,
+			ContentType: obj.ContentType,
+		})
+	}
+
+	return list, nil
+}
+
+func (s *s3Store) PresignedURL(key, fileName string, expiry time.Duration) (string, error) {
+	params := make(url.Values)
+	if fileName != "" {
+		params.Set("response-content-disposition", "attachment; filename="+strconv.Quote(fileName))
+	}
+
+	u, err := s.client.PresignedGetObject(s.ctx, s.bucket, s.objectName(key), expiry, params)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *s3Store) LocalPath(_ string) string {
+	return ""
+}
+
+// moveTo uses server side copy as S3 doesn't have any rename
+func (s *s3Store) moveTo(key string, dst *s3Store, dstKey string) error {
+	_, err := s.client.CopyObject(s.ctx, minio.CopyDestOptions{
+		Bucket: dst.bucket,
+		Object: dst.objectName(dstKey),
+	}, minio.CopySrcOptions{
+		Bucket: s.bucket,
+		Object: s.objectName(key),
+	})
+	if err != nil {
+		return s.convertErr(err)
+	}
+
+	return s.Delete(key)
+}
+
+func (s *s3Store) convertErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,152 @@
+package storageservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrNotFound     = errors.New("file not found")
+	ErrNotSupported = errors.New("operation not supported by storage driver")
+)
+
+// ObjectInfo holds basic information of a stored file
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ModTime     time.Time
+	ContentType string
+}
+
+// Store is the common interface of all storage drivers.
+// Keys are always relative & use forward slash as separator.
+type Store interface {
+	// Put will write data from reader to the key
+	Put(key string, r io.Reader, size int64, contentType string) error
+	// PutFile will copy the local file to the key
+	PutFile(key, localPath string) error
+	// GetFile will copy the content of the key to the local file
+	GetFile(key, localPath string) error
+	// Get returns a reader of the key, caller must close it
+	Get(key string) (io.ReadCloser, *ObjectInfo, error)
+	// GetRange returns a reader of length bytes starting from offset, caller must close it
+	GetRange(key string, offset, length int64) (io.ReadCloser, *ObjectInfo, error)
+	// Stat returns ErrNotFound if the key does not exist
+	Stat(key string) (*ObjectInfo, error)
+	// Delete won't return error if the key does not exist
+	Delete(key string) error
+	DeletePrefix(prefix string) error
+	List(prefix string) ([]*ObjectInfo, error)
+	// PresignedURL returns ErrNotSupported for drivers which can't generate url
+	PresignedURL(key, fileName string, expiry time.Duration) (string, error)
+	// LocalPath returns the path of the key in local filesystem
+	// or empty string if the driver isn't local
+	LocalPath(key string) string
+}
+
+type StorageService struct {
+	ctx              context.Context
+	app              *config.AppConfig
+	recordings       Store
+	recordingBackups Store
+	uploads          Store
+	analytics        Store
+	logger           *logrus.Entry
+}
+
+func New(ctx context.Context, app *config.AppConfig, logger *logrus.Logger) (*StorageService, error) {
+	s := &StorageService{
+		ctx:    ctx,
+		app:    app,
+		logger: logger.WithField("service", "storage"),
+	}
+
+	switch app.StorageSettings.Driver {
+	case config.StorageDriverLocal:
+		s.recordings = newLocalStore(app.RecorderInfo.RecordingFilesPath)
+		s.recordingBackups = newLocalStore(app.RecorderInfo.DelRecordingBackupPath)
+		s.uploads = newLocalStore(app.UploadFileSettings.Path)
+		analyticsPath := "./analytics"
+		if app.AnalyticsSettings != nil && app.AnalyticsSettings.FilesStorePath != nil {
+			analyticsPath = *app.AnalyticsSettings.FilesStorePath
+		}
+		s.analytics = newLocalStore(analyticsPath)
+	case config.StorageDriverS3:
+		cnf := app.StorageSettings.S3
+		client, err := newS3Client(ctx, cnf)
+		if err != nil {
+			return nil, err
+		}
+		s.recordings = newS3Store(ctx, client, cnf.Bucket, cnf.RecordingsPrefix)
+		s.recordingBackups = newS3Store(ctx, client, cnf.Bucket, cnf.RecordingBackupsPrefix)
+		s.uploads = newS3Store(ctx, client, cnf.Bucket, cnf.UploadsPrefix)
+		s.analytics = newS3Store(ctx, client, cnf.Bucket, cnf.AnalyticsPrefix)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", app.StorageSettings.Driver)
+	}
+
+	s.logger.Infof("using %s storage driver", app.StorageSettings.Driver)
+	return s, nil
+}
+
+func (s *StorageService) Recordings() Store {
+	return s.recordings
+}
+
+func (s *StorageService) RecordingBackups() Store {
+	return s.recordingBackups
+}
+
+func (s *StorageService) Uploads() Store {
+	return s.uploads
+}
+
+func (s *StorageService) Analytics() Store {
+	return s.analytics
+}
+
+// IsLocal returns true if files are stored in local filesystem
+func (s *StorageService) IsLocal() bool {
+	return s.app.StorageSettings.Driver == config.StorageDriverLocal
+}
+
+// PresignedDownload returns true & url expiry if downloads should be redirected to presigned url
+func (s *StorageService) PresignedDownload() (bool, time.Duration) {
+	if s.app.StorageSettings.S3 == nil || s.IsLocal() {
+		return false, 0
+	}
+	return s.app.StorageSettings.S3.PresignedDownload, s.app.StorageSettings.S3.PresignedUrlExpiry
+}
+
+// Move will move the key from src store to dst store.
+// Modification time of the destination will be the time of moving.
+func (s *StorageService) Move(src Store, srcKey string, dst Store, dstKey string) error {
+	if ls, ok := src.(*localStore); ok {
+		if ld, ok := dst.(*localStore); ok {
+			return ls.moveTo(srcKey, ld, dstKey)
+		}
+	}
+	if ss, ok := src.(*s3Store); ok {
+		if sd, ok := dst.(*s3Store); ok {
+			return ss.moveTo(srcKey, sd, dstKey)
+		}
+	}
+
+	// different drivers, so we'll need to stream it
+	rc, info, err := src.Get(srcKey)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err = dst.Put(dstKey, rc, info.Size, info.ContentType); err != nil {
+		return err
+	}
+	return src.Delete(srcKey)
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FiberMiddleware starts a server span for every request,
+// continuing the trace from the incoming headers if there is any.
+// The context with the span will be available using c.UserContext()
+func FiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		carrier := propagation.MapCarrier{}
+		c.Request().Header.VisitAll(func(k, v []byte) {
+			carrier[strings.ToLower(string(k))] = string(v)
+		})
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), carrier)
+
+		method := utils.CopyString(c.Method())
+		ctx, span := Tracer().Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.URLPath(utils.CopyString(c.Path())),
+			semconv.ClientAddress(utils.CopyString(c.IP())),
+		))
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// route will be known only after matching, use it to keep the span name low cardinality
+		route := c.Route().Path
+		span.SetName(method + " " + route)
+		status := c.Response().StatusCode()
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		return err
+	}
+}
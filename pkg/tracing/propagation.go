@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// natsHeaderCarrier adapts nats.Header to propagation.TextMapCarrier.
+// Unlike http.Header, keys of nats.Header are case-sensitive,
+// so we won't canonicalize them & other clients (e.g. recorder) can read "traceparent" as it is.
+type natsHeaderCarrier nats.Header
+
+func (c natsHeaderCarrier) Get(key string) string {
+	return nats.Header(c).Get(key)
+}
+
+func (c natsHeaderCarrier) Set(key, value string) {
+	nats.Header(c).Set(key, value)
+}
+
+func (c natsHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// InjectNatsHeader will add the trace context of ctx to the headers of the NATS message.
+// The header will be created if necessary.
+func InjectNatsHeader(ctx context.Context, msg *nats.Msg) {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, natsHeaderCarrier(msg.Header))
+}
+
+// ExtractNatsHeader returns a copy of ctx with the trace context from the headers of a NATS message
+func ExtractNatsHeader(ctx context.Context, h nats.Header) context.Context {
+	if len(h) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, natsHeaderCarrier(h))
+}
+
+// InjectHttpHeader will add the trace context of ctx to the outgoing HTTP request headers
+func InjectHttpHeader(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// HeaderMap returns the trace context of ctx as a map,
+// which can be stored with a job & extracted later using ContextFromMap
+func HeaderMap(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// ContextFromMap returns a copy of ctx with the trace context stored by HeaderMap
+func ContextFromMap(ctx context.Context, m map[string]string) context.Context {
+	if len(m) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(m))
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNatsHeaderPropagation(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:     trace.SpanID{0x04, 0x05},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	msg := &nats.Msg{Subject: "test"}
+	InjectNatsHeader(ctx, msg)
+	// nats headers are case-sensitive, so the key must be kept as it is
+	if msg.Header.Get("traceparent") == "" {
+		t.Fatalf("traceparent header missing, got %v", msg.Header)
+	}
+
+	got := trace.SpanContextFromContext(ExtractNatsHeader(context.Background(), msg.Header))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsRemote() {
+		t.Errorf("unexpected span context after extract: %+v", got)
+	}
+
+	// without headers, the same context should be returned
+	if ExtractNatsHeader(ctx, nil) != ctx {
+		t.Error("expected same context for empty headers")
+	}
+}
+
+func TestContextWithSpanOf(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if ContextWithSpanOf(parent, context.Background()) != parent {
+		t.Error("expected parent when ctx has no span")
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01},
+		SpanID:  trace.SpanID{0x02},
+	})
+	ctx := ContextWithSpanOf(parent, trace.ContextWithSpanContext(context.Background(), sc))
+	if trace.SpanContextFromContext(ctx).TraceID() != sc.TraceID() {
+		t.Error("span was not carried to the parent context")
+	}
+
+	cancel()
+	if ctx.Err() == nil {
+		t.Error("cancellation of parent should be kept")
+	}
+}
@@ -0,0 +1,119 @@
+// Package tracing sets up OpenTelemetry tracing for plugNmeet.
+// When tracing isn't enabled, the global no-op provider of otel stays in use,
+// so creating spans will cost almost nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mynaparrot/plugnmeet-server/pkg/config"
+	"github.com/mynaparrot/plugnmeet-server/version"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/mynaparrot/plugnmeet-server"
+
+// common attributes of plugNmeet spans
+const (
+	RoomIdKey = attribute.Key("pnm.room_id")
+	UserIdKey = attribute.Key("pnm.user_id")
+)
+
+// ShutdownFunc will flush the pending spans & stop the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// Init will set the global tracer provider & propagator based on the config.
+func Init(ctx context.Context, cnf *config.TracingSettings, logger *logrus.Logger) (ShutdownFunc, error) {
+	if cnf == nil || !cnf.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cnf.Exporter {
+	case config.TracingExporterOtlp:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cnf.OtlpEndpoint),
+		}
+		if cnf.OtlpInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case config.TracingExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", cnf.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cnf.ServiceName),
+		semconv.ServiceVersion(version.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cnf.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.WithField("service", "tracing").WithError(err).Warnln("opentelemetry error")
+	}))
+
+	logger.WithFields(logrus.Fields{
+		"exporter": cnf.Exporter,
+		"ratio":    cnf.SampleRatio,
+	}).Infoln("opentelemetry tracing enabled")
+
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer of plugNmeet from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start will start a new internal span as child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End will record the error, if any, & end the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ContextWithSpanOf returns a copy of parent which carries the span of ctx.
+// This is useful for the services which use a long-lived context for cancellation
+// but the spans should still be linked with the current request.
+func ContextWithSpanOf(parent, ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return parent
+	}
+	return trace.ContextWithSpan(parent, trace.SpanFromContext(ctx))
+}
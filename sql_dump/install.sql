@@ -16,13 +16,17 @@ CREATE TABLE IF NOT EXISTS `pnm_room_info` (
   `webhook_url` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
   `is_breakout_room` int(1) NOT NULL DEFAULT 0,
   `parent_room_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `recording_started_at` int(11) NOT NULL DEFAULT 0,
+  `recording_retention` int(11) NOT NULL DEFAULT 0,
   `creation_time` int(10) NOT NULL DEFAULT 0,
   `created` datetime NOT NULL DEFAULT current_timestamp(),
   `ended` datetime NOT NULL DEFAULT '0000-00-00 00:00:00',
   `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
   PRIMARY KEY (`id`),
   UNIQUE KEY `sid` (`sid`),
-  KEY `idx_room_id` (`roomId`, `is_running`)
+  KEY `idx_room_id` (`roomId`, `is_running`),
+  KEY `idx_tenant_id` (`tenant_id`, `is_running`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 CREATE TABLE IF NOT EXISTS `pnm_recordings` (
@@ -36,11 +40,27 @@ CREATE TABLE IF NOT EXISTS `pnm_recordings` (
   `published` int(1) NOT NULL DEFAULT 1,
   `creation_time` int(10) NOT NULL DEFAULT 0,
   `room_creation_time` int(10) NOT NULL DEFAULT 0,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `parent_room_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `title` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `description` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `tags` varchar(1024) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `metadata` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expire_at` int(11) NOT NULL DEFAULT 0,
+  `duration` double NOT NULL DEFAULT 0,
+  `video_codec` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `audio_codec` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `width` int(6) NOT NULL DEFAULT 0,
+  `height` int(6) NOT NULL DEFAULT 0,
+  `thumbnails` int(3) NOT NULL DEFAULT 0,
   `created` datetime NOT NULL DEFAULT current_timestamp(),
   `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
   PRIMARY KEY (`id`),
   UNIQUE KEY `record_id` (`record_id`),
   KEY `idx_room_id` (`room_id`),
+  KEY `idx_tenant_id` (`tenant_id`),
+  KEY `idx_parent_room_id` (`parent_room_id`),
+  KEY `idx_expire_at` (`expire_at`),
   FOREIGN KEY (room_sid) REFERENCES `pnm_room_info` (sid)
      ON DELETE RESTRICT
      ON UPDATE CASCADE
@@ -55,10 +75,268 @@ CREATE TABLE IF NOT EXISTS `pnm_room_analytics` (
   `file_size` double UNSIGNED NOT NULL,
   `room_creation_time` int(11) NOT NULL,
   `creation_time` int(11) NOT NULL,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
   PRIMARY KEY (`id`),
   KEY `idx_room_id` (`room_id`),
   KEY `idx_file_id` (`file_id`),
+  KEY `idx_tenant_id` (`tenant_id`),
   FOREIGN KEY (room_table_id) REFERENCES `pnm_room_info` (id)
      ON DELETE RESTRICT
      ON UPDATE CASCADE
  ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_scheduled_meetings` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `schedule_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `room_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `title` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `room_template` mediumtext COLLATE utf8mb4_unicode_ci NOT NULL,
+  `start_time` int(11) NOT NULL,
+  `recurrence_rule` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `timezone` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `lead_time` int(10) NOT NULL DEFAULT 5,
+  `next_occurrence` int(11) NOT NULL DEFAULT 0,
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'active',
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `schedule_id` (`schedule_id`),
+  KEY `idx_room_id` (`room_id`),
+  KEY `idx_next_occurrence` (`status`, `next_occurrence`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_scheduled_meeting_occurrences` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `schedule_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `occurrence_time` int(11) NOT NULL,
+  `status` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `room_sid` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_schedule_occurrence` (`schedule_id`, `occurrence_time`),
+  FOREIGN KEY (schedule_id) REFERENCES `pnm_scheduled_meetings` (schedule_id)
+     ON DELETE CASCADE
+     ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_webhook_subscriptions` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `subscription_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `format` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `room_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `url` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `secret` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `events` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `enabled` int(1) NOT NULL DEFAULT 1,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `subscription_id` (`subscription_id`),
+  KEY `idx_room_id` (`room_id`, `enabled`),
+  KEY `idx_tenant_id` (`tenant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_tenants` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `max_concurrent_rooms` int(10) NOT NULL DEFAULT 0,
+  `max_participants` int(10) NOT NULL DEFAULT 0,
+  `max_recording_minutes` int(10) NOT NULL DEFAULT 0,
+  `recording_retention_days` int(10) NOT NULL DEFAULT 0,
+  `enabled` int(1) NOT NULL DEFAULT 1,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `tenant_id` (`tenant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_tenant_api_keys` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `api_key` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `secret` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `revoked` int(1) NOT NULL DEFAULT 0,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `api_key` (`api_key`),
+  KEY `idx_tenant_id` (`tenant_id`),
+  FOREIGN KEY (tenant_id) REFERENCES `pnm_tenants` (tenant_id)
+     ON DELETE CASCADE
+     ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_tenant_usage` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `period` varchar(7) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `recording_seconds` int(11) NOT NULL DEFAULT 0,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_tenant_period` (`tenant_id`, `period`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_chat_messages` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `room_table_id` int(11) NOT NULL,
+  `room_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `room_sid` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `message_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `from_user_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `from_name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `from_admin` int(1) NOT NULL DEFAULT 0,
+  `to_user_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `is_private` int(1) NOT NULL DEFAULT 0,
+  `message` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `sent_at` bigint(20) NOT NULL DEFAULT 0,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_room_message` (`room_sid`, `message_id`),
+  KEY `idx_room_id` (`room_id`),
+  KEY `idx_tenant_id` (`tenant_id`),
+  KEY `idx_created` (`created`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_polls` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `room_table_id` int(11) NOT NULL,
+  `room_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `room_sid` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `poll_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `question` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `options` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `total_responses` int(11) NOT NULL DEFAULT 0,
+  `created_by` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `closed_by` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `poll_created_at` int(11) NOT NULL DEFAULT 0,
+  `is_quiz` int(1) NOT NULL DEFAULT 0,
+  `is_anonymous` int(1) NOT NULL DEFAULT 0,
+  `multiple_choice` int(1) NOT NULL DEFAULT 0,
+  `correct_options` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_room_poll` (`room_sid`, `poll_id`),
+  KEY `idx_room_id` (`room_id`),
+  KEY `idx_tenant_id` (`tenant_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_poll_responses` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `poll_table_id` bigint(20) NOT NULL,
+  `user_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `selected_option` int(11) NOT NULL DEFAULT 0,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_poll_user_option` (`poll_table_id`, `user_id`, `selected_option`),
+  FOREIGN KEY (poll_table_id) REFERENCES `pnm_polls` (id)
+     ON DELETE CASCADE
+     ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_download_audits` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `file_type` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `file_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `file_key` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `token_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `requested_by` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `ip` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `user_agent` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `range_header` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `status` int(4) NOT NULL DEFAULT 0,
+  `bytes_served` bigint(20) NOT NULL DEFAULT 0,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  KEY `idx_file` (`file_type`, `file_id`),
+  KEY `idx_tenant_id` (`tenant_id`),
+  KEY `idx_created` (`created`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_lti_platforms` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `platform_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `issuer` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `client_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `deployment_ids` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `auth_login_url` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `auth_token_url` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `jwks_url` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `enabled` int(1) NOT NULL DEFAULT 1,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `platform_id` (`platform_id`),
+  UNIQUE KEY `idx_issuer_client_id` (`issuer`, `client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_lti_consumers` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `consumer_key` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `secret` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `default_params` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `enabled` int(1) NOT NULL DEFAULT 1,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `consumer_key` (`consumer_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `pnm_bbb_integrations` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `api_key` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `secret` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `default_params` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `webhook_url` varchar(2048) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `enabled` int(1) NOT NULL DEFAULT 1,
+  `created` datetime NOT NULL DEFAULT current_timestamp(),
+  `modified` datetime NOT NULL DEFAULT '0000-00-00 00:00:00' ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `api_key` (`api_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- for upgrading existing installations
+ALTER TABLE `pnm_room_info`
+  ADD COLUMN IF NOT EXISTS `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `parent_room_id`,
+  ADD COLUMN IF NOT EXISTS `recording_started_at` int(11) NOT NULL DEFAULT 0 AFTER `tenant_id`,
+  ADD COLUMN IF NOT EXISTS `recording_retention` int(11) NOT NULL DEFAULT 0 AFTER `recording_started_at`,
+  ADD INDEX IF NOT EXISTS `idx_tenant_id` (`tenant_id`, `is_running`);
+ALTER TABLE `pnm_recordings`
+  ADD COLUMN IF NOT EXISTS `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `room_creation_time`,
+  ADD COLUMN IF NOT EXISTS `parent_room_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `tenant_id`,
+  ADD COLUMN IF NOT EXISTS `title` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `parent_room_id`,
+  ADD COLUMN IF NOT EXISTS `description` text COLLATE utf8mb4_unicode_ci NOT NULL AFTER `title`,
+  ADD COLUMN IF NOT EXISTS `tags` varchar(1024) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `description`,
+  ADD COLUMN IF NOT EXISTS `metadata` text COLLATE utf8mb4_unicode_ci NOT NULL AFTER `tags`,
+  ADD COLUMN IF NOT EXISTS `expire_at` int(11) NOT NULL DEFAULT 0 AFTER `metadata`,
+  ADD COLUMN IF NOT EXISTS `duration` double NOT NULL DEFAULT 0 AFTER `expire_at`,
+  ADD COLUMN IF NOT EXISTS `video_codec` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `duration`,
+  ADD COLUMN IF NOT EXISTS `audio_codec` varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `video_codec`,
+  ADD COLUMN IF NOT EXISTS `width` int(6) NOT NULL DEFAULT 0 AFTER `audio_codec`,
+  ADD COLUMN IF NOT EXISTS `height` int(6) NOT NULL DEFAULT 0 AFTER `width`,
+  ADD COLUMN IF NOT EXISTS `thumbnails` int(3) NOT NULL DEFAULT 0 AFTER `height`,
+  ADD INDEX IF NOT EXISTS `idx_tenant_id` (`tenant_id`),
+  ADD INDEX IF NOT EXISTS `idx_parent_room_id` (`parent_room_id`),
+  ADD INDEX IF NOT EXISTS `idx_expire_at` (`expire_at`);
+ALTER TABLE `pnm_room_analytics`
+  ADD COLUMN IF NOT EXISTS `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `creation_time`,
+  ADD INDEX IF NOT EXISTS `idx_tenant_id` (`tenant_id`);
+ALTER TABLE `pnm_scheduled_meetings`
+  ADD COLUMN IF NOT EXISTS `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `status`,
+  ADD COLUMN IF NOT EXISTS `timezone` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `recurrence_rule`;
+ALTER TABLE `pnm_webhook_subscriptions`
+  ADD COLUMN IF NOT EXISTS `tenant_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `subscription_id`,
+  ADD COLUMN IF NOT EXISTS `format` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `tenant_id`,
+  ADD INDEX IF NOT EXISTS `idx_tenant_id` (`tenant_id`);